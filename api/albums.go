@@ -16,9 +16,10 @@ import (
 
 // AlbumsResponse represents the JSON response for the Albums API.
 type AlbumsResponse struct {
-	Error  *Error       `json:"error"`
-	Albums []data.Album `json:"albums"`
-	Songs  []data.Song  `json:"songs"`
+	Error   *Error       `json:"error"`
+	Albums  []data.Album `json:"albums"`
+	Songs   []data.Song  `json:"songs"`
+	Gallery []int        `json:"gallery"`
 }
 
 // GetAlbums retrieves one or more albums from wavepipe, and returns a HTTP status and JSON.
@@ -72,9 +73,21 @@ func GetAlbums(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// Add album and songs to output
+		// Load the art IDs of the album's gallery, in order
+		gallery, err := album.Gallery()
+		if err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		// Add album, songs, and gallery to output
 		out.Albums = []data.Album{*album}
 		out.Songs = songs
+		out.Gallery = make([]int, 0, len(gallery))
+		for _, a := range gallery {
+			out.Gallery = append(out.Gallery, a.ID)
+		}
 
 		// HTTP 200 OK with JSON
 		ren.JSON(w, 200, out)
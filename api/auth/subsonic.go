@@ -12,7 +12,7 @@ import (
 )
 
 // subsonicAuthenticate uses the Subsonic authentication method to log in to the API, returning
-// only a pair of client/server errors
+// a user, session, and a pair of client/server errors
 func subsonicAuthenticate(req *http.Request) (*data.User, *data.Session, error, error) {
 	// Check for required credentials via querystring
 	query := req.URL.Query()
@@ -66,12 +66,17 @@ func subsonicAuthenticate(req *http.Request) (*data.User, *data.Session, error,
 		return nil, nil, nil, err
 	}
 
+	// Ensure the session belongs to this user
+	if session.UserID != user.ID {
+		return nil, nil, subsonic.ErrBadCredentials, nil
+	}
+
 	// Update session expiration date by 1 week
 	session.Expire = time.Now().Add(7 * 24 * time.Hour).Unix()
 	if err := session.Update(); err != nil {
 		return nil, nil, nil, err
 	}
 
-	// No errors, return no user or session because the emulated Subsonic API is read-only
-	return nil, nil, nil, nil
+	// No errors, return user and session
+	return user, session, nil, nil
 }
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// PlayThreshold is the fraction of a song which must be streamed before a play is recorded.
+// It is set from configuration at startup, and a value of zero disables automatic play recording.
+var PlayThreshold = 0.5
+
+// HistoryResponse represents the JSON response for the History API.
+type HistoryResponse struct {
+	Error *Error      `json:"error"`
+	Plays []data.Play `json:"plays"`
+	Songs []data.Song `json:"songs"`
+}
+
+// GetHistory retrieves the current user's listening history from wavepipe, and returns a HTTP
+// status and JSON.  It can be used to fetch all plays, or a limited subset of plays, depending
+// on the request parameters.
+func GetHistory(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for history request
+	out := HistoryResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for a limit parameter
+	var plays []data.Play
+	var err error
+	if pLimit := r.URL.Query().Get("limit"); pLimit != "" {
+		// Split limit into two integers
+		var offset int
+		var count int
+		if n, err := fmt.Sscanf(pLimit, "%d,%d", &offset, &count); n < 2 || err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid comma-separated integer pair for limit"))
+			return
+		}
+
+		// Retrieve limited subset of plays
+		plays, err = data.DB.LimitPlaysForUser(user.ID, offset, count)
+	} else {
+		// Retrieve all plays
+		plays, err = data.DB.PlaysForUser(user.ID)
+	}
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Load each unique song which appears in the history
+	songs := make([]data.Song, 0)
+	seen := make(map[int]struct{})
+	for _, p := range plays {
+		if _, ok := seen[p.SongID]; ok {
+			continue
+		}
+		seen[p.SongID] = struct{}{}
+
+		// Skip any songs which have since been removed
+		song := &data.Song{ID: p.SongID}
+		if err := song.Load(); err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		songs = append(songs, *song)
+	}
+
+	// HTTP 200 OK with JSON
+	out.Plays = plays
+	out.Songs = songs
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PostHistory records a play of the specified song by the current user, and returns a HTTP
+// status and JSON.  It is used by clients which play songs without streaming them through
+// wavepipe, or which wish to record plays explicitly.
+func PostHistory(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for history request
+	out := HistoryResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	pID, ok := mux.Vars(r)["id"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no integer song ID provided"))
+		return
+	}
+
+	// Verify valid integer ID
+	id, err := strconv.Atoi(pID)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer song ID"))
+		return
+	}
+
+	// Load the song
+	song := &data.Song{ID: id}
+	if err := song.Load(); err != nil {
+		// Check for invalid ID
+		if err == sql.ErrNoRows {
+			ren.JSON(w, 404, errRes(404, "song ID not found"))
+			return
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Record the play
+	play, err := data.NewPlay(user.ID, song.ID)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Reload song to capture updated play statistics
+	if err := song.Load(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	out.Plays = []data.Play{*play}
+	out.Songs = []data.Song{*song}
+	ren.JSON(w, 200, out)
+	return
+}
+
+// recordPlay records a play of the input song by the user stored in the request context, if the
+// stream from the start to the end position, as fractions of the song, crossed the configured
+// play threshold.  Ranges which start beyond the threshold are not counted again, so that
+// seeking or probing the end of a song does not record extra plays.
+func recordPlay(r *http.Request, song *data.Song, start float64, end float64) {
+	// Only record plays for authenticated users
+	user, ok := context.Get(r, CtxUser).(*data.User)
+	if !ok || user == nil {
+		return
+	}
+
+	// A threshold of zero disables automatic play recording
+	if PlayThreshold <= 0 || start >= PlayThreshold || end < PlayThreshold {
+		return
+	}
+
+	// Record the play
+	if _, err := data.NewPlay(user.ID, song.ID); err != nil {
+		log.Println(err)
+	}
+}
@@ -38,6 +38,14 @@ func HTTPStream(song *data.Song, mimeType string, contentLength int64, inputStre
 	// Output data stream, which uses the input stream by default
 	stream := inputStream
 
+	// Size of the entire stream and offset of the requested range within it, used to estimate
+	// the fraction of the song which was played
+	size := contentLength
+	var offset int64
+
+	// HTTP status, which indicates partial content if a range is requested
+	status := 200
+
 	// Check for a Range header with bytes request, meaning the client is seeking through the stream
 	// If client requests the entire stream (browsers, "bytes=0-"), skip range logic
 	rawRange := req.Header.Get("Range")
@@ -50,6 +58,9 @@ func HTTPStream(song *data.Song, mimeType string, contentLength int64, inputStre
 
 		// Attempt to parse byte range
 		pair := strings.Split(rawRange[6:], "-")
+		if len(pair) != 2 {
+			return ErrInvalidRange
+		}
 
 		// Parse first element as the starting point
 		startOffset, err := strconv.ParseInt(pair[0], 10, 64)
@@ -57,19 +68,18 @@ func HTTPStream(song *data.Song, mimeType string, contentLength int64, inputStre
 			return err
 		}
 
-		// Parse second element as the ending point, if available
-		var endOffset int64
+		// By default, use the length of the file (minus 1 byte) as the ending offset, or parse
+		// second element as the ending point, if available
+		rangeEnd := contentLength - 1
 		if pair[1] != "" {
-			tempEndOffset, err := strconv.ParseInt(pair[1], 10, 64)
+			rangeEnd, err = strconv.ParseInt(pair[1], 10, 64)
 			if err != nil {
 				return err
 			}
-
-			endOffset = tempEndOffset
 		}
 
 		// Check for invalid boundaries for seeking
-		if startOffset > contentLength || endOffset > contentLength {
+		if startOffset < 0 || startOffset > rangeEnd || rangeEnd >= contentLength {
 			return ErrInvalidRange
 		}
 
@@ -78,27 +88,15 @@ func HTTPStream(song *data.Song, mimeType string, contentLength int64, inputStre
 			return err
 		}
 
-		// Recalculate content length to account for start offset
-		contentLength = song.FileSize - startOffset
-
-		// By default, use the length of the file (minus 1 byte) as the ending offset
-		rangeEnd := contentLength - 1
-
-		// If an ending offset was set, recalculate values, and limit the stream to return up to that point
-		if endOffset > 0 {
-			// Recalculate range ending offset using the specified end point
-			rangeEnd = endOffset
-
-			// Recalculate content length
-			contentLength = endOffset - startOffset
-
-			// Wrap the stream to return only contentLength bytes
-			stream = io.LimitReader(seekStream.(io.Reader), contentLength)
-		}
-
-		// Respond with HTTP 206 Partial Content, and Content-Range header indicating the stream offset
-		res.WriteHeader(206)
+		// Respond with HTTP 206 Partial Content, and Content-Range header indicating the stream
+		// offset within the entire stream
+		status = 206
+		offset = startOffset
 		res.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", startOffset, rangeEnd, contentLength))
+
+		// Recalculate content length, and wrap the stream to return only contentLength bytes
+		contentLength = rangeEnd - startOffset + 1
+		stream = io.LimitReader(seekStream, contentLength)
 	}
 
 	// Track the stream's progress via log
@@ -159,13 +157,17 @@ func HTTPStream(song *data.Song, mimeType string, contentLength int64, inputStre
 
 	// Set Content-Length if set
 	// NOTE: HTTP standards specify that this must be an exact length, so we cannot estimate it for
-	// transcodes unless the entire file is transcoded and then sent
+	// transcodes unless the entire file is transcoded and cached
 	if contentLength > 0 {
 		res.Header().Set("Content-Length", strconv.FormatInt(contentLength, 10))
 	}
 
-	// Override Content-Type if set
-	contentType := mime.TypeByExtension(path.Ext(song.FileName))
+	// Use the MIME type of the song's file type, falling back to its extension, and override
+	// Content-Type if set
+	contentType, ok := data.MIMEMap[song.FileTypeID]
+	if !ok {
+		contentType = mime.TypeByExtension(path.Ext(song.FileName))
+	}
 	if mimeType != "" {
 		contentType = mimeType
 	}
@@ -174,22 +176,51 @@ func HTTPStream(song *data.Song, mimeType string, contentLength int64, inputStre
 	// Set Last-Modified using filesystem modify time
 	res.Header().Set("Last-Modified", common.UNIXtoRFC1123(song.LastModified))
 
+	// Advertise ranges for streams which can be seeked
+	if _, ok := inputStream.(io.ReadSeeker); ok && size > 0 {
+		res.Header().Set("Accept-Ranges", "bytes")
+	}
+
 	// Specify connection close on send
 	res.Header().Set("Connection", "close")
+	res.WriteHeader(status)
 
 	// Begin transferring the data stream
 	for {
 		// Copy bytes in chunks from input stream to output HTTP response
 		n, err := io.CopyN(res, stream, 8192)
+
+		// Count bytes sent to track progress
+		atomic.AddInt64(&total, int64(n))
+
+		// On client disconnect or EOF, record a play if enough of the song was streamed
 		if err != nil && err != io.EOF {
+			start, end := playFraction(size, offset, atomic.LoadInt64(&total), false)
+			recordPlay(req, song, start, end)
 			return err
 		} else if err == io.EOF {
+			start, end := playFraction(size, offset, atomic.LoadInt64(&total), true)
+			recordPlay(req, song, start, end)
 			return nil
 		}
+	}
+}
 
-		// Count bytes sent to track progress
-		atomic.AddInt64(&total, int64(n))
+// playFraction estimates the positions within a song, as fractions of its length, where a
+// stream to a client started and stopped.  Ranged requests start at their offset within the
+// stream, so a song which is played using many ranges reaches the end of the song.
+func playFraction(size int64, offset int64, sent int64, complete bool) (float64, float64) {
+	// If the size of the entire stream is known, compare against it
+	if size > 0 {
+		return float64(offset) / float64(size), float64(offset+sent) / float64(size)
 	}
+
+	// Transcodes in progress have no known length, so only count complete streams
+	if complete && sent > 0 {
+		return 0, 1
+	}
+
+	return 0, 0
 }
 
 var (
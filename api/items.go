@@ -0,0 +1,102 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// Items contains the artists, albums, folders, and songs referenced by a user's stars or ratings
+type Items struct {
+	Artists []data.Artist `json:"artists"`
+	Albums  []data.Album  `json:"albums"`
+	Folders []data.Folder `json:"folders"`
+	Songs   []data.Song   `json:"songs"`
+}
+
+// newItems creates a new, empty Items struct
+func newItems() *Items {
+	return &Items{
+		Artists: make([]data.Artist, 0),
+		Albums:  make([]data.Album, 0),
+		Folders: make([]data.Folder, 0),
+		Songs:   make([]data.Song, 0),
+	}
+}
+
+// add loads the item with the specified type and ID, and adds it to the matching list.
+// Items which no longer exist are skipped.
+func (i *Items) add(itemType string, itemID int) error {
+	item, err := data.LoadItem(itemType, itemID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	switch v := item.(type) {
+	case *data.Artist:
+		i.Artists = append(i.Artists, *v)
+	case *data.Album:
+		i.Albums = append(i.Albums, *v)
+	case *data.Folder:
+		i.Folders = append(i.Folders, *v)
+	case *data.Song:
+		i.Songs = append(i.Songs, *v)
+	}
+
+	return nil
+}
+
+// itemVars parses the item type and ID from the request's route variables, and verifies that
+// the item exists.  On failure, an error is rendered to the client, and false is returned.
+func itemVars(ren *render.Render, w http.ResponseWriter, r *http.Request) (string, int, bool) {
+	// Check for a valid item type
+	itemType, ok := mux.Vars(r)["type"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no item type provided"))
+		return "", 0, false
+	}
+	if !data.ValidItemType(itemType) {
+		ren.JSON(w, 400, errRes(400, "invalid item type: "+itemType))
+		return "", 0, false
+	}
+
+	// Check for an ID parameter
+	pID, ok := mux.Vars(r)["id"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no integer item ID provided"))
+		return "", 0, false
+	}
+
+	// Verify valid integer ID
+	id, err := strconv.Atoi(pID)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer item ID"))
+		return "", 0, false
+	}
+
+	// Verify the item exists
+	if _, err := data.LoadItem(itemType, id); err != nil {
+		// Check for invalid ID
+		if err == sql.ErrNoRows {
+			ren.JSON(w, 404, errRes(404, itemType+" ID not found"))
+			return "", 0, false
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return "", 0, false
+	}
+
+	return itemType, id, true
+}
@@ -0,0 +1,534 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// PlaylistsResponse represents the JSON response for the Playlists API.
+type PlaylistsResponse struct {
+	Error     *Error               `json:"error"`
+	Playlists []data.Playlist      `json:"playlists"`
+	Entries   []data.PlaylistEntry `json:"entries"`
+	Songs     []data.Song          `json:"songs"`
+}
+
+// GetPlaylists retrieves one or more playlists from wavepipe, and returns a HTTP status and JSON.
+// It can be used to fetch a single playlist and its songs, or all playlists visible to the
+// current user, depending on the request parameters.
+func GetPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for playlists request
+	out := PlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	if pID, ok := mux.Vars(r)["id"]; ok {
+		// Verify valid integer ID
+		id, err := strconv.Atoi(pID)
+		if err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid integer playlist ID"))
+			return
+		}
+
+		// Load the playlist
+		playlist := &data.Playlist{ID: id}
+		if err := playlist.Load(); err != nil {
+			// Check for invalid ID
+			if err == sql.ErrNoRows {
+				ren.JSON(w, 404, errRes(404, "playlist ID not found"))
+				return
+			}
+
+			// All other errors
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		// Private playlists may only be viewed by their owner
+		if !playlist.CanView(user) {
+			ren.JSON(w, 403, permissionErr)
+			return
+		}
+
+		// Load the playlist's entries and songs, and add all to output
+		if err := playlistOutput(&out, playlist); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		// HTTP 200 OK with JSON
+		ren.JSON(w, 200, out)
+		return
+	}
+
+	// If no other case, retrieve all playlists visible to this user
+	playlists, err := data.DB.PlaylistsForUser(user.ID)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	out.Playlists = playlists
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PostPlaylists creates a new playlist owned by the current user, and returns a HTTP status and JSON.
+func PostPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for playlists request
+	out := PlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Do not allow guests and below to create playlists
+	if user.RoleID < data.RoleUser {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Check for required title parameter
+	title := r.PostFormValue("title")
+	if title == "" {
+		ren.JSON(w, 400, errRes(400, "missing required parameter: title"))
+		return
+	}
+
+	// Check for optional public parameter, defaulting to private
+	var public bool
+	if pPublic := r.PostFormValue("public"); pPublic != "" {
+		tempPublic, err := strconv.ParseBool(pPublic)
+		if err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid boolean for public"))
+			return
+		}
+
+		public = tempPublic
+	}
+
+	// Check for optional list of song IDs to add to the playlist
+	songIDs, err := parseIntList(r.PostFormValue("songs"))
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid comma-separated integer song IDs"))
+		return
+	}
+
+	// Verify this user does not already own a playlist with the same title
+	existing := &data.Playlist{UserID: user.ID, Title: title}
+	if err := existing.Load(); err == nil {
+		ren.JSON(w, 409, errRes(409, "playlist title already exists"))
+		return
+	} else if err != sql.ErrNoRows {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Verify all songs exist before creating the playlist
+	if ok, err := songsExist(songIDs); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	} else if !ok {
+		ren.JSON(w, 404, errRes(404, "song ID not found"))
+		return
+	}
+
+	// Generate a new playlist owned by this user
+	playlist, err := data.NewPlaylist(user.ID, title, public)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Add any initial songs to the playlist
+	if len(songIDs) > 0 {
+		if err := playlist.Append(songIDs); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+	}
+
+	// Load the playlist's entries and songs, and add all to output
+	if err := playlistOutput(&out, playlist); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PutPlaylists updates an existing playlist on the wavepipe API, and returns a HTTP status and JSON.
+// It can be used to rename a playlist, change its visibility, and append, remove, or reorder its entries.
+func PutPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for playlists request
+	out := PlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	pID, ok := mux.Vars(r)["id"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no integer playlist ID provided"))
+		return
+	}
+
+	// Verify valid integer ID
+	id, err := strconv.Atoi(pID)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer playlist ID"))
+		return
+	}
+
+	// Load the playlist
+	playlist := &data.Playlist{ID: id}
+	if err := playlist.Load(); err != nil {
+		// Check for invalid ID
+		if err == sql.ErrNoRows {
+			ren.JSON(w, 404, errRes(404, "playlist ID not found"))
+			return
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Only allow the owner of a playlist, or an administrator, to update it
+	if !playlist.CanEdit(user) {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Check for parameters to update the playlist
+	if title := r.PostFormValue("title"); title != "" && title != playlist.Title {
+		// Verify the owner does not already have a playlist with the new title
+		existing := &data.Playlist{UserID: playlist.UserID, Title: title}
+		if err := existing.Load(); err == nil {
+			ren.JSON(w, 409, errRes(409, "playlist title already exists"))
+			return
+		} else if err != sql.ErrNoRows {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		playlist.Title = title
+	}
+
+	if pPublic := r.PostFormValue("public"); pPublic != "" {
+		public, err := strconv.ParseBool(pPublic)
+		if err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid boolean for public"))
+			return
+		}
+
+		playlist.Public = public
+	}
+
+	// Check for lists of entries to modify, and validate all before making changes
+	appendIDs, err := parseIntList(r.PostFormValue("append"))
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid comma-separated integer song IDs for append"))
+		return
+	}
+
+	removeIDs, err := parseIntList(r.PostFormValue("remove"))
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid comma-separated integer entry IDs for remove"))
+		return
+	}
+
+	orderIDs, err := parseIntList(r.PostFormValue("order"))
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid comma-separated integer entry IDs for order"))
+		return
+	}
+
+	// Verify all appended songs exist
+	if ok, err := songsExist(appendIDs); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	} else if !ok {
+		ren.JSON(w, 404, errRes(404, "song ID not found"))
+		return
+	}
+
+	// Verify a new order contains every entry exactly once, before any changes are saved
+	if len(orderIDs) > 0 {
+		if err := playlist.CheckOrder(orderIDs); err != nil {
+			// Check for an incomplete or invalid ordering
+			if err == data.ErrPlaylistEntries {
+				ren.JSON(w, 400, errRes(400, "order must contain every playlist entry ID exactly once"))
+				return
+			}
+
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+	}
+
+	// Save and update the playlist
+	if err := playlist.Update(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Reorder existing entries first, so that entry IDs refer to the playlist as the client saw it
+	if len(orderIDs) > 0 {
+		if err := playlist.Reorder(orderIDs); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+	}
+
+	// Remove any specified entries
+	if len(removeIDs) > 0 {
+		if err := playlist.Remove(removeIDs); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+	}
+
+	// Append any new songs to the end of the playlist
+	if len(appendIDs) > 0 {
+		if err := playlist.Append(appendIDs); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+	}
+
+	// Load the playlist's entries and songs, and add all to output
+	if err := playlistOutput(&out, playlist); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// DeletePlaylists deletes a playlist from the wavepipe API, and returns a HTTP status and JSON.
+func DeletePlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for playlists request
+	out := PlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	pID, ok := mux.Vars(r)["id"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no integer playlist ID provided"))
+		return
+	}
+
+	// Verify valid integer ID
+	id, err := strconv.Atoi(pID)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer playlist ID"))
+		return
+	}
+
+	// Load the playlist
+	playlist := &data.Playlist{ID: id}
+	if err := playlist.Load(); err != nil {
+		// Check for invalid ID
+		if err == sql.ErrNoRows {
+			ren.JSON(w, 404, errRes(404, "playlist ID not found"))
+			return
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Only allow the owner of a playlist, or an administrator, to delete it
+	if !playlist.CanEdit(user) {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Delete the playlist and its entries
+	if err := playlist.Delete(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	out.Playlists = []data.Playlist{*playlist}
+	ren.JSON(w, 200, out)
+	return
+}
+
+// playlistOutput loads the entries and songs for a playlist, and copies them into the output struct
+func playlistOutput(out *PlaylistsResponse, playlist *data.Playlist) error {
+	// Load entries, which clients use to remove or reorder items
+	entries, err := playlist.Entries()
+	if err != nil {
+		return err
+	}
+
+	// Load songs in playlist order
+	songs, err := playlist.Songs()
+	if err != nil {
+		return err
+	}
+
+	out.Playlists = []data.Playlist{*playlist}
+	out.Entries = entries
+	out.Songs = songs
+	return nil
+}
+
+// songsExist verifies that songs with all of the input IDs exist in the database
+func songsExist(songIDs []int) (bool, error) {
+	for _, id := range songIDs {
+		song := &data.Song{ID: id}
+		if err := song.Load(); err != nil {
+			if err == sql.ErrNoRows {
+				return false, nil
+			}
+
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// parseIntList parses a comma-separated list of integers, such as "1,2,3".  An empty
+// input string produces an empty list.
+func parseIntList(list string) ([]int, error) {
+	// Nothing to parse
+	if list == "" {
+		return nil, nil
+	}
+
+	// Parse each comma-separated element as an integer
+	pairs := strings.Split(list, ",")
+	ints := make([]int, 0, len(pairs))
+	for _, p := range pairs {
+		i, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+
+		ints = append(ints, i)
+	}
+
+	return ints, nil
+}
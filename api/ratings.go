@@ -0,0 +1,225 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// RatingsResponse represents the JSON response for the Ratings API.
+type RatingsResponse struct {
+	Error   *Error        `json:"error"`
+	Ratings []data.Rating `json:"ratings"`
+	Items   *Items        `json:"items"`
+}
+
+// GetRatings retrieves the current user's rated items from wavepipe, and returns a HTTP status
+// and JSON.  It can be used to fetch all ratings, or only ratings of a specified item type,
+// depending on the request parameters.
+func GetRatings(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for ratings request
+	out := RatingsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an item type filter
+	itemType, filter := mux.Vars(r)["type"]
+	if filter && !data.ValidItemType(itemType) {
+		ren.JSON(w, 400, errRes(400, "invalid item type: "+itemType))
+		return
+	}
+
+	// Retrieve all ratings for this user
+	ratings, err := data.DB.RatingsForUser(user.ID)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Load each rated item, filtering by type if needed
+	out.Ratings = make([]data.Rating, 0)
+	out.Items = newItems()
+	for _, rt := range ratings {
+		if filter && rt.ItemType != itemType {
+			continue
+		}
+
+		if err := out.Items.add(rt.ItemType, rt.ItemID); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		out.Ratings = append(out.Ratings, rt)
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PostRatings rates an item for the current user, replacing any existing rating, and returns
+// a HTTP status and JSON.
+func PostRatings(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for ratings request
+	out := RatingsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Do not allow guests and below to rate items
+	if user.RoleID < data.RoleUser {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Parse and verify the item to rate
+	itemType, itemID, ok := itemVars(ren, w, r)
+	if !ok {
+		return
+	}
+
+	// Check for required rating parameter
+	pRating := r.PostFormValue("rating")
+	if pRating == "" {
+		ren.JSON(w, 400, errRes(400, "missing required parameter: rating"))
+		return
+	}
+
+	// Verify valid integer rating
+	value, err := strconv.Atoi(pRating)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer rating"))
+		return
+	}
+
+	// Rate the item, or update the existing rating
+	rating, err := data.NewRating(user.ID, itemType, itemID, value)
+	if err != nil {
+		// Check for out of range rating
+		if err == data.ErrInvalidRating {
+			ren.JSON(w, 400, errRes(400, "rating must be between 1 and 5"))
+			return
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Add the rated item to output
+	out.Ratings = []data.Rating{*rating}
+	out.Items = newItems()
+	if err := out.Items.add(rating.ItemType, rating.ItemID); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// DeleteRatings removes a rating from an item for the current user, and returns a HTTP status
+// and JSON.
+func DeleteRatings(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for ratings request
+	out := RatingsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Do not allow guests and below to remove ratings
+	if user.RoleID < data.RoleUser {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Parse and verify the item to remove a rating from
+	itemType, itemID, ok := itemVars(ren, w, r)
+	if !ok {
+		return
+	}
+
+	// Remove the rating, if one exists
+	rating := &data.Rating{UserID: user.ID, ItemType: itemType, ItemID: itemID}
+	if err := rating.Delete(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
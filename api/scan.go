@@ -0,0 +1,139 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"path"
+
+	"github.com/mdlayher/wavepipe/common"
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// ScanResponse represents the JSON response for the Scan API
+type ScanResponse struct {
+	Error *Error             `json:"error"`
+	Scan  *common.ScanStatus `json:"scan"`
+}
+
+// GetScan returns the status of the current, or most recent, filesystem scan, and returns
+// a HTTP status and JSON.
+func GetScan(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// HTTP 200 OK with JSON
+	scan := common.CurrentScan()
+	ren.JSON(w, 200, ScanResponse{Scan: &scan})
+	return
+}
+
+// PostScan queues a media or orphan scan of all libraries, a single library, or a folder
+// within a library, and returns a HTTP status and JSON.
+func PostScan(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	sessionUser := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		sessionUser = tempUser.(*data.User)
+	} else {
+		// No sessionUser stored in context
+		log.Println("api: no sessionUser stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Only allow administrators to trigger scans
+	if sessionUser.RoleID < data.RoleAdmin {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Check for scan type, defaulting to a media scan
+	scanType := r.PostFormValue("type")
+	if scanType == "" {
+		scanType = common.ScanMedia
+	}
+	if scanType != common.ScanMedia && scanType != common.ScanOrphan {
+		ren.JSON(w, 400, errRes(400, "invalid scan type: "+scanType))
+		return
+	}
+
+	// Load all libraries, to determine which will be scanned
+	libraries, err := data.DB.AllLibraries()
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Check for optional library name and folder, and build requests for matching libraries
+	name := r.PostFormValue("library")
+	folder := r.PostFormValue("folder")
+	if folder != "" {
+		folder = path.Clean(folder)
+	}
+
+	requests := make([]common.ScanRequest, 0)
+	for _, l := range libraries {
+		if (name != "" && l.Name != name) || (folder != "" && !l.Contains(folder)) {
+			continue
+		}
+
+		requests = append(requests, common.ScanRequest{
+			Type:      scanType,
+			LibraryID: l.ID,
+			Folder:    folder,
+		})
+	}
+
+	// Ensure some library matched
+	if len(requests) == 0 {
+		if folder != "" {
+			ren.JSON(w, 404, errRes(404, "no library contains folder: "+folder))
+			return
+		}
+
+		if name != "" {
+			ren.JSON(w, 404, errRes(404, "library not found: "+name))
+			return
+		}
+
+		ren.JSON(w, 404, errRes(404, "no libraries to scan"))
+		return
+	}
+
+	// Queue all scans, or none if the queue does not have room for all of them
+	if !common.QueueScan(requests...) {
+		ren.JSON(w, 503, errRes(503, "scan queue is full, try again later"))
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	scan := common.CurrentScan()
+	ren.JSON(w, 200, ScanResponse{Scan: &scan})
+	return
+}
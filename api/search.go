@@ -3,6 +3,7 @@ package api
 import (
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/mdlayher/wavepipe/data"
@@ -20,10 +21,22 @@ type SearchResponse struct {
 	Albums  []data.Album  `json:"albums"`
 	Songs   []data.Song   `json:"songs"`
 	Folders []data.Folder `json:"folders"`
+	Totals  SearchTotals  `json:"totals"`
+}
+
+// SearchTotals contains the total number of items of each type which match a search query,
+// regardless of limit and offset
+type SearchTotals struct {
+	Artists int64 `json:"artists"`
+	Albums  int64 `json:"albums"`
+	Songs   int64 `json:"songs"`
+	Folders int64 `json:"folders"`
 }
 
 // GetSearch searches for artists, albums, songs, and folders matching a specified search query,
-// and returns a HTTP status and JSON.
+// and returns a HTTP status and JSON.  The search query may contain field filters, such as
+// `artist:"Boards of Canada" year:>=1998`.  Results are ranked by relevance, and may be limited
+// to a number of results of each type, starting at an offset.
 func GetSearch(w http.ResponseWriter, r *http.Request) {
 	// Retrieve render
 	ren := context.Get(r, CtxRender).(*render.Render)
@@ -47,6 +60,42 @@ func GetSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Parse the search query into free text and field filters
+	q, err := data.ParseQuery(query)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid search query: "+err.Error()))
+		return
+	}
+
+	// Rating filters match the current user's ratings
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		q.UserID = tempUser.(*data.User).ID
+	}
+
+	// Check for a limit parameter, which applies to each type
+	limit := -1
+	if pLimit := r.URL.Query().Get("limit"); pLimit != "" {
+		l, err := strconv.Atoi(pLimit)
+		if err != nil || l < 0 {
+			ren.JSON(w, 400, errRes(400, "invalid integer for limit"))
+			return
+		}
+
+		limit = l
+	}
+
+	// Check for an offset parameter, which applies to each type
+	offset := 0
+	if pOffset := r.URL.Query().Get("offset"); pOffset != "" {
+		o, err := strconv.Atoi(pOffset)
+		if err != nil || o < 0 {
+			ren.JSON(w, 400, errRes(400, "invalid integer for offset"))
+			return
+		}
+
+		offset = o
+	}
+
 	// Default list of type to include in outults
 	defaultTypeSet := set.New("artists", "albums", "songs", "folders")
 
@@ -70,7 +119,7 @@ func GetSearch(w http.ResponseWriter, r *http.Request) {
 	// If selected, include artists
 	if typeSet.Has("artists") {
 		// Search for artists which match the search query
-		artists, err := data.DB.SearchArtists(query)
+		artists, total, err := data.DB.SearchArtists(q, offset, limit)
 		if err != nil {
 			log.Println(err)
 			ren.JSON(w, 500, serverErr)
@@ -79,12 +128,13 @@ func GetSearch(w http.ResponseWriter, r *http.Request) {
 
 		// Copy into outponse
 		out.Artists = artists
+		out.Totals.Artists = total
 	}
 
 	// If selected, include albums
 	if typeSet.Has("albums") {
 		// Search for albums which match the search query
-		albums, err := data.DB.SearchAlbums(query)
+		albums, total, err := data.DB.SearchAlbums(q, offset, limit)
 		if err != nil {
 			log.Println(err)
 			ren.JSON(w, 500, serverErr)
@@ -93,12 +143,13 @@ func GetSearch(w http.ResponseWriter, r *http.Request) {
 
 		// Copy into outponse
 		out.Albums = albums
+		out.Totals.Albums = total
 	}
 
 	// If selected, include songs
 	if typeSet.Has("songs") {
 		// Search for songs which match the search query
-		songs, err := data.DB.SearchSongs(query)
+		songs, total, err := data.DB.SearchSongs(q, offset, limit)
 		if err != nil {
 			log.Println(err)
 			ren.JSON(w, 500, serverErr)
@@ -107,12 +158,13 @@ func GetSearch(w http.ResponseWriter, r *http.Request) {
 
 		// Copy into outponse
 		out.Songs = songs
+		out.Totals.Songs = total
 	}
 
 	// If selected, include folders
 	if typeSet.Has("folders") {
 		// Search for folders which match the search query
-		folders, err := data.DB.SearchFolders(query)
+		folders, total, err := data.DB.SearchFolders(q, offset, limit)
 		if err != nil {
 			log.Println(err)
 			ren.JSON(w, 500, serverErr)
@@ -121,6 +173,7 @@ func GetSearch(w http.ResponseWriter, r *http.Request) {
 
 		// Copy into outponse
 		out.Folders = folders
+		out.Totals.Folders = total
 	}
 
 	// HTTP 200 OK with JSON
@@ -0,0 +1,457 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// SmartPlaylistsResponse represents the JSON response for the Smart Playlists API.
+type SmartPlaylistsResponse struct {
+	Error          *Error               `json:"error"`
+	SmartPlaylists []data.SmartPlaylist `json:"smartPlaylists"`
+	Songs          []data.Song          `json:"songs"`
+}
+
+// GetSmartPlaylists retrieves one or more smart playlists from wavepipe, and returns a HTTP status
+// and JSON.  It can be used to fetch a single smart playlist and the songs which currently match
+// its rules, or all smart playlists visible to the current user, depending on the request parameters.
+func GetSmartPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for smart playlists request
+	out := SmartPlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	if pID, ok := mux.Vars(r)["id"]; ok {
+		// Verify valid integer ID
+		id, err := strconv.Atoi(pID)
+		if err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid integer smart playlist ID"))
+			return
+		}
+
+		// Load the smart playlist
+		playlist := &data.SmartPlaylist{ID: id}
+		if err := playlist.Load(); err != nil {
+			// Check for invalid ID
+			if err == sql.ErrNoRows {
+				ren.JSON(w, 404, errRes(404, "smart playlist ID not found"))
+				return
+			}
+
+			// All other errors
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		// Private smart playlists may only be viewed by their owner
+		if !playlist.CanView(user) {
+			ren.JSON(w, 403, permissionErr)
+			return
+		}
+
+		// Evaluate the smart playlist, and add it and its songs to output
+		if err := smartPlaylistOutput(&out, playlist); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		// HTTP 200 OK with JSON
+		ren.JSON(w, 200, out)
+		return
+	}
+
+	// If no other case, retrieve all smart playlists visible to this user
+	playlists, err := data.DB.SmartPlaylistsForUser(user.ID)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	out.SmartPlaylists = playlists
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PostSmartPlaylists creates a new smart playlist owned by the current user, and returns a HTTP
+// status and JSON.
+func PostSmartPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for smart playlists request
+	out := SmartPlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Do not allow guests and below to create smart playlists
+	if user.RoleID < data.RoleUser {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Check for required title parameter
+	title := r.PostFormValue("title")
+	if title == "" {
+		ren.JSON(w, 400, errRes(400, "missing required parameter: title"))
+		return
+	}
+
+	// Rules default to a private smart playlist of all songs
+	rules := &data.SmartPlaylist{
+		UserID: user.ID,
+		Title:  title,
+	}
+
+	// Check for optional public parameter
+	if pPublic := r.PostFormValue("public"); pPublic != "" {
+		public, err := strconv.ParseBool(pPublic)
+		if err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid boolean for public"))
+			return
+		}
+
+		rules.Public = public
+	}
+
+	// Check for optional rule parameters.  Query and sort may be set to empty strings, to match
+	// all songs in any order.
+	if _, ok := r.PostForm["query"]; ok {
+		rules.Query = r.PostFormValue("query")
+	}
+
+	if _, ok := r.PostForm["sort"]; ok {
+		rules.Sort = r.PostFormValue("sort")
+	}
+
+	if pLimit := r.PostFormValue("limit"); pLimit != "" {
+		limit, err := strconv.Atoi(pLimit)
+		if err != nil || limit < 0 {
+			ren.JSON(w, 400, errRes(400, "invalid integer for limit"))
+			return
+		}
+
+		rules.Limit = limit
+	}
+
+	// Verify the rules can be evaluated
+	if err := rules.Validate(); err != nil {
+		if err == data.ErrSmartPlaylistSort {
+			ren.JSON(w, 400, errRes(400, "invalid sort order: "+rules.Sort))
+			return
+		}
+
+		ren.JSON(w, 400, errRes(400, "invalid search query: "+err.Error()))
+		return
+	}
+
+	// Verify this user does not already own a smart playlist with the same title
+	existing := &data.SmartPlaylist{UserID: user.ID, Title: title}
+	if err := existing.Load(); err == nil {
+		ren.JSON(w, 409, errRes(409, "smart playlist title already exists"))
+		return
+	} else if err != sql.ErrNoRows {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Generate a new smart playlist owned by this user
+	playlist, err := data.NewSmartPlaylist(user.ID, title, rules.Public, rules.Query, rules.Sort, rules.Limit)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Evaluate the smart playlist, and add it and its songs to output
+	if err := smartPlaylistOutput(&out, playlist); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PutSmartPlaylists updates an existing smart playlist on the wavepipe API, and returns a HTTP status
+// and JSON.  It can be used to rename a smart playlist, change its visibility, and change its rules.
+func PutSmartPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for smart playlists request
+	out := SmartPlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	pID, ok := mux.Vars(r)["id"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no integer smart playlist ID provided"))
+		return
+	}
+
+	// Verify valid integer ID
+	id, err := strconv.Atoi(pID)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer smart playlist ID"))
+		return
+	}
+
+	// Load the smart playlist
+	playlist := &data.SmartPlaylist{ID: id}
+	if err := playlist.Load(); err != nil {
+		// Check for invalid ID
+		if err == sql.ErrNoRows {
+			ren.JSON(w, 404, errRes(404, "smart playlist ID not found"))
+			return
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Only allow the owner of a smart playlist, or an administrator, to update it
+	if !playlist.CanEdit(user) {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Check for parameters to update the smart playlist
+	if title := r.PostFormValue("title"); title != "" && title != playlist.Title {
+		// Verify the owner does not already have a smart playlist with the new title
+		existing := &data.SmartPlaylist{UserID: playlist.UserID, Title: title}
+		if err := existing.Load(); err == nil {
+			ren.JSON(w, 409, errRes(409, "smart playlist title already exists"))
+			return
+		} else if err != sql.ErrNoRows {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		playlist.Title = title
+	}
+
+	// Check for optional public parameter
+	if pPublic := r.PostFormValue("public"); pPublic != "" {
+		public, err := strconv.ParseBool(pPublic)
+		if err != nil {
+			ren.JSON(w, 400, errRes(400, "invalid boolean for public"))
+			return
+		}
+
+		playlist.Public = public
+	}
+
+	// Check for optional rule parameters.  Query and sort may be set to empty strings, to match
+	// all songs in any order.
+	if _, ok := r.PostForm["query"]; ok {
+		playlist.Query = r.PostFormValue("query")
+	}
+
+	if _, ok := r.PostForm["sort"]; ok {
+		playlist.Sort = r.PostFormValue("sort")
+	}
+
+	if pLimit := r.PostFormValue("limit"); pLimit != "" {
+		limit, err := strconv.Atoi(pLimit)
+		if err != nil || limit < 0 {
+			ren.JSON(w, 400, errRes(400, "invalid integer for limit"))
+			return
+		}
+
+		playlist.Limit = limit
+	}
+
+	// Verify the rules can be evaluated
+	if err := playlist.Validate(); err != nil {
+		if err == data.ErrSmartPlaylistSort {
+			ren.JSON(w, 400, errRes(400, "invalid sort order: "+playlist.Sort))
+			return
+		}
+
+		ren.JSON(w, 400, errRes(400, "invalid search query: "+err.Error()))
+		return
+	}
+
+	// Save and update the smart playlist
+	if err := playlist.Update(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Evaluate the smart playlist, and add it and its songs to output
+	if err := smartPlaylistOutput(&out, playlist); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// DeleteSmartPlaylists deletes a smart playlist from the wavepipe API, and returns a HTTP status and JSON.
+func DeleteSmartPlaylists(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for smart playlists request
+	out := SmartPlaylistsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an ID parameter
+	pID, ok := mux.Vars(r)["id"]
+	if !ok {
+		ren.JSON(w, 400, errRes(400, "no integer smart playlist ID provided"))
+		return
+	}
+
+	// Verify valid integer ID
+	id, err := strconv.Atoi(pID)
+	if err != nil {
+		ren.JSON(w, 400, errRes(400, "invalid integer smart playlist ID"))
+		return
+	}
+
+	// Load the smart playlist
+	playlist := &data.SmartPlaylist{ID: id}
+	if err := playlist.Load(); err != nil {
+		// Check for invalid ID
+		if err == sql.ErrNoRows {
+			ren.JSON(w, 404, errRes(404, "smart playlist ID not found"))
+			return
+		}
+
+		// All other errors
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Only allow the owner of a smart playlist, or an administrator, to delete it
+	if !playlist.CanEdit(user) {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Delete the smart playlist
+	if err := playlist.Delete(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	out.SmartPlaylists = []data.SmartPlaylist{*playlist}
+	ren.JSON(w, 200, out)
+	return
+}
+
+// smartPlaylistOutput evaluates a smart playlist, and copies it and its songs into the output struct
+func smartPlaylistOutput(out *SmartPlaylistsResponse, playlist *data.SmartPlaylist) error {
+	songs, err := playlist.Songs()
+	if err != nil {
+		return err
+	}
+
+	out.SmartPlaylists = []data.SmartPlaylist{*playlist}
+	out.Songs = songs
+	return nil
+}
@@ -0,0 +1,201 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/gorilla/mux"
+	"github.com/unrolled/render"
+)
+
+// StarsResponse represents the JSON response for the Stars API.
+type StarsResponse struct {
+	Error *Error      `json:"error"`
+	Stars []data.Star `json:"stars"`
+	Items *Items      `json:"items"`
+}
+
+// GetStars retrieves the current user's starred items from wavepipe, and returns a HTTP status
+// and JSON.  It can be used to fetch all stars, or only stars of a specified item type, depending
+// on the request parameters.
+func GetStars(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for stars request
+	out := StarsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Check for an item type filter
+	itemType, filter := mux.Vars(r)["type"]
+	if filter && !data.ValidItemType(itemType) {
+		ren.JSON(w, 400, errRes(400, "invalid item type: "+itemType))
+		return
+	}
+
+	// Retrieve all stars for this user
+	stars, err := data.DB.StarsForUser(user.ID)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Load each starred item, filtering by type if needed
+	out.Stars = make([]data.Star, 0)
+	out.Items = newItems()
+	for _, s := range stars {
+		if filter && s.ItemType != itemType {
+			continue
+		}
+
+		if err := out.Items.add(s.ItemType, s.ItemID); err != nil {
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
+
+		out.Stars = append(out.Stars, s)
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// PostStars stars an item for the current user, and returns a HTTP status and JSON.
+func PostStars(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for stars request
+	out := StarsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Do not allow guests and below to star items
+	if user.RoleID < data.RoleUser {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Parse and verify the item to star
+	itemType, itemID, ok := itemVars(ren, w, r)
+	if !ok {
+		return
+	}
+
+	// Star the item, or load the existing star
+	star, err := data.NewStar(user.ID, itemType, itemID)
+	if err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Add the starred item to output
+	out.Stars = []data.Star{*star}
+	out.Items = newItems()
+	if err := out.Items.add(star.ItemType, star.ItemID); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
+
+// DeleteStars removes a star from an item for the current user, and returns a HTTP status and JSON.
+func DeleteStars(w http.ResponseWriter, r *http.Request) {
+	// Retrieve render
+	ren := context.Get(r, CtxRender).(*render.Render)
+
+	// Attempt to retrieve user from context
+	user := new(data.User)
+	if tempUser := context.Get(r, CtxUser); tempUser != nil {
+		user = tempUser.(*data.User)
+	} else {
+		// No user stored in context
+		log.Println("api: no user stored in request context!")
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// Output struct for stars request
+	out := StarsResponse{}
+
+	// Check API version
+	if version, ok := mux.Vars(r)["version"]; ok {
+		// Check if this API call is supported in the advertised version
+		if !apiVersionSet.Has(version) {
+			ren.JSON(w, 400, errRes(400, "unsupported API version: "+version))
+			return
+		}
+	}
+
+	// Do not allow guests and below to unstar items
+	if user.RoleID < data.RoleUser {
+		ren.JSON(w, 403, permissionErr)
+		return
+	}
+
+	// Parse and verify the item to unstar
+	itemType, itemID, ok := itemVars(ren, w, r)
+	if !ok {
+		return
+	}
+
+	// Remove the star, if one exists
+	star := &data.Star{UserID: user.ID, ItemType: itemType, ItemID: itemID}
+	if err := star.Delete(); err != nil {
+		log.Println(err)
+		ren.JSON(w, 500, serverErr)
+		return
+	}
+
+	// HTTP 200 OK with JSON
+	ren.JSON(w, 200, out)
+	return
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/mdlayher/wavepipe/data"
+	"github.com/mdlayher/wavepipe/transcode"
 
 	"github.com/gorilla/context"
 	"github.com/gorilla/mux"
@@ -62,6 +63,19 @@ func GetStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// CUE tracks in files which cannot be split, such as FLAC, are extracted from their file
+	// using a lossless transcode
+	if song.IsCueTrack() && !song.Splittable() {
+		transcoder, err := transcode.Factory("FLAC", "", "")
+		if err != nil {
+			ren.JSON(w, 503, errRes(503, "CUE track cannot be split from its file without FLAC transcoding"))
+			return
+		}
+
+		serveTranscode(w, r, ren, song, transcoder)
+		return
+	}
+
 	// Attempt to access data stream
 	stream, err := song.Stream()
 	if err != nil {
@@ -69,6 +83,7 @@ func GetStream(w http.ResponseWriter, r *http.Request) {
 		ren.JSON(w, 500, serverErr)
 		return
 	}
+	defer stream.Close()
 
 	// Generate a string used for logging this operation
 	opStr := fmt.Sprintf("[#%05d] %s - %s [%s %dkbps]", song.ID, song.Artist, song.Title,
@@ -77,8 +92,9 @@ func GetStream(w http.ResponseWriter, r *http.Request) {
 	// Attempt to send file stream over HTTP
 	log.Println("stream: starting:", opStr)
 
-	// Pass stream using song's file size, auto-detect MIME type
-	if err := HTTPStream(song, "", song.FileSize, stream, r, w); err != nil {
+	// Pass stream using song's stream size, which is smaller than its file for CUE tracks,
+	// auto-detect MIME type
+	if err := HTTPStream(song, "", song.StreamSize(), stream, r, w); err != nil {
 		// Check for client reset
 		if strings.Contains(err.Error(), "connection reset by peer") || strings.Contains(err.Error(), "broken pipe") {
 			return
@@ -3,6 +3,7 @@ package api
 import (
 	"database/sql"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -68,18 +69,31 @@ func GetTranscode(w http.ResponseWriter, r *http.Request) {
 		codec = "MP3"
 	}
 
-	// Check for an input quality
+	// Check for an input quality, which defaults to 192kbps, or a codec's own default
 	quality := query.Get("quality")
-	if quality == "" {
-		// Default to 192kbps
-		quality = "192"
+
+	// Check for a time offset, in seconds, at which the transcode should begin
+	offset := 0
+	if pOffset := query.Get("timeOffset"); pOffset != "" {
+		offset, err = strconv.Atoi(pOffset)
+		if err != nil || offset < 0 || offset >= song.Length {
+			ren.JSON(w, 400, errRes(400, "invalid integer timeOffset"))
+			return
+		}
 	}
 
+	// Check for a named transcoding profile, which sets its own codec and quality
+	profile := query.Get("profile")
+
 	// Create a transcoder using factory
-	transcoder, err := transcode.Factory(codec, quality)
+	transcoder, err := transcode.Factory(codec, quality, profile)
 	if err != nil {
 		// Check for client errors
 		switch err {
+		// Invalid profile selected
+		case transcode.ErrInvalidProfile:
+			ren.JSON(w, 400, errRes(400, "invalid transcoding profile: "+profile))
+			return
 		// Invalid codec selected
 		case transcode.ErrInvalidCodec:
 			ren.JSON(w, 400, errRes(400, "invalid transcoder codec: "+codec))
@@ -104,6 +118,14 @@ func GetTranscode(w http.ResponseWriter, r *http.Request) {
 		case transcode.ErrOPUSDisabled:
 			ren.JSON(w, 503, errRes(503, "ffmpeg codec "+transcode.FFmpegOPUSCodec+" not found, OPUS transcoding disabled"))
 			return
+		// AAC transcoding disabled
+		case transcode.ErrAACDisabled:
+			ren.JSON(w, 503, errRes(503, "ffmpeg codec "+transcode.FFmpegAACCodec+" not found, AAC transcoding disabled"))
+			return
+		// FLAC transcoding disabled
+		case transcode.ErrFLACDisabled:
+			ren.JSON(w, 503, errRes(503, "ffmpeg codec "+transcode.FFmpegFLACCodec+" not found, FLAC transcoding disabled"))
+			return
 		// All other errors
 		default:
 			log.Println(err)
@@ -112,16 +134,34 @@ func GetTranscode(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Start the transcoder, grab output stream
-	transcodeStream, err := transcoder.Start(song)
+	transcoder.SetOffset(offset)
+	serveTranscode(w, r, ren, song, transcoder)
+}
+
+// serveTranscode sends a stream of the input song, transcoded by the input transcoder, over
+// HTTP.  Errors which occur before the stream begins are returned as JSON.
+func serveTranscode(w http.ResponseWriter, r *http.Request, ren *render.Render, song *data.Song, transcoder transcode.Transcoder) {
+	// Open the transcode, reading it from the cache if it was already created, or waiting for
+	// an ffmpeg process if too many are running
+	transcodeStream, contentLength, err := OpenTranscode(w, r, song, transcoder)
 	if err != nil {
-		log.Println(err)
-		ren.JSON(w, 500, serverErr)
-		return
+		switch err {
+		// Too many transcodes queued, so the client should try again later
+		case transcode.ErrQueueFull:
+			w.Header().Set("Retry-After", strconv.Itoa(transcode.RetryAfter))
+			ren.JSON(w, 503, errRes(503, "too many transcodes in progress, try again later"))
+			return
+		// Client disconnected while queued
+		case transcode.ErrCanceled:
+			return
+		// All other errors
+		default:
+			log.Println(err)
+			ren.JSON(w, 500, serverErr)
+			return
+		}
 	}
-
-	// Output the command ffmpeg will use to create the transcode
-	log.Println("transcode: command:", transcoder.Command())
+	defer transcodeStream.Close()
 
 	// Now that ffmpeg has started, we must assume binary data is being transferred,
 	// so no more error JSON may be sent.
@@ -133,30 +173,67 @@ func GetTranscode(w http.ResponseWriter, r *http.Request) {
 	// Attempt to send transcoded file stream over HTTP
 	log.Println("transcode: starting:", opStr)
 
-	// Send transcode stream, no size for now (estimate later)
-	if err := HTTPStream(song, transcoder.MIMEType(), -1, transcodeStream, r, w); err != nil {
+	// Send transcode stream, which has a known size once it is cached, and is otherwise sent
+	// without a Content-Length, but with its estimated size in advisory headers
+	if contentLength < 0 {
+		SetTranscodeEstimate(w, song, transcoder)
+	}
+	if err := HTTPStream(song, transcoder.MIMEType(), contentLength, transcodeStream, r, w); err != nil {
 		// Check for client reset
 		if strings.Contains(err.Error(), "connection reset by peer") || strings.Contains(err.Error(), "broken pipe") {
 			return
 		}
 
-		// Check for cannot seek error, since transcodes cannot currently take advantage of seeking
+		// Check for cannot seek error, since transcodes can only be seeked once they are cached
 		if err == ErrCannotSeek {
 			// We can send JSON HTTP 416 error, because no data is written on this error
-			ren.JSON(w, 416, errRes(416, "seeking is unavailable on transcoded media"))
+			ren.JSON(w, 416, errRes(416, "seeking is unavailable until transcoded media is cached"))
 			return
 		}
 
-		log.Println("transcode: error:", err)
-		return
-	}
+		// Check for invalid range, return HTTP 416
+		if err == ErrInvalidRange {
+			ren.JSON(w, 416, errRes(416, "invalid HTTP Range header boundaries"))
+			return
+		}
 
-	// Wait for ffmpeg to exit
-	if err := transcoder.Wait(); err != nil {
-		log.Println(err)
+		log.Println("transcode: error:", err)
 		return
 	}
 
 	log.Println("transcode: completed:", opStr)
 	return
 }
+
+// SetTranscodeEstimate sets advisory headers for a transcode whose exact length is unknown,
+// containing the duration of the song which remains after the transcoder's offset, and the
+// estimated size of the transcode.  The estimated size is returned, or -1 if it is unknown.
+func SetTranscodeEstimate(w http.ResponseWriter, song *data.Song, transcoder transcode.Transcoder) int64 {
+	w.Header().Set("X-Content-Duration", strconv.Itoa(song.Length-transcoder.Offset()))
+
+	size := transcode.EstimateSize(song, transcoder)
+	if size > 0 {
+		w.Header().Set("X-Estimated-Content-Length", strconv.FormatInt(size, 10))
+	}
+
+	return size
+}
+
+// OpenTranscode opens a stream of the input song transcoded by the input transcoder, and its
+// length, using the transcode cache.  ffmpeg processes are shared fairly between users, and
+// are stopped if the client disconnects.
+func OpenTranscode(w http.ResponseWriter, r *http.Request, song *data.Song, transcoder transcode.Transcoder) (io.ReadCloser, int64, error) {
+	// Queue transcodes by user, if one is authenticated
+	userID := 0
+	if user, ok := context.Get(r, CtxUser).(*data.User); ok && user != nil {
+		userID = user.ID
+	}
+
+	// Stop waiting for ffmpeg, or stop ffmpeg, if the client disconnects
+	var cancel <-chan bool
+	if notifier, ok := w.(http.CloseNotifier); ok {
+		cancel = notifier.CloseNotify()
+	}
+
+	return transcode.DefaultCache.Open(song, transcoder, userID, cancel)
+}
@@ -84,13 +84,19 @@ func GetWaveform(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// Open song's backing stream
+		// Open song's backing stream, which CUE tracks in files that cannot be split lack
 		stream, err := song.Stream()
 		if err != nil {
+			if err == data.ErrSongCannotSplit {
+				ren.JSON(w, 501, errRes(501, "unsupported audio format"))
+				return
+			}
+
 			log.Println(err)
 			ren.JSON(w, 500, serverErr)
 			return
 		}
+		defer stream.Close()
 
 		// Generate a waveform object
 		wave, err := waveform.New(stream, waveform.Resolution(4))
@@ -0,0 +1,226 @@
+package common
+
+import (
+	"io/ioutil"
+	"path"
+	"strings"
+	"sync"
+)
+
+// IgnoreFile is the name of a file which lists paths the media scanner should ignore, using
+// gitignore-style patterns.  Its patterns apply to the folder containing it, and all folders
+// beneath it.
+const IgnoreFile = ".wavepipeignore"
+
+// ignoreRule is a single gitignore-style pattern, which applies beneath its base folder.  Rules
+// with no base folder apply beneath the root folder of any path being checked.
+type ignoreRule struct {
+	base     string
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// parseIgnoreRule parses a gitignore-style pattern, returning false for blank lines and comments
+func parseIgnoreRule(base string, line string) (ignoreRule, bool) {
+	rule := ignoreRule{base: base}
+
+	// Skip blank lines and comments
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false
+	}
+
+	// Check for negation, which re-includes a path, and for escaped leading characters
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\") {
+		line = line[1:]
+	}
+
+	// A trailing slash only matches folders
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	// Patterns containing a slash match the path relative to the base folder, while others
+	// match a file or folder name at any depth
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimLeft(line, "/")
+	}
+
+	if line == "" {
+		return rule, false
+	}
+
+	rule.pattern = line
+	return rule, true
+}
+
+// match determines if this rule matches the input path, beneath the input root folder
+func (r ignoreRule) match(root string, p string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	// Rules only match paths beneath their base folder
+	base := r.base
+	if base == "" {
+		base = root
+	}
+	if !strings.HasPrefix(p, strings.TrimSuffix(base, "/")+"/") {
+		return false
+	}
+
+	if !r.anchored {
+		ok, _ := path.Match(r.pattern, path.Base(p))
+		return ok
+	}
+
+	rel := strings.TrimPrefix(p, strings.TrimSuffix(base, "/")+"/")
+	return matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchSegments matches pattern segments against path segments, where a '**' segment matches
+// any number of path segments
+func matchSegments(pattern []string, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try to match the remaining pattern at every remaining depth
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+
+		pattern = pattern[1:]
+		segments = segments[1:]
+	}
+
+	return len(segments) == 0
+}
+
+// Ignorer determines if paths should be ignored by the media scanner, using global exclude
+// patterns and the ignore files in each folder.  Ignore files are cached once read, so an
+// Ignorer should be reset when an ignore file changes.
+type Ignorer struct {
+	global []ignoreRule
+
+	mutex   sync.Mutex
+	folders map[string][]ignoreRule
+}
+
+// NewIgnorer creates a new Ignorer, using the input gitignore-style patterns as global excludes,
+// which apply beneath the root folder of any path being checked
+func NewIgnorer(excludes []string) *Ignorer {
+	i := &Ignorer{
+		global:  make([]ignoreRule, 0, len(excludes)),
+		folders: make(map[string][]ignoreRule),
+	}
+
+	for _, e := range excludes {
+		if rule, ok := parseIgnoreRule("", e); ok {
+			i.global = append(i.global, rule)
+		}
+	}
+
+	return i
+}
+
+// Reset clears all cached ignore files, so they are read again when they are next needed
+func (i *Ignorer) Reset() {
+	i.mutex.Lock()
+	i.folders = make(map[string][]ignoreRule)
+	i.mutex.Unlock()
+}
+
+// Ignored determines if the input path, beneath the input root folder, should be ignored.  As
+// with gitignore, a path is ignored if any folder containing it is ignored, and the last
+// matching pattern wins, so that later and deeper patterns may re-include paths.  The root
+// folder itself is never ignored.
+func (i *Ignorer) Ignored(root string, p string, isDir bool) bool {
+	root = path.Clean(root)
+	p = path.Clean(p)
+
+	// Collect the path and each folder containing it, beneath the root folder
+	paths := make([]string, 0)
+	for c := p; c != root && strings.HasPrefix(c, strings.TrimSuffix(root, "/")+"/"); c = path.Dir(c) {
+		paths = append(paths, c)
+	}
+
+	// Check from the top down, stopping once a folder is ignored
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	for j := len(paths) - 1; j >= 0; j-- {
+		if i.ignored(root, paths[j], j > 0 || isDir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ignored determines if a single path is matched by the global patterns, or by the ignore
+// files in any folder from the root folder to its own folder.  The mutex must be held.
+func (i *Ignorer) ignored(root string, p string, isDir bool) bool {
+	ignored := false
+	for _, r := range i.global {
+		if r.match(root, p, isDir) {
+			ignored = !r.negate
+		}
+	}
+
+	// Apply ignore files from the top down, so deeper patterns take priority
+	folders := make([]string, 0)
+	for d := path.Dir(p); ; d = path.Dir(d) {
+		folders = append(folders, d)
+		if d == root || d == "/" || d == "." {
+			break
+		}
+	}
+
+	for j := len(folders) - 1; j >= 0; j-- {
+		for _, r := range i.rules(folders[j]) {
+			if r.match(root, p, isDir) {
+				ignored = !r.negate
+			}
+		}
+	}
+
+	return ignored
+}
+
+// rules loads the rules from the ignore file in a folder, caching them for later use.  Folders
+// without an ignore file have no rules.  The mutex must be held.
+func (i *Ignorer) rules(folder string) []ignoreRule {
+	if rules, ok := i.folders[folder]; ok {
+		return rules
+	}
+
+	rules := make([]ignoreRule, 0)
+	if buf, err := ioutil.ReadFile(path.Join(folder, IgnoreFile)); err == nil {
+		for _, line := range strings.Split(string(buf), "\n") {
+			if rule, ok := parseIgnoreRule(folder, line); ok {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	i.folders[folder] = rules
+	return rules
+}
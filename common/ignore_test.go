@@ -0,0 +1,82 @@
+package common
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+// TestIgnorer verifies that global excludes and per-folder ignore files are applied using
+// gitignore-style patterns
+func TestIgnorer(t *testing.T) {
+	root, err := ioutil.TempDir("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(root)
+
+	// Create ignore files in the root and in a nested folder
+	files := map[string]string{
+		IgnoreFile:                           "# NAS thumbnails\n@eaDir/\n/Samples\n*.wma\n!keep.wma\nArtist/Live/**/bonus.mp3\n",
+		path.Join("Artist", IgnoreFile):      "\\#hash.mp3\n",
+		path.Join("Artist", "B", IgnoreFile): "!*.wma\n",
+	}
+	for name, contents := range files {
+		p := path.Join(root, name)
+		if err := os.MkdirAll(path.Dir(p), 0755); err != nil {
+			t.Fatalf("Could not create directory: %s", err.Error())
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatalf("Could not write ignore file: %s", err.Error())
+		}
+	}
+
+	var tests = []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"", true, false},
+		{"Artist/A/song.mp3", false, false},
+		// Global excludes
+		{".sync", true, true},
+		{"Artist/.sync/song.mp3", false, true},
+		// Folder-only patterns
+		{"Artist/@eaDir", true, true},
+		{"Artist/@eaDir/cover.jpg", false, true},
+		{"Artist/@eaDir", false, false},
+		// Anchored patterns only match beneath the ignore file's folder
+		{"Samples/kick.flac", false, true},
+		{"Artist/Samples/kick.flac", false, false},
+		// Wildcards, negation, and escapes
+		{"Artist/A/song.wma", false, true},
+		{"Artist/A/keep.wma", false, false},
+		{"Artist/#hash.mp3", false, true},
+		{"Artist/Live/1999/Disc 1/bonus.mp3", false, true},
+		{"Artist/Live/bonus.mp3", false, true},
+		{"Artist/Studio/bonus.mp3", false, false},
+		// Deeper ignore files take priority
+		{"Artist/B/song.wma", false, false},
+	}
+
+	i := NewIgnorer([]string{".sync", "", "# comment"})
+	for _, test := range tests {
+		if ignored := i.Ignored(root, path.Join(root, test.path), test.isDir); ignored != test.ignored {
+			t.Fatalf("Unexpected result for %q: %v != %v", test.path, ignored, test.ignored)
+		}
+	}
+
+	// Verify that changes to ignore files are only seen after a reset
+	if err := ioutil.WriteFile(path.Join(root, IgnoreFile), []byte("A/\n"), 0644); err != nil {
+		t.Fatalf("Could not write ignore file: %s", err.Error())
+	}
+	if i.Ignored(root, path.Join(root, "Artist/A/song.mp3"), false) {
+		t.Fatalf("Ignore file was read before reset")
+	}
+
+	i.Reset()
+	if !i.Ignored(root, path.Join(root, "Artist/A/song.mp3"), false) {
+		t.Fatalf("Ignore file was not read after reset")
+	}
+}
@@ -0,0 +1,135 @@
+package common
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// ScanMedia is the type of a scan which adds new and modified media
+	ScanMedia = "media"
+	// ScanOrphan is the type of a scan which removes missing media
+	ScanOrphan = "orphan"
+	// ScanMove is the type of a scan which updates the paths of moved media
+	ScanMove = "move"
+)
+
+// ScanStatus represents the status of the current, or most recent, filesystem scan.  The last
+// error is kept until another scan fails, so that it may be reported after a scan completes.
+type ScanStatus struct {
+	Running   bool   `json:"running"`
+	Type      string `json:"type"`
+	Library   string `json:"library"`
+	Folder    string `json:"folder"`
+	Seen      int64  `json:"seen"`
+	Added     int64  `json:"added"`
+	Updated   int64  `json:"updated"`
+	Removed   int64  `json:"removed"`
+	Started   int64  `json:"started"`
+	Finished  int64  `json:"finished"`
+	LastError string `json:"lastError"`
+}
+
+// ScanRequest represents a request for a filesystem scan of a library.  If a folder is set,
+// only that folder is scanned, rather than the entire library.
+type ScanRequest struct {
+	Type      string
+	LibraryID int
+	Folder    string
+}
+
+// ScanRequests is the queue of scans requested by clients, which is consumed by the
+// filesystem manager
+var ScanRequests = make(chan ScanRequest, 10)
+
+// queueMutex guards ScanRequests while requests are queued, so that several requests are
+// queued together, or not at all
+var queueMutex sync.Mutex
+
+// scanStatus is the status of the current or most recent scan, guarded by scanMutex
+var (
+	scanStatus ScanStatus
+	scanMutex  sync.RWMutex
+)
+
+// CurrentScan returns the status of the current, or most recent, filesystem scan
+func CurrentScan() ScanStatus {
+	scanMutex.RLock()
+	defer scanMutex.RUnlock()
+
+	return scanStatus
+}
+
+// QueueScan attempts to queue one or more scan requests, and returns false if the queue does
+// not have room for all of them, in which case none are queued
+func QueueScan(requests ...ScanRequest) bool {
+	queueMutex.Lock()
+	defer queueMutex.Unlock()
+
+	// Requests are only removed from the queue by its consumer, so room checked while holding
+	// the lock cannot be taken by another caller
+	if cap(ScanRequests)-len(ScanRequests) < len(requests) {
+		return false
+	}
+
+	for _, r := range requests {
+		ScanRequests <- r
+	}
+
+	return true
+}
+
+// StartScan resets the scan status for a new scan of the specified type, library, and folder
+func StartScan(scanType string, library string, folder string) {
+	scanMutex.Lock()
+	defer scanMutex.Unlock()
+
+	scanStatus = ScanStatus{
+		Running:   true,
+		Type:      scanType,
+		Library:   library,
+		Folder:    folder,
+		Started:   time.Now().Unix(),
+		LastError: scanStatus.LastError,
+	}
+}
+
+// FinishScan marks the current scan as complete, storing its error if one occurred
+func FinishScan(err error) {
+	scanMutex.Lock()
+	defer scanMutex.Unlock()
+
+	scanStatus.Running = false
+	scanStatus.Finished = time.Now().Unix()
+	if err != nil {
+		scanStatus.LastError = err.Error()
+	}
+}
+
+// AddScanSeen adds to the number of files seen by the current scan
+func AddScanSeen(n int) {
+	scanMutex.Lock()
+	scanStatus.Seen += int64(n)
+	scanMutex.Unlock()
+}
+
+// AddScanAdded adds to the number of items added by the current scan
+func AddScanAdded(n int) {
+	scanMutex.Lock()
+	scanStatus.Added += int64(n)
+	scanMutex.Unlock()
+}
+
+// AddScanUpdated adds to the number of items updated by the current scan
+func AddScanUpdated(n int) {
+	scanMutex.Lock()
+	scanStatus.Updated += int64(n)
+	scanMutex.Unlock()
+}
+
+// AddScanRemoved adds to the number of items removed by the current scan
+func AddScanRemoved(n int) {
+	scanMutex.Lock()
+	scanStatus.Removed += int64(n)
+	scanMutex.Unlock()
+}
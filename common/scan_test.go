@@ -0,0 +1,58 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestScanStatus verifies that scan progress is reported, and that the last error is kept
+// across scans
+func TestScanStatus(t *testing.T) {
+	// Fail a scan
+	StartScan(ScanOrphan, "test", "/test")
+	AddScanRemoved(2)
+	FinishScan(errors.New("scan failed"))
+
+	// Start a new scan, and report progress
+	StartScan(ScanMedia, "test", "/test/foo")
+	AddScanSeen(3)
+	AddScanAdded(2)
+	AddScanUpdated(1)
+
+	scan := CurrentScan()
+	if !scan.Running || scan.Type != ScanMedia || scan.Folder != "/test/foo" || scan.Started == 0 {
+		t.Fatalf("Unexpected running scan: %v", scan)
+	}
+	if scan.Seen != 3 || scan.Added != 2 || scan.Updated != 1 || scan.Removed != 0 {
+		t.Fatalf("Unexpected scan progress: %v", scan)
+	}
+	if scan.LastError != "scan failed" {
+		t.Fatalf("Unexpected last error: %q", scan.LastError)
+	}
+
+	// Complete the scan
+	FinishScan(nil)
+	if scan := CurrentScan(); scan.Running || scan.Finished == 0 || scan.LastError != "scan failed" {
+		t.Fatalf("Unexpected finished scan: %v", scan)
+	}
+
+	// Verify requests are queued until the queue is full, and that several requests are
+	// only queued if there is room for all of them
+	for i := 0; i < cap(ScanRequests)-1; i++ {
+		if !QueueScan(ScanRequest{Type: ScanMedia, LibraryID: 1}) {
+			t.Fatalf("Could not queue scan request %d", i)
+		}
+	}
+	if QueueScan(ScanRequest{Type: ScanMedia, LibraryID: 1}, ScanRequest{Type: ScanMedia, LibraryID: 2}) {
+		t.Fatal("Queued scan requests beyond queue capacity")
+	}
+	if len(ScanRequests) != cap(ScanRequests)-1 {
+		t.Fatalf("Unexpected partially queued scan requests: %d", len(ScanRequests))
+	}
+	if !QueueScan(ScanRequest{Type: ScanMedia, LibraryID: 1}) {
+		t.Fatal("Could not queue final scan request")
+	}
+	if QueueScan(ScanRequest{Type: ScanMedia, LibraryID: 1}) {
+		t.Fatal("Queued scan request on full queue")
+	}
+}
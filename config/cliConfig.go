@@ -2,6 +2,9 @@ package config
 
 import (
 	"flag"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 var (
@@ -9,27 +12,186 @@ var (
 	hostFlag = flag.String("host", ":8080", "The host which wavepipe will bind to.")
 	// mediaFlag is a flag which defines the media folder wavepipe will scan
 	mediaFlag = flag.String("media", "", "The media folder which wavepipe will scan and watch.")
+	// libraryFlag is a flag which defines named media libraries wavepipe will scan, and may be
+	// specified more than once
+	libraryFlag = make(libraryFlagMap)
+	// profileFlag is a flag which defines named transcoding profiles, and may be specified more
+	// than once
+	profileFlag = make(profileFlagMap)
+	// artStoreFlag is a flag which defines the folder where art embedded in media files is stored
+	artStoreFlag = flag.String("artstore", "~/.config/wavepipe/art", "The folder where wavepipe will store art embedded in media files.")
+	// artPolicyFlag is a flag which defines whether art files or embedded art take priority
+	artPolicyFlag = flag.String("artpolicy", ArtPolicyFolder, "Which art is preferred for songs, with the other used as a fallback ('folder' or 'embedded').")
+	// artPatternsFlag is a flag which defines the file name patterns used to choose the cover among
+	// several art files in a folder, in priority order
+	artPatternsFlag = flag.String("artpatterns", strings.Join(DefaultArtPatterns, ","), "Comma-separated file name patterns which choose the cover among art files in a folder, in priority order.")
+	// excludeFlag is a flag which defines gitignore-style patterns for paths the media scanner
+	// will ignore in all libraries
+	excludeFlag = flag.String("exclude", "", "Comma-separated gitignore-style patterns for paths which wavepipe will ignore in all libraries.")
+	// playThresholdFlag is a flag which defines the fraction of a song which must be streamed
+	// before a play is recorded
+	playThresholdFlag = flag.Float64("playthreshold", 0.5, "The fraction of a song which must be streamed to record a play (0 disables).")
+	// transcodeCacheFlag is a flag which defines the folder where transcoded files are cached
+	transcodeCacheFlag = flag.String("transcodecache", "~/.config/wavepipe/transcode", "The folder where wavepipe will cache transcoded files.")
+	// transcodeCacheSizeFlag is a flag which defines the maximum size of the transcode cache
+	transcodeCacheSizeFlag = flag.Int64("transcodecachesize", 1024, "The maximum size of the transcode cache, in megabytes (0 disables).")
+	// transcodeLimitFlag is a flag which defines the maximum number of ffmpeg processes which
+	// may run at once
+	transcodeLimitFlag = flag.Int("transcodelimit", 2, "The maximum number of ffmpeg processes which may run at once (0 is unlimited).")
+	// transcodeQueueFlag is a flag which defines the number of transcodes which may wait for an
+	// ffmpeg process
+	transcodeQueueFlag = flag.Int("transcodequeue", 8, "The maximum number of transcodes which may wait for an ffmpeg process.")
 	// sqliteFlag is a flag which defines the location of the wavepipe sqlite database
 	sqliteFlag = flag.String("sqlite", "~/.config/wavepipe/wavepipe.db", "The sqlite database which wavepipe will use.")
+	// postgresFlag is a flag which defines the connection string of a wavepipe postgres database
+	postgresFlag = flag.String("postgres", "", "The postgres connection string which wavepipe will use, instead of sqlite.")
+	// memoryFlag is a flag which enables an ephemeral, in-memory wavepipe database
+	memoryFlag = flag.Bool("memory", false, "Use an ephemeral, in-memory database, instead of sqlite.")
 )
 
+func init() {
+	flag.Var(libraryFlag, "library", "A named media library which wavepipe will scan and watch, as 'name=folder' (may be repeated).")
+	flag.Var(profileFlag, "profile", "A named transcoding profile, as 'name=codec=MP3;quality=128;samplerate=22050;channels=1;filters=...;container=...' (may be repeated).")
+}
+
+// libraryFlagMap is a flag.Value which maps media library names to their folders
+type libraryFlagMap map[string]string
+
+// String returns all media libraries in 'name=folder' format
+func (l libraryFlagMap) String() string {
+	pairs := make([]string, 0, len(l))
+	for name, folder := range l {
+		pairs = append(pairs, name+"="+folder)
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Set adds a media library from a 'name=folder' pair
+func (l libraryFlagMap) Set(value string) error {
+	pair := strings.SplitN(value, "=", 2)
+	if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+		return fmt.Errorf("invalid library, expected 'name=folder': %s", value)
+	}
+
+	l[pair[0]] = pair[1]
+	return nil
+}
+
+// profileFlagMap is a flag.Value which maps transcoding profile names to their settings
+type profileFlagMap map[string]string
+
+// String returns all transcoding profiles in 'name=settings' format
+func (p profileFlagMap) String() string {
+	pairs := make([]string, 0, len(p))
+	for name, settings := range p {
+		pairs = append(pairs, name+"="+settings)
+	}
+
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// Set adds a transcoding profile from a 'name=settings' pair
+func (p profileFlagMap) Set(value string) error {
+	pair := strings.SplitN(value, "=", 2)
+	if len(pair) != 2 || pair[0] == "" || pair[1] == "" {
+		return fmt.Errorf("invalid profile, expected 'name=settings': %s", value)
+	}
+
+	p[pair[0]] = pair[1]
+	return nil
+}
+
 // CLIConfig represents configuration from command-line flags
 type CLIConfig struct{}
 
 // Help returns a string containing help information about command-line flags
 func (CLIConfig) Help() string {
-	return "use the '-media' flag to specify a folder"
+	return "use the '-media' flag to specify a folder, or '-library name=folder' flags to specify libraries"
 }
 
 // Load returns the configuration from command-line flags
 func (c *CLIConfig) Load() (*Config, error) {
 	flag.Parse()
 
-	return &Config{
-		Host:        *hostFlag,
-		MediaFolder: *mediaFlag,
-		Sqlite: &SqliteConfig{
-			File: *sqliteFlag,
-		},
-	}, nil
+	conf := &Config{
+		Host:               *hostFlag,
+		MediaFolder:        *mediaFlag,
+		Libraries:          make([]Library, 0, len(libraryFlag)),
+		ArtStore:           *artStoreFlag,
+		ArtPolicy:          *artPolicyFlag,
+		ArtPatterns:        make([]string, 0),
+		Excludes:           make([]string, 0),
+		Profiles:           make([]Profile, 0, len(profileFlag)),
+		PlayThreshold:      *playThresholdFlag,
+		TranscodeCache:     *transcodeCacheFlag,
+		TranscodeCacheSize: *transcodeCacheSizeFlag,
+		TranscodeLimit:     *transcodeLimitFlag,
+		TranscodeQueue:     *transcodeQueueFlag,
+	}
+
+	// Add art patterns, in priority order
+	for _, p := range strings.Split(*artPatternsFlag, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			conf.ArtPatterns = append(conf.ArtPatterns, p)
+		}
+	}
+
+	// Add global exclude patterns
+	for _, e := range strings.Split(*excludeFlag, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			conf.Excludes = append(conf.Excludes, e)
+		}
+	}
+
+	// Add named media libraries, ordered by name
+	names := make([]string, 0, len(libraryFlag))
+	for name := range libraryFlag {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		conf.Libraries = append(conf.Libraries, Library{
+			Name:   name,
+			Folder: libraryFlag[name],
+		})
+	}
+
+	// Add transcoding profiles, ordered by name
+	names = make([]string, 0, len(profileFlag))
+	for name := range profileFlag {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		p, err := ParseProfile(name, profileFlag[name])
+		if err != nil {
+			return nil, err
+		}
+
+		conf.Profiles = append(conf.Profiles, p)
+	}
+
+	// If an in-memory database is requested, use it instead of sqlite
+	if *memoryFlag {
+		conf.Memory = &MemoryConfig{}
+		return conf, nil
+	}
+
+	// If a postgres connection string is specified, use it instead of sqlite
+	if *postgresFlag != "" {
+		conf.Postgres = &PostgresConfig{
+			ConnString: *postgresFlag,
+		}
+		return conf, nil
+	}
+
+	conf.Sqlite = &SqliteConfig{
+		File: *sqliteFlag,
+	}
+	return conf, nil
 }
@@ -1,27 +1,234 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"path"
+	"strconv"
+	"strings"
 
 	"github.com/mdlayher/wavepipe/common"
 )
 
+var (
+	// ErrNoLibraries is returned when no media folder or libraries are set in config
+	ErrNoLibraries = errors.New("config: no media folder or libraries set")
+	// ErrInvalidArtPolicy is returned when the art policy is not a known policy
+	ErrInvalidArtPolicy = errors.New("config: art policy must be 'folder' or 'embedded'")
+)
+
+const (
+	// ArtPolicyFolder prefers art files in a song's folder, and falls back to art embedded in
+	// the song's tags
+	ArtPolicyFolder = "folder"
+	// ArtPolicyEmbedded prefers art embedded in a song's tags, and falls back to art files in
+	// the song's folder
+	ArtPolicyEmbedded = "embedded"
+)
+
+// profileNameChars are the characters which may be used in the name of a transcoding profile,
+// which is also used in the names of cached transcodes
+const profileNameChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+
+// DefaultArtPatterns are the file name patterns used to choose the cover among several art files
+// in a folder, in priority order, when none are set in config
+var DefaultArtPatterns = []string{"cover.*", "folder.*", "front.*"}
+
 // C is the active configuration instance
 var C ConfigSource
 
 // Config represents the program configuration options
 type Config struct {
-	Host        string        `json:"host"`
-	MediaFolder string        `json:"mediaFolder"`
-	Sqlite      *SqliteConfig `json:"sqlite"`
+	Host               string          `json:"host"`
+	MediaFolder        string          `json:"mediaFolder"`
+	Libraries          []Library       `json:"libraries"`
+	ArtStore           string          `json:"artStore"`
+	ArtPolicy          string          `json:"artPolicy"`
+	ArtPatterns        []string        `json:"artPatterns"`
+	Excludes           []string        `json:"excludes"`
+	PlayThreshold      float64         `json:"playThreshold"`
+	TranscodeCache     string          `json:"transcodeCache"`
+	TranscodeCacheSize int64           `json:"transcodeCacheSize"`
+	TranscodeLimit     int             `json:"transcodeLimit"`
+	TranscodeQueue     int             `json:"transcodeQueue"`
+	Profiles           []Profile       `json:"profiles"`
+	Sqlite             *SqliteConfig   `json:"sqlite"`
+	Postgres           *PostgresConfig `json:"postgres"`
+	Memory             *MemoryConfig   `json:"memory"`
 }
 
-// Media returns the media folder from config, but with special
-// characters such as '~' replaced, and any trailing slashes trimmed.
-func (c Config) Media() string {
+// Library represents configuration for a named media library, stored in its own folder
+type Library struct {
+	Name   string `json:"name"`
+	Folder string `json:"folder"`
+}
+
+// Path returns the library's folder, but with special characters such
+// as '~' replaced, and any trailing slashes trimmed.
+func (l Library) Path() string {
 	// Return path with strings replaced, trailing slash removed,
 	// tilde replaced with current user's home directory
-	return path.Clean(common.ExpandHomeDir(c.MediaFolder))
+	return path.Clean(common.ExpandHomeDir(l.Folder))
+}
+
+// MediaLibraries returns all media libraries from config.  A single media folder may also be
+// set, in which case it is named after its base folder.  Libraries must have unique names,
+// and their folders may not overlap.
+func (c Config) MediaLibraries() ([]Library, error) {
+	libraries := make([]Library, 0, len(c.Libraries)+1)
+	if c.MediaFolder != "" {
+		libraries = append(libraries, Library{
+			Name:   path.Base(Library{Folder: c.MediaFolder}.Path()),
+			Folder: c.MediaFolder,
+		})
+	}
+	libraries = append(libraries, c.Libraries...)
+
+	if len(libraries) == 0 {
+		return nil, ErrNoLibraries
+	}
+
+	// Check for missing or duplicate names, and overlapping folders
+	for i, l := range libraries {
+		if l.Name == "" || l.Folder == "" {
+			return nil, fmt.Errorf("config: library %d must have a name and folder", i)
+		}
+
+		for _, l2 := range libraries[:i] {
+			if l.Name == l2.Name {
+				return nil, fmt.Errorf("config: duplicate library name: %s", l.Name)
+			}
+
+			if within(l.Path(), l2.Path()) || within(l2.Path(), l.Path()) {
+				return nil, fmt.Errorf("config: library folders overlap: %s, %s", l2.Path(), l.Path())
+			}
+		}
+	}
+
+	return libraries, nil
+}
+
+// ArtStorePath returns the folder in which embedded art is stored, with special characters such
+// as '~' replaced
+func (c Config) ArtStorePath() string {
+	return path.Clean(common.ExpandHomeDir(c.ArtStore))
+}
+
+// TranscodeCachePath returns the folder in which transcoded files are cached, with special
+// characters such as '~' replaced
+func (c Config) TranscodeCachePath() string {
+	return path.Clean(common.ExpandHomeDir(c.TranscodeCache))
+}
+
+// TranscodeCacheBytes returns the maximum size of the transcode cache in bytes, which is
+// configured in megabytes.  A size of zero, or no folder, disables the cache.
+func (c Config) TranscodeCacheBytes() int64 {
+	if c.TranscodeCache == "" || c.TranscodeCacheSize <= 0 {
+		return 0
+	}
+
+	return c.TranscodeCacheSize * 1024 * 1024
+}
+
+// EmbeddedArtFirst determines if art embedded in songs takes priority over art files, returning
+// an error if the art policy is invalid
+func (c Config) EmbeddedArtFirst() (bool, error) {
+	switch c.ArtPolicy {
+	case "", ArtPolicyFolder:
+		return false, nil
+	case ArtPolicyEmbedded:
+		return true, nil
+	}
+
+	return false, ErrInvalidArtPolicy
+}
+
+// CoverArtPatterns returns the file name patterns used to choose the cover among several art
+// files in a folder, in priority order, falling back to the defaults if none are set
+func (c Config) CoverArtPatterns() []string {
+	if len(c.ArtPatterns) == 0 {
+		return DefaultArtPatterns
+	}
+
+	return c.ArtPatterns
+}
+
+// within determines if the first path is the second path, or resides beneath it
+func within(p string, root string) bool {
+	return p == root || strings.HasPrefix(p, strings.TrimSuffix(root, "/")+"/")
+}
+
+// Profile represents configuration for a named transcoding profile.  Settings which are not set
+// use the codec's defaults.
+type Profile struct {
+	Name       string `json:"name"`
+	Codec      string `json:"codec"`
+	Quality    string `json:"quality"`
+	SampleRate int    `json:"sampleRate"`
+	Channels   int    `json:"channels"`
+	Filters    string `json:"filters"`
+	Container  string `json:"container"`
+}
+
+// ParseProfile parses a transcoding profile from its name, and a list of 'key=value' settings
+// separated by semicolons, since ffmpeg filters may contain commas
+func ParseProfile(name string, settings string) (Profile, error) {
+	p := Profile{Name: name}
+	for _, s := range strings.Split(settings, ";") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+
+		pair := strings.SplitN(s, "=", 2)
+		if len(pair) != 2 {
+			return p, fmt.Errorf("config: invalid setting for profile %s, expected 'key=value': %s", name, s)
+		}
+
+		var err error
+		switch key, value := strings.ToLower(pair[0]), pair[1]; key {
+		case "codec":
+			p.Codec = value
+		case "quality":
+			p.Quality = value
+		case "samplerate":
+			p.SampleRate, err = strconv.Atoi(value)
+		case "channels":
+			p.Channels, err = strconv.Atoi(value)
+		case "filters":
+			p.Filters = value
+		case "container":
+			p.Container = value
+		default:
+			return p, fmt.Errorf("config: unknown setting for profile %s: %s", name, key)
+		}
+		if err != nil {
+			return p, fmt.Errorf("config: invalid integer setting for profile %s: %s", name, s)
+		}
+	}
+
+	return p, nil
+}
+
+// TranscodeProfiles returns all transcoding profiles from config.  Profiles must have unique
+// names, which are limited to letters, numbers, dashes, and underscores, and must set a codec.
+func (c Config) TranscodeProfiles() ([]Profile, error) {
+	for i, p := range c.Profiles {
+		if p.Name == "" || strings.Trim(p.Name, profileNameChars) != "" {
+			return nil, fmt.Errorf("config: invalid profile name: %q", p.Name)
+		}
+
+		if p.Codec == "" {
+			return nil, fmt.Errorf("config: profile %s must have a codec", p.Name)
+		}
+
+		for _, p2 := range c.Profiles[:i] {
+			if p.Name == p2.Name {
+				return nil, fmt.Errorf("config: duplicate profile name: %s", p.Name)
+			}
+		}
+	}
+
+	return c.Profiles, nil
 }
 
 // SqliteConfig represents configuration for an sqlite backend
@@ -29,6 +236,14 @@ type SqliteConfig struct {
 	File string `json:"file"`
 }
 
+// PostgresConfig represents configuration for a postgres backend
+type PostgresConfig struct {
+	ConnString string `json:"connString"`
+}
+
+// MemoryConfig represents configuration for an ephemeral, in-memory backend
+type MemoryConfig struct{}
+
 // ConfigSource represents the configuration source for the program
 type ConfigSource interface {
 	Help() string
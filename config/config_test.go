@@ -0,0 +1,85 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseProfile verifies that transcoding profiles are parsed from their settings, and
+// that invalid settings are rejected
+func TestParseProfile(t *testing.T) {
+	tests := []struct {
+		settings string
+		profile  Profile
+		ok       bool
+	}{
+		// Empty settings, which are rejected later for lacking a codec
+		{"", Profile{Name: "test"}, true},
+		// All settings, in any case, with filters which contain commas and equals signs
+		{
+			"codec=OPUS;quality=128;SampleRate=48000;channels=2;filters=volume=0.5,highpass=f=200;container=ogg",
+			Profile{"test", "OPUS", "128", 48000, 2, "volume=0.5,highpass=f=200", "ogg"},
+			true,
+		},
+		// Whitespace and empty settings are ignored
+		{" codec=MP3 ; ; quality=V0 ;", Profile{Name: "test", Codec: "MP3", Quality: "V0"}, true},
+		// Invalid settings
+		{"codec", Profile{}, false},
+		{"bitrate=128", Profile{}, false},
+		{"samplerate=fast", Profile{}, false},
+		{"codec=MP3;channels=", Profile{}, false},
+	}
+
+	for _, test := range tests {
+		p, err := ParseProfile("test", test.settings)
+		if !test.ok {
+			if err == nil {
+				t.Fatalf("No error for invalid settings %q", test.settings)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Could not parse settings %q: %s", test.settings, err.Error())
+		}
+
+		if !reflect.DeepEqual(p, test.profile) {
+			t.Fatalf("Unexpected profile for %q: %v != %v", test.settings, p, test.profile)
+		}
+	}
+}
+
+// TestTranscodeProfiles verifies that transcoding profiles must have valid, unique names, and
+// must set a codec
+func TestTranscodeProfiles(t *testing.T) {
+	tests := []struct {
+		profiles []Profile
+		ok       bool
+	}{
+		{nil, true},
+		{[]Profile{{Name: "mobile-low_1", Codec: "MP3"}, {Name: "car", Codec: "AAC"}}, true},
+		{[]Profile{{Name: "", Codec: "MP3"}}, false},
+		{[]Profile{{Name: "mobile low", Codec: "MP3"}}, false},
+		{[]Profile{{Name: "mobile/low", Codec: "MP3"}}, false},
+		{[]Profile{{Name: "mobile"}}, false},
+		{[]Profile{{Name: "mobile", Codec: "MP3"}, {Name: "mobile", Codec: "AAC"}}, false},
+	}
+
+	for i, test := range tests {
+		profiles, err := Config{Profiles: test.profiles}.TranscodeProfiles()
+		if !test.ok {
+			if err == nil {
+				t.Fatalf("No error for invalid profiles %d: %v", i, test.profiles)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error for profiles %d: %s", i, err.Error())
+		}
+
+		if len(profiles) != len(test.profiles) {
+			t.Fatalf("Unexpected profiles %d: %v", i, profiles)
+		}
+	}
+}
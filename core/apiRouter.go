@@ -262,6 +262,11 @@ func newRouter() *mux.Router {
 	ar.HandleFunc("/folders", api.GetFolders).Methods("GET")
 	ar.HandleFunc("/folders/{id}", api.GetFolders).Methods("GET")
 
+	// History API
+	ar.HandleFunc("/history", api.GetHistory).Methods("GET")
+	ar.HandleFunc("/history", api.PostHistory).Methods("POST")
+	ar.HandleFunc("/history/{id}", api.PostHistory).Methods("POST")
+
 	// LastFM API
 	ar.HandleFunc("/lastfm", api.PostLastFM).Methods("POST")
 	ar.HandleFunc("/lastfm/{action}", api.PostLastFM).Methods("POST")
@@ -273,14 +278,44 @@ func newRouter() *mux.Router {
 	// Logout API
 	ar.HandleFunc("/logout", api.PostLogout).Methods("POST")
 
+	// Playlists API
+	ar.HandleFunc("/playlists", api.GetPlaylists).Methods("GET")
+	ar.HandleFunc("/playlists/{id}", api.GetPlaylists).Methods("GET")
+	ar.HandleFunc("/playlists", api.PostPlaylists).Methods("POST")
+	ar.HandleFunc("/playlists/{id}", api.PutPlaylists).Methods("PUT", "PATCH")
+	ar.HandleFunc("/playlists/{id}", api.DeletePlaylists).Methods("DELETE")
+
+	// Ratings API
+	ar.HandleFunc("/ratings", api.GetRatings).Methods("GET")
+	ar.HandleFunc("/ratings/{type}", api.GetRatings).Methods("GET")
+	ar.HandleFunc("/ratings/{type}/{id}", api.PostRatings).Methods("POST")
+	ar.HandleFunc("/ratings/{type}/{id}", api.DeleteRatings).Methods("DELETE")
+
+	// Scan API
+	ar.HandleFunc("/scan", api.GetScan).Methods("GET")
+	ar.HandleFunc("/scan", api.PostScan).Methods("POST")
+
 	// Search API
 	ar.HandleFunc("/search", api.GetSearch).Methods("GET")
 	ar.HandleFunc("/search/{query}", api.GetSearch).Methods("GET")
 
+	// Smart Playlists API
+	ar.HandleFunc("/smartplaylists", api.GetSmartPlaylists).Methods("GET")
+	ar.HandleFunc("/smartplaylists/{id}", api.GetSmartPlaylists).Methods("GET")
+	ar.HandleFunc("/smartplaylists", api.PostSmartPlaylists).Methods("POST")
+	ar.HandleFunc("/smartplaylists/{id}", api.PutSmartPlaylists).Methods("PUT", "PATCH")
+	ar.HandleFunc("/smartplaylists/{id}", api.DeleteSmartPlaylists).Methods("DELETE")
+
 	// Songs API
 	ar.HandleFunc("/songs", api.GetSongs).Methods("GET")
 	ar.HandleFunc("/songs/{id}", api.GetSongs).Methods("GET")
 
+	// Stars API
+	ar.HandleFunc("/stars", api.GetStars).Methods("GET")
+	ar.HandleFunc("/stars/{type}", api.GetStars).Methods("GET")
+	ar.HandleFunc("/stars/{type}/{id}", api.PostStars).Methods("POST")
+	ar.HandleFunc("/stars/{type}/{id}", api.DeleteStars).Methods("DELETE")
+
 	// Status API
 	ar.HandleFunc("/status", api.GetStatus).Methods("GET")
 
@@ -330,20 +365,41 @@ func newRouter() *mux.Router {
 	// GetMusicFolders - used to retrieve list of known music folders
 	sr.HandleFunc("/getMusicFolders.view", subsonic.GetMusicFolders)
 
+	// GetPlaylist - used to retrieve one playlist and its songs
+	// (smart playlists only, which are read-only)
+	sr.HandleFunc("/getPlaylist.view", subsonic.GetPlaylist)
+
 	// GetPlaylists - used to retrieve playlists from the server
-	// (not currently implemented by wavepipe)
+	// (smart playlists only, which are read-only)
 	sr.HandleFunc("/getPlaylists.view", subsonic.GetPlaylists)
 
 	// GetRandomSongs - used to retrieve a number of random songs
 	sr.HandleFunc("/getRandomSongs.view", subsonic.GetRandomSongs)
 
 	// GetStarred - used to retrieve a list of favorite items
-	// (not currently implemented by wavepipe)
 	sr.HandleFunc("/getStarred.view", subsonic.GetStarred)
 
+	// GetStarred2 - used to retrieve a list of favorite items, organized by tags
+	sr.HandleFunc("/getStarred2.view", subsonic.GetStarred2)
+
+	// GetScanStatus - used to retrieve the status of the current media scan
+	sr.HandleFunc("/getScanStatus.view", subsonic.GetScanStatus)
+
+	// SetRating - used to rate an item, or remove its rating
+	sr.HandleFunc("/setRating.view", subsonic.SetRating)
+
+	// Star - used to add items to the list of favorite items
+	sr.HandleFunc("/star.view", subsonic.Star)
+
+	// StartScan - used to start a media scan of all libraries
+	sr.HandleFunc("/startScan.view", subsonic.StartScan)
+
 	// Stream - used to return a binary file stream
 	sr.HandleFunc("/stream.view", subsonic.Stream)
 
+	// Unstar - used to remove items from the list of favorite items
+	sr.HandleFunc("/unstar.view", subsonic.Unstar)
+
 	// On debug mode, enable pprof debug endpoints
 	// Thanks: https://github.com/go-martini/martini/issues/228
 	if env.IsDebug() {
@@ -24,17 +24,54 @@ var n = negroni.New()
 var r = render.New(render.Options{})
 
 func init() {
-	// Set up database connection
-	data.DB = new(data.SqliteBackend)
-	data.DB.DSN("~/.config/wavepipe/wavepipe.db")
+	// Set up in-memory database connection, so tests do not depend on local state
+	data.DB = new(data.MemoryBackend)
 	if err := data.DB.Open(); err != nil {
 		os.Exit(1)
 	}
 
+	// Add items with ID 1, which are requested by tests
+	if err := apiFixtures(); err != nil {
+		os.Exit(1)
+	}
+
 	// Set up Negroni with API routes
 	n.UseHandler(newRouter())
 }
 
+// apiFixtures saves an artist, album, folder, song, and user to the database, so that
+// requests for valid items may be tested
+func apiFixtures() error {
+	artist := &data.Artist{Title: "TestArtist"}
+	if err := artist.Save(); err != nil {
+		return err
+	}
+
+	album := &data.Album{ArtistID: artist.ID, Title: "TestAlbum"}
+	if err := album.Save(); err != nil {
+		return err
+	}
+
+	folder := &data.Folder{Title: "TestFolder", Path: "/mem/TestFolder"}
+	if err := folder.Save(); err != nil {
+		return err
+	}
+
+	song := &data.Song{
+		AlbumID:  album.ID,
+		ArtistID: artist.ID,
+		FileName: "/mem/TestFolder/test.mp3",
+		FolderID: folder.ID,
+		Title:    "TestSong",
+	}
+	if err := song.Save(); err != nil {
+		return err
+	}
+
+	_, err := data.NewUser("test", "test", data.RoleAdmin)
+	return err
+}
+
 // TestAPIRouter verifies that all API request processing functionality is working properly
 func TestAPIRouter(t *testing.T) {
 	// Table of tests to run, and their expected HTTP status results
@@ -107,6 +144,24 @@ func TestAPIRouter(t *testing.T) {
 		//   - folder ID not found
 		{404, "GET", "/api/v0/folders/99999999"},
 
+		// History API
+		//   - valid request
+		{200, "GET", "/api/v0/history"},
+		//   - valid limit items request
+		{200, "GET", "/api/v0/history?limit=0,10"},
+		//   - valid play of 1 item
+		{200, "POST", "/api/v0/history/1"},
+		//   - invalid API version
+		{400, "GET", "/api/v999/history"},
+		//   - invalid integer pair for limit
+		{400, "GET", "/api/v0/history?limit=foo,bar"},
+		//   - no integer song ID provided
+		{400, "POST", "/api/v0/history"},
+		//   - invalid integer song ID
+		{400, "POST", "/api/v0/history/foo"},
+		//   - song ID not found
+		{404, "POST", "/api/v0/history/99999999"},
+
 		// LastFM API - skip valid requests, due to need for external service
 		//   - invalid API version
 		{400, "POST", "/api/v999/lastfm"},
@@ -124,13 +179,85 @@ func TestAPIRouter(t *testing.T) {
 
 		// Login/Logout API - skip due to need for sessions and users
 
+		// Playlists API
+		//   - valid request
+		{200, "GET", "/api/v0/playlists"},
+		//   - invalid API version
+		{400, "GET", "/api/v999/playlists"},
+		//   - invalid integer playlist ID
+		{400, "GET", "/api/v0/playlists/foo"},
+		//   - playlist ID not found
+		{404, "GET", "/api/v0/playlists/99999999"},
+		//   - no title provided
+		{400, "POST", "/api/v0/playlists"},
+		//   - invalid integer playlist ID
+		{400, "PUT", "/api/v0/playlists/foo"},
+		//   - playlist ID not found
+		{404, "PUT", "/api/v0/playlists/99999999"},
+		//   - playlist ID not found
+		{404, "DELETE", "/api/v0/playlists/99999999"},
+
+		// Ratings API
+		//   - valid request
+		{200, "GET", "/api/v0/ratings"},
+		//   - valid request for 1 item type
+		{200, "GET", "/api/v0/ratings/song"},
+		//   - valid removal of rating for 1 item
+		{200, "DELETE", "/api/v0/ratings/song/1"},
+		//   - invalid API version
+		{400, "GET", "/api/v999/ratings"},
+		//   - invalid item type
+		{400, "GET", "/api/v0/ratings/foo"},
+		//   - missing rating parameter
+		{400, "POST", "/api/v0/ratings/song/1"},
+		//   - invalid integer item ID
+		{400, "POST", "/api/v0/ratings/song/foo"},
+		//   - item ID not found
+		{404, "POST", "/api/v0/ratings/song/99999999"},
+
+		// Scan API
+		//   - valid request
+		{200, "GET", "/api/v0/scan"},
+		//   - invalid API version
+		{400, "GET", "/api/v999/scan"},
+		//   - only administrators may trigger scans
+		{403, "POST", "/api/v0/scan"},
+
 		// Search API
 		//   - valid request
 		{200, "GET", "/api/v0/search/foo"},
+		//   - valid limit and offset request
+		{200, "GET", "/api/v0/search/foo?limit=10&offset=10"},
+		//   - valid field-qualified request
+		{200, "GET", "/api/v0/search/artist:%22foo%20bar%22%20year:%3E=1998%20codec:flac"},
 		//   - invalid API version
 		{400, "GET", "/api/v999/search"},
 		//   - no search query specified
 		{400, "GET", "/api/v0/search"},
+		//   - invalid search query
+		{400, "GET", "/api/v0/search/foo:bar"},
+		//   - invalid integer for limit
+		{400, "GET", "/api/v0/search/foo?limit=foo"},
+		//   - invalid integer for offset
+		{400, "GET", "/api/v0/search/foo?offset=foo"},
+
+		// Smart Playlists API
+		//   - valid request
+		{200, "GET", "/api/v0/smartplaylists"},
+		//   - invalid API version
+		{400, "GET", "/api/v999/smartplaylists"},
+		//   - invalid integer smart playlist ID
+		{400, "GET", "/api/v0/smartplaylists/foo"},
+		//   - smart playlist ID not found
+		{404, "GET", "/api/v0/smartplaylists/99999999"},
+		//   - no title provided
+		{400, "POST", "/api/v0/smartplaylists"},
+		//   - invalid integer smart playlist ID
+		{400, "PUT", "/api/v0/smartplaylists/foo"},
+		//   - smart playlist ID not found
+		{404, "PUT", "/api/v0/smartplaylists/99999999"},
+		//   - smart playlist ID not found
+		{404, "DELETE", "/api/v0/smartplaylists/99999999"},
 
 		// Songs API
 		//   - valid request
@@ -154,6 +281,24 @@ func TestAPIRouter(t *testing.T) {
 		//   - song ID not found
 		{404, "GET", "/api/v0/songs/99999999"},
 
+		// Stars API
+		//   - valid request
+		{200, "GET", "/api/v0/stars"},
+		//   - valid request for 1 item type
+		{200, "GET", "/api/v0/stars/album"},
+		//   - valid star of 1 item
+		{200, "POST", "/api/v0/stars/song/1"},
+		//   - valid unstar of 1 item
+		{200, "DELETE", "/api/v0/stars/song/1"},
+		//   - invalid API version
+		{400, "GET", "/api/v999/stars"},
+		//   - invalid item type
+		{400, "POST", "/api/v0/stars/foo/1"},
+		//   - invalid integer item ID
+		{400, "POST", "/api/v0/stars/song/foo"},
+		//   - item ID not found
+		{404, "DELETE", "/api/v0/stars/artist/99999999"},
+
 		// Status API
 		//   - valid request
 		{200, "GET", "/api/v0/status"},
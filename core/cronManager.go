@@ -5,19 +5,13 @@ import (
 	"time"
 
 	"github.com/mdlayher/wavepipe/common"
-	"github.com/mdlayher/wavepipe/config"
+	"github.com/mdlayher/wavepipe/data"
 )
 
 // cronManager spawns and triggers events at regular intervals
 func cronManager(cronKillChan chan struct{}) {
 	log.Println("cron: starting...")
 
-	// Retrieve configuration for use with crons
-	conf, err := config.C.Load()
-	if err != nil {
-		log.Fatal("cron: could not load configuration")
-	}
-
 	// cronPrintCurrentStatus - run on startup, and every 5 minutes
 	status := time.NewTicker(5 * time.Minute)
 	go cronPrintCurrentStatus()
@@ -47,22 +41,36 @@ func cronManager(cronKillChan chan struct{}) {
 			go cronPrintCurrentStatus()
 		// Trigger media scan
 		case <-mediaScan.C:
-			// Queue a new media scan
-			m := new(fsMediaScan)
-			m.SetFolders(conf.Media(), "")
-			m.Verbose(true)
-			fsQueue <- m
+			// Queue a new media scan for each library
+			for _, l := range cronLibraries() {
+				m := new(fsMediaScan)
+				m.SetFolders(l, "")
+				m.Verbose(true)
+				fsQueue <- m
+			}
 		// Trigger orphan scan
 		case <-orphanScan.C:
-			// Queue a new orphan scan
-			o := new(fsOrphanScan)
-			o.SetFolders(conf.Media(), "")
-			o.Verbose(true)
-			fsQueue <- o
+			// Queue a new orphan scan for each library
+			for _, l := range cronLibraries() {
+				o := new(fsOrphanScan)
+				o.SetFolders(l, "")
+				o.Verbose(true)
+				fsQueue <- o
+			}
 		}
 	}
 }
 
+// cronLibraries loads all media libraries for use with scans, logging any errors
+func cronLibraries() []data.Library {
+	libraries, err := data.DB.AllLibraries()
+	if err != nil {
+		log.Println(err)
+	}
+
+	return libraries
+}
+
 // cronPrintCurrentStatus logs the regular status check banner
 func cronPrintCurrentStatus() {
 	// Regular status banner
@@ -39,14 +39,45 @@ func dbManager(conf config.Config, dbLaunchChan chan struct{}, dbKillChan chan s
 		if err := data.DB.Open(); err != nil {
 			log.Fatalf("db: could not open database: %s", err)
 		}
+	} else if conf.Postgres != nil {
+		// postgres
+		log.Println("db: postgres")
 
-		// TODO: temporary, create a test user
-		data.NewUser("test", "test", data.RoleAdmin)
+		// Set DSN
+		data.DB = new(data.PostgresBackend)
+		data.DB.DSN(conf.Postgres.ConnString)
+
+		// Set up the database
+		if err := data.DB.Setup(); err != nil {
+			log.Fatalf("db: could not set up database: %s", err.Error())
+		}
+
+		// Open the database connection
+		if err := data.DB.Open(); err != nil {
+			log.Fatalf("db: could not open database: %s", err)
+		}
+	} else if conf.Memory != nil {
+		// memory
+		log.Println("db: memory")
+
+		// Open the in-memory database, which is lost on exit
+		data.DB = new(data.MemoryBackend)
+		if err := data.DB.Open(); err != nil {
+			log.Fatalf("db: could not open database: %s", err)
+		}
 	} else {
 		// Invalid config
 		log.Fatalf("db: invalid database selected")
 	}
 
+	// Bring the database schema up to date, refusing to start on a newer schema
+	if err := data.DB.Migrate(); err != nil {
+		log.Fatalf("db: could not migrate database: %s", err)
+	}
+
+	// TODO: temporary, create a test user
+	data.NewUser("test", "test", data.RoleAdmin)
+
 	// Database set up, trigger manager that it's ready
 	close(dbLaunchChan)
 
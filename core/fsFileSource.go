@@ -9,335 +9,1107 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/mdlayher/wavepipe/common"
 	"github.com/mdlayher/wavepipe/data"
 
 	"github.com/wtolson/go-taglib"
 )
 
-// fsFileSource represents a file source which indexes files in the local filesystem
-type fsFileSource struct{}
+// fsScanWorkers is the number of goroutines which read tags from media files during a media scan.
+// Tag reading spends most of its time waiting on disk or network I/O, so more workers than CPUs
+// are used.
+var fsScanWorkers = 4 * runtime.NumCPU()
+
+// fsScanBatchSize is the maximum number of songs which are saved or updated in a single transaction
+const fsScanBatchSize = 500
+
+// fsFileSource represents a file source which indexes files in the local filesystem.  Art
+// embedded in media files is stored in the art store, and takes priority over art files if
+// embeddedArtFirst is set.  The cover among several art files in a folder is chosen using
+// artPatterns.  Paths matching excludes, or the ignore files in each folder, are not indexed.
+type fsFileSource struct {
+	artStore         string
+	artPatterns      []string
+	embeddedArtFirst bool
+	excludes         []string
+}
+
+// fsScanItem is an item found by the filesystem walk of a media scan.  For media files, it also
+// contains the CUE sheet which describes the file, if one exists, and the song read by a worker,
+// or the error which occurred while reading it.
+type fsScanItem struct {
+	path    string
+	info    os.FileInfo
+	cue     *fsCue
+	song    *data.Song
+	picture *data.Picture
+	err     error
+}
+
+// fsCue is a CUE sheet found in a media folder, and the time it was last modified
+type fsCue struct {
+	sheet    *data.CueSheet
+	modified int64
+}
+
+// fsCueSheets reads all CUE sheets in a folder.  Sheets which cannot be read are logged and
+// skipped, so the files they describe are indexed as single songs.
+func fsCueSheets(folder string) []fsCue {
+	cues := make([]fsCue, 0)
+
+	files, err := ioutil.ReadDir(folder)
+	if err != nil {
+		return cues
+	}
+
+	for _, info := range files {
+		if info.IsDir() || path.Ext(info.Name()) != cueExt {
+			continue
+		}
+
+		file, err := os.Open(path.Join(folder, info.Name()))
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		sheet, err := data.ParseCueSheet(file)
+		file.Close()
+		if err != nil {
+			log.Printf("fs: %s: %s", path.Join(folder, info.Name()), err.Error())
+			continue
+		}
+
+		cues = append(cues, fsCue{sheet: sheet, modified: info.ModTime().Unix()})
+	}
+
+	return cues
+}
+
+// fsCueForFile returns the first CUE sheet which describes the media file at the input path
+func fsCueForFile(cues []fsCue, p string) (*fsCue, bool) {
+	for i := range cues {
+		if _, ok := cues[i].sheet.File(path.Base(p)); ok {
+			return &cues[i], true
+		}
+	}
 
-// folderArtPair contains a folder ID and associated art ID
-type folderArtPair struct {
-	folderID int
-	artID    int
+	return nil, false
 }
 
-// MediaScan scans for media files in the local filesystem
-func (fsFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan chan struct{}) (int, error) {
+// MediaScan scans for media files in the local filesystem, within the specified library.
+// If a media folder is set, only that item is scanned, rather than the entire library.
+// Media files whose size and modification time are unchanged since the last scan are skipped
+// without being opened.  Media files described by a CUE sheet are indexed as a song for each
+// track, and are also scanned when their sheet changes.
+// The filesystem walk feeds a pool of workers which read tags from media files, and a single
+// writer indexes their results, so that only one goroutine modifies the database and its caches.
+func (f fsFileSource) MediaScan(library data.Library, mediaFolder string, verbose bool, walkCancelChan chan struct{}) (int, error) {
+	// If no media folder is set, scan the entire library
+	if mediaFolder == "" {
+		mediaFolder = library.Path
+	}
+
 	// Halt walk if needed
 	var mutex sync.RWMutex
 	haltWalk := false
+	halt := func() {
+		mutex.Lock()
+		haltWalk = true
+		mutex.Unlock()
+	}
+	halted := func() bool {
+		mutex.RLock()
+		defer mutex.RUnlock()
+		return haltWalk
+	}
 	go func() {
 		// Wait for signal
 		<-walkCancelChan
 
 		// Halt!
-		mutex.Lock()
-		haltWalk = true
-		mutex.Unlock()
+		halt()
 	}()
 
 	// Track metrics about the walk
-	artCount := 0
-	artistCount := 0
-	albumCount := 0
-	songCount := 0
-	songUpdateCount := 0
-	folderCount := 0
 	startTime := time.Now()
 
-	// Track all folder IDs containing new art, and hold their art IDs
-	artFiles := make([]folderArtPair, 0)
-
-	// Cache entries which have been seen previously, to reduce database load
-	folderCache := map[string]*data.Folder{}
-	artistCache := map[string]*data.Artist{}
-	albumCache := map[string]*data.Album{}
-
 	if verbose {
-		log.Println("fs: beginning media scan:", mediaFolder)
+		log.Printf("fs: beginning media scan: %s [%s]", library.Name, mediaFolder)
 	} else {
 		log.Println("fs: scanning:", mediaFolder)
 	}
 
-	// Invoke a recursive file walk on the given media folder, passing closure variables into
-	// walkFunc to enable additional functionality
-	err := filepath.Walk(mediaFolder, func(currPath string, info os.FileInfo, err error) error {
-		// Stop walking immediately if needed
-		mutex.RLock()
-		if haltWalk {
-			return errors.New("media scan: halted by channel")
-		}
-		mutex.RUnlock()
+	// Load the file information of all known songs in this folder, so unchanged files can be
+	// skipped without reading their tags
+	files, err := data.DB.SongFilesInPath(mediaFolder)
+	if err != nil {
+		return 0, err
+	}
+	knownFiles := make(map[string]data.SongFile, len(files))
+	for _, f := range files {
+		knownFiles[f.FileName] = f
+	}
 
-		// Make sure path is actually valid
-		if info == nil {
-			return errors.New("media scan: invalid path: " + currPath)
-		}
+	// Read ignore files as they are needed during the walk, and CUE sheets for each folder
+	ignore := common.NewIgnorer(f.excludes)
+	cues := make(map[string][]fsCue)
 
-		// Check for an existing folder for this item
-		folder := new(data.Folder)
-		if info.IsDir() {
-			// If directory, use this path
-			folder.Path = currPath
-		} else {
-			// If file, use the directory path
-			folder.Path = path.Dir(currPath)
-		}
+	// Invoke a recursive file walk on the given media folder, sending all folders, new or changed
+	// media, and art to the workers
+	walkItems := make(chan fsScanItem, fsScanWorkers)
+	walkErrChan := make(chan error, 1)
+	skipCount := 0
+	go func() {
+		defer close(walkItems)
 
-		// Check for a cached folder, or attempt to load it
-		if tempFolder, ok := folderCache[folder.Path]; ok {
-			folder = tempFolder
-		} else if err := folder.Load(); err != nil && err == sql.ErrNoRows {
-			// Make sure items actually exist at this path
-			files, err := ioutil.ReadDir(folder.Path)
-			if err != nil {
-				log.Println(err)
-				return err
+		walkErrChan <- filepath.Walk(mediaFolder, func(currPath string, info os.FileInfo, err error) error {
+			// Stop walking immediately if needed
+			if halted() {
+				return errors.New("media scan: halted by channel")
 			}
 
-			// No items, skip it
-			if len(files) == 0 {
-				return nil
+			// Make sure path is actually valid
+			if info == nil {
+				return errors.New("media scan: invalid path: " + currPath)
 			}
 
-			// Set short title
-			folder.Title = path.Base(folder.Path)
+			// Skip ignored files, and ignored folders along with everything beneath them
+			if ignore.Ignored(library.Path, currPath, info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
 
-			// Check for a parent folder
-			pFolder := new(data.Folder)
+				return nil
+			}
 
-			// If scan is triggered by a file, we have to check the dir twice to get parent
-			if info.IsDir() {
-				pFolder.Path = path.Dir(currPath)
-			} else {
-				pFolder.Path = path.Dir(path.Dir(currPath))
+			// Skip files without a valid media or art extension
+			ext := path.Ext(currPath)
+			if !info.IsDir() && !mediaSet.Has(ext) && !artSet.Has(ext) {
+				return nil
 			}
 
-			// Load parent
-			if err := pFolder.Load(); err != nil && err != sql.ErrNoRows {
-				log.Println(err)
-				return err
+			// Report progress for all media and art files
+			if !info.IsDir() {
+				common.AddScanSeen(1)
 			}
 
-			// Copy parent folder's ID
-			folder.ParentID = pFolder.ID
+			// Check for a CUE sheet describing media files, which are modified along with it
+			item := fsScanItem{path: currPath, info: info}
+			modified := info.ModTime().Unix()
+			if mediaSet.Has(ext) {
+				folder := path.Dir(currPath)
+				if _, ok := cues[folder]; !ok {
+					cues[folder] = fsCueSheets(folder)
+				}
 
-			// Save new folder
-			if err := folder.Save(); err != nil {
-				log.Println(err)
-				return err
+				if cue, ok := fsCueForFile(cues[folder], currPath); ok {
+					item.cue = cue
+					if cue.modified > modified {
+						modified = cue.modified
+					}
+				}
 			}
 
-			// Continue traversal
-			folderCount++
-		}
-
-		// Cache this folder
-		folderCache[folder.Path] = folder
+			// Skip media files which have not changed since the last scan
+			if f, ok := knownFiles[currPath]; ok && !info.IsDir() && !f.Changed(info.Size(), modified) {
+				skipCount++
+				return nil
+			}
 
-		// Check for a valid media or art extension
-		ext := path.Ext(currPath)
-		if !mediaSet.Has(ext) && !artSet.Has(ext) {
+			walkItems <- item
 			return nil
-		}
+		})
+	}()
 
-		// If item is art, check for existing art
-		if artSet.Has(ext) {
-			// Attempt to load existing art
-			art := new(data.Art)
-			art.FileName = currPath
-			if err := art.Load(); err == sql.ErrNoRows {
-				// On new art, capture art information from filesystem
-				art.FileSize = info.Size()
-				art.LastModified = info.ModTime().Unix()
-
-				// Refuse to save a file with size 0, because the HTTP server will
-				// not allow it to be sent with 0 Content-Length
-				if art.FileSize == 0 {
-					return nil
+	// Read tags from media files using a pool of workers
+	scanItems := make(chan fsScanItem, fsScanWorkers)
+	var wg sync.WaitGroup
+	for i := 0; i < fsScanWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for item := range walkItems {
+				if !item.info.IsDir() && mediaSet.Has(path.Ext(item.path)) && !halted() {
+					item.song, item.picture, item.err = fsReadSong(item.path, item.info)
 				}
 
-				// Save new art
-				if err := art.Save(); err != nil {
-					log.Println(err)
-				} else if err == nil {
-					artCount++
-
-					// Add folder ID and to new art ID to slice
-					artFiles = append(artFiles, folderArtPair{
-						folderID: folder.ID,
-						artID:    art.ID,
-					})
-				}
+				scanItems <- item
 			}
+		}()
+	}
 
-			// Continue to next file
-			return nil
+	// Stop indexing once all workers are finished
+	go func() {
+		wg.Wait()
+		close(scanItems)
+	}()
+
+	// Index all items using a single writer.  On error, halt the walk, but continue draining
+	// items so that all workers can exit.
+	w := newFsScanWriter(library, f.artStore, f.artPatterns, f.embeddedArtFirst)
+	var scanErr error
+	for item := range scanItems {
+		if scanErr != nil || halted() {
+			continue
 		}
 
-		// Attempt to scan media file with taglib
-		file, err := taglib.Read(currPath)
-		if err != nil {
+		if err := w.Index(item); err != nil {
 			log.Println(err)
-			return fmt.Errorf("%s: %s", currPath, err.Error())
+			scanErr = err
+			halt()
 		}
+	}
 
-		// Generate a song model from the TagLib file
-		song, err := data.SongFromFile(file)
+	// Check for filesystem walk errors, and save any remaining songs
+	if err := <-walkErrChan; err != nil && scanErr == nil {
+		scanErr = err
+	}
+	if scanErr == nil {
+		scanErr = w.Flush()
+	}
+	if scanErr != nil {
+		return 0, scanErr
+	}
+
+	// Choose art for all folders containing new art or songs, in order of their paths, so that
+	// the result does not depend on the order in which they were scanned
+	artFolders := make([]string, 0, len(w.artFolders))
+	for p := range w.artFolders {
+		artFolders = append(artFolders, p)
+	}
+	sort.Strings(artFolders)
+
+	for _, p := range artFolders {
+		if err := w.FolderArt(w.artFolders[p]); err != nil {
+			return 0, err
+		}
+	}
+
+	// Print metrics
+	if verbose {
+		log.Printf("fs: media scan complete [time: %s]", time.Since(startTime).String())
+		log.Printf("fs: added: [art: %d] [artists: %d] [albums: %d] [songs: %d] [folders: %d]",
+			w.artCount, w.artistCount, w.albumCount, w.songCount, w.folderCount)
+		log.Printf("fs: updated: [songs: %d]", w.songUpdateCount)
+		log.Printf("fs: skipped: [unchanged songs: %d]", skipCount)
+	}
+
+	// Sum up all changes
+	sum := w.artCount + w.artistCount + w.albumCount + w.songCount + w.folderCount + w.songUpdateCount
+
+	// No errors
+	return sum, nil
+}
+
+// fsReadSong reads a song's tags and properties from a media file, and populates its filesystem
+// information.  The picture embedded in the file is also returned, if one exists.  Empty files
+// produce no song.
+func fsReadSong(currPath string, info os.FileInfo) (*data.Song, *data.Picture, error) {
+	// Refuse to save a file with size 0, because the HTTP server will
+	// not allow it to be sent with 0 Content-Length
+	if info.Size() == 0 {
+		return nil, nil, nil
+	}
+
+	// Check for a valid wavepipe file type integer
+	ext := path.Ext(info.Name())
+	fileType, ok := data.FileTypeMap[ext]
+	if !ok {
+		return nil, nil, fmt.Errorf("fs: invalid file type: %s", ext)
+	}
+
+	// Generate a song model from the media file; TagLib cannot read DSD files, so they are
+	// read directly
+	var song *data.Song
+	var err error
+	if fileType == data.DSD {
+		song, err = fsReadDSD(currPath)
+	} else {
+		song, err = fsReadTagLib(currPath)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	song.FileTypeID = fileType
+
+	// Read tags which TagLib does not expose, such as album artist and disc number, and the
+	// embedded picture.  The audio codec may also refine the file type.
+	picture, err := song.ReadExtendedTags(currPath)
+	if err != nil {
+		log.Println(err)
+	}
+
+	// Fingerprint the file, so the song can be found again if the file is moved
+	fingerprint, err := fsFingerprint(currPath, info.Size())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Populate filesystem-related struct fields using OS info
+	song.FileName = currPath
+	song.FileSize = info.Size()
+	song.Fingerprint = fingerprint
+	song.LastModified = info.ModTime().Unix()
+
+	return song, picture, nil
+}
+
+// fsFingerprint generates a fingerprint from the contents of a media file
+func fsFingerprint(currPath string, size int64) (string, error) {
+	file, err := os.Open(currPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return data.Fingerprint(file, size)
+}
+
+// fsReadTagLib reads a song's tags and properties from a media file using TagLib
+func fsReadTagLib(currPath string) (*data.Song, error) {
+	// Attempt to scan media file with taglib
+	file, err := taglib.Read(currPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", currPath, err.Error())
+	}
+
+	// Generate a song model from the TagLib file, and close the file handle; no longer needed
+	song, err := data.SongFromFile(file)
+	file.Close()
+	return song, err
+}
+
+// fsReadDSD reads a song's tags and properties from a DSD media file
+func fsReadDSD(currPath string) (*data.Song, error) {
+	file, err := os.Open(currPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return data.SongFromDSD(file)
+}
+
+// fsScanWriter indexes the items found by a media scan in the database.  It caches entries which
+// have been seen previously, and batches new and updated songs into transactions.  It must only
+// be used by a single goroutine, which keeps its caches consistent.
+type fsScanWriter struct {
+	library data.Library
+
+	// Store embedded art in this folder, and prefer it over art files if set.  Choose the
+	// cover among art files using patterns.
+	artStore         string
+	artPatterns      []string
+	embeddedArtFirst bool
+
+	// Cache entries which have been seen previously, to reduce database load
+	folderCache      map[string]*data.Folder
+	artistCache      map[string]*data.Artist
+	albumCache       map[string]*data.Album
+	embeddedArtCache map[string]*data.Art
+	artSourceCache   map[int]int
+
+	// Songs waiting to be saved or updated in a batch
+	newSongs     data.SongSlice
+	updatedSongs data.SongSlice
+
+	// Track all folders containing new art or songs, whose art must be chosen, by path
+	artFolders map[string]*data.Folder
+
+	// Track metrics about the scan
+	artCount        int
+	artistCount     int
+	albumCount      int
+	songCount       int
+	songUpdateCount int
+	folderCount     int
+}
+
+// newFsScanWriter creates a new fsScanWriter for the specified library, which stores embedded
+// art in the specified art store, and chooses covers using the specified patterns
+func newFsScanWriter(library data.Library, artStore string, artPatterns []string, embeddedArtFirst bool) *fsScanWriter {
+	return &fsScanWriter{
+		library:          library,
+		artStore:         artStore,
+		artPatterns:      artPatterns,
+		embeddedArtFirst: embeddedArtFirst,
+		folderCache:      map[string]*data.Folder{},
+		artistCache:      map[string]*data.Artist{},
+		albumCache:       map[string]*data.Album{},
+		embeddedArtCache: map[string]*data.Art{},
+		artSourceCache:   map[int]int{},
+		newSongs:         make(data.SongSlice, 0, fsScanBatchSize),
+		updatedSongs:     make(data.SongSlice, 0, fsScanBatchSize),
+		artFolders:       map[string]*data.Folder{},
+	}
+}
+
+// Index indexes a single folder, art, or media item in the database
+func (w *fsScanWriter) Index(item fsScanItem) error {
+	// Check for an existing folder for this item
+	folderPath := item.path
+	if !item.info.IsDir() {
+		// If file, use the directory path
+		folderPath = path.Dir(item.path)
+	}
+
+	folder, err := w.folder(folderPath)
+	if err != nil {
+		return err
+	}
+
+	// Nothing more to do for folders, or items in empty folders
+	if item.info.IsDir() || folder == nil {
+		return nil
+	}
+
+	// If item is art, check for existing art
+	if artSet.Has(path.Ext(item.path)) {
+		return w.art(item, folder)
+	}
+
+	// Check for errors while reading the media file, and skip empty files
+	if item.err != nil {
+		return item.err
+	}
+	if item.song == nil {
+		return nil
+	}
+
+	if item.cue != nil {
+		return w.cueSongs(item.song, item.picture, item.cue, folder)
+	}
+
+	return w.song(item.song, item.picture, folder)
+}
+
+// Flush saves and updates all songs waiting in a batch
+func (w *fsScanWriter) Flush() error {
+	// Save new songs (don't log these because they really slow things down)
+	if len(w.newSongs) > 0 {
+		if err := w.newSongs.Save(); err != nil {
+			return err
+		}
+
+		w.songCount += len(w.newSongs)
+		common.AddScanAdded(len(w.newSongs))
+		w.newSongs = w.newSongs[:0]
+	}
+
+	// Update existing songs
+	if len(w.updatedSongs) > 0 {
+		if err := w.updatedSongs.Update(); err != nil {
+			return err
+		}
+
+		w.songUpdateCount += len(w.updatedSongs)
+		common.AddScanUpdated(len(w.updatedSongs))
+		w.updatedSongs = w.updatedSongs[:0]
+	}
+
+	return nil
+}
+
+// folder loads or creates the folder at the specified path.  Items are indexed in the order in
+// which workers finish with them, so parent folders within the library are created first if
+// needed.  Folders with no items return no folder.
+func (w *fsScanWriter) folder(folderPath string) (*data.Folder, error) {
+	// Check for a cached folder, or attempt to load it
+	if folder, ok := w.folderCache[folderPath]; ok {
+		return folder, nil
+	}
+
+	folder := &data.Folder{Path: folderPath}
+	if err := folder.Load(); err == nil {
+		w.folderCache[folderPath] = folder
+		return folder, nil
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// Make sure items actually exist at this path
+	files, err := ioutil.ReadDir(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// No items, skip it
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	// Set short title, and library
+	folder.Title = path.Base(folderPath)
+	folder.LibraryID = w.library.ID
+
+	// Check for a parent folder, creating it first if it resides in the library
+	parentPath := path.Dir(folderPath)
+	if parentPath != folderPath && w.library.Contains(parentPath) {
+		parent, err := w.folder(parentPath)
 		if err != nil {
+			return nil, err
+		}
+
+		if parent != nil {
+			folder.ParentID = parent.ID
+		}
+	} else {
+		// Load parent
+		parent := &data.Folder{Path: parentPath}
+		if err := parent.Load(); err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		folder.ParentID = parent.ID
+	}
+
+	// Save new folder
+	if err := folder.Save(); err != nil {
+		return nil, err
+	}
+	w.folderCount++
+	common.AddScanAdded(1)
+
+	// Cache this folder
+	w.folderCache[folderPath] = folder
+	return folder, nil
+}
+
+// art indexes an art file, if it does not already exist
+func (w *fsScanWriter) art(item fsScanItem, folder *data.Folder) error {
+	// Attempt to load existing art
+	art := new(data.Art)
+	art.FileName = item.path
+	if err := art.Load(); err != sql.ErrNoRows {
+		return nil
+	}
+
+	// On new art, capture art information from filesystem
+	art.FileSize = item.info.Size()
+	art.LastModified = item.info.ModTime().Unix()
+	art.LibraryID = w.library.ID
+
+	// Refuse to save a file with size 0, because the HTTP server will
+	// not allow it to be sent with 0 Content-Length
+	if art.FileSize == 0 {
+		return nil
+	}
+
+	// Save new art
+	if err := art.Save(); err != nil {
+		log.Println(err)
+		return nil
+	}
+	w.artCount++
+	common.AddScanAdded(1)
+
+	// Choose art for this folder once the scan is complete
+	w.artFolders[folder.Path] = folder
+	return nil
+}
+
+// artist loads or creates the artist with the same title as the input artist
+func (w *fsScanWriter) artist(artist *data.Artist) *data.Artist {
+	// Check for existing artist
+	// Note: if the artist exists, this operation also loads necessary scanning information
+	// such as their artist ID, for use in album and song generation
+	if tempArtist, ok := w.artistCache[artist.Title]; ok {
+		return tempArtist
+	} else if err := artist.Load(); err == sql.ErrNoRows {
+		// Save new artist
+		if err := artist.Save(); err != nil {
 			log.Println(err)
-			return err
+		} else if err == nil {
+			log.Printf("Artist: [#%05d] %s", artist.ID, artist.Title)
+			w.artistCount++
+			common.AddScanAdded(1)
 		}
+	}
 
-		// Close file handle; no longer needed
-		file.Close()
+	// Cache this artist
+	w.artistCache[artist.Title] = artist
+	return artist
+}
+
+// embeddedArt stores a picture embedded in a media file in the art store, and loads or creates
+// its art.  Pictures which cannot be stored produce no art.
+func (w *fsScanWriter) embeddedArt(picture *data.Picture) (*data.Art, error) {
+	art, err := data.ArtFromPicture(w.artStore, picture)
+	if err != nil || art == nil {
+		return nil, err
+	}
 
-		// Populate filesystem-related struct fields using OS info
-		song.FileName = currPath
-		song.FileSize = info.Size()
-		song.LastModified = info.ModTime().Unix()
+	// Check for cached art, or attempt to load it
+	if tempArt, ok := w.embeddedArtCache[art.FileName]; ok {
+		return tempArt, nil
+	}
 
-		// Refuse to save a file with size 0, because the HTTP server will
-		// not allow it to be sent with 0 Content-Length
-		if song.FileSize == 0 {
-			return nil
+	if err := art.Load(); err == sql.ErrNoRows {
+		// Save new art, which is shared by all libraries
+		if err := art.Save(); err != nil {
+			return nil, err
 		}
+		w.artCount++
+		common.AddScanAdded(1)
+	} else if err != nil {
+		return nil, err
+	}
 
-		// Use this folder's ID
-		song.FolderID = folder.ID
+	// Cache this art
+	w.embeddedArtCache[art.FileName] = art
+	w.artSourceCache[art.ID] = art.Source
+	return art, nil
+}
+
+// artSource returns the source of the art with the specified ID.  Missing art is treated as an
+// art file, so that it may be replaced.
+func (w *fsScanWriter) artSource(id int) int {
+	if source, ok := w.artSourceCache[id]; ok {
+		return source
+	}
 
-		// Check for a valid wavepipe file type integer
-		ext = path.Ext(info.Name())
-		fileType, ok := data.FileTypeMap[ext]
-		if !ok {
-			return fmt.Errorf("fs: invalid file type: %s", ext)
+	art := &data.Art{ID: id}
+	if err := art.Load(); err != nil {
+		art.Source = data.ArtSourceFile
+	}
+
+	w.artSourceCache[id] = art.Source
+	return art.Source
+}
+
+// FolderArt chooses the cover among the art files in a folder using the writer's patterns, and
+// attaches it to the songs and albums in the folder.  The remaining art files become the gallery
+// of each album.  Albums which already have an art file from another folder keep it, so that
+// albums split across several folders are not claimed by each in turn.
+func (w *fsScanWriter) FolderArt(folder *data.Folder) error {
+	// Load all art files directly within this folder, and rank them
+	all, err := data.DB.ArtInPath(folder.Path + "/")
+	if err != nil {
+		return err
+	}
+
+	art := make([]data.Art, 0, len(all))
+	for _, a := range all {
+		if a.Source == data.ArtSourceFile && path.Dir(a.FileName) == folder.Path {
+			art = append(art, a)
 		}
-		song.FileTypeID = fileType
+	}
+	art = data.RankArt(art, w.artPatterns)
 
-		// Generate an artist model from this song's metadata
-		artist := data.ArtistFromSong(song)
+	songs, err := data.DB.SongsForFolder(folder.ID)
+	if err != nil {
+		return err
+	}
 
-		// Check for existing artist
-		// Note: if the artist exists, this operation also loads necessary scanning information
-		// such as their artist ID, for use in album and song generation
-		if tempArtist, ok := artistCache[artist.Title]; ok {
-			artist = tempArtist
-		} else if err := artist.Load(); err == sql.ErrNoRows {
-			// Save new artist
-			if err := artist.Save(); err != nil {
-				log.Println(err)
-			} else if err == nil {
-				log.Printf("Artist: [#%05d] %s", artist.ID, artist.Title)
-				artistCount++
+	// Update songs with the cover, unless their embedded art takes priority
+	if len(art) > 0 {
+		updated := make(data.SongSlice, 0, len(songs))
+		for i, s := range songs {
+			if s.ArtID == art[0].ID || (w.embeddedArtFirst && w.artSource(s.ArtID) == data.ArtSourceEmbedded) {
+				continue
 			}
+
+			songs[i].ArtID = art[0].ID
+			updated = append(updated, songs[i])
 		}
+		if err := updated.Update(); err != nil {
+			return err
+		}
+	}
 
-		// Cache this artist
-		artistCache[artist.Title] = artist
+	// Attach art to each album in this folder, once
+	seen := make(map[int]struct{})
+	for _, s := range songs {
+		if _, ok := seen[s.AlbumID]; ok {
+			continue
+		}
+		seen[s.AlbumID] = struct{}{}
 
-		// Generate the album model from this song's metadata
-		album := data.AlbumFromSong(song)
-		album.ArtistID = artist.ID
+		album := &data.Album{ID: s.AlbumID}
+		if err := album.Load(); err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			return err
+		}
 
-		// Generate cache key
-		albumCacheKey := strconv.Itoa(album.ArtistID) + "_" + album.Title
+		// Keep art files chosen from another folder
+		if album.ArtID != 0 {
+			current := &data.Art{ID: album.ArtID}
+			if err := current.Load(); err == nil && current.Source == data.ArtSourceFile &&
+				path.Dir(current.FileName) != folder.Path {
+				continue
+			}
+		}
 
-		// Check for existing album
-		// Note: if the album exists, this operation also loads necessary scanning information
-		// such as the album ID, for use in song generation
-		if tempAlbum, ok := albumCache[albumCacheKey]; ok {
-			album = tempAlbum
-		} else if err := album.Load(); err == sql.ErrNoRows {
-			// Save album
-			if err := album.Save(); err != nil {
-				log.Println(err)
-			} else if err == nil {
-				log.Printf("  - Album: [#%05d] %s - %d - %s", album.ID, album.Artist, album.Year, album.Title)
-				albumCount++
+		// Use the cover, or the first embedded art of the album's songs if there is no cover,
+		// or if embedded art takes priority
+		album.ArtID = 0
+		if len(art) > 0 {
+			album.ArtID = art[0].ID
+		}
+		for _, s2 := range songs {
+			if s2.AlbumID != album.ID || s2.ArtID == 0 {
+				continue
+			}
+
+			if album.ArtID == 0 || (w.embeddedArtFirst && w.artSource(s2.ArtID) == data.ArtSourceEmbedded) {
+				album.ArtID = s2.ArtID
+				break
+			}
+		}
+		if err := album.Update(); err != nil {
+			return err
+		}
+
+		// All other art files become the album's gallery, in order
+		gallery := make([]int, 0, len(art))
+		for _, a := range art {
+			if a.ID != album.ArtID {
+				gallery = append(gallery, a.ID)
 			}
 		}
+		if err := album.SetGallery(gallery); err != nil {
+			return err
+		}
+	}
 
-		// Cache this album
-		albumCache[albumCacheKey] = album
+	return nil
+}
 
-		// Add ID fields to song
-		song.ArtistID = artist.ID
-		song.AlbumID = album.ID
+// song indexes a song read from a media file, queueing it to be saved or updated in a batch.
+// Embedded art is used for the song unless it already has an art file, and art files are
+// preferred.
+func (w *fsScanWriter) song(song *data.Song, picture *data.Picture, folder *data.Folder) error {
+	// Use this folder's ID, and the library's ID
+	song.FolderID = folder.ID
+	song.LibraryID = w.library.ID
+
+	// Generate an artist model from this song's metadata
+	artist := w.artist(data.ArtistFromSong(song))
+
+	// Generate the album artist model, which only differs from the song's artist on
+	// albums such as compilations
+	albumArtist := w.artist(data.AlbumArtistFromSong(song))
+
+	// Generate the album model from this song's metadata, using the album artist so
+	// that songs by many artists are grouped into one album
+	album := data.AlbumFromSong(song)
+	album.ArtistID = albumArtist.ID
+
+	// Generate cache key
+	albumCacheKey := strconv.Itoa(album.ArtistID) + "_" + album.Title
+
+	// Check for existing album
+	// Note: if the album exists, this operation also loads necessary scanning information
+	// such as the album ID, for use in song generation
+	if tempAlbum, ok := w.albumCache[albumCacheKey]; ok {
+		album = tempAlbum
+	} else if err := album.Load(); err == sql.ErrNoRows {
+		// Save album
+		if err := album.Save(); err != nil {
+			log.Println(err)
+		} else if err == nil {
+			log.Printf("  - Album: [#%05d] %s - %d - %s", album.ID, album.Artist, album.Year, album.Title)
+			w.albumCount++
+			common.AddScanAdded(1)
+		}
+	}
 
-		// Make a duplicate song to check if song has been modified since last scan
-		song2 := new(data.Song)
-		song2.FileName = song.FileName
+	// Cache this album
+	w.albumCache[albumCacheKey] = album
 
-		// Check for existing song
-		if err := song2.Load(); err == sql.ErrNoRows {
-			// Save song (don't log these because they really slow things down)
-			if err2 := song.Save(); err2 != nil && err2 != sql.ErrNoRows {
-				log.Println(err2)
-			} else if err2 == nil {
-				songCount++
-			}
-		} else {
-			// Song already existed.  Check if it's been updated
-			if song.LastModified > song2.LastModified {
-				// Update existing song
-				song.ID = song2.ID
-				if err2 := song.Update(); err2 != nil {
-					log.Println(err2)
-				}
+	// Add ID fields to song
+	song.ArtistID = artist.ID
+	song.AlbumID = album.ID
+
+	// Make a duplicate song to check if song has been modified since last scan
+	song2 := new(data.Song)
+	song2.FileName = song.FileName
+	song2.StartOffset = song.StartOffset
+
+	// Check for existing song
+	err := song2.Load()
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	isNew := err == sql.ErrNoRows
+
+	// A new song may be an existing song whose file was moved while it was not being watched, so
+	// move it in place and update it, to keep its ID
+	moved := false
+	if isNew {
+		if moved, err = w.move(song); err != nil {
+			return err
+		}
 
-				songUpdateCount++
+		if moved {
+			err = song2.Load()
+			if err != nil && err != sql.ErrNoRows {
+				return err
 			}
+			isNew = err == sql.ErrNoRows
 		}
+	}
 
-		// Successful media scan
+	// Skip existing songs which have not been modified, unless they were moved, or were indexed
+	// without a fingerprint
+	if !isNew && !moved && song.LastModified <= song2.LastModified && song2.Fingerprint != "" {
 		return nil
-	})
+	}
+
+	// Keep the song's existing art, and use its embedded art if it takes priority, or if the
+	// song has no art file
+	song.ArtID = song2.ArtID
+	if picture != nil {
+		art, err := w.embeddedArt(picture)
+		if err != nil {
+			return err
+		}
+
+		if art != nil && (w.embeddedArtFirst || song.ArtID == 0 || w.artSource(song.ArtID) == data.ArtSourceEmbedded) {
+			song.ArtID = art.ID
+		}
+	}
+
+	if isNew {
+		// Queue new song, and choose art for its folder once the scan is complete
+		w.newSongs = append(w.newSongs, *song)
+		w.artFolders[folder.Path] = folder
+	} else {
+		// Song already existed, but has been updated
+		song.ID = song2.ID
+		w.updatedSongs = append(w.updatedSongs, *song)
+	}
+
+	// Save a full batch
+	if len(w.newSongs)+len(w.updatedSongs) >= fsScanBatchSize {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// move checks for an indexed song with the same fingerprint as a new song, whose file no longer
+// exists, and moves all songs from that file to the new song's file
+func (w *fsScanWriter) move(song *data.Song) (bool, error) {
+	if song.Fingerprint == "" {
+		return false, nil
+	}
+
+	songs, err := data.DB.SongsForFingerprint(song.Fingerprint)
+	if err != nil {
+		return false, err
+	}
+
+	for _, s := range songs {
+		// Copies of a file which still exist are separate songs
+		if _, err := os.Stat(s.FileName); s.FileName == song.FileName || !os.IsNotExist(err) {
+			continue
+		}
+
+		if err := data.DB.MovePath(s.FileName, song.FileName); err != nil {
+			return false, err
+		}
+
+		log.Printf("fs: moved: %s -> %s", s.FileName, song.FileName)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// cueSongs indexes a song for each track of a CUE sheet within a media file, which is modified
+// along with its sheet.  Songs previously indexed from the file which no longer match a track,
+// such as the whole file before its sheet was added, are removed.
+func (w *fsScanWriter) cueSongs(song *data.Song, picture *data.Picture, cue *fsCue, folder *data.Folder) error {
+	if cue.modified > song.LastModified {
+		song.LastModified = cue.modified
+	}
+
+	// Index the whole file if the sheet has no tracks which fit in it
+	songs := cue.sheet.Songs(song)
+	if len(songs) == 0 {
+		songs = []data.Song{*song}
+	}
+
+	offsets := make(map[int]bool, len(songs))
+	for i := range songs {
+		offsets[songs[i].StartOffset] = true
+		if err := w.song(&songs[i], picture, folder); err != nil {
+			return err
+		}
+	}
+
+	// Remove stale songs from this file
+	existing, err := data.DB.SongsInPath(song.FileName)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range existing {
+		if s.FileName != song.FileName || offsets[s.StartOffset] {
+			continue
+		}
+
+		if err := s.Delete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
-	// Check for filesystem walk errors
+// MoveScan updates the paths of all items moved from one path to another within the specified
+// library, so that songs, folders, and art keep their IDs.  Items which were replaced at the new
+// path are removed.  The new path is then scanned for any other changes.
+func (f fsFileSource) MoveScan(library data.Library, from string, to string, verbose bool, moveCancelChan chan struct{}) (int, error) {
+	// If nothing was indexed at the old path, such as a temporary file which replaced a media
+	// file, scan the new path as usual
+	songs, art, folders, err := fsItemsInPath(from)
 	if err != nil {
 		return 0, err
 	}
+	if len(songs)+len(art)+len(folders) == 0 {
+		return f.MediaScan(library, to, verbose, moveCancelChan)
+	}
 
-	// Iterate all new folder/art ID pairs
-	for _, a := range artFiles {
-		// Fetch all songs for the folder from the pair
-		songs, err := data.DB.SongsForFolder(a.folderID)
+	log.Printf("fs: moving: %s -> %s", from, to)
+
+	// Remove items which were replaced at the new path
+	oldSongs, oldArt, oldFolders, err := fsItemsInPath(to)
+	if err != nil {
+		return 0, err
+	}
+	for _, s := range oldSongs {
+		if err := s.Delete(); err != nil {
+			return 0, err
+		}
+	}
+	for _, a := range oldArt {
+		if err := a.Delete(); err != nil {
+			return 0, err
+		}
+	}
+	for _, f := range oldFolders {
+		if err := f.Delete(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := data.DB.MovePath(from, to); err != nil {
+		return 0, err
+	}
+
+	// A moved folder now resides in a new parent folder, and moved files in a new folder
+	w := newFsScanWriter(library, f.artStore, f.artPatterns, f.embeddedArtFirst)
+	parent, err := w.folder(path.Dir(to))
+	if err != nil {
+		return 0, err
+	}
+	parentID := 0
+	if parent != nil {
+		parentID = parent.ID
+	}
+
+	if len(folders) > 0 {
+		folder := &data.Folder{Path: to}
+		if err := folder.Load(); err != nil && err != sql.ErrNoRows {
+			return 0, err
+		} else if err == nil {
+			folder.ParentID = parentID
+			folder.Title = path.Base(to)
+			if err := folder.Update(); err != nil {
+				return 0, err
+			}
+		}
+	} else if parent != nil {
+		moved, _, _, err := fsItemsInPath(to)
 		if err != nil {
 			return 0, err
 		}
 
-		// Iterate and update songs with their new art ID
-		for _, s := range songs {
-			s.ArtID = a.artID
-			if err := s.Update(); err != nil {
+		for i := range moved {
+			moved[i].FolderID = parent.ID
+		}
+		if err := data.SongSlice(moved).Update(); err != nil {
+			return 0, err
+		}
+
+		// Choose art again for the old and new folders, in case art was moved between them
+		w.artFolders[parent.Path] = parent
+		old := &data.Folder{Path: path.Dir(from)}
+		if err := old.Load(); err == nil {
+			w.artFolders[old.Path] = old
+		}
+		for _, folder := range w.artFolders {
+			if err := w.FolderArt(folder); err != nil {
 				return 0, err
 			}
 		}
 	}
 
-	// Print metrics
-	if verbose {
-		log.Printf("fs: media scan complete [time: %s]", time.Since(startTime).String())
-		log.Printf("fs: added: [art: %d] [artists: %d] [albums: %d] [songs: %d] [folders: %d]",
-			artCount, artistCount, albumCount, songCount, folderCount)
-		log.Printf("fs: updated: [songs: %d]", songUpdateCount)
+	sum := len(songs) + len(art) + len(folders)
+	common.AddScanUpdated(sum)
+
+	// Scan the new path for any other changes
+	changes, err := f.MediaScan(library, to, verbose, moveCancelChan)
+	return sum + changes, err
+}
+
+// fsItemsInPath loads all songs, art, and folders at the specified path, or beneath it
+func fsItemsInPath(p string) ([]data.Song, []data.Art, []data.Folder, error) {
+	within := func(itemPath string) bool {
+		return itemPath == p || strings.HasPrefix(itemPath, p+"/")
 	}
 
-	// Sum up all changes
-	sum := artCount + artistCount + albumCount + songCount + folderCount + songUpdateCount
+	allSongs, err := data.DB.SongsInPath(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	songs := make([]data.Song, 0, len(allSongs))
+	for _, s := range allSongs {
+		if within(s.FileName) {
+			songs = append(songs, s)
+		}
+	}
 
-	// No errors
-	return sum, nil
+	allArt, err := data.DB.ArtInPath(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	art := make([]data.Art, 0, len(allArt))
+	for _, a := range allArt {
+		if within(a.FileName) {
+			art = append(art, a)
+		}
+	}
+
+	allFolders, err := data.DB.FoldersInPath(p)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	folders := make([]data.Folder, 0, len(allFolders))
+	for _, f := range allFolders {
+		if within(f.Path) {
+			folders = append(folders, f)
+		}
+	}
+
+	return songs, art, folders, nil
 }
 
-// OrphanScan scans for missing "orphaned" media files in the local filesystem
-func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool, orphanCancelChan chan struct{}) (int, error) {
+// OrphanScan scans for missing "orphaned" media files in the local filesystem, within the
+// specified library.  Items which have become ignored are removed as well.
+func (f fsFileSource) OrphanScan(library data.Library, subFolder string, verbose bool, orphanCancelChan chan struct{}) (int, error) {
 	// Halt scan if needed
 	var mutex sync.RWMutex
 	haltOrphanScan := false
@@ -357,14 +1129,14 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 	songCount := 0
 	startTime := time.Now()
 
-	// Check if a baseFolder is set, meaning remove ANYTHING not under this base
-	if baseFolder != "" {
+	// Check if no subfolder is set, meaning remove ANYTHING in this library not under its folder
+	if subFolder == "" {
 		if verbose {
-			log.Println("fs: orphan scanning base folder:", baseFolder)
+			log.Printf("fs: orphan scanning library: %s [%s]", library.Name, library.Path)
 		}
 
-		// Scan for all art NOT under the base folder
-		art, err := data.DB.ArtNotInPath(baseFolder)
+		// Scan for all art in this library NOT under its folder
+		art, err := data.DB.ArtNotInPath(library.ID, library.Path)
 		if err != nil {
 			log.Println(err)
 			return 0, err
@@ -381,8 +1153,8 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 			artCount++
 		}
 
-		// Scan for all songs NOT under the base folder
-		songs, err := data.DB.SongsNotInPath(baseFolder)
+		// Scan for all songs in this library NOT under its folder
+		songs, err := data.DB.SongsNotInPath(library.ID, library.Path)
 		if err != nil {
 			log.Println(err)
 			return 0, err
@@ -399,8 +1171,8 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 			songCount++
 		}
 
-		// Scan for all folders NOT under the base folder
-		folders, err := data.DB.FoldersNotInPath(baseFolder)
+		// Scan for all folders in this library NOT under its folder
+		folders, err := data.DB.FoldersNotInPath(library.ID, library.Path)
 		if err != nil {
 			log.Println(err)
 			return 0, err
@@ -416,11 +1188,9 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 
 			folderCount++
 		}
-	}
 
-	// If no subfolder set, use the base folder to check file existence
-	if subFolder == "" {
-		subFolder = baseFolder
+		// Use the library's folder to check file existence
+		subFolder = library.Path
 	}
 
 	if verbose {
@@ -429,17 +1199,21 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 		log.Println("fs: removing:", subFolder)
 	}
 
+	// Remove items which are now ignored, as if they no longer exist
+	ignore := common.NewIgnorer(f.excludes)
+
 	// Scan for all art in subfolder
 	art, err := data.DB.ArtInPath(subFolder)
 	if err != nil {
 		log.Println(err)
 		return 0, err
 	}
+	common.AddScanSeen(len(art))
 
 	// Iterate all art in this path
 	for _, a := range art {
-		// Check that the art still exists in this place
-		if _, err := os.Stat(a.FileName); os.IsNotExist(err) {
+		// Check that the art still exists in this place, and is not ignored
+		if _, err := os.Stat(a.FileName); os.IsNotExist(err) || ignore.Ignored(library.Path, a.FileName, false) {
 			// Remove art from database
 			if err := a.Delete(); err != nil {
 				log.Println(err)
@@ -456,11 +1230,26 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 		log.Println(err)
 		return 0, err
 	}
+	common.AddScanSeen(len(songs))
+
+	// Read CUE sheets for each folder as they are needed
+	cues := make(map[string][]fsCue)
 
 	// Iterate all songs in this path
 	for _, s := range songs {
-		// Check that the song still exists in this place
-		if _, err := os.Stat(s.FileName); os.IsNotExist(err) {
+		// Check that CUE tracks are still described by a sheet
+		orphan := false
+		if s.IsCueTrack() {
+			folder := path.Dir(s.FileName)
+			if _, ok := cues[folder]; !ok {
+				cues[folder] = fsCueSheets(folder)
+			}
+
+			orphan = !fsCueHasTrack(cues[folder], s)
+		}
+
+		// Check that the song still exists in this place, and is not ignored
+		if _, err := os.Stat(s.FileName); orphan || os.IsNotExist(err) || ignore.Ignored(library.Path, s.FileName, false) {
 			// Remove song from database
 			if err := s.Delete(); err != nil {
 				log.Println(err)
@@ -486,8 +1275,8 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 			return 0, err
 		}
 
-		// Delete any folders with 0 items
-		if len(files) == 0 {
+		// Delete any folders with 0 items, or which are ignored
+		if len(files) == 0 || ignore.Ignored(library.Path, f.Path, true) {
 			if err := f.Delete(); err != nil {
 				log.Println(err)
 				return 0, err
@@ -511,6 +1300,27 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 		return 0, err
 	}
 
+	// Check for embedded art which is no longer used by any song
+	embeddedArt, err := data.DB.OrphanEmbeddedArt()
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	// Remove embedded art from the database and the art store
+	for _, a := range embeddedArt {
+		if err := a.Delete(); err != nil {
+			log.Println(err)
+			return 0, err
+		}
+
+		if err := os.Remove(a.FileName); err != nil && !os.IsNotExist(err) {
+			log.Println(err)
+		}
+
+		artCount++
+	}
+
 	// Print metrics
 	if verbose {
 		log.Printf("fs: orphan scan complete [time: %s]", time.Since(startTime).String())
@@ -518,8 +1328,27 @@ func (fsFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool
 			artCount, artistCount, albumCount, songCount, folderCount)
 	}
 
-	// Sum up changes
+	// Sum up changes, and report them as removed
 	sum := artCount + artistCount + albumCount + songCount + folderCount
+	common.AddScanRemoved(sum)
 
 	return sum, nil
 }
+
+// fsCueHasTrack determines if a CUE sheet still describes a track beginning at the song's offset,
+// within the song's file
+func fsCueHasTrack(cues []fsCue, song data.Song) bool {
+	cue, ok := fsCueForFile(cues, song.FileName)
+	if !ok {
+		return false
+	}
+
+	file, _ := cue.sheet.File(path.Base(song.FileName))
+	for _, t := range file.Tracks {
+		if t.Start == song.StartOffset {
+			return true
+		}
+	}
+
+	return false
+}
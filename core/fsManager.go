@@ -1,11 +1,16 @@
 package core
 
 import (
+	"database/sql"
 	"errors"
 	"log"
+	"os"
+	"path"
 	"time"
 
 	"github.com/mdlayher/wavepipe/common"
+	"github.com/mdlayher/wavepipe/config"
+	"github.com/mdlayher/wavepipe/data"
 	"github.com/mdlayher/wavepipe/env"
 
 	"github.com/mdlayher/goset"
@@ -15,9 +20,17 @@ import (
 // artSet is a set of valid file extensions which we should scan as art
 var artSet = set.New(".jpg", ".jpeg", ".png")
 
+// cueExt is the file extension of CUE sheets, which describe the tracks within media files
+const cueExt = ".cue"
+
 // mediaSet is a set of valid file extensions which we should scan as media, as they are the ones
-// which TagLib is capable of reading
-var mediaSet = set.New(".ape", ".flac", ".m4a", ".mp3", ".mpc", ".ogg", ".wma", ".wv")
+// which TagLib, or wavepipe itself, is capable of reading
+var mediaSet = set.New(".aif", ".aiff", ".ape", ".dff", ".dsf", ".flac", ".m4a", ".mp3", ".mpc",
+	".ogg", ".opus", ".wav", ".wma", ".wv")
+
+// fsRenameTimeout is the time to wait for the path which a renamed path was renamed to, before
+// the renamed path is removed as an orphan
+const fsRenameTimeout = 2 * time.Second
 
 // fsQueue is a queue of tasks to be performed by the filesystem, such as media and orphan scans
 var fsQueue = make(chan fsTask, 10)
@@ -25,51 +38,79 @@ var fsQueue = make(chan fsTask, 10)
 // fsSource is the data source used to scan for media files (could be filesystem, memory, etc)
 var fsSource fileSource
 
-// fsTask is the interface which defines a filesystem task, such as a media, or orphan scan
+// fsTask is the interface which defines a filesystem task, such as a media, orphan, or move scan
 type fsTask interface {
-	Folders() (string, string)
-	SetFolders(string, string)
-	Scan(string, string, chan struct{}) (int, error)
+	Folders() (data.Library, string)
+	SetFolders(data.Library, string)
+	Scan(data.Library, string, chan struct{}) (int, error)
 	Verbose(bool)
 }
 
 // fileSource represents a source from which files can be scanned and indexed
 type fileSource interface {
-	MediaScan(string, bool, chan struct{}) (int, error)
-	OrphanScan(string, string, bool, chan struct{}) (int, error)
+	MediaScan(data.Library, string, bool, chan struct{}) (int, error)
+	OrphanScan(data.Library, string, bool, chan struct{}) (int, error)
+	MoveScan(data.Library, string, string, bool, chan struct{}) (int, error)
+}
+
+// fsRename is a path which was renamed, and is waiting to be matched with the path it was
+// renamed to
+type fsRename struct {
+	library data.Library
+	path    string
 }
 
 // fsManager handles fsWalker processes, and communicates back and forth with the manager goroutine
-func fsManager(mediaFolder string, fsKillChan chan struct{}) {
+func fsManager(conf config.Config, libraryConfigs []config.Library, fsKillChan chan struct{}) {
 	log.Println("fs: starting...")
 
 	// Initialize a queue to cancel filesystem tasks
 	cancelQueue := make(chan chan struct{}, 10)
 
-	// Set up the data source (typically filesystem, unless in test mode)
-	fsSource = fsFileSource{}
+	// Set up the data source (typically filesystem, unless in test mode), choosing art using
+	// the configured patterns and policy
+	embeddedArtFirst, _ := conf.EmbeddedArtFirst()
+	fsSource = fsFileSource{
+		artStore:         conf.ArtStorePath(),
+		artPatterns:      conf.CoverArtPatterns(),
+		embeddedArtFirst: embeddedArtFirst,
+		excludes:         conf.Excludes,
+	}
 	if env.IsTest() {
 		// Mock file source
 		fsSource = memFileSource{}
 	}
 
+	// Store the configured libraries in the database, removing any which no longer exist
+	libraries, err := fsSyncLibraries(libraryConfigs)
+	if err != nil {
+		log.Fatalf("fs: could not sync libraries: %s", err.Error())
+	}
+
 	// Track the number of filesystem events fired
 	fsTaskCount := 0
 
 	// Initialize filesystem watcher when ready
 	watcherChan := make(chan struct{})
 
-	// Queue an initial, verbose orphan scan
-	o := new(fsOrphanScan)
-	o.SetFolders(mediaFolder, "")
-	o.Verbose(true)
-	fsQueue <- o
-
-	// Queue a media scan
-	m := new(fsMediaScan)
-	m.SetFolders(mediaFolder, "")
-	m.Verbose(true)
-	fsQueue <- m
+	// Queue initial scans for each library in a goroutine, so a large number of libraries
+	// cannot fill the queue before it is processed.  Media scans run first, so that songs which
+	// were moved while wavepipe was stopped are found by their fingerprints, rather than removed.
+	go func() {
+		for _, l := range libraries {
+			// Queue an initial, verbose media scan
+			m := new(fsMediaScan)
+			m.SetFolders(l, "")
+			m.Verbose(true)
+			fsQueue <- m
+
+			// Queue an orphan scan
+			o := new(fsOrphanScan)
+			o.SetFolders(l, "")
+			o.Verbose(true)
+			fsQueue <- o
+		}
+	}()
 
 	// Invoke task queue via goroutine, so it can be halted via the manager
 	go func() {
@@ -81,14 +122,21 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 				cancelChan := make(chan struct{})
 				cancelQueue <- cancelChan
 
-				// Retrieve the folders to use with scan
-				baseFolder, subFolder := task.Folders()
+				// Retrieve the library and subfolder to use with scan
+				library, subFolder := task.Folders()
+
+				// Start the scan, reporting its status to clients
+				folder := subFolder
+				if folder == "" {
+					folder = library.Path
+				}
+				common.StartScan(fsTaskType(task), library.Name, folder)
 
-				// Start the scan
-				changes, err := task.Scan(baseFolder, subFolder, cancelChan)
+				changes, err := task.Scan(library, subFolder, cancelChan)
 				if err != nil {
 					log.Println(err)
 				}
+				common.FinishScan(err)
 
 				// If changes occurred, update the scan time
 				if changes > 0 {
@@ -100,8 +148,8 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 				close(cancelChan)
 				fsTaskCount++
 
-				// After both initial scans complete, start the filesystem watcher
-				if fsTaskCount == 2 {
+				// After both initial scans complete for all libraries, start the filesystem watcher
+				if fsTaskCount == 2*len(libraries) {
 					close(watcherChan)
 				}
 			}
@@ -128,10 +176,49 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 			recentModifySet := set.New()
 			recentRenameSet := set.New()
 
+			// Skip events for ignored paths, reading ignore files as they are needed
+			ignore := common.NewIgnorer(conf.Excludes)
+
+			// Renamed paths waiting to be matched with the paths they were renamed to, so that
+			// moved media keeps its identity.  Paths which are not matched in time are orphaned.
+			renames := make([]fsRename, 0)
+			renameExpired := make(chan string)
+
 			for {
 				select {
 				// Event occurred
 				case ev := <-watcher.Event:
+					// Determine which library contains this item
+					library, ok := fsLibraryForPath(libraries, ev.Name)
+					if !ok {
+						break
+					}
+
+					// On changes to an ignore file, reload ignore files, and rescan its folder to
+					// remove newly ignored items and add items which are no longer ignored.  CUE
+					// sheets are rescanned the same way, to replace the tracks they describe.
+					if path.Base(ev.Name) == common.IgnoreFile || path.Ext(ev.Name) == cueExt {
+						if path.Base(ev.Name) == common.IgnoreFile {
+							ignore.Reset()
+						}
+
+						o := new(fsOrphanScan)
+						o.SetFolders(library, path.Dir(ev.Name))
+						o.Verbose(false)
+						fsQueue <- o
+
+						m := new(fsMediaScan)
+						m.SetFolders(library, path.Dir(ev.Name))
+						m.Verbose(false)
+						fsQueue <- m
+						break
+					}
+
+					info, err := os.Stat(ev.Name)
+					if ignore.Ignored(library.Path, ev.Name, err == nil && info.IsDir()) {
+						break
+					}
+
 					switch {
 					// On modify, trigger a media scan
 					case ev.IsModify():
@@ -147,17 +234,28 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 						}()
 
 						fallthrough
-					// On create, trigger a media scan
+					// On create, trigger a move scan if a path was just renamed to this one, or a
+					// media scan otherwise
 					case ev.IsCreate():
 						// Invoke a slight delay to enable file creation
 						<-time.After(250 * time.Millisecond)
 
-						// Scan item as the "base folder", so it just adds this item
+						// Modified files also reach this point, but were not renamed
+						if i, ok := fsMatchRename(renames, library, ev.Name); ok && ev.IsCreate() {
+							mv := &fsMoveScan{from: renames[i].path}
+							mv.SetFolders(library, ev.Name)
+							mv.Verbose(false)
+							renames = append(renames[:i], renames[i+1:]...)
+							fsQueue <- mv
+							break
+						}
+
+						// Scan item as the "subfolder", so it just adds this item
 						m := new(fsMediaScan)
-						m.SetFolders(ev.Name, "")
+						m.SetFolders(library, ev.Name)
 						m.Verbose(false)
 						fsQueue <- m
-					// On rename, trigger an orphan scan
+					// On rename, wait for the path it was renamed to
 					case ev.IsRename():
 						// Add file to set, stopping it from propogating if the event was recently triggered
 						if !recentRenameSet.Add(ev.Name) {
@@ -170,7 +268,12 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 							recentRenameSet.Remove(ev.Name)
 						}()
 
-						fallthrough
+						// Orphan this path if it is not matched in time
+						renames = append(renames, fsRename{library: library, path: ev.Name})
+						go func() {
+							<-time.After(fsRenameTimeout)
+							renameExpired <- ev.Name
+						}()
 					// On delete, trigger an orphan scan
 					case ev.IsDelete():
 						// Invoke a slight delay to enable file deletion
@@ -178,9 +281,24 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 
 						// Scan item as the "subfolder", so it just removes this item
 						o := new(fsOrphanScan)
-						o.SetFolders("", ev.Name)
+						o.SetFolders(library, ev.Name)
+						o.Verbose(false)
+						fsQueue <- o
+					}
+				// Renamed path was not matched, so trigger an orphan scan
+				case name := <-renameExpired:
+					for i, r := range renames {
+						if r.path != name {
+							continue
+						}
+
+						// Scan item as the "subfolder", so it just removes this item
+						o := new(fsOrphanScan)
+						o.SetFolders(r.library, r.path)
 						o.Verbose(false)
+						renames = append(renames[:i], renames[i+1:]...)
 						fsQueue <- o
+						break
 					}
 				// Watcher errors
 				case err := <-watcher.Error:
@@ -190,12 +308,42 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 			}
 		}()
 
-		// Watch media folder
-		if err := watcher.Watch(mediaFolder); err != nil {
-			log.Println(err)
-			return
+		// Watch each library's folder
+		for _, l := range libraries {
+			if err := watcher.Watch(l.Path); err != nil {
+				log.Println(err)
+				return
+			}
+			log.Printf("fs: watching library: %s [%s]", l.Name, l.Path)
+		}
+	}()
+
+	// Queue scans requested by clients one at a time, so that requests remain in their own
+	// queue until the task queue has room, and clients are refused once both queues are full
+	go func() {
+		for req := range common.ScanRequests {
+			// Find the requested library
+			var library data.Library
+			for _, l := range libraries {
+				if l.ID == req.LibraryID {
+					library = l
+				}
+			}
+			if library.ID == 0 {
+				log.Println("fs: scan requested for unknown library:", req.LibraryID)
+				continue
+			}
+
+			// Create a verbose task of the requested type
+			var task fsTask = new(fsMediaScan)
+			if req.Type == common.ScanOrphan {
+				task = new(fsOrphanScan)
+			}
+			task.SetFolders(library, req.Folder)
+			task.Verbose(true)
+
+			fsQueue <- task
 		}
-		log.Println("fs: watching folder:", mediaFolder)
 	}()
 
 	// Trigger manager events via channel
@@ -225,21 +373,119 @@ func fsManager(mediaFolder string, fsKillChan chan struct{}) {
 	}
 }
 
-// fsMediaScan represents a filesystem task which scans the given path for new media
+// fsSyncLibraries stores all configured libraries in the database, updating any whose folder
+// has changed.  Libraries which are no longer configured are deleted, releasing their media so
+// that it is removed by orphan scans, unless it is claimed by another library.
+func fsSyncLibraries(libraryConfigs []config.Library) ([]data.Library, error) {
+	// Check for libraries which are no longer configured
+	existing, err := data.DB.AllLibraries()
+	if err != nil {
+		return nil, err
+	}
+
+	configured := set.New()
+	for _, c := range libraryConfigs {
+		configured.Add(c.Name)
+	}
+
+	for _, l := range existing {
+		if configured.Has(l.Name) {
+			continue
+		}
+
+		// Delete library
+		if err := l.Delete(); err != nil {
+			return nil, err
+		}
+		log.Printf("fs: removed library: %s [%s]", l.Name, l.Path)
+	}
+
+	// Save or update all configured libraries
+	libraries := make([]data.Library, 0, len(libraryConfigs))
+	for _, c := range libraryConfigs {
+		library := &data.Library{Name: c.Name}
+		if err := library.Load(); err == sql.ErrNoRows {
+			// Save new library
+			library.Path = c.Path()
+			if err := library.Save(); err != nil {
+				return nil, err
+			}
+			log.Printf("fs: added library: %s [%s]", library.Name, library.Path)
+		} else if err != nil {
+			return nil, err
+		} else if library.Path != c.Path() {
+			// Update library with a new folder
+			library.Path = c.Path()
+			if err := library.Update(); err != nil {
+				return nil, err
+			}
+			log.Printf("fs: moved library: %s [%s]", library.Name, library.Path)
+		}
+
+		libraries = append(libraries, *library)
+	}
+
+	return libraries, nil
+}
+
+// fsLibraryForPath returns the library which contains the input path, if one exists
+func fsLibraryForPath(libraries []data.Library, p string) (data.Library, bool) {
+	for _, l := range libraries {
+		if l.Contains(p) {
+			return l, true
+		}
+	}
+
+	return data.Library{}, false
+}
+
+// fsMatchRename returns the index of the pending rename within the input library which most
+// likely produced the input path.  Renames which keep their name, such as moves to another
+// folder, are preferred.  Otherwise, the most recent rename is used, because the watcher reports
+// both halves of a rename one after another.
+func fsMatchRename(renames []fsRename, library data.Library, p string) (int, bool) {
+	match := -1
+	for i, r := range renames {
+		if r.library.ID != library.ID || r.path == p {
+			continue
+		}
+
+		if path.Base(r.path) == path.Base(p) {
+			return i, true
+		}
+		match = i
+	}
+
+	return match, match != -1
+}
+
+// fsTaskType returns the scan type of the input filesystem task
+func fsTaskType(task fsTask) string {
+	switch task.(type) {
+	case *fsOrphanScan:
+		return common.ScanOrphan
+	case *fsMoveScan:
+		return common.ScanMove
+	}
+
+	return common.ScanMedia
+}
+
+// fsMediaScan represents a filesystem task which scans the given library for new media
 type fsMediaScan struct {
-	baseFolder string
-	subFolder  string
-	verbose    bool
+	library   data.Library
+	subFolder string
+	verbose   bool
 }
 
-// Folders returns the base folder and subfolder for use with a scanning task
-func (fs *fsMediaScan) Folders() (string, string) {
-	return fs.baseFolder, fs.subFolder
+// Folders returns the library and subfolder for use with a scanning task
+func (fs *fsMediaScan) Folders() (data.Library, string) {
+	return fs.library, fs.subFolder
 }
 
-// SetFolders sets the base folder and subfolder for use with a scanning task
-func (fs *fsMediaScan) SetFolders(baseFolder string, subFolder string) {
-	fs.baseFolder = baseFolder
+// SetFolders sets the library and subfolder for use with a scanning task
+func (fs *fsMediaScan) SetFolders(library data.Library, subFolder string) {
+	fs.library = library
 	fs.subFolder = subFolder
 }
 
@@ -248,33 +494,33 @@ func (fs *fsMediaScan) Verbose(verbose bool) {
 	fs.verbose = verbose
 }
 
-// Scan scans for media files in a specified path, and queues them up for inclusion
-// in the wavepipe database
-func (fs *fsMediaScan) Scan(mediaFolder string, subFolder string, walkCancelChan chan struct{}) (int, error) {
-	// Media scans are comprehensive, so subfolder has no purpose
-	if subFolder != "" {
-		return 0, errors.New("media scan: subfolder not valid for media scan operation")
+// Scan scans for media files in a specified library, and queues them up for inclusion
+// in the wavepipe database.  If a subfolder is set, only that item is scanned.
+func (fs *fsMediaScan) Scan(library data.Library, subFolder string, walkCancelChan chan struct{}) (int, error) {
+	// Media must reside within the library
+	if library.Path == "" || (subFolder != "" && !library.Contains(subFolder)) {
+		return 0, errors.New("media scan: subfolder not valid for library")
 	}
 
 	// Scan for media using the specified file source
-	return fsSource.MediaScan(mediaFolder, fs.verbose, walkCancelChan)
+	return fsSource.MediaScan(library, subFolder, fs.verbose, walkCancelChan)
 }
 
-// fsOrphanScan represents a filesystem task which scans the given path for orphaned media
+// fsOrphanScan represents a filesystem task which scans the given library for orphaned media
 type fsOrphanScan struct {
-	baseFolder string
-	subFolder  string
-	verbose    bool
+	library   data.Library
+	subFolder string
+	verbose   bool
 }
 
-// Folders returns the base folder and subfolder for use with a scanning task
-func (fs *fsOrphanScan) Folders() (string, string) {
-	return fs.baseFolder, fs.subFolder
+// Folders returns the library and subfolder for use with a scanning task
+func (fs *fsOrphanScan) Folders() (data.Library, string) {
+	return fs.library, fs.subFolder
 }
 
-// SetFolders sets the base folder and subfolder for use with a scanning task
-func (fs *fsOrphanScan) SetFolders(baseFolder string, subFolder string) {
-	fs.baseFolder = baseFolder
+// SetFolders sets the library and subfolder for use with a scanning task
+func (fs *fsOrphanScan) SetFolders(library data.Library, subFolder string) {
+	fs.library = library
 	fs.subFolder = subFolder
 }
 
@@ -290,18 +536,56 @@ func (fs *fsOrphanScan) Verbose(verbose bool) {
 //   - Album: no more songs contain this album's ID
 //   - Folder: folder no longer present in the filesystem, or folder contains no items
 //   - Song: song is no longer present in the filesystem
-// The baseFolder is the root location of the media folder.  As wavepipe currently supports only
-// one media folder, any media which does not reside in this folder is orphaned.  If left blank,
-// only the subFolder will be checked.
-// The subFolder is the current file location, under the baseFolder.  This is used to allow for
-// quick scans of a small subsection of the directory, such as on a filesystem change.  Any files
-// which are in the database, but do not exist on disk, will be orphaned and removed.
-func (fs *fsOrphanScan) Scan(baseFolder string, subFolder string, orphanCancelChan chan struct{}) (int, error) {
-	// If both folders are empty, there is nothing to do
-	if baseFolder == "" && subFolder == "" {
-		return 0, errors.New("orphan scan: no base folder or subfolder")
+// The library contains the root location of a media folder.  Any media belonging to this library,
+// or to no library, which does not reside in this folder is orphaned.  Media belonging to other
+// libraries is left untouched.
+// The subFolder is the current file location, under the library's folder.  This is used to allow
+// for quick scans of a small subsection of the directory, such as on a filesystem change.  If set,
+// only the subFolder will be checked.  Any files which are in the database, but do not exist on
+// disk, will be orphaned and removed.
+func (fs *fsOrphanScan) Scan(library data.Library, subFolder string, orphanCancelChan chan struct{}) (int, error) {
+	// If the library has no folder, there is nothing to do
+	if library.Path == "" {
+		return 0, errors.New("orphan scan: no library folder")
 	}
 
 	// Scan for orphans using the specified file source
-	return fsSource.OrphanScan(baseFolder, subFolder, fs.verbose, orphanCancelChan)
+	return fsSource.OrphanScan(library, subFolder, fs.verbose, orphanCancelChan)
+}
+
+// fsMoveScan represents a filesystem task which moves media from one path to another within the
+// given library, so that it keeps its identity
+type fsMoveScan struct {
+	library   data.Library
+	from      string
+	subFolder string
+	verbose   bool
+}
+
+// Folders returns the library and subfolder for use with a scanning task
+func (fs *fsMoveScan) Folders() (data.Library, string) {
+	return fs.library, fs.subFolder
+}
+
+// SetFolders sets the library and subfolder for use with a scanning task
+func (fs *fsMoveScan) SetFolders(library data.Library, subFolder string) {
+	fs.library = library
+	fs.subFolder = subFolder
+}
+
+// Verbose sets the verbosity level of the scanning task
+func (fs *fsMoveScan) Verbose(verbose bool) {
+	fs.verbose = verbose
+}
+
+// Scan updates the paths of media which was moved to the specified subfolder, so that songs,
+// folders, and art keep their IDs, and then scans the subfolder for any other changes
+func (fs *fsMoveScan) Scan(library data.Library, subFolder string, moveCancelChan chan struct{}) (int, error) {
+	// Both paths must reside within the library
+	if library.Path == "" || !library.Contains(fs.from) || !library.Contains(subFolder) {
+		return 0, errors.New("move scan: paths not valid for library")
+	}
+
+	// Move media using the specified file source
+	return fsSource.MoveScan(library, fs.from, subFolder, fs.verbose, moveCancelChan)
 }
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/mdlayher/wavepipe/api"
 	"github.com/mdlayher/wavepipe/common"
 	"github.com/mdlayher/wavepipe/config"
 	"github.com/mdlayher/wavepipe/env"
@@ -38,18 +39,33 @@ func Manager(killChan chan struct{}, exitChan chan int) {
 		log.Fatalf("manager: could not load config: %s", err.Error())
 	}
 
-	// Check valid media folder, unless in test mode
-	folder := conf.Media()
-	if !env.IsTest() {
-		// Check empty folder, provide help information if not set
-		if folder == "" {
-			log.Fatal("manager: no media folder set in config: ", config.C.Help())
-		} else if _, err := os.Stat(folder); err != nil {
-			// Check file existence
-			log.Fatalf("manager: invalid media folder set in config: %s", err.Error())
+	// Check valid media libraries, unless in test mode
+	libraries, err := conf.MediaLibraries()
+	if env.IsTest() {
+		// Mock library, containing mock files
+		libraries = []config.Library{{Name: "mem", Folder: "/mem"}}
+	} else if err == config.ErrNoLibraries {
+		// Check empty libraries, provide help information if not set
+		log.Fatal("manager: no media folder set in config: ", config.C.Help())
+	} else if err != nil {
+		log.Fatalf("manager: invalid media libraries set in config: %s", err.Error())
+	} else {
+		// Check folder existence
+		for _, l := range libraries {
+			if _, err := os.Stat(l.Path()); err != nil {
+				log.Fatalf("manager: invalid media folder set in config: %s", err.Error())
+			}
 		}
 	}
 
+	// Check valid art policy
+	if _, err := conf.EmbeddedArtFirst(); err != nil {
+		log.Fatalf("manager: invalid art policy set in config: %s", err.Error())
+	}
+
+	// Set the fraction of a song which must be streamed to record a play
+	api.PlayThreshold = conf.PlayThreshold
+
 	// Launch database manager to handle database/ORM connections
 	dbLaunchChan := make(chan struct{})
 	dbKillChan := make(chan struct{})
@@ -64,7 +80,7 @@ func Manager(killChan chan struct{}, exitChan chan int) {
 
 	// Launch filesystem manager to handle file scanning
 	fsKillChan := make(chan struct{})
-	go fsManager(folder, fsKillChan)
+	go fsManager(*conf, libraries, fsKillChan)
 
 	// Launch HTTP API server
 	apiKillChan := make(chan struct{})
@@ -72,7 +88,7 @@ func Manager(killChan chan struct{}, exitChan chan int) {
 
 	// Launch transcode manager to handle ffmpeg and file transcoding
 	transcodeKillChan := make(chan struct{})
-	go transcodeManager(transcodeKillChan)
+	go transcodeManager(*conf, transcodeKillChan)
 
 	// Wait for termination signal
 	for {
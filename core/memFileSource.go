@@ -64,7 +64,12 @@ var mockFiles = []data.Song{
 }
 
 // MediaScan adds mock media files to the database from memory
-func (memFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan chan struct{}) (int, error) {
+func (memFileSource) MediaScan(library data.Library, mediaFolder string, verbose bool, walkCancelChan chan struct{}) (int, error) {
+	// If no media folder is set, scan the entire library
+	if mediaFolder == "" {
+		mediaFolder = library.Path
+	}
+
 	log.Println("mem: beginning mock media scan:", mediaFolder)
 
 	// Iterate all media files and check for the matching prefix
@@ -75,6 +80,7 @@ func (memFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan
 			folder := new(data.Folder)
 			folder.Path = "/mem"
 			folder.Title = "/mem"
+			folder.LibraryID = library.ID
 
 			// Attempt to load folder
 			if err := folder.Load(); err == sql.ErrNoRows {
@@ -87,6 +93,7 @@ func (memFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan
 			// Generate an art model
 			art := new(data.Art)
 			art.FileName = "/mem/test.jpg"
+			art.LibraryID = library.ID
 
 			// Attempt to load art
 			if err := art.Load(); err == sql.ErrNoRows {
@@ -109,9 +116,19 @@ func (memFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan
 				}
 			}
 
+			// Generate the album artist model, which only differs from the song's artist on
+			// albums such as compilations
+			albumArtist := data.AlbumArtistFromSong(&song)
+			if err := albumArtist.Load(); err == sql.ErrNoRows {
+				// Save new album artist
+				if err := albumArtist.Save(); err != nil {
+					log.Println(err)
+				}
+			}
+
 			// Generate the album model from this song's metadata
 			album := data.AlbumFromSong(&song)
-			album.ArtistID = artist.ID
+			album.ArtistID = albumArtist.ID
 
 			// Check for existing album
 			// Note: if the album exists, this operation also loads necessary scanning information
@@ -126,6 +143,7 @@ func (memFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan
 			// Add ID fields to song
 			song.ArtistID = artist.ID
 			song.AlbumID = album.ID
+			song.LibraryID = library.ID
 
 			// Check for existing song
 			if err := song.Load(); err == sql.ErrNoRows {
@@ -142,6 +160,11 @@ func (memFileSource) MediaScan(mediaFolder string, verbose bool, walkCancelChan
 }
 
 // OrphanScan does nothing for mock media files, because the database is temporary anyway
-func (memFileSource) OrphanScan(baseFolder string, subFolder string, verbose bool, orphanCancelChan chan struct{}) (int, error) {
+func (memFileSource) OrphanScan(library data.Library, subFolder string, verbose bool, orphanCancelChan chan struct{}) (int, error) {
 	return 0, nil
 }
+
+// MoveScan updates the paths of mock media files moved from one path to another
+func (memFileSource) MoveScan(library data.Library, from string, to string, verbose bool, moveCancelChan chan struct{}) (int, error) {
+	return 0, data.DB.MovePath(from, to)
+}
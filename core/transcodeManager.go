@@ -5,14 +5,59 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/mdlayher/wavepipe/config"
 	"github.com/mdlayher/wavepipe/transcode"
+
+	"github.com/mdlayher/goset"
 )
 
-// transcodeManager manages active file transcodes, their caching, etc, and communicates back
-// and forth with the manager goroutine
-func transcodeManager(transcodeKillChan chan struct{}) {
+// transcodeManager manages active file transcodes, their caching, and the number of ffmpeg
+// processes which may run at once, and communicates back and forth with the manager goroutine
+func transcodeManager(conf config.Config, transcodeKillChan chan struct{}) {
 	log.Println("transcode: starting...")
 
+	// Limit the number of ffmpeg processes, queueing transcodes beyond the limit
+	limiter := transcode.NewLimiter(conf.TranscodeLimit, conf.TranscodeQueue)
+	if conf.TranscodeLimit > 0 {
+		log.Printf("transcode: limiting to %d ffmpeg processes, with %d queued", conf.TranscodeLimit, conf.TranscodeQueue)
+	}
+
+	// Set up the transcode cache, streaming directly from ffmpeg if it is disabled
+	cache := transcode.NewCache(conf.TranscodeCachePath(), conf.TranscodeCacheBytes(), limiter)
+	if err := cache.Load(); err != nil {
+		log.Println("transcode: could not load cache, transcodes will not be cached:", err)
+		transcode.DefaultCache = transcode.NewCache("", 0, limiter)
+	} else {
+		transcode.DefaultCache = cache
+		if conf.TranscodeCacheBytes() > 0 {
+			log.Printf("transcode: caching up to %d MB in: %s", conf.TranscodeCacheSize, conf.TranscodeCachePath())
+		}
+	}
+
+	// Set up transcoding profiles from config, which must be valid
+	profiles, err := conf.TranscodeProfiles()
+	if err != nil {
+		log.Fatalf("transcode: invalid profiles set in config: %s", err.Error())
+	}
+
+	for _, p := range profiles {
+		profile := &transcode.Profile{
+			Name:       p.Name,
+			Codec:      p.Codec,
+			Quality:    p.Quality,
+			SampleRate: p.SampleRate,
+			Channels:   p.Channels,
+			Filters:    p.Filters,
+			Container:  p.Container,
+		}
+		if err := profile.Validate(); err != nil {
+			log.Fatalf("transcode: invalid profile %s set in config: %s", p.Name, err.Error())
+		}
+
+		log.Println("transcode: profile:", p.Name)
+		transcode.Profiles[p.Name] = profile
+	}
+
 	// Perform setup routines for ffmpeg transcoding
 	go ffmpegSetup()
 
@@ -63,10 +108,16 @@ func ffmpegSetup() {
 	}
 
 	// Check for available codecs
-	codecStr := string(codecs)
-	for _, c := range []string{transcode.FFmpegMP3Codec, transcode.FFmpegOGGCodec, transcode.FFmpegOPUSCodec} {
+	encoders := ffmpegEncoders(string(codecs))
+	for _, c := range []string{
+		transcode.FFmpegMP3Codec,
+		transcode.FFmpegOGGCodec,
+		transcode.FFmpegOPUSCodec,
+		transcode.FFmpegAACCodec,
+		transcode.FFmpegFLACCodec,
+	} {
 		// See if codec is found in output
-		if strings.Contains(codecStr, c) {
+		if encoders.Has(c) {
 			log.Println("transcode:", c, "found, enabling transcoding")
 			transcode.CodecSet.Add(c)
 		} else {
@@ -74,3 +125,36 @@ func ffmpegSetup() {
 		}
 	}
 }
+
+// ffmpegEncoders parses the output of 'ffmpeg -codecs' into a set of the encoders it lists.
+// Codecs which ffmpeg can encode are listed by name with an 'E' flag, and any other encoders
+// for a codec, such as libmp3lame, are listed after it.  Short names such as 'aac' and 'flac'
+// are also listed for decoders, so only codecs with an encoder are added.
+func ffmpegEncoders(output string) *set.Set {
+	encoders := set.New()
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields[0]) < 2 {
+			continue
+		}
+
+		// Check for a list of encoders, which are used instead of the codec's name
+		if i := strings.Index(line, "(encoders:"); i >= 0 {
+			list := line[i+len("(encoders:"):]
+			if j := strings.Index(list, ")"); j >= 0 {
+				list = list[:j]
+			}
+
+			for _, e := range strings.Fields(list) {
+				encoders.Add(e)
+			}
+			continue
+		}
+
+		if fields[0][1] == 'E' {
+			encoders.Add(fields[1])
+		}
+	}
+
+	return encoders
+}
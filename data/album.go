@@ -1,25 +1,56 @@
 package data
 
 // Album represents an album known to wavepipe, and contains information
-// extracted from song tags
+// extracted from song tags.  Its art is the cover chosen from its folder, and
+// any other art in the folder is kept in its gallery.
 type Album struct {
-	ID       int    `json:"id"`
-	Artist   string `json:"artist"`
-	ArtistID int    `db:"artist_id" json:"artistId"`
-	Title    string `json:"title"`
-	Year     int    `json:"year"`
+	ID        int    `json:"id"`
+	ArtID     int    `db:"art_id" json:"artId"`
+	Artist    string `json:"artist"`
+	ArtistID  int    `db:"artist_id" json:"artistId"`
+	PlayCount int    `db:"play_count" json:"playCount"`
+	Title     string `json:"title"`
+	Year      int    `json:"year"`
 }
 
 // AlbumFromSong creates a new Album from a Song model, extracting its
-// fields as needed to build the struct
+// fields as needed to build the struct.  Albums are identified by their album
+// artist, so that compilations are not split up by each song's artist.
 func AlbumFromSong(song *Song) *Album {
 	return &Album{
-		Artist: song.Artist,
+		Artist: albumArtist(song),
 		Title:  song.Album,
 		Year:   song.Year,
 	}
 }
 
+// AlbumArtistFromSong creates a new Artist from the album artist of a Song model,
+// falling back to the song's artist if it has no album artist
+func AlbumArtistFromSong(song *Song) *Artist {
+	return &Artist{
+		Title: albumArtist(song),
+	}
+}
+
+// albumArtist returns the album artist of a song, or its artist if it has no album artist
+func albumArtist(song *Song) string {
+	if song.AlbumArtist != "" {
+		return song.AlbumArtist
+	}
+
+	return song.Artist
+}
+
+// Gallery retrieves all secondary art for this album, in display order
+func (a *Album) Gallery() ([]Art, error) {
+	return DB.GalleryForAlbum(a.ID)
+}
+
+// SetGallery replaces this album's secondary art with the art with the specified IDs, in order
+func (a *Album) SetGallery(artIDs []int) error {
+	return DB.SetAlbumGallery(a.ID, artIDs)
+}
+
 // Delete removes an existing Album from the database
 func (a *Album) Delete() error {
 	return DB.DeleteAlbum(a)
@@ -34,3 +65,8 @@ func (a *Album) Load() error {
 func (a *Album) Save() error {
 	return DB.SaveAlbum(a)
 }
+
+// Update updates an existing Album in the database
+func (a *Album) Update() error {
+	return DB.UpdateAlbum(a)
+}
@@ -14,13 +14,17 @@ var album = Album{
 
 // TestAlbumDatabase verifies that an Album can be saved and loaded from the database
 func TestAlbumDatabase(t *testing.T) {
-	// Load database configuration
-	DB = new(SqliteBackend)
-	DB.DSN("~/.config/wavepipe/wavepipe.db")
-	if err := DB.Open(); err != nil {
-		t.Fatalf("Could not open database connection: %s", err.Error())
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
+
+	// Save the album's artist, so the album can be loaded
+	a := &Artist{Title: album.Artist}
+	if err := a.Save(); err != nil {
+		t.Fatalf("Could not save artist: %s", err.Error())
 	}
-	defer DB.Close()
+	album.ArtistID = a.ID
 
 	// Attempt to save the album
 	if err := album.Save(); err != nil {
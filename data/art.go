@@ -1,16 +1,129 @@
 package data
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
 	"io"
+	"io/ioutil"
 	"os"
+	"path"
+	"sort"
+	"strings"
 )
 
+const (
+	// ArtSourceFile is the source of art which is read from an image file in a media folder
+	ArtSourceFile = iota
+	// ArtSourceEmbedded is the source of art which is extracted from the tags of a media file,
+	// and stored in the art store
+	ArtSourceEmbedded
+)
+
+// artExtensions maps the MIME types of embedded pictures to the file extensions used to store them
+var artExtensions = map[string]string{
+	"image/bmp":  ".bmp",
+	"image/gif":  ".gif",
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
 // Art represents folder or album art known to wavepipe, and contains filesystem metadata
 type Art struct {
 	ID           int
 	FileSize     int64  `db:"file_size"`
 	FileName     string `db:"file_name"`
 	LastModified int64  `db:"last_modified"`
+	LibraryID    int    `db:"library_id"`
+	Source       int    `db:"source"`
+}
+
+// ArtFromPicture stores a picture embedded in a media file in the art store at the specified
+// folder, and creates an Art struct for it.  Pictures are stored by the hash of their contents,
+// so identical pictures embedded in many files are only stored once.  The art is not saved to
+// the database.
+func ArtFromPicture(store string, p *Picture) (*Art, error) {
+	// Pictures of unknown types cannot be served
+	ext, ok := artExtensions[p.MIME]
+	if !ok {
+		return nil, nil
+	}
+
+	// Store the picture, split into folders by the first byte of its hash
+	hash := sha1.Sum(p.Data)
+	name := hex.EncodeToString(hash[:])
+	fileName := path.Join(store, name[0:2], name+ext)
+
+	info, err := os.Stat(fileName)
+	if os.IsNotExist(err) {
+		if err := writeArtFile(fileName, p.Data); err != nil {
+			return nil, err
+		}
+
+		info, err = os.Stat(fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Art{
+		FileSize:     info.Size(),
+		FileName:     fileName,
+		LastModified: info.ModTime().Unix(),
+		Source:       ArtSourceEmbedded,
+	}, nil
+}
+
+// writeArtFile writes art to a file in the art store.  The art is written to a temporary file
+// first, so that a partially written file is never served.
+func writeArtFile(fileName string, data []byte) error {
+	if err := os.MkdirAll(path.Dir(fileName), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(path.Dir(fileName), path.Base(fileName))
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if err2 := tmp.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), fileName)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+
+	return err
+}
+
+// RankArt sorts art in the order it should be used for a folder or album, returning a new slice.
+// Art whose file name matches an earlier pattern comes first, larger art comes first among art
+// matching the same pattern, and art matching no pattern comes last.  Patterns use the syntax of
+// path.Match, and are matched against lower-case file names.
+func RankArt(art []Art, patterns []string) []Art {
+	ranked := artByRank{
+		art:   make([]Art, len(art)),
+		ranks: make([]int, len(art)),
+	}
+	copy(ranked.art, art)
+
+	// Rank each art by the first pattern which matches its file name
+	for i, a := range ranked.art {
+		ranked.ranks[i] = len(patterns)
+		name := strings.ToLower(path.Base(a.FileName))
+		for j, p := range patterns {
+			if ok, _ := path.Match(strings.ToLower(p), name); ok {
+				ranked.ranks[i] = j
+				break
+			}
+		}
+	}
+
+	sort.Sort(ranked)
+	return ranked.art
 }
 
 // Delete removes existing Art from the database
@@ -32,3 +145,34 @@ func (a *Art) Save() error {
 func (a Art) Stream() (io.ReadSeeker, error) {
 	return os.Open(a.FileName)
 }
+
+// artByRank allows sorting of art by pattern rank, file size, and file name
+type artByRank struct {
+	art   []Art
+	ranks []int
+}
+
+// Len returns the number of art
+func (a artByRank) Len() int {
+	return len(a.art)
+}
+
+// Swap swaps two art by index
+func (a artByRank) Swap(i, j int) {
+	a.art[i], a.art[j] = a.art[j], a.art[i]
+	a.ranks[i], a.ranks[j] = a.ranks[j], a.ranks[i]
+}
+
+// Less compares two art by pattern rank, then by larger file size, and then by file name, so
+// that the order is always the same
+func (a artByRank) Less(i, j int) bool {
+	if a.ranks[i] != a.ranks[j] {
+		return a.ranks[i] < a.ranks[j]
+	}
+
+	if a.art[i].FileSize != a.art[j].FileSize {
+		return a.art[i].FileSize > a.art[j].FileSize
+	}
+
+	return a.art[i].FileName < a.art[j].FileName
+}
@@ -0,0 +1,86 @@
+package data
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+// TestArtFromPicture verifies that embedded pictures are stored once by the hash of their
+// contents, and that pictures of unknown types are not stored
+func TestArtFromPicture(t *testing.T) {
+	store, err := ioutil.TempDir("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(store)
+
+	p := &Picture{Type: pictureFrontCover, MIME: "image/png", Data: []byte("\x89PNG picture")}
+	art, err := ArtFromPicture(store, p)
+	if err != nil {
+		t.Fatalf("Could not store picture: %s", err.Error())
+	}
+	if art.Source != ArtSourceEmbedded || art.FileSize != int64(len(p.Data)) ||
+		!strings.HasPrefix(art.FileName, store+"/") || path.Ext(art.FileName) != ".png" {
+		t.Fatalf("Unexpected art: %v", art)
+	}
+
+	// Verify an identical picture uses the same file
+	art2, err := ArtFromPicture(store, &Picture{MIME: "image/png", Data: []byte("\x89PNG picture")})
+	if err != nil || art2.FileName != art.FileName {
+		t.Fatalf("Unexpected art for identical picture: %v (%v)", art2, err)
+	}
+
+	// Verify unknown pictures are skipped
+	if art, err := ArtFromPicture(store, &Picture{MIME: "image/x-foo", Data: []byte("foo")}); art != nil || err != nil {
+		t.Fatalf("Unexpected art for unknown picture: %v (%v)", art, err)
+	}
+}
+
+// TestRankArt verifies that art is ranked by the first matching pattern, then by size, and
+// that art matching no pattern is ranked last
+func TestRankArt(t *testing.T) {
+	art := []Art{
+		{ID: 1, FileName: "/music/back.jpg", FileSize: 500},
+		{ID: 2, FileName: "/music/Folder.png", FileSize: 100},
+		{ID: 3, FileName: "/music/cd.png", FileSize: 900},
+		{ID: 4, FileName: "/music/COVER.jpg", FileSize: 200},
+		{ID: 5, FileName: "/music/cover.png", FileSize: 300},
+		{ID: 6, FileName: "/music/a.jpg", FileSize: 500},
+	}
+
+	var tests = []struct {
+		patterns []string
+		ids      []int
+	}{
+		// Patterns take priority, then larger art
+		{[]string{"cover.*", "folder.*", "front.*"}, []int{5, 4, 2, 3, 6, 1}},
+		{[]string{"folder.*", "cover.*"}, []int{2, 5, 4, 3, 6, 1}},
+		// Without patterns, only size and file name are used
+		{nil, []int{3, 6, 1, 5, 4, 2}},
+	}
+
+	for _, test := range tests {
+		ranked := RankArt(art, test.patterns)
+		ids := make([]int, 0, len(ranked))
+		for _, a := range ranked {
+			ids = append(ids, a.ID)
+		}
+
+		if len(ids) != len(test.ids) {
+			t.Fatalf("Unexpected ranked art: %v != %v", ids, test.ids)
+		}
+		for i := range ids {
+			if ids[i] != test.ids[i] {
+				t.Fatalf("Unexpected ranked art: %v != %v", ids, test.ids)
+			}
+		}
+	}
+
+	// Verify the input slice is not modified
+	if art[0].ID != 1 {
+		t.Fatalf("Input art was modified: %v", art)
+	}
+}
@@ -11,13 +11,10 @@ var artist = Artist{
 
 // TestArtistDatabase verifies that an Artist can be saved and loaded from the database
 func TestArtistDatabase(t *testing.T) {
-	// Load database configuration
-	DB = new(SqliteBackend)
-	DB.DSN("~/.config/wavepipe/wavepipe.db")
-	if err := DB.Open(); err != nil {
-		t.Fatalf("Could not open database connection: %s", err.Error())
-	}
-	defer DB.Close()
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
 
 	// Attempt to save the artist
 	if err := artist.Save(); err != nil {
@@ -0,0 +1,1471 @@
+package data
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+// testBackend is a named database backend, used to run the conformance suite
+type testBackend struct {
+	name string
+	db   dbBackend
+}
+
+// testSqliteBackend opens a sqlite backend using a new, temporary database file, returning
+// the backend and a function which closes and removes it
+func testSqliteBackend(t *testing.T) (*SqliteBackend, func()) {
+	// Create a temporary directory for the database
+	dir, err := ioutil.TempDir("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %s", err.Error())
+	}
+
+	// Copy the embedded database into the directory, and open it
+	db := new(SqliteBackend)
+	db.DSN(path.Join(dir, "wavepipe.db"))
+	if err := db.Setup(); err != nil {
+		t.Fatalf("Could not set up database: %s", err.Error())
+	}
+	if err := db.Open(); err != nil {
+		t.Fatalf("Could not open database connection: %s", err.Error())
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Could not migrate database: %s", err.Error())
+	}
+
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// testBackends returns newly opened, empty instances of each backend which must conform
+// to the semantics of dbBackend, and a function which closes them.  Postgres is only
+// included when a local instance is set in the environment.
+func testBackends(t *testing.T) ([]testBackend, func()) {
+	// In-memory backend
+	memory := new(MemoryBackend)
+	if err := memory.Open(); err != nil {
+		t.Fatalf("Could not open memory database: %s", err.Error())
+	}
+
+	// Temporary sqlite backend
+	sqlite, sqliteCleanup := testSqliteBackend(t)
+
+	backends := []testBackend{
+		{"memory", memory},
+		{"sqlite", sqlite},
+	}
+
+	// Local postgres backend, if available
+	postgres, postgresCleanup := testPostgresBackend(t)
+	if postgres != nil {
+		backends = append(backends, testBackend{"postgres", postgres})
+	}
+
+	return backends, func() {
+		sqliteCleanup()
+		postgresCleanup()
+	}
+}
+
+// TestBackendConformance verifies that all database backends share the same semantics,
+// including unique constraints, joins, album artists and discs, CUE tracks, moves, path queries, libraries,
+// batches, limits, orphan purges, embedded art, album art and galleries, play statistics,
+// search, search query filters, and smart playlists
+func TestBackendConformance(t *testing.T) {
+	backends, cleanup := testBackends(t)
+	defer cleanup()
+
+	// Run each conformance test against each backend
+	tests := []func(*testing.T, string){
+		conformUnique,
+		conformNotFound,
+		conformJoins,
+		conformDiscs,
+		conformCueTracks,
+		conformMoves,
+		conformPaths,
+		conformLibraries,
+		conformBatches,
+		conformLimits,
+		conformOrphans,
+		conformEmbeddedArt,
+		conformAlbumArt,
+		conformPlaylists,
+		conformPlays,
+		conformQuery,
+		conformSearch,
+		conformSmartPlaylists,
+		conformStars,
+		conformUsers,
+	}
+	for _, b := range backends {
+		DB = b.db
+		for _, test := range tests {
+			test(t, b.name)
+		}
+	}
+}
+
+// conformUnique verifies that saving a duplicate item loads the existing item, instead of
+// creating a new one
+func conformUnique(t *testing.T, name string) {
+	artist := &Artist{Title: "UniqueArtist"}
+	duplicate := &Artist{Title: artist.Title}
+	if err := artist.Save(); err != nil {
+		t.Fatalf("[%s] Could not save artist: %s", name, err.Error())
+	}
+	if err := duplicate.Save(); err != nil {
+		t.Fatalf("[%s] Could not save duplicate artist: %s", name, err.Error())
+	}
+	if artist.ID == 0 || artist.ID != duplicate.ID {
+		t.Fatalf("[%s] Duplicate artist ID mismatch: %d != %d", name, artist.ID, duplicate.ID)
+	}
+
+	// Verify a duplicate does not overwrite existing fields
+	folder := &Folder{Title: "first", Path: "/unique"}
+	if err := folder.Save(); err != nil {
+		t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+	}
+	folder2 := &Folder{Title: "second", Path: "/unique"}
+	if err := folder2.Save(); err != nil {
+		t.Fatalf("[%s] Could not save duplicate folder: %s", name, err.Error())
+	}
+	if folder2.ID != folder.ID || folder2.Title != "first" {
+		t.Fatalf("[%s] Unexpected duplicate folder: %v", name, folder2)
+	}
+
+	// Verify IDs are never reused after a delete
+	if err := artist.Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete artist: %s", name, err.Error())
+	}
+	artist2 := &Artist{Title: artist.Title}
+	if err := artist2.Save(); err != nil {
+		t.Fatalf("[%s] Could not save artist: %s", name, err.Error())
+	}
+	if artist2.ID <= artist.ID {
+		t.Fatalf("[%s] Artist ID reused: %d <= %d", name, artist2.ID, artist.ID)
+	}
+
+	// Clean up
+	artist2.Delete()
+	folder.Delete()
+}
+
+// conformNotFound verifies that loading a missing item returns sql.ErrNoRows
+func conformNotFound(t *testing.T, name string) {
+	loaders := []func() error{
+		(&Album{ID: 99999999}).Load,
+		(&Art{ID: 99999999}).Load,
+		(&Artist{ID: 99999999}).Load,
+		(&Folder{ID: 99999999}).Load,
+		(&Play{ID: 99999999}).Load,
+		(&Playlist{ID: 99999999}).Load,
+		(&Rating{ID: 99999999}).Load,
+		(&Session{ID: 99999999}).Load,
+		(&Song{ID: 99999999}).Load,
+		(&Star{ID: 99999999}).Load,
+		(&User{ID: 99999999}).Load,
+		(&Artist{Title: "NotFound"}).Load,
+		(&Song{FileName: "/not/found"}).Load,
+	}
+
+	for i, load := range loaders {
+		if err := load(); err != sql.ErrNoRows {
+			t.Fatalf("[%s] Unexpected error for missing item %d: %v", name, i, err)
+		}
+	}
+}
+
+// conformFixture saves an artist, album, and a number of songs in the specified path,
+// returning the songs
+func conformFixture(t *testing.T, name string, title string, path string, count int) (*Artist, *Album, []*Song) {
+	artist := &Artist{Title: title}
+	if err := artist.Save(); err != nil {
+		t.Fatalf("[%s] Could not save artist: %s", name, err.Error())
+	}
+
+	album := &Album{ArtistID: artist.ID, Title: title}
+	if err := album.Save(); err != nil {
+		t.Fatalf("[%s] Could not save album: %s", name, err.Error())
+	}
+
+	songs := make([]*Song, 0)
+	for i := 0; i < count; i++ {
+		song := &Song{
+			AlbumID:  album.ID,
+			ArtistID: artist.ID,
+			FileName: path + "/" + string('a'+rune(i)) + ".mp3",
+			Title:    title + string('A'+rune(i)),
+			Track:    i + 1,
+		}
+		if err := song.Save(); err != nil {
+			t.Fatalf("[%s] Could not save song: %s", name, err.Error())
+		}
+
+		songs = append(songs, song)
+	}
+
+	return artist, album, songs
+}
+
+// conformCleanup removes all songs in the specified path, and purges orphaned artists and albums
+func conformCleanup(t *testing.T, name string, path string) {
+	songs, err := DB.SongsInPath(path)
+	if err != nil {
+		t.Fatalf("[%s] Could not load songs: %s", name, err.Error())
+	}
+
+	for _, s := range songs {
+		if err := s.Delete(); err != nil {
+			t.Fatalf("[%s] Could not delete song: %s", name, err.Error())
+		}
+	}
+
+	DB.PurgeOrphanAlbums()
+	DB.PurgeOrphanArtists()
+}
+
+// conformJoins verifies that songs and albums are joined with their artist and album titles,
+// and that items without a matching artist or album are omitted
+func conformJoins(t *testing.T, name string) {
+	artist, album, songs := conformFixture(t, name, "Join", "/join", 1)
+	defer conformCleanup(t, name, "/join")
+
+	// Verify song is loaded with artist and album titles
+	song := &Song{ID: songs[0].ID}
+	if err := song.Load(); err != nil {
+		t.Fatalf("[%s] Could not load song: %s", name, err.Error())
+	}
+	if song.Artist != artist.Title || song.Album != album.Title {
+		t.Fatalf("[%s] Unexpected song join: %s - %s", name, song.Artist, song.Album)
+	}
+
+	// Verify album is loaded with artist title
+	if albums, err := DB.AlbumsForArtist(artist.ID); err != nil || len(albums) != 1 || albums[0].Artist != artist.Title {
+		t.Fatalf("[%s] Unexpected albums for artist: %v (%v)", name, albums, err)
+	}
+
+	// Save a song with a missing album, and verify it is omitted
+	orphan := &Song{AlbumID: 99999999, ArtistID: artist.ID, FileName: "/join/orphan.mp3"}
+	if err := DB.SaveSong(orphan); err != sql.ErrNoRows {
+		t.Fatalf("[%s] Unexpected error saving song with missing album: %v", name, err)
+	}
+	if songs, err := DB.SongsInPath("/join"); err != nil || len(songs) != 1 {
+		t.Fatalf("[%s] Unexpected songs after orphan: %v (%v)", name, songs, err)
+	}
+
+	// The song row itself still exists, and is counted
+	if count, err := DB.CountSongs(); err != nil || count != 2 {
+		t.Fatalf("[%s] Unexpected song count: %d (%v)", name, count, err)
+	}
+	DB.DeleteSong(&Song{FileName: orphan.FileName})
+}
+
+// conformDiscs verifies that songs are joined with their album's artist, that album songs are
+// ordered by disc and then by track, and that album artists are not purged while their albums exist
+func conformDiscs(t *testing.T, name string) {
+	albumArtist := &Artist{Title: "Discs Various"}
+	if err := albumArtist.Save(); err != nil {
+		t.Fatalf("[%s] Could not save artist: %s", name, err.Error())
+	}
+	guest := &Artist{Title: "Discs Guest"}
+	if err := guest.Save(); err != nil {
+		t.Fatalf("[%s] Could not save artist: %s", name, err.Error())
+	}
+
+	album := &Album{ArtistID: albumArtist.ID, Title: "Discs"}
+	if err := album.Save(); err != nil {
+		t.Fatalf("[%s] Could not save album: %s", name, err.Error())
+	}
+
+	// Save songs out of order, across two discs
+	songs := []*Song{
+		{Title: "DiscsA", FileName: "/discs/a.mp3", Disc: 2, DiscTotal: 2, Track: 1, TrackTotal: 1},
+		{Title: "DiscsB", FileName: "/discs/b.mp3", Disc: 1, DiscTotal: 2, Track: 2, TrackTotal: 2},
+		{Title: "DiscsC", FileName: "/discs/c.mp3", Disc: 1, DiscTotal: 2, Track: 1, TrackTotal: 2},
+	}
+	for _, s := range songs {
+		s.ArtistID = guest.ID
+		s.AlbumID = album.ID
+		if err := s.Save(); err != nil {
+			t.Fatalf("[%s] Could not save song: %s", name, err.Error())
+		}
+	}
+
+	results, err := DB.SongsForAlbum(album.ID)
+	if err != nil {
+		t.Fatalf("[%s] Could not load album songs: %s", name, err.Error())
+	}
+	if len(results) != 3 || results[0].ID != songs[2].ID || results[1].ID != songs[1].ID || results[2].ID != songs[0].ID {
+		t.Fatalf("[%s] Unexpected album song order: %v", name, results)
+	}
+
+	for _, s := range results {
+		if s.Artist != "Discs Guest" || s.AlbumArtist != "Discs Various" || s.DiscTotal != 2 || s.TrackTotal == 0 {
+			t.Fatalf("[%s] Unexpected album song: %v", name, s)
+		}
+	}
+
+	// Album artists with no songs of their own must survive an orphan purge
+	if _, err := DB.PurgeOrphanArtists(); err != nil {
+		t.Fatalf("[%s] Could not purge orphan artists: %s", name, err.Error())
+	}
+	if err := (&Artist{ID: albumArtist.ID}).Load(); err != nil {
+		t.Fatalf("[%s] Album artist was purged: %v", name, err)
+	}
+
+	// Once the album is gone, the album artist is an orphan
+	conformCleanup(t, name, "/discs")
+	if err := (&Artist{ID: albumArtist.ID}).Load(); err != sql.ErrNoRows {
+		t.Fatalf("[%s] Album artist was not purged: %v", name, err)
+	}
+}
+
+// conformCueTracks verifies that CUE tracks sharing a single file are unique by their offset,
+// and are loaded and deleted by their file name and offset
+func conformCueTracks(t *testing.T, name string) {
+	_, album, songs := conformFixture(t, name, "Cue", "/cue", 1)
+	defer conformCleanup(t, name, "/cue")
+
+	// Save a second track from the fixture's file, and verify a duplicate is not created
+	for i := 0; i < 2; i++ {
+		track := &Song{
+			AlbumID:     album.ID,
+			ArtistID:    songs[0].ArtistID,
+			FileName:    songs[0].FileName,
+			StartOffset: 180000,
+			Title:       "CueB",
+			Track:       2,
+		}
+		if err := track.Save(); err != nil {
+			t.Fatalf("[%s] Could not save track: %s", name, err.Error())
+		}
+		if track.ID == songs[0].ID || track.Title != "CueB" {
+			t.Fatalf("[%s] Unexpected track: %v", name, track)
+		}
+	}
+	if results, err := DB.SongsInPath("/cue"); err != nil || len(results) != 2 {
+		t.Fatalf("[%s] Unexpected tracks: %v (%v)", name, results, err)
+	}
+
+	// Verify tracks are loaded and deleted by file name and offset
+	first := &Song{FileName: songs[0].FileName}
+	if err := first.Load(); err != nil || first.ID != songs[0].ID {
+		t.Fatalf("[%s] Unexpected first track: %v (%v)", name, first, err)
+	}
+
+	if err := DB.DeleteSong(&Song{FileName: songs[0].FileName, StartOffset: 180000}); err != nil {
+		t.Fatalf("[%s] Could not delete track: %s", name, err.Error())
+	}
+	if results, err := DB.SongsInPath("/cue"); err != nil || len(results) != 1 || results[0].ID != songs[0].ID {
+		t.Fatalf("[%s] Unexpected tracks after delete: %v (%v)", name, results, err)
+	}
+}
+
+// conformMoves verifies that moved songs, art, and folders keep their IDs, that items which only
+// share a prefix with the moved path are not moved, and that songs are found by their fingerprint
+func conformMoves(t *testing.T, name string) {
+	_, _, songs := conformFixture(t, name, "Move", "/move/album", 2)
+	defer conformCleanup(t, name, "/moved")
+	_, _, others := conformFixture(t, name, "MoveOther", "/move/album2", 1)
+	defer conformCleanup(t, name, "/move")
+
+	folder := &Folder{Title: "album", Path: "/move/album"}
+	if err := folder.Save(); err != nil {
+		t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+	}
+	defer folder.Delete()
+
+	art := &Art{FileName: "/move/album/cover.jpg"}
+	if err := art.Save(); err != nil {
+		t.Fatalf("[%s] Could not save art: %s", name, err.Error())
+	}
+	defer art.Delete()
+
+	songs[0].Fingerprint = "MoveFingerprint"
+	if err := songs[0].Update(); err != nil {
+		t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+	}
+
+	if err := DB.MovePath("/move/album", "/moved/Album (2014)"); err != nil {
+		t.Fatalf("[%s] Could not move path: %s", name, err.Error())
+	}
+
+	// Verify all items were moved, keeping their IDs
+	moved, err := DB.SongsInPath("/moved/Album (2014)/")
+	if err != nil || len(moved) != 2 || moved[0].ID != songs[0].ID || moved[0].FileName != "/moved/Album (2014)/a.mp3" {
+		t.Fatalf("[%s] Unexpected moved songs: %v (%v)", name, moved, err)
+	}
+	if err := art.Load(); err != nil || art.FileName != "/moved/Album (2014)/cover.jpg" {
+		t.Fatalf("[%s] Unexpected moved art: %v (%v)", name, art, err)
+	}
+	if err := folder.Load(); err != nil || folder.Path != "/moved/Album (2014)" {
+		t.Fatalf("[%s] Unexpected moved folder: %v (%v)", name, folder, err)
+	}
+
+	// Verify items which only share a prefix were not moved
+	if err := others[0].Load(); err != nil || others[0].FileName != "/move/album2/a.mp3" {
+		t.Fatalf("[%s] Unexpected other song: %v (%v)", name, others[0], err)
+	}
+
+	// Verify a single file may be moved
+	if err := DB.MovePath("/moved/Album (2014)/b.mp3", "/moved/Album (2014)/02 - b.mp3"); err != nil {
+		t.Fatalf("[%s] Could not move file: %s", name, err.Error())
+	}
+	if err := songs[1].Load(); err != nil || songs[1].FileName != "/moved/Album (2014)/02 - b.mp3" {
+		t.Fatalf("[%s] Unexpected moved file: %v (%v)", name, songs[1], err)
+	}
+
+	// Verify songs are found by their fingerprint, and the fingerprint is loaded with file information
+	if results, err := DB.SongsForFingerprint("MoveFingerprint"); err != nil || len(results) != 1 || results[0].ID != songs[0].ID {
+		t.Fatalf("[%s] Unexpected fingerprint songs: %v (%v)", name, results, err)
+	}
+	files, err := DB.SongFilesInPath("/moved/Album (2014)/a.mp3")
+	if err != nil || len(files) != 1 || files[0].Fingerprint != "MoveFingerprint" {
+		t.Fatalf("[%s] Unexpected song files: %v (%v)", name, files, err)
+	}
+
+	// Verify the folder may be updated with a new title, which is searched
+	folder.Title = "Album (2014)"
+	if err := folder.Update(); err != nil {
+		t.Fatalf("[%s] Could not update folder: %s", name, err.Error())
+	}
+	if folders, total, err := DB.SearchFolders(Query{Text: "2014"}, 0, -1); err != nil || total != 1 || folders[0].ID != folder.ID {
+		t.Fatalf("[%s] Unexpected folder search: %v (%v)", name, folders, err)
+	}
+}
+
+// conformPaths verifies that path queries match using the semantics of sqlite's LIKE operator
+func conformPaths(t *testing.T, name string) {
+	conformFixture(t, name, "Path", "/music/path", 2)
+	defer conformCleanup(t, name, "/music/path")
+	conformFixture(t, name, "Other", "/other", 1)
+	defer conformCleanup(t, name, "/other")
+
+	tests := []struct {
+		path    string
+		in      int
+		notIn   int
+		comment string
+	}{
+		{"/music/path", 2, 1, "exact prefix"},
+		{"/MUSIC/PATH", 2, 1, "case-insensitive prefix"},
+		{"/music/p_th", 2, 1, "single character wildcard"},
+		{"/music", 2, 1, "parent prefix"},
+		{"/nothing", 0, 3, "no matches"},
+	}
+
+	for _, test := range tests {
+		in, err := DB.SongsInPath(test.path)
+		if err != nil {
+			t.Fatalf("[%s] Could not load songs in path: %s", name, err.Error())
+		}
+		notIn, err := DB.SongsNotInPath(0, test.path)
+		if err != nil {
+			t.Fatalf("[%s] Could not load songs not in path: %s", name, err.Error())
+		}
+
+		if len(in) != test.in || len(notIn) != test.notIn {
+			t.Fatalf("[%s] Unexpected path results (%s): %d/%d != %d/%d", name, test.comment,
+				len(in), len(notIn), test.in, test.notIn)
+		}
+	}
+
+	// Verify folder and art path queries
+	folder := &Folder{Title: "path", Path: "/music/path"}
+	if err := folder.Save(); err != nil {
+		t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+	}
+	defer folder.Delete()
+	if folders, err := DB.FoldersInPath("/Music"); err != nil || len(folders) != 1 {
+		t.Fatalf("[%s] Unexpected folders in path: %v (%v)", name, folders, err)
+	}
+
+	art := &Art{FileName: "/music/path/cover.jpg"}
+	if err := art.Save(); err != nil {
+		t.Fatalf("[%s] Could not save art: %s", name, err.Error())
+	}
+	defer art.Delete()
+	if notIn, err := DB.ArtNotInPath(0, "/music"); err != nil || len(notIn) != 0 {
+		t.Fatalf("[%s] Unexpected art not in path: %v (%v)", name, notIn, err)
+	}
+
+	// Verify search is case-insensitive
+	if songs, _, err := DB.SearchSongs(Query{Text: "patha"}, 0, -1); err != nil || len(songs) != 1 {
+		t.Fatalf("[%s] Unexpected search results: %v (%v)", name, songs, err)
+	}
+}
+
+// conformLibraries verifies that libraries claim unowned media under their paths, that path
+// queries are scoped to a library, and that deleted libraries release their media
+func conformLibraries(t *testing.T, name string) {
+	conformFixture(t, name, "Library", "/library", 2)
+	defer conformCleanup(t, name, "/library")
+
+	folder := &Folder{Title: "library", Path: "/library"}
+	if err := folder.Save(); err != nil {
+		t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+	}
+	defer folder.Delete()
+
+	// Save a library, claiming the existing media under its path
+	lossless := &Library{Name: "lossless", Path: "/library"}
+	if err := lossless.Save(); err != nil {
+		t.Fatalf("[%s] Could not save library: %s", name, err.Error())
+	}
+	lossy := &Library{Name: "lossy", Path: "/lossy"}
+	if err := lossy.Save(); err != nil {
+		t.Fatalf("[%s] Could not save library: %s", name, err.Error())
+	}
+	defer lossy.Delete()
+
+	songs, err := DB.SongsInPath("/library")
+	if err != nil || len(songs) != 2 || songs[0].LibraryID != lossless.ID || songs[1].LibraryID != lossless.ID {
+		t.Fatalf("[%s] Unexpected library songs: %v (%v)", name, songs, err)
+	}
+	if err := folder.Load(); err != nil || folder.LibraryID != lossless.ID {
+		t.Fatalf("[%s] Unexpected library folder: %v (%v)", name, folder, err)
+	}
+
+	if libraries, err := DB.AllLibraries(); err != nil || len(libraries) != 2 || libraries[0].ID != lossless.ID {
+		t.Fatalf("[%s] Unexpected libraries: %v (%v)", name, libraries, err)
+	}
+
+	// Media belonging to another library is never outside this library's path
+	if notIn, err := DB.SongsNotInPath(lossy.ID, "/lossy"); err != nil || len(notIn) != 0 {
+		t.Fatalf("[%s] Unexpected songs not in library path: %v (%v)", name, notIn, err)
+	}
+	if notIn, err := DB.FoldersNotInPath(lossless.ID, "/library/moved"); err != nil || len(notIn) != 1 {
+		t.Fatalf("[%s] Unexpected folders not in library path: %v (%v)", name, notIn, err)
+	}
+
+	// Deleting a library releases its media, which may then be claimed by another library
+	if err := lossless.Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete library: %s", name, err.Error())
+	}
+	if err := lossless.Load(); err != sql.ErrNoRows {
+		t.Fatalf("[%s] Library was not deleted: %v", name, err)
+	}
+	if notIn, err := DB.SongsNotInPath(lossy.ID, "/lossy"); err != nil || len(notIn) != 2 {
+		t.Fatalf("[%s] Unexpected released songs: %v (%v)", name, notIn, err)
+	}
+
+	lossy.Path = "/library"
+	if err := lossy.Update(); err != nil {
+		t.Fatalf("[%s] Could not update library: %s", name, err.Error())
+	}
+	songs, err = DB.SongsInPath("/library")
+	if err != nil || len(songs) != 2 || songs[0].LibraryID != lossy.ID {
+		t.Fatalf("[%s] Unexpected claimed songs: %v (%v)", name, songs, err)
+	}
+}
+
+// conformBatches verifies that batches of songs are saved and updated together, and that
+// batches respect the unique file name constraint.  Also verifies that song file information
+// is loaded for change detection.
+func conformBatches(t *testing.T, name string) {
+	artist, album, existing := conformFixture(t, name, "Batch", "/batch", 1)
+	defer conformCleanup(t, name, "/batch")
+
+	// Save a batch, including a duplicate of an existing song
+	batch := SongSlice{
+		{AlbumID: album.ID, ArtistID: artist.ID, FileName: "/batch/x.mp3", Title: "BatchX"},
+		{AlbumID: album.ID, ArtistID: artist.ID, FileName: "/batch/y.mp3", Title: "BatchY"},
+		{AlbumID: album.ID, ArtistID: artist.ID, FileName: existing[0].FileName, Title: "BatchDuplicate"},
+	}
+	if err := batch.Save(); err != nil {
+		t.Fatalf("[%s] Could not save batch: %s", name, err.Error())
+	}
+
+	songs, err := DB.SongsInPath("/batch")
+	if err != nil || len(songs) != 3 {
+		t.Fatalf("[%s] Unexpected batch songs: %v (%v)", name, songs, err)
+	}
+	for _, s := range songs {
+		if s.Title == "BatchDuplicate" || s.Added == 0 {
+			t.Fatalf("[%s] Unexpected batch song: %v", name, s)
+		}
+	}
+
+	// Update all songs in a batch
+	for i := range songs {
+		songs[i].Title = "Updated" + songs[i].Title
+	}
+	if err := SongSlice(songs).Update(); err != nil {
+		t.Fatalf("[%s] Could not update batch: %s", name, err.Error())
+	}
+
+	songs, err = DB.SongsInPath("/batch")
+	if err != nil || len(songs) != 3 {
+		t.Fatalf("[%s] Unexpected updated batch songs: %v (%v)", name, songs, err)
+	}
+	for _, s := range songs {
+		if s.Title[:7] != "Updated" {
+			t.Fatalf("[%s] Song was not updated: %v", name, s)
+		}
+	}
+
+	// Verify file information is loaded for all songs in the batch
+	files, err := DB.SongFilesInPath("/batch")
+	if err != nil || len(files) != 3 {
+		t.Fatalf("[%s] Unexpected song files: %v (%v)", name, files, err)
+	}
+}
+
+// conformLimits verifies that limit queries use an offset and count, in ID order
+func conformLimits(t *testing.T, name string) {
+	_, album, songs := conformFixture(t, name, "Limit", "/limit", 3)
+	defer conformCleanup(t, name, "/limit")
+
+	// Update the first song and album, which may move their rows in storage, so that only an
+	// explicit order keeps them first
+	songs[0].Title = "LimitZ"
+	if err := songs[0].Update(); err != nil {
+		t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+	}
+	album.Year = 2000
+	if err := album.Update(); err != nil {
+		t.Fatalf("[%s] Could not update album: %s", name, err.Error())
+	}
+
+	folders := make([]*Folder, 0)
+	for _, title := range []string{"a", "b"} {
+		folder := &Folder{Title: title, Path: "/limit/" + title}
+		if err := folder.Save(); err != nil {
+			t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+		}
+		defer folder.Delete()
+
+		folders = append(folders, folder)
+	}
+	folders[0].Title = "z"
+	if err := folders[0].Update(); err != nil {
+		t.Fatalf("[%s] Could not update folder: %s", name, err.Error())
+	}
+
+	// Page through each table one item at a time, and verify each page follows the last
+	pages := []struct {
+		table string
+		page  func(int) ([]int, error)
+	}{
+		{"artists", func(offset int) ([]int, error) {
+			artists, err := DB.LimitArtists(offset, 1)
+			ids := make([]int, 0)
+			for _, a := range artists {
+				ids = append(ids, a.ID)
+			}
+			return ids, err
+		}},
+		{"albums", func(offset int) ([]int, error) {
+			albums, err := DB.LimitAlbums(offset, 1)
+			ids := make([]int, 0)
+			for _, a := range albums {
+				ids = append(ids, a.ID)
+			}
+			return ids, err
+		}},
+		{"folders", func(offset int) ([]int, error) {
+			folders, err := DB.LimitFolders(offset, 1)
+			ids := make([]int, 0)
+			for _, f := range folders {
+				ids = append(ids, f.ID)
+			}
+			return ids, err
+		}},
+		{"songs", func(offset int) ([]int, error) {
+			songs, err := DB.LimitSongs(offset, 1)
+			ids := make([]int, 0)
+			for _, s := range songs {
+				ids = append(ids, s.ID)
+			}
+			return ids, err
+		}},
+	}
+
+	for _, p := range pages {
+		last := 0
+		for offset := 0; ; offset++ {
+			ids, err := p.page(offset)
+			if err != nil {
+				t.Fatalf("[%s] Could not limit %s: %s", name, p.table, err.Error())
+			}
+			if len(ids) == 0 {
+				break
+			}
+
+			if len(ids) != 1 || ids[0] <= last {
+				t.Fatalf("[%s] Unexpected %s page at offset %d: %v after ID %d", name, p.table, offset, ids, last)
+			}
+			last = ids[0]
+		}
+	}
+
+	tests := []struct {
+		offset int
+		count  int
+		ids    []int
+	}{
+		{0, 10, []int{songs[0].ID, songs[1].ID, songs[2].ID}},
+		{1, 1, []int{songs[1].ID}},
+		{2, 10, []int{songs[2].ID}},
+		{5, 10, []int{}},
+	}
+
+	for _, test := range tests {
+		limit, err := DB.LimitSongs(test.offset, test.count)
+		if err != nil {
+			t.Fatalf("[%s] Could not limit songs: %s", name, err.Error())
+		}
+
+		if len(limit) != len(test.ids) {
+			t.Fatalf("[%s] Unexpected limit length: %d != %d", name, len(limit), len(test.ids))
+		}
+		for i, s := range limit {
+			if s.ID != test.ids[i] {
+				t.Fatalf("[%s] Unexpected limit song ID: %d != %d", name, s.ID, test.ids[i])
+			}
+		}
+	}
+
+	// Verify random songs are limited
+	if random, err := DB.RandomSongs(2); err != nil || len(random) != 2 {
+		t.Fatalf("[%s] Unexpected random songs: %v (%v)", name, random, err)
+	}
+}
+
+// conformOrphans verifies that only artists and albums without songs are purged
+func conformOrphans(t *testing.T, name string) {
+	conformFixture(t, name, "Orphan", "/orphan", 1)
+
+	// Add an artist and album without songs
+	lonely := &Artist{Title: "Lonely"}
+	if err := lonely.Save(); err != nil {
+		t.Fatalf("[%s] Could not save artist: %s", name, err.Error())
+	}
+	if err := (&Album{ArtistID: lonely.ID, Title: "Lonely"}).Save(); err != nil {
+		t.Fatalf("[%s] Could not save album: %s", name, err.Error())
+	}
+
+	// Verify only the lonely artist and album are purged
+	if count, err := DB.PurgeOrphanAlbums(); err != nil || count != 1 {
+		t.Fatalf("[%s] Unexpected orphan album count: %d (%v)", name, count, err)
+	}
+	if count, err := DB.PurgeOrphanArtists(); err != nil || count != 1 {
+		t.Fatalf("[%s] Unexpected orphan artist count: %d (%v)", name, count, err)
+	}
+
+	// Remove songs, and verify remaining artist and album are purged
+	conformCleanup(t, name, "/orphan")
+	if count, err := DB.CountArtists(); err != nil || count != 0 {
+		t.Fatalf("[%s] Unexpected artist count: %d (%v)", name, count, err)
+	}
+	if count, err := DB.CountAlbums(); err != nil || count != 0 {
+		t.Fatalf("[%s] Unexpected album count: %d (%v)", name, count, err)
+	}
+}
+
+// conformEmbeddedArt verifies that embedded art is not orphaned by its path, and is only orphaned
+// once no songs use it
+func conformEmbeddedArt(t *testing.T, name string) {
+	_, _, songs := conformFixture(t, name, "Embedded", "/embedded", 1)
+
+	file := &Art{FileName: "/other/cover.jpg", FileSize: 1}
+	if err := file.Save(); err != nil {
+		t.Fatalf("[%s] Could not save art: %s", name, err.Error())
+	}
+	defer file.Delete()
+
+	embedded := &Art{FileName: "/store/ab/abcdef.jpg", FileSize: 1, Source: ArtSourceEmbedded}
+	if err := embedded.Save(); err != nil {
+		t.Fatalf("[%s] Could not save art: %s", name, err.Error())
+	}
+	defer embedded.Delete()
+
+	songs[0].ArtID = embedded.ID
+	if err := songs[0].Update(); err != nil {
+		t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+	}
+
+	// Verify only art files outside a library's path are returned
+	art, err := DB.ArtNotInPath(0, "/embedded")
+	if err != nil || len(art) != 1 || art[0].ID != file.ID {
+		t.Fatalf("[%s] Unexpected art not in path: %v (%v)", name, art, err)
+	}
+
+	// Verify embedded art is only orphaned once its song is removed
+	if art, err := DB.OrphanEmbeddedArt(); err != nil || len(art) != 0 {
+		t.Fatalf("[%s] Unexpected orphan embedded art: %v (%v)", name, art, err)
+	}
+
+	conformCleanup(t, name, "/embedded")
+	art, err = DB.OrphanEmbeddedArt()
+	if err != nil || len(art) != 1 || art[0].ID != embedded.ID || art[0].Source != ArtSourceEmbedded {
+		t.Fatalf("[%s] Unexpected orphan embedded art: %v (%v)", name, art, err)
+	}
+}
+
+// conformAlbumArt verifies that album art and galleries are stored in order, and are cleared
+// when their art is removed
+func conformAlbumArt(t *testing.T, name string) {
+	_, album, _ := conformFixture(t, name, "Gallery", "/gallery", 1)
+
+	art := make([]*Art, 0)
+	for _, f := range []string{"cover.jpg", "back.jpg", "cd.jpg"} {
+		a := &Art{FileName: "/gallery/" + f, FileSize: 1}
+		if err := a.Save(); err != nil {
+			t.Fatalf("[%s] Could not save art: %s", name, err.Error())
+		}
+		defer a.Delete()
+
+		art = append(art, a)
+	}
+
+	// Set the album's cover, and its gallery in reverse order
+	album.ArtID = art[0].ID
+	if err := album.Update(); err != nil {
+		t.Fatalf("[%s] Could not update album: %s", name, err.Error())
+	}
+	if err := album.SetGallery([]int{art[2].ID, art[1].ID}); err != nil {
+		t.Fatalf("[%s] Could not set gallery: %s", name, err.Error())
+	}
+
+	loaded := &Album{ID: album.ID}
+	if err := loaded.Load(); err != nil || loaded.ArtID != art[0].ID {
+		t.Fatalf("[%s] Unexpected album: %v (%v)", name, loaded, err)
+	}
+	gallery, err := loaded.Gallery()
+	if err != nil || len(gallery) != 2 || gallery[0].ID != art[2].ID || gallery[1].ID != art[1].ID {
+		t.Fatalf("[%s] Unexpected gallery: %v (%v)", name, gallery, err)
+	}
+
+	// Verify removed art is cleared from the album and its gallery
+	if err := art[0].Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete art: %s", name, err.Error())
+	}
+	if err := art[2].Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete art: %s", name, err.Error())
+	}
+	if err := loaded.Load(); err != nil || loaded.ArtID != 0 {
+		t.Fatalf("[%s] Unexpected album: %v (%v)", name, loaded, err)
+	}
+	gallery, err = loaded.Gallery()
+	if err != nil || len(gallery) != 1 || gallery[0].ID != art[1].ID {
+		t.Fatalf("[%s] Unexpected gallery: %v (%v)", name, gallery, err)
+	}
+
+	// Verify the gallery is removed along with its album
+	conformCleanup(t, name, "/gallery")
+	if gallery, err := DB.GalleryForAlbum(album.ID); err != nil || len(gallery) != 0 {
+		t.Fatalf("[%s] Unexpected gallery: %v (%v)", name, gallery, err)
+	}
+}
+
+// conformPlaylists verifies playlist visibility, ordering, and entry manipulation
+func conformPlaylists(t *testing.T, name string) {
+	_, _, songs := conformFixture(t, name, "Playlist", "/playlist", 2)
+	defer conformCleanup(t, name, "/playlist")
+
+	// Create playlists for two users
+	mine, err := NewPlaylist(1, "b", false)
+	if err != nil {
+		t.Fatalf("[%s] Could not create playlist: %s", name, err.Error())
+	}
+	defer mine.Delete()
+	public, err := NewPlaylist(2, "a", true)
+	if err != nil {
+		t.Fatalf("[%s] Could not create playlist: %s", name, err.Error())
+	}
+	defer public.Delete()
+	private, err := NewPlaylist(2, "c", false)
+	if err != nil {
+		t.Fatalf("[%s] Could not create playlist: %s", name, err.Error())
+	}
+	defer private.Delete()
+
+	// Verify only owned and public playlists are visible, ordered by title
+	playlists, err := DB.PlaylistsForUser(1)
+	if err != nil {
+		t.Fatalf("[%s] Could not load playlists: %s", name, err.Error())
+	}
+	if len(playlists) != 2 || playlists[0].ID != public.ID || playlists[1].ID != mine.ID {
+		t.Fatalf("[%s] Unexpected playlists: %v", name, playlists)
+	}
+
+	// Verify renaming to an existing title is ignored
+	renamed := &Playlist{ID: private.ID, UserID: 2, Title: "a"}
+	if err := renamed.Update(); err != nil {
+		t.Fatalf("[%s] Could not update playlist: %s", name, err.Error())
+	}
+	if err := renamed.Load(); err != nil || renamed.Title != "c" {
+		t.Fatalf("[%s] Unexpected playlist after conflicting update: %v (%v)", name, renamed, err)
+	}
+
+	// Append, including a missing song, and verify only existing songs are returned
+	if err := mine.Append([]int{songs[1].ID, 99999999, songs[0].ID}); err != nil {
+		t.Fatalf("[%s] Could not append to playlist: %s", name, err.Error())
+	}
+	playlistSongs, err := mine.Songs()
+	if err != nil {
+		t.Fatalf("[%s] Could not load playlist songs: %s", name, err.Error())
+	}
+	if len(playlistSongs) != 2 || playlistSongs[0].ID != songs[1].ID || playlistSongs[1].Artist != "Playlist" {
+		t.Fatalf("[%s] Unexpected playlist songs: %v", name, playlistSongs)
+	}
+
+	// Remove the missing song, and verify positions are contiguous
+	entries, err := mine.Entries()
+	if err != nil {
+		t.Fatalf("[%s] Could not load playlist entries: %s", name, err.Error())
+	}
+	if err := mine.Remove([]int{entries[1].ID}); err != nil {
+		t.Fatalf("[%s] Could not remove playlist entry: %s", name, err.Error())
+	}
+	entries, err = mine.Entries()
+	if err != nil {
+		t.Fatalf("[%s] Could not load playlist entries: %s", name, err.Error())
+	}
+	if len(entries) != 2 || entries[0].Position != 0 || entries[1].Position != 1 {
+		t.Fatalf("[%s] Unexpected playlist entries: %v", name, entries)
+	}
+
+	// Reverse the playlist, and verify an incomplete reorder is rejected
+	if err := mine.Reorder([]int{entries[1].ID, entries[0].ID}); err != nil {
+		t.Fatalf("[%s] Could not reorder playlist: %s", name, err.Error())
+	}
+	if err := mine.Reorder([]int{entries[0].ID}); err != ErrPlaylistEntries {
+		t.Fatalf("[%s] Incomplete reorder did not fail: %v", name, err)
+	}
+	if reordered, err := mine.Entries(); err != nil || reordered[0].ID != entries[1].ID {
+		t.Fatalf("[%s] Unexpected reordered entries: %v (%v)", name, reordered, err)
+	}
+	if err := mine.CheckOrder([]int{entries[0].ID, entries[0].ID}); err != ErrPlaylistEntries {
+		t.Fatalf("[%s] Duplicate order was not rejected: %v", name, err)
+	}
+	if err := mine.CheckOrder([]int{entries[0].ID, entries[1].ID}); err != nil {
+		t.Fatalf("[%s] Complete order was rejected: %s", name, err.Error())
+	}
+
+	// Delete a song which appears twice by its file name, and verify its entries are removed
+	// while the remaining positions stay contiguous
+	if err := mine.Append([]int{songs[0].ID}); err != nil {
+		t.Fatalf("[%s] Could not append to playlist: %s", name, err.Error())
+	}
+	if err := (&Song{FileName: songs[0].FileName}).Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete song: %s", name, err.Error())
+	}
+	entries, err = mine.Entries()
+	if err != nil {
+		t.Fatalf("[%s] Could not load playlist entries: %s", name, err.Error())
+	}
+	if len(entries) != 1 || entries[0].SongID != songs[1].ID || entries[0].Position != 0 {
+		t.Fatalf("[%s] Unexpected playlist entries after song delete: %v", name, entries)
+	}
+
+	// Verify entries are removed with the playlist
+	if err := mine.Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete playlist: %s", name, err.Error())
+	}
+	if entries, err := DB.EntriesForPlaylist(mine.ID); err != nil || len(entries) != 0 {
+		t.Fatalf("[%s] Unexpected entries after delete: %v (%v)", name, entries, err)
+	}
+}
+
+// conformPlays verifies that plays are ordered from most to least recent, and that songs
+// and albums are loaded with their play statistics
+func conformPlays(t *testing.T, name string) {
+	_, album, songs := conformFixture(t, name, "Plays", "/plays", 2)
+	defer conformCleanup(t, name, "/plays")
+
+	// Play the first song twice, and the second song once
+	plays := []*Play{
+		{UserID: 1, SongID: songs[0].ID, Timestamp: 100},
+		{UserID: 1, SongID: songs[1].ID, Timestamp: 200},
+		{UserID: 1, SongID: songs[0].ID, Timestamp: 300},
+		{UserID: 2, SongID: songs[1].ID, Timestamp: 400},
+	}
+	for _, p := range plays {
+		if err := p.Save(); err != nil {
+			t.Fatalf("[%s] Could not save play: %s", name, err.Error())
+		}
+		defer p.Delete()
+	}
+
+	// Verify a duplicate play loads the existing play
+	duplicate := &Play{UserID: 1, SongID: songs[0].ID, Timestamp: 100}
+	if err := duplicate.Save(); err != nil || duplicate.ID != plays[0].ID {
+		t.Fatalf("[%s] Unexpected duplicate play: %v (%v)", name, duplicate, err)
+	}
+
+	// Verify history is ordered from most to least recent
+	history, err := DB.PlaysForUser(1)
+	if err != nil || len(history) != 3 || history[0].ID != plays[2].ID || history[2].ID != plays[0].ID {
+		t.Fatalf("[%s] Unexpected history: %v (%v)", name, history, err)
+	}
+	if history, err := DB.LimitPlaysForUser(1, 1, 1); err != nil || len(history) != 1 || history[0].ID != plays[1].ID {
+		t.Fatalf("[%s] Unexpected limited history: %v (%v)", name, history, err)
+	}
+
+	// Verify song play count and last played time
+	song := &Song{ID: songs[0].ID}
+	if err := song.Load(); err != nil || song.PlayCount != 2 || song.LastPlayed != 300 {
+		t.Fatalf("[%s] Unexpected song play statistics: %d, %d (%v)", name, song.PlayCount, song.LastPlayed, err)
+	}
+
+	// Verify song update does not store play statistics
+	song.Title = "Played"
+	if err := song.Update(); err != nil {
+		t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+	}
+	if songs, err := DB.SongsForAlbum(album.ID); err != nil || len(songs) != 2 || songs[0].PlayCount+songs[1].PlayCount != 4 {
+		t.Fatalf("[%s] Unexpected songs after update: %v (%v)", name, songs, err)
+	}
+
+	// Verify album play count includes all users
+	if err := album.Load(); err != nil || album.PlayCount != 4 {
+		t.Fatalf("[%s] Unexpected album play count: %d (%v)", name, album.PlayCount, err)
+	}
+}
+
+// conformQuery verifies that search query filters match songs by field, and match artists,
+// albums, and folders which contain matching songs
+func conformQuery(t *testing.T, name string) {
+	artist, album, _ := conformFixture(t, name, "Query", "/query", 0)
+	defer conformCleanup(t, name, "/query")
+
+	folder := &Folder{Title: "Query", Path: "/query"}
+	if err := folder.Save(); err != nil {
+		t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+	}
+	defer folder.Delete()
+
+	// Save songs with distinct fields to filter
+	songs := []*Song{
+		{Title: "QueryA", FileName: "/query/a.flac", Genre: "Ambient", Year: 1998, Bitrate: 320, FileTypeID: FLAC},
+		{Title: "QueryB", FileName: "/query/b.mp3", Genre: "Ambient Techno", Year: 2002, Bitrate: 192, FileTypeID: MP3},
+		{Title: "QueryC", FileName: "/query/c.mp3", Genre: "Electronic", Year: 2005, Bitrate: 128, FileTypeID: MP3},
+	}
+	for _, s := range songs {
+		s.ArtistID = artist.ID
+		s.AlbumID = album.ID
+		s.FolderID = folder.ID
+		if err := s.Save(); err != nil {
+			t.Fatalf("[%s] Could not save song: %s", name, err.Error())
+		}
+	}
+
+	tests := []struct {
+		query string
+		ids   []int
+	}{
+		{"genre:ambient", []int{songs[0].ID, songs[1].ID}},
+		{"genre:AMBIENT year:>=2000", []int{songs[1].ID}},
+		{"year:<2005 bitrate:<=192", []int{songs[1].ID}},
+		{"codec:mp3", []int{songs[1].ID, songs[2].ID}},
+		{`artist:"query" album:que title:querya`, []int{songs[0].ID}},
+		{"year:1990", []int{}},
+		// Free text and filters must both match
+		{"queryc codec:mp3", []int{songs[2].ID}},
+		{"queryc codec:flac", []int{}},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.query)
+		if err != nil {
+			t.Fatalf("[%s] Could not parse query %q: %s", name, test.query, err.Error())
+		}
+
+		results, total, err := DB.SearchSongs(q, 0, -1)
+		if err != nil {
+			t.Fatalf("[%s] Could not search songs: %s", name, err.Error())
+		}
+
+		ids := make([]int, 0)
+		for _, s := range results {
+			ids = append(ids, s.ID)
+		}
+		if total != int64(len(test.ids)) || !reflect.DeepEqual(ids, test.ids) {
+			t.Fatalf("[%s] Unexpected query results for %q: %v (%d)", name, test.query, ids, total)
+		}
+	}
+
+	// Verify artists, albums, and folders are matched by their songs
+	match, err := ParseQuery("genre:ambient year:>=2000")
+	if err != nil {
+		t.Fatalf("[%s] Could not parse query: %s", name, err.Error())
+	}
+	if artists, total, err := DB.SearchArtists(match, 0, -1); err != nil || total != 1 || artists[0].ID != artist.ID {
+		t.Fatalf("[%s] Unexpected artist query: %v (%v)", name, artists, err)
+	}
+	if albums, total, err := DB.SearchAlbums(match, 0, -1); err != nil || total != 1 || albums[0].ID != album.ID {
+		t.Fatalf("[%s] Unexpected album query: %v (%v)", name, albums, err)
+	}
+	if folders, total, err := DB.SearchFolders(match, 0, -1); err != nil || total != 1 || folders[0].ID != folder.ID {
+		t.Fatalf("[%s] Unexpected folder query: %v (%v)", name, folders, err)
+	}
+
+	none, err := ParseQuery("genre:ambient year:>=2010")
+	if err != nil {
+		t.Fatalf("[%s] Could not parse query: %s", name, err.Error())
+	}
+	if albums, total, err := DB.SearchAlbums(none, 0, -1); err != nil || total != 0 || len(albums) != 0 {
+		t.Fatalf("[%s] Unexpected album query: %v (%v)", name, albums, err)
+	}
+}
+
+// conformSearch verifies that search matches term prefixes across all indexed fields, ranks
+// title matches first, applies offset and limit, and is kept up to date as items change
+func conformSearch(t *testing.T, name string) {
+	artist, album, songs := conformFixture(t, name, "Search", "/search", 3)
+	defer conformCleanup(t, name, "/search")
+
+	// Match one song by title, and another only by comment
+	songs[0].Title = "Ranked"
+	songs[1].Comment = "ranked"
+	songs[2].Genre = "Ambient"
+	for _, s := range songs {
+		if err := s.Update(); err != nil {
+			t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+		}
+	}
+
+	tests := []struct {
+		query  string
+		offset int
+		count  int
+		total  int64
+		ids    []int
+	}{
+		// Title matches rank above comment matches
+		{"ranked", 0, -1, 2, []int{songs[0].ID, songs[1].ID}},
+		{"RAN", 0, -1, 2, []int{songs[0].ID, songs[1].ID}},
+		{"ran", 1, 1, 2, []int{songs[1].ID}},
+		{"ran", 5, 10, 2, []int{}},
+		// All terms must match, in any field
+		{"amb searchc", 0, -1, 1, []int{songs[2].ID}},
+		{"amb ranked", 0, -1, 0, []int{}},
+		// Artist and album titles are indexed, below song titles
+		{"sear", 0, -1, 3, []int{songs[1].ID, songs[2].ID, songs[0].ID}},
+		// Queries with no terms match nothing
+		{"", 0, -1, 0, []int{}},
+		{"*\"", 0, -1, 0, []int{}},
+	}
+
+	for _, test := range tests {
+		results, total, err := DB.SearchSongs(Query{Text: test.query}, test.offset, test.count)
+		if err != nil {
+			t.Fatalf("[%s] Could not search songs: %s", name, err.Error())
+		}
+
+		ids := make([]int, 0)
+		for _, s := range results {
+			ids = append(ids, s.ID)
+		}
+		if total != test.total || !reflect.DeepEqual(ids, test.ids) {
+			t.Fatalf("[%s] Unexpected search results for %q: %v (%d)", name, test.query, ids, total)
+		}
+	}
+
+	// Verify search results are joined with artist and album titles
+	if results, _, err := DB.SearchSongs(Query{Text: "ranked"}, 0, 1); err != nil || len(results) != 1 ||
+		results[0].Artist != artist.Title || results[0].Album != album.Title {
+		t.Fatalf("[%s] Unexpected search join: %v (%v)", name, results, err)
+	}
+
+	// Verify artists, albums, and folders are searched by title
+	if artists, total, err := DB.SearchArtists(Query{Text: "sear"}, 0, -1); err != nil || total != 1 || len(artists) != 1 {
+		t.Fatalf("[%s] Unexpected artist search: %v (%v)", name, artists, err)
+	}
+	if albums, total, err := DB.SearchAlbums(Query{Text: "search"}, 0, -1); err != nil || total != 1 ||
+		len(albums) != 1 || albums[0].Artist != artist.Title {
+		t.Fatalf("[%s] Unexpected album search: %v (%v)", name, albums, err)
+	}
+
+	folder := &Folder{Title: "Search Folder", Path: "/search"}
+	if err := folder.Save(); err != nil {
+		t.Fatalf("[%s] Could not save folder: %s", name, err.Error())
+	}
+	if folders, total, err := DB.SearchFolders(Query{Text: "fold"}, 0, -1); err != nil || total != 1 || len(folders) != 1 {
+		t.Fatalf("[%s] Unexpected folder search: %v (%v)", name, folders, err)
+	}
+	if err := folder.Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete folder: %s", name, err.Error())
+	}
+	if folders, total, err := DB.SearchFolders(Query{Text: "fold"}, 0, -1); err != nil || total != 0 || len(folders) != 0 {
+		t.Fatalf("[%s] Unexpected folder search after delete: %v (%v)", name, folders, err)
+	}
+
+	// Verify updated and deleted songs are reflected in search
+	songs[0].Title = "Renamed"
+	if err := songs[0].Update(); err != nil {
+		t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+	}
+	if results, total, err := DB.SearchSongs(Query{Text: "renamed"}, 0, -1); err != nil || total != 1 || len(results) != 1 {
+		t.Fatalf("[%s] Unexpected search after update: %v (%v)", name, results, err)
+	}
+	if err := songs[1].Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete song: %s", name, err.Error())
+	}
+	if results, total, err := DB.SearchSongs(Query{Text: "ranked"}, 0, -1); err != nil || total != 0 || len(results) != 0 {
+		t.Fatalf("[%s] Unexpected search after delete: %v (%v)", name, results, err)
+	}
+
+	// Verify diacritics are ignored, in titles and in queries
+	songs[2].Title = "Café"
+	if err := songs[2].Update(); err != nil {
+		t.Fatalf("[%s] Could not update song: %s", name, err.Error())
+	}
+	for _, query := range []string{"cafe", "CAFÉ", "caf"} {
+		if results, total, err := DB.SearchSongs(Query{Text: query}, 0, -1); err != nil || total != 1 || len(results) != 1 {
+			t.Fatalf("[%s] Unexpected search ignoring diacritics for %q: %v (%v)", name, query, results, err)
+		}
+	}
+	if artists, total, err := DB.SearchArtists(Query{Text: "séarch"}, 0, -1); err != nil || total != 1 || len(artists) != 1 {
+		t.Fatalf("[%s] Unexpected artist search ignoring diacritics: %v (%v)", name, artists, err)
+	}
+}
+
+// conformSmartPlaylists verifies smart playlist validation and visibility, and that smart
+// playlists are evaluated using their owner's ratings, sort order, and limit
+func conformSmartPlaylists(t *testing.T, name string) {
+	artist, album, _ := conformFixture(t, name, "Smart", "/smart", 0)
+	defer conformCleanup(t, name, "/smart")
+
+	// Save songs with distinct years to sort
+	songs := []*Song{
+		{Title: "smartA", FileName: "/smart/a.mp3", Genre: "Smartwave", Year: 1990, Length: 100},
+		{Title: "SmartB", FileName: "/smart/b.mp3", Genre: "Smartwave", Year: 2000, Length: 200},
+		{Title: "SmartC", FileName: "/smart/c.mp3", Genre: "Smartwave", Year: 2010, Length: 300},
+	}
+	for _, s := range songs {
+		s.ArtistID = artist.ID
+		s.AlbumID = album.ID
+		if err := s.Save(); err != nil {
+			t.Fatalf("[%s] Could not save song: %s", name, err.Error())
+		}
+	}
+
+	// Rate songs differently for two users
+	ratings := []struct {
+		userID int
+		song   *Song
+		rating int
+	}{
+		{1, songs[0], 5},
+		{1, songs[2], 4},
+		{2, songs[1], 5},
+	}
+	for _, r := range ratings {
+		rating, err := NewRating(r.userID, "song", r.song.ID, r.rating)
+		if err != nil {
+			t.Fatalf("[%s] Could not rate song: %s", name, err.Error())
+		}
+		defer rating.Delete()
+	}
+
+	// Verify invalid rules are rejected
+	if _, err := NewSmartPlaylist(1, "invalid", false, "codec:foo", "", 0); err == nil {
+		t.Fatalf("[%s] Invalid smart playlist query did not fail", name)
+	}
+	if _, err := NewSmartPlaylist(1, "invalid", false, "", "foo", 0); err != ErrSmartPlaylistSort {
+		t.Fatalf("[%s] Invalid smart playlist sort did not fail: %v", name, err)
+	}
+
+	// Create smart playlists for two users, using the same rules
+	mine, err := NewSmartPlaylist(1, "b", false, "genre:smartwave rating:>=4", "-year", 0)
+	if err != nil {
+		t.Fatalf("[%s] Could not create smart playlist: %s", name, err.Error())
+	}
+	defer mine.Delete()
+	theirs, err := NewSmartPlaylist(2, "a", true, "genre:smartwave rating:>=4", "-year", 0)
+	if err != nil {
+		t.Fatalf("[%s] Could not create smart playlist: %s", name, err.Error())
+	}
+	defer theirs.Delete()
+	private, err := NewSmartPlaylist(2, "c", false, "", "", 0)
+	if err != nil {
+		t.Fatalf("[%s] Could not create smart playlist: %s", name, err.Error())
+	}
+	defer private.Delete()
+
+	// Verify only owned and public smart playlists are visible, ordered by title
+	playlists, err := DB.SmartPlaylistsForUser(1)
+	if err != nil {
+		t.Fatalf("[%s] Could not load smart playlists: %s", name, err.Error())
+	}
+	if len(playlists) != 2 || playlists[0].ID != theirs.ID || playlists[1].ID != mine.ID {
+		t.Fatalf("[%s] Unexpected smart playlists: %v", name, playlists)
+	}
+
+	// Verify each smart playlist matches its owner's ratings, in its sort order
+	if results, err := mine.Songs(); err != nil || len(results) != 2 || results[0].ID != songs[2].ID || results[1].ID != songs[0].ID {
+		t.Fatalf("[%s] Unexpected smart playlist songs: %v (%v)", name, results, err)
+	}
+	if results, err := theirs.Songs(); err != nil || len(results) != 1 || results[0].ID != songs[1].ID {
+		t.Fatalf("[%s] Unexpected smart playlist songs: %v (%v)", name, results, err)
+	}
+
+	// Update the rules, and verify the limit is applied after sorting
+	mine.Query = "genre:smartwave"
+	mine.Sort = "year"
+	mine.Limit = 2
+	if err := mine.Update(); err != nil {
+		t.Fatalf("[%s] Could not update smart playlist: %s", name, err.Error())
+	}
+	updated := &SmartPlaylist{ID: mine.ID}
+	if err := updated.Load(); err != nil || updated.Query != mine.Query || updated.Sort != "year" || updated.Limit != 2 {
+		t.Fatalf("[%s] Unexpected smart playlist after update: %v (%v)", name, updated, err)
+	}
+	if results, err := updated.Songs(); err != nil || len(results) != 2 || results[0].ID != songs[0].ID || results[1].ID != songs[1].ID {
+		t.Fatalf("[%s] Unexpected smart playlist songs: %v (%v)", name, results, err)
+	}
+	if count, length, err := updated.Count(); err != nil || count != 2 || length != 300 {
+		t.Fatalf("[%s] Unexpected smart playlist count: %d, %d (%v)", name, count, length, err)
+	}
+
+	// Verify text is sorted ignoring case, and that equal songs remain in relevance order
+	sorts := []struct {
+		query string
+		sort  string
+		limit int
+		ids   []int
+	}{
+		{"genre:smartwave", "-title", 0, []int{songs[2].ID, songs[1].ID, songs[0].ID}},
+		{"genre:smartwave", "artist", 0, []int{songs[0].ID, songs[1].ID, songs[2].ID}},
+		{"smartb genre:smartwave", "", 0, []int{songs[1].ID}},
+		{"genre:smartwave", "-year", 1, []int{songs[2].ID}},
+	}
+	for _, test := range sorts {
+		p := &SmartPlaylist{UserID: 1, Query: test.query, Sort: test.sort, Limit: test.limit}
+		results, err := p.Songs()
+		if err != nil {
+			t.Fatalf("[%s] Could not evaluate smart playlist: %s", name, err.Error())
+		}
+
+		ids := make([]int, 0)
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		if !reflect.DeepEqual(ids, test.ids) {
+			t.Fatalf("[%s] Unexpected smart playlist songs for %q %q: %v", name, test.query, test.sort, ids)
+		}
+	}
+
+	// Verify an empty query matches every song, in any order
+	total, err := DB.CountSongs()
+	if err != nil {
+		t.Fatalf("[%s] Could not count songs: %s", name, err.Error())
+	}
+	random := &SmartPlaylist{UserID: 1, Sort: "random"}
+	if results, err := random.Songs(); err != nil || int64(len(results)) != total {
+		t.Fatalf("[%s] Unexpected smart playlist songs for empty query: %d (%v)", name, len(results), err)
+	}
+	if count, _, err := random.Count(); err != nil || count != total {
+		t.Fatalf("[%s] Unexpected smart playlist count for empty query: %d (%v)", name, count, err)
+	}
+
+	// Verify deleted smart playlists cannot be loaded
+	if err := private.Delete(); err != nil {
+		t.Fatalf("[%s] Could not delete smart playlist: %s", name, err.Error())
+	}
+	if err := (&SmartPlaylist{ID: private.ID}).Load(); err != sql.ErrNoRows {
+		t.Fatalf("[%s] Unexpected error loading deleted smart playlist: %v", name, err)
+	}
+}
+
+// conformStars verifies that stars and ratings are unique per user and item, and that
+// ratings are updated in place
+func conformStars(t *testing.T, name string) {
+	_, album, songs := conformFixture(t, name, "Stars", "/stars", 1)
+	defer conformCleanup(t, name, "/stars")
+
+	// Star a song and an album, and verify a duplicate star loads the existing star
+	song, err := NewStar(1, ItemSong, songs[0].ID)
+	if err != nil {
+		t.Fatalf("[%s] Could not star song: %s", name, err.Error())
+	}
+	defer song.Delete()
+	duplicate, err := NewStar(1, ItemSong, songs[0].ID)
+	if err != nil || duplicate.ID != song.ID {
+		t.Fatalf("[%s] Unexpected duplicate star: %v (%v)", name, duplicate, err)
+	}
+	albumStar := &Star{UserID: 1, ItemType: ItemAlbum, ItemID: album.ID, Created: song.Created + 1}
+	if err := albumStar.Save(); err != nil {
+		t.Fatalf("[%s] Could not star album: %s", name, err.Error())
+	}
+
+	// Verify stars are ordered from most to least recent
+	if stars, err := DB.StarsForUser(1); err != nil || len(stars) != 2 || stars[0].ID != albumStar.ID {
+		t.Fatalf("[%s] Unexpected stars: %v (%v)", name, stars, err)
+	}
+
+	// Verify unstar by item removes the star
+	if err := (&Star{UserID: 1, ItemType: ItemAlbum, ItemID: album.ID}).Delete(); err != nil {
+		t.Fatalf("[%s] Could not unstar album: %s", name, err.Error())
+	}
+	if stars, err := DB.StarsForUser(1); err != nil || len(stars) != 1 {
+		t.Fatalf("[%s] Unexpected stars after unstar: %v (%v)", name, stars, err)
+	}
+
+	// Verify invalid and missing items cannot be starred
+	if _, err := NewStar(1, "foo", songs[0].ID); err != ErrInvalidItemType {
+		t.Fatalf("[%s] Unexpected error for invalid item type: %v", name, err)
+	}
+	if _, err := NewStar(1, ItemArtist, 99999999); err != sql.ErrNoRows {
+		t.Fatalf("[%s] Unexpected error for missing item: %v", name, err)
+	}
+
+	// Rate a song twice, and verify the rating is updated in place
+	rating, err := NewRating(1, ItemSong, songs[0].ID, 3)
+	if err != nil {
+		t.Fatalf("[%s] Could not rate song: %s", name, err.Error())
+	}
+	defer rating.Delete()
+	updated, err := NewRating(1, ItemSong, songs[0].ID, 5)
+	if err != nil || updated.ID != rating.ID {
+		t.Fatalf("[%s] Unexpected updated rating: %v (%v)", name, updated, err)
+	}
+	if ratings, err := DB.RatingsForUser(1); err != nil || len(ratings) != 1 || ratings[0].Rating != 5 {
+		t.Fatalf("[%s] Unexpected ratings: %v (%v)", name, ratings, err)
+	}
+
+	// Verify out of range ratings are rejected
+	if _, err := NewRating(1, ItemSong, songs[0].ID, 6); err != ErrInvalidRating {
+		t.Fatalf("[%s] Unexpected error for invalid rating: %v", name, err)
+	}
+}
+
+// conformUsers verifies that users and sessions can be updated by ID or unique key
+func conformUsers(t *testing.T, name string) {
+	user := &User{Username: "conform", RoleID: RoleGuest}
+	if err := user.Save(); err != nil {
+		t.Fatalf("[%s] Could not save user: %s", name, err.Error())
+	}
+	defer user.Delete()
+
+	// Update user by username
+	if err := (&User{Username: "conform", RoleID: RoleAdmin}).Update(); err != nil {
+		t.Fatalf("[%s] Could not update user: %s", name, err.Error())
+	}
+	if err := user.Load(); err != nil || user.RoleID != RoleAdmin {
+		t.Fatalf("[%s] Unexpected user after update: %v (%v)", name, user, err)
+	}
+
+	// Update session by key
+	session := &Session{UserID: user.ID, Key: "conform", Expire: 1}
+	if err := session.Save(); err != nil {
+		t.Fatalf("[%s] Could not save session: %s", name, err.Error())
+	}
+	defer session.Delete()
+	if err := (&Session{Key: "conform", Expire: 2}).Update(); err != nil {
+		t.Fatalf("[%s] Could not update session: %s", name, err.Error())
+	}
+	if sessions, err := DB.SessionsForUser(user.ID); err != nil || len(sessions) != 1 || sessions[0].Expire != 2 {
+		t.Fatalf("[%s] Unexpected sessions after update: %v (%v)", name, sessions, err)
+	}
+}
@@ -25,99 +25,1032 @@ func bindata_read(data []byte, name string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+func res_postgres_migrations_0001_schema_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xa5, 0x56,
+		0x5d, 0x93, 0x9a, 0x30, 0x14, 0x7d, 0xae, 0xbf, 0x22, 0xc3, 0x53, 0xbb,
+		0xb3, 0x33, 0xda, 0xd7, 0xf6, 0x49, 0x5b, 0xba, 0xc3, 0xd4, 0x62, 0xab,
+		0x38, 0xe3, 0x3e, 0x31, 0x59, 0xb8, 0xb2, 0x99, 0x0d, 0x81, 0x26, 0xb1,
+		0x5b, 0xfb, 0xeb, 0x9b, 0x00, 0xc1, 0xa0, 0x41, 0xb0, 0xf5, 0xcd, 0x70,
+		0xb8, 0xf7, 0x9c, 0x93, 0xfb, 0xc1, 0xf4, 0x0e, 0xbd, 0xe2, 0x5f, 0x50,
+		0x92, 0x12, 0x50, 0x59, 0x08, 0x99, 0x71, 0x10, 0x28, 0x27, 0x19, 0xc7,
+		0x92, 0x14, 0x0c, 0xcd, 0x66, 0xb3, 0xf7, 0x1f, 0x10, 0x61, 0x44, 0x12,
+		0x4c, 0x91, 0x48, 0x9e, 0x21, 0xc7, 0xf7, 0xea, 0x7f, 0x42, 0x0f, 0x29,
+		0x61, 0x19, 0x3a, 0x08, 0xe0, 0xa8, 0xa4, 0xf8, 0x48, 0x89, 0x90, 0x02,
+		0xdd, 0x4d, 0x27, 0xd3, 0x3b, 0x84, 0xe9, 0xd3, 0x21, 0xaf, 0xfe, 0x7c,
+		0x5a, 0xfb, 0xf3, 0xc8, 0x47, 0xd1, 0x7c, 0xb1, 0xf4, 0x51, 0xf0, 0x05,
+		0x85, 0xab, 0x08, 0xf9, 0xbb, 0x60, 0x13, 0x6d, 0x90, 0x57, 0xa3, 0x3c,
+		0xf4, 0x76, 0xf2, 0xc6, 0x23, 0xa9, 0x87, 0x9a, 0xdf, 0xc6, 0x5f, 0x07,
+		0xf3, 0x25, 0xfa, 0xbe, 0x0e, 0xbe, 0xcd, 0xd7, 0x8f, 0xe8, 0xab, 0xff,
+		0x78, 0xaf, 0x00, 0x98, 0x4b, 0x95, 0x20, 0xd6, 0xb8, 0x20, 0x8c, 0xfc,
+		0x07, 0x7f, 0x5d, 0xc5, 0x0a, 0xb7, 0xcb, 0xa5, 0x7e, 0x2c, 0x89, 0xa4,
+		0x50, 0x87, 0x88, 0xfc, 0x5d, 0xa4, 0x8f, 0x8e, 0x80, 0x79, 0x13, 0xb4,
+		0x79, 0x63, 0xf2, 0xee, 0xa3, 0x21, 0xb4, 0x0d, 0x83, 0x1f, 0x5b, 0xc5,
+		0x28, 0xfc, 0xec, 0xef, 0xdc, 0xbc, 0xe2, 0x03, 0x23, 0x3f, 0x0f, 0x10,
+		0xd7, 0x89, 0x83, 0x34, 0x6e, 0x52, 0xac, 0x42, 0x8b, 0xb9, 0x45, 0xeb,
+		0x1e, 0x35, 0x24, 0x54, 0x12, 0x6d, 0x01, 0x97, 0x83, 0xfa, 0xb9, 0xbc,
+		0x10, 0xdf, 0x6f, 0xc0, 0x9e, 0x50, 0x88, 0x05, 0xf9, 0xd3, 0xa8, 0x5c,
+		0x04, 0x0f, 0x4a, 0x55, 0xc7, 0x83, 0x0a, 0xc1, 0x70, 0x7e, 0xe6, 0x03,
+		0xc5, 0x8a, 0x60, 0x5e, 0xa4, 0x64, 0x4f, 0x40, 0x25, 0x3a, 0x7b, 0x71,
+		0xac, 0x27, 0x5c, 0x1a, 0x43, 0x74, 0x9a, 0xb0, 0xca, 0x52, 0x59, 0x51,
+		0x89, 0xb0, 0x72, 0xb7, 0xf2, 0x4d, 0x3d, 0x0c, 0x58, 0xa0, 0x61, 0x1d,
+		0x1b, 0xdc, 0xf2, 0x1b, 0xf7, 0xb5, 0xa8, 0xf1, 0x94, 0x75, 0x6c, 0x43,
+		0xdb, 0xbe, 0xbe, 0x36, 0x6b, 0xe7, 0xca, 0xf6, 0x05, 0x4d, 0x81, 0x0f,
+		0x72, 0x6e, 0x60, 0x23, 0xeb, 0xb6, 0xc4, 0x1c, 0x58, 0xa7, 0x6e, 0x7b,
+		0xca, 0xb5, 0xc4, 0xf2, 0xb9, 0x89, 0x75, 0x83, 0xc8, 0x86, 0x8c, 0x11,
+		0x59, 0x07, 0xd1, 0x1a, 0x4f, 0x2c, 0xeb, 0xc8, 0xb5, 0x44, 0xd3, 0xa8,
+		0xb1, 0xe2, 0xc4, 0x09, 0x0c, 0x6a, 0x3d, 0xc7, 0x3b, 0xea, 0xb5, 0x47,
+		0xb6, 0x79, 0xb1, 0xaf, 0x61, 0x45, 0xc1, 0xb2, 0xd8, 0x04, 0x72, 0x01,
+		0xd4, 0x2c, 0x22, 0x7a, 0x02, 0x79, 0x2e, 0x80, 0xe5, 0x8e, 0xd3, 0x96,
+		0x73, 0xde, 0xb1, 0x39, 0x08, 0xd2, 0xda, 0x1e, 0x87, 0xb0, 0x0e, 0xe7,
+		0xae, 0x5d, 0xa3, 0x7d, 0x3a, 0x37, 0xc8, 0x6d, 0x8e, 0x9e, 0x99, 0xc3,
+		0x93, 0xac, 0x2d, 0x8c, 0xc3, 0x13, 0x25, 0x89, 0x3a, 0x59, 0xac, 0x56,
+		0x4b, 0x7f, 0x1e, 0x76, 0xd0, 0x09, 0x07, 0x2c, 0xff, 0xbd, 0xad, 0x5b,
+		0xde, 0xa6, 0x80, 0x34, 0xb5, 0xee, 0xac, 0xb3, 0xa5, 0xb5, 0xcc, 0xcf,
+		0x86, 0x9d, 0x00, 0x21, 0xd4, 0x55, 0x0d, 0xda, 0x64, 0x70, 0xff, 0xef,
+		0x52, 0x42, 0x89, 0xba, 0x39, 0xef, 0xe4, 0x12, 0xfc, 0x2e, 0x09, 0xd7,
+		0xbe, 0x39, 0x06, 0xe3, 0x0b, 0x1c, 0xbd, 0x5b, 0x1b, 0xcb, 0x50, 0x35,
+		0xc6, 0x54, 0x31, 0xb4, 0x1f, 0x96, 0x86, 0x2a, 0x70, 0xe3, 0x80, 0x2a,
+		0xe7, 0x61, 0xf9, 0x1a, 0x74, 0xc3, 0xc8, 0xaf, 0xd6, 0x4c, 0xdb, 0x24,
+		0x4e, 0x1f, 0xf4, 0x5c, 0xb6, 0x62, 0xf5, 0x40, 0x4c, 0x23, 0xf6, 0x41,
+		0x9e, 0x88, 0x54, 0xeb, 0x1e, 0xbc, 0x2b, 0x51, 0x92, 0x67, 0xcc, 0x18,
+		0x50, 0x71, 0x85, 0x4b, 0x52, 0xe4, 0x79, 0x7d, 0x29, 0x9d, 0xc1, 0xd6,
+		0xb3, 0x96, 0x46, 0xee, 0x33, 0x79, 0x2c, 0xa1, 0xe6, 0xee, 0x4a, 0x59,
+		0x4f, 0xb9, 0xab, 0xda, 0x32, 0x60, 0x1c, 0x4e, 0x66, 0x8f, 0xdb, 0x8a,
+		0x15, 0x02, 0x58, 0xd6, 0x8e, 0x64, 0x77, 0x6c, 0x81, 0xf3, 0x52, 0x71,
+		0x34, 0xde, 0x0d, 0x7c, 0x98, 0xd8, 0xe9, 0x95, 0xdf, 0xc9, 0xcb, 0xe9,
+		0xd8, 0x5a, 0x0d, 0xd6, 0x67, 0x8b, 0xf5, 0x64, 0x6c, 0xd5, 0xea, 0x0a,
+		0x73, 0x2f, 0x6a, 0x53, 0x7c, 0x17, 0xab, 0x5a, 0xf7, 0xd9, 0x60, 0xe9,
+		0x56, 0x20, 0x57, 0xe9, 0xf6, 0xf7, 0xee, 0xe5, 0x95, 0x97, 0x58, 0x88,
+		0xd7, 0x82, 0xa7, 0xdd, 0x53, 0x5e, 0x50, 0xb8, 0x28, 0x72, 0x73, 0x45,
+		0xfb, 0x3c, 0x96, 0xc5, 0x0b, 0xb0, 0x9b, 0x36, 0x7f, 0x45, 0xd6, 0x9e,
+		0x68, 0xac, 0x75, 0xc1, 0xe8, 0x38, 0x31, 0x54, 0x21, 0xff, 0x02, 0x14,
+		0xb0, 0xa5, 0x84, 0x03, 0x0b, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0001_schema.sql",
+	)
+}
+
+func res_postgres_migrations_0002_plays_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x7d, 0x8f,
+		0x41, 0x6b, 0xc2, 0x30, 0x00, 0x85, 0xcf, 0xeb, 0xaf, 0x78, 0xe4, 0xe4,
+		0x44, 0xb0, 0xec, 0xb8, 0x9d, 0xea, 0x96, 0x49, 0xb0, 0xc6, 0x2d, 0x4d,
+		0x41, 0x4f, 0xa5, 0x60, 0xe8, 0x02, 0xb6, 0xcd, 0x9a, 0x74, 0xe2, 0xbf,
+		0x37, 0x99, 0xb3, 0x1d, 0x63, 0x98, 0x4b, 0x02, 0xef, 0xf1, 0xbe, 0x2f,
+		0xf3, 0x29, 0x8e, 0xe5, 0x97, 0x32, 0xda, 0x28, 0x98, 0xd6, 0xba, 0xaa,
+		0x53, 0x16, 0xb5, 0xae, 0xba, 0xd2, 0xe9, 0xb6, 0x41, 0x1c, 0xc7, 0x0f,
+		0x8f, 0xb0, 0x6d, 0x53, 0xc1, 0x1c, 0xca, 0x13, 0x3e, 0xb4, 0x75, 0x6d,
+		0x77, 0xc2, 0x74, 0x1e, 0x3d, 0x0b, 0x9a, 0x48, 0x0a, 0x99, 0x2c, 0x52,
+		0x0a, 0xf6, 0x0a, 0xbe, 0x91, 0xa0, 0x5b, 0x96, 0xc9, 0x0c, 0x24, 0x74,
+		0x2d, 0xc1, 0x24, 0xba, 0x23, 0x7a, 0x4f, 0xf0, 0x73, 0x32, 0x2a, 0x58,
+		0x92, 0xe2, 0x4d, 0xb0, 0x75, 0x22, 0x76, 0x58, 0xd1, 0xdd, 0xcc, 0x17,
+		0x7a, 0xab, 0xba, 0xe2, 0xd2, 0x62, 0x5c, 0xd2, 0x25, 0x15, 0xdf, 0x53,
+		0x3c, 0x4f, 0xd3, 0x10, 0x07, 0xf6, 0x8d, 0xd8, 0xe9, 0x5a, 0x59, 0x57,
+		0xd6, 0x86, 0x60, 0xc1, 0x96, 0xbe, 0x31, 0xa4, 0xd1, 0xfd, 0xd3, 0xd5,
+		0x31, 0xe7, 0xec, 0x3d, 0xf7, 0x92, 0xfc, 0x85, 0x6e, 0xff, 0x55, 0x2d,
+		0xfa, 0x46, 0x7f, 0xf6, 0xaa, 0x08, 0x2e, 0x6c, 0x5f, 0x04, 0xa6, 0xbf,
+		0x7e, 0x6d, 0x6f, 0xf8, 0xf8, 0xa9, 0xc1, 0x78, 0x86, 0xc1, 0xce, 0x3f,
+		0xc7, 0xf6, 0x08, 0xbe, 0x41, 0xbc, 0x30, 0xfe, 0x2c, 0x5f, 0xe7, 0xfc,
+		0xc2, 0x19, 0x4c, 0x32, 0xa6, 0xa0, 0x99, 0x01, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0002_plays.sql",
+	)
+}
+
+func res_postgres_migrations_0003_stars_ratings_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xc5, 0x90,
+		0xc1, 0x4f, 0xc3, 0x20, 0x18, 0xc5, 0xcf, 0xf6, 0xaf, 0xf8, 0xc2, 0x49,
+		0x97, 0x99, 0x35, 0xf1, 0xa6, 0xa7, 0x4e, 0x71, 0x21, 0x56, 0xa6, 0x94,
+		0x26, 0xdd, 0x89, 0x10, 0x4b, 0x1a, 0x0e, 0xeb, 0x10, 0xa8, 0xc6, 0xff,
+		0x5e, 0x60, 0xab, 0x2e, 0xd3, 0x25, 0xf3, 0x24, 0x27, 0xe0, 0x7d, 0xf0,
+		0xde, 0xef, 0xcd, 0x26, 0xf0, 0x2e, 0xdf, 0x94, 0xd1, 0x46, 0x81, 0xd9,
+		0x38, 0xdf, 0x59, 0xe5, 0x60, 0xad, 0x3b, 0x2b, 0xbd, 0xde, 0xf4, 0x90,
+		0xe7, 0xf9, 0xd5, 0x35, 0x18, 0x65, 0x2f, 0x07, 0xa7, 0x2c, 0x38, 0x2f,
+		0xad, 0x03, 0xd9, 0xb7, 0x10, 0xf5, 0xbe, 0x73, 0x30, 0x99, 0x65, 0xb7,
+		0x0c, 0x17, 0x1c, 0x03, 0x2f, 0xe6, 0x25, 0x06, 0x72, 0x0f, 0x74, 0xc9,
+		0x01, 0x37, 0xa4, 0xe2, 0x15, 0xa0, 0xdd, 0x18, 0x82, 0xf3, 0xec, 0x0c,
+		0xe9, 0x16, 0xc1, 0x6e, 0x55, 0x98, 0x91, 0xa2, 0x84, 0x27, 0x46, 0x1e,
+		0x0b, 0xb6, 0x82, 0x07, 0xbc, 0x9a, 0x86, 0x81, 0xe8, 0x21, 0xb6, 0x53,
+		0x84, 0x72, 0xbc, 0xc0, 0x2c, 0x7d, 0x46, 0xeb, 0xb2, 0x8c, 0xb2, 0xf6,
+		0x6a, 0x2d, 0xfc, 0x87, 0x51, 0x08, 0x38, 0x6e, 0xf8, 0x4f, 0xed, 0xf8,
+		0xd3, 0x6d, 0x8e, 0x64, 0x7f, 0x28, 0x67, 0x17, 0x37, 0x23, 0x42, 0x4d,
+		0xc9, 0x73, 0x1d, 0x18, 0xe8, 0x1d, 0x6e, 0x8e, 0x90, 0x88, 0xa1, 0xd7,
+		0xaf, 0x83, 0x12, 0x31, 0x2a, 0x69, 0x45, 0xb4, 0xe5, 0x21, 0x51, 0xda,
+		0x90, 0x60, 0xbf, 0xa4, 0xfb, 0xd0, 0x5f, 0x44, 0x53, 0xd8, 0x4b, 0x3f,
+		0x1e, 0xc2, 0xfd, 0xb7, 0xf7, 0xaf, 0xf5, 0xa5, 0xbe, 0xff, 0xbd, 0xbc,
+		0x17, 0xab, 0xa4, 0x57, 0x49, 0x9e, 0x93, 0x45, 0x98, 0xf8, 0x6b, 0x77,
+		0x09, 0xe3, 0x94, 0xe6, 0x46, 0xde, 0x53, 0x7a, 0xfb, 0x04, 0x01, 0xb9,
+		0x90, 0x56, 0xba, 0x02, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0003_stars_ratings.sql",
+	)
+}
+
+func res_postgres_migrations_0004_search_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xa5, 0xcd,
+		0x3b, 0x0b, 0xc2, 0x30, 0x18, 0x85, 0xe1, 0xdd, 0x5f, 0x71, 0xc8, 0xd2,
+		0x0b, 0x8a, 0x1d, 0x9c, 0x74, 0x92, 0x1a, 0x25, 0x20, 0x29, 0xd8, 0x0a,
+		0x6e, 0xa5, 0xb4, 0x9f, 0x1a, 0x88, 0xa6, 0x24, 0x9f, 0x97, 0x9f, 0xaf,
+		0xa0, 0x08, 0x2e, 0x2e, 0xae, 0x2f, 0xe7, 0xf0, 0x8c, 0x53, 0xdc, 0x9a,
+		0x2b, 0xf5, 0xa6, 0x27, 0xf4, 0x2e, 0xf0, 0xc1, 0x53, 0xc0, 0xc9, 0x1c,
+		0x7c, 0xc3, 0xc6, 0x9d, 0x91, 0x65, 0xd9, 0x64, 0x8a, 0xfd, 0xc5, 0xda,
+		0x11, 0xd3, 0x9d, 0x11, 0xa8, 0xf1, 0xed, 0x11, 0xe6, 0xdc, 0xd1, 0xfd,
+		0x39, 0x4c, 0xc7, 0x83, 0x7c, 0x23, 0xe7, 0x95, 0x84, 0xd2, 0x0b, 0xb9,
+		0x83, 0x5a, 0x42, 0x17, 0x15, 0xe4, 0x4e, 0x95, 0x55, 0x09, 0xd1, 0x78,
+		0x36, 0x81, 0x43, 0xfd, 0x7a, 0x09, 0x14, 0xfa, 0xd3, 0x04, 0xb6, 0xa5,
+		0xd2, 0x2b, 0xac, 0x94, 0x46, 0xcc, 0xae, 0xe6, 0x70, 0xa5, 0x96, 0x9d,
+		0x8f, 0xa3, 0x60, 0x4e, 0xbd, 0xa5, 0x68, 0x88, 0xbc, 0x98, 0xaf, 0x65,
+		0x99, 0xcb, 0x58, 0xb0, 0x61, 0x4b, 0x62, 0x88, 0x28, 0x4a, 0x92, 0x64,
+		0xf6, 0x93, 0xdc, 0x3b, 0xdb, 0x91, 0xff, 0x26, 0xdf, 0xed, 0x1f, 0xf2,
+		0x01, 0x63, 0xf2, 0x2d, 0x84, 0x27, 0x01, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0004_search.sql",
+	)
+}
+
+func res_postgres_migrations_0005_smart_playlists_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x85, 0x50,
+		0xcb, 0x6e, 0xc2, 0x30, 0x10, 0x3c, 0x37, 0x5f, 0xb1, 0xca, 0xa9, 0x45,
+		0x48, 0xe4, 0xd2, 0x4b, 0x51, 0x0f, 0x86, 0x18, 0x64, 0xd5, 0x38, 0x34,
+		0x71, 0x24, 0x38, 0x59, 0x2e, 0x71, 0x23, 0x4b, 0x79, 0x35, 0x76, 0x5a,
+		0xf1, 0xf7, 0x75, 0x20, 0x04, 0x15, 0x21, 0x75, 0x8f, 0x33, 0x3b, 0xbb,
+		0x33, 0x33, 0x9b, 0xc0, 0x8f, 0xfc, 0x56, 0x8d, 0x6e, 0x14, 0x34, 0xb5,
+		0xb1, 0x79, 0xab, 0x0c, 0x94, 0x3a, 0x6f, 0xa5, 0xd5, 0x75, 0x05, 0x41,
+		0x10, 0x3c, 0xbf, 0x80, 0x29, 0x65, 0x6b, 0xa1, 0x29, 0xe4, 0xb1, 0xd0,
+		0xc6, 0x9a, 0x29, 0xc8, 0x2a, 0x03, 0x53, 0x57, 0x39, 0xc8, 0x2c, 0x53,
+		0x19, 0x58, 0x5d, 0x2a, 0x98, 0xcc, 0x3c, 0x44, 0x39, 0x8e, 0x81, 0xa3,
+		0x05, 0xc5, 0xe0, 0xf7, 0xbc, 0xf1, 0x01, 0x85, 0x21, 0x2c, 0x23, 0x9a,
+		0x6e, 0x18, 0x90, 0x15, 0xb0, 0x88, 0x03, 0xde, 0x91, 0x84, 0x27, 0xe0,
+		0x9f, 0xb4, 0x3e, 0x2c, 0xc8, 0x9a, 0x30, 0x7e, 0x62, 0x58, 0x4a, 0x29,
+		0x84, 0x78, 0x85, 0x52, 0xca, 0x21, 0x98, 0x7b, 0xe9, 0x36, 0x44, 0xfc,
+		0x7a, 0x2a, 0xc1, 0x7c, 0x54, 0xbd, 0x82, 0x5f, 0x48, 0x63, 0x45, 0x59,
+		0x67, 0xfa, 0x53, 0x3b, 0x64, 0xee, 0x2d, 0x63, 0xdc, 0x6f, 0x9f, 0xdf,
+		0xdf, 0xfc, 0x3a, 0x25, 0x10, 0x63, 0x02, 0x1f, 0x1e, 0xbd, 0x07, 0x5f,
+		0xbb, 0x3b, 0x97, 0x49, 0x70, 0x4c, 0x10, 0x85, 0x6d, 0x4c, 0x36, 0x28,
+		0xde, 0xc3, 0x1b, 0xde, 0x4f, 0xdd, 0x46, 0x67, 0x54, 0x2b, 0x86, 0x35,
+		0x67, 0x12, 0xaf, 0x5d, 0xbc, 0x8b, 0xd1, 0x9e, 0xb7, 0xda, 0x16, 0x6a,
+		0x38, 0xc2, 0xf1, 0x8e, 0xf7, 0x58, 0xd3, 0x7d, 0x14, 0xfa, 0x70, 0x06,
+		0x17, 0x51, 0x44, 0x31, 0x62, 0x7f, 0x34, 0x5f, 0x9d, 0x6a, 0x8f, 0x37,
+		0x1a, 0x53, 0xb7, 0xf6, 0xe2, 0xe5, 0x8a, 0x55, 0xb9, 0x28, 0x74, 0xa9,
+		0x1d, 0x73, 0xef, 0xf7, 0xa1, 0x55, 0xd2, 0xaa, 0xb3, 0xb7, 0x9b, 0x0e,
+		0xbd, 0xa7, 0xb1, 0x8d, 0x94, 0x91, 0xf7, 0xd4, 0xd5, 0xc1, 0x42, 0xbc,
+		0xfb, 0xa7, 0x14, 0xd1, 0x55, 0xda, 0x99, 0x13, 0x7d, 0x68, 0x92, 0x89,
+		0x21, 0x5b, 0xc4, 0xee, 0xb5, 0x37, 0x36, 0x33, 0x85, 0xa1, 0x04, 0xf7,
+		0xf2, 0x17, 0x68, 0x47, 0x17, 0x88, 0x4c, 0x02, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0005_smart_playlists.sql",
+	)
+}
+
+func res_postgres_migrations_0006_song_discs_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xad, 0x8f,
+		0xc1, 0x0a, 0xc2, 0x30, 0x00, 0x43, 0xef, 0x7e, 0x45, 0xe8, 0x71, 0x08,
+		0xeb, 0xc9, 0x83, 0x9e, 0xaa, 0xeb, 0x64, 0x50, 0x3b, 0x70, 0x1d, 0x78,
+		0x93, 0xba, 0x8d, 0x51, 0x74, 0xdd, 0x68, 0xab, 0xfe, 0xbe, 0x76, 0xfe,
+		0x80, 0x88, 0xb7, 0x90, 0x90, 0x47, 0x92, 0x26, 0x78, 0xea, 0x47, 0x37,
+		0x99, 0xa9, 0xc3, 0x34, 0xfa, 0xd0, 0xbb, 0xce, 0x63, 0x30, 0xbd, 0xd3,
+		0xc1, 0x8c, 0x16, 0x94, 0xd2, 0xd5, 0x1a, 0x7e, 0xb4, 0x3d, 0x5a, 0xe3,
+		0x1b, 0xd8, 0xfb, 0x70, 0xe9, 0x9c, 0x5f, 0x42, 0xdb, 0xf6, 0xe3, 0x44,
+		0x11, 0x9c, 0x6e, 0xae, 0x08, 0x63, 0xd0, 0x37, 0x8f, 0x24, 0x5d, 0x30,
+		0xa1, 0xf8, 0x11, 0x8a, 0x6d, 0x05, 0x07, 0x89, 0x65, 0x4f, 0xc0, 0xb2,
+		0x0c, 0xbb, 0x52, 0xd4, 0x07, 0x89, 0x22, 0x87, 0x2c, 0x15, 0xf8, 0xa9,
+		0xa8, 0x54, 0x05, 0x12, 0x31, 0x04, 0x85, 0x54, 0x7c, 0xff, 0x6e, 0xc5,
+		0x44, 0xd6, 0x42, 0x20, 0xe3, 0x39, 0xab, 0x85, 0x02, 0xdd, 0xfc, 0xc0,
+		0x3b, 0xcf, 0x63, 0xfe, 0x49, 0x9d, 0x3f, 0x7e, 0x83, 0x7d, 0x01, 0x52,
+		0x84, 0x91, 0x34, 0x52, 0x01, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0006_song_discs.sql",
+	)
+}
+
+func res_postgres_migrations_0007_libraries_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xad, 0x90,
+		0x4f, 0x4b, 0xc3, 0x30, 0x1c, 0x86, 0xcf, 0xf6, 0x53, 0xbc, 0xe4, 0x34,
+		0x87, 0xb0, 0xde, 0x04, 0x3d, 0xc5, 0x35, 0x93, 0x60, 0x96, 0x6a, 0x9b,
+		0x42, 0x77, 0x2a, 0x91, 0xc6, 0x1a, 0x58, 0xff, 0x98, 0x76, 0x8a, 0xdf,
+		0xde, 0xd4, 0x56, 0x51, 0x99, 0xb7, 0x1d, 0x7f, 0xbc, 0xc9, 0xf3, 0xbc,
+		0xbc, 0xab, 0x25, 0xde, 0xf4, 0xab, 0xe9, 0x6c, 0x67, 0xd0, 0xb5, 0xfd,
+		0x50, 0x39, 0xd3, 0xa3, 0xb6, 0x95, 0xd3, 0x83, 0x6d, 0x1b, 0x84, 0x61,
+		0x78, 0x79, 0x85, 0xda, 0x94, 0x56, 0x63, 0x6f, 0x1f, 0x9d, 0x76, 0xd6,
+		0xe7, 0xcb, 0x55, 0xb0, 0x4e, 0x18, 0x55, 0x0c, 0x8a, 0xde, 0x08, 0x06,
+		0xbe, 0x81, 0x8c, 0x15, 0x58, 0xce, 0x53, 0x95, 0x82, 0x7c, 0x3f, 0x24,
+		0x58, 0x04, 0x67, 0xc4, 0x96, 0x04, 0x40, 0xca, 0x12, 0x4e, 0x05, 0xee,
+		0x13, 0xbe, 0xa5, 0xc9, 0x0e, 0x77, 0x6c, 0x77, 0xe1, 0xb3, 0x46, 0xd7,
+		0x86, 0x40, 0xb1, 0x5c, 0x8d, 0x57, 0xa7, 0x87, 0xe7, 0xe9, 0x0a, 0xce,
+		0xaf, 0xbf, 0x14, 0x99, 0xe4, 0x0f, 0x99, 0x77, 0xc8, 0x88, 0xe5, 0xff,
+		0x9a, 0x8a, 0x43, 0x63, 0x5f, 0x0e, 0xa6, 0x98, 0x78, 0xb1, 0xfc, 0x5d,
+		0x62, 0xd2, 0x78, 0x24, 0x15, 0x8a, 0x25, 0x73, 0x69, 0xa2, 0xdd, 0x40,
+		0x40, 0xa3, 0x08, 0xeb, 0x58, 0x64, 0x5b, 0x79, 0x9c, 0xfd, 0x5e, 0x8c,
+		0xf5, 0xb9, 0x54, 0xec, 0xd6, 0xff, 0x1c, 0x73, 0x99, 0x09, 0x81, 0x88,
+		0x6d, 0x68, 0x26, 0x14, 0xc2, 0x3f, 0xcc, 0xa7, 0x76, 0x5f, 0x1a, 0xd7,
+		0x9f, 0x9c, 0xdb, 0xb7, 0x4d, 0x75, 0x12, 0xea, 0x3c, 0xea, 0xd1, 0x35,
+		0x3f, 0x25, 0xc5, 0x4c, 0xe2, 0xe5, 0xb4, 0xe3, 0x6c, 0x5e, 0xfc, 0x34,
+		0xf8, 0x25, 0x3f, 0x00, 0xf4, 0x55, 0xeb, 0x6e, 0x38, 0x02, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0007_libraries.sql",
+	)
+}
+
+func res_postgres_migrations_0008_art_source_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x25, 0xcb,
+		0xb1, 0x0e, 0xc2, 0x20, 0x14, 0x05, 0xd0, 0xbd, 0x5f, 0x71, 0xc3, 0xd8,
+		0xa5, 0x8c, 0x46, 0x27, 0x14, 0x6a, 0x48, 0x90, 0x26, 0x16, 0x12, 0x57,
+		0x94, 0x97, 0xa6, 0x43, 0x85, 0x40, 0xd5, 0xdf, 0xb7, 0xc6, 0xf9, 0xe4,
+		0x74, 0x2d, 0x3e, 0xe1, 0x4d, 0x79, 0xce, 0x84, 0x9c, 0xea, 0x3a, 0x15,
+		0xaa, 0x58, 0xe6, 0xa9, 0x84, 0x75, 0x4e, 0x4f, 0x70, 0xce, 0x77, 0x7b,
+		0xd0, 0x72, 0xa7, 0x18, 0x29, 0x22, 0x94, 0x15, 0x35, 0xbd, 0xca, 0x83,
+		0xd0, 0x76, 0x8d, 0x30, 0x4e, 0x5d, 0xe1, 0xc4, 0xd1, 0x28, 0xb0, 0x8d,
+		0x18, 0x84, 0x94, 0x38, 0x0d, 0xc6, 0x5f, 0x2c, 0x74, 0x0f, 0x3b, 0x38,
+		0xa8, 0x9b, 0x1e, 0xdd, 0x08, 0xf6, 0x5f, 0x0c, 0xda, 0x3a, 0x75, 0xde,
+		0xd6, 0xcf, 0xac, 0x37, 0x06, 0x52, 0xf5, 0xc2, 0x1b, 0x07, 0x7e, 0x68,
+		0xbe, 0x3f, 0x3c, 0xe8, 0x82, 0x8c, 0x00, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0008_art_source.sql",
+	)
+}
+
+func res_postgres_migrations_0009_album_art_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x7d, 0x50,
+		0xcf, 0x6b, 0xc2, 0x30, 0x14, 0x3e, 0xaf, 0x7f, 0xc5, 0x47, 0x4e, 0x4e,
+		0x06, 0xf6, 0xea, 0x76, 0xca, 0xec, 0x53, 0x82, 0x31, 0x1d, 0x69, 0x0a,
+		0x7a, 0x2a, 0x19, 0x96, 0x12, 0xa8, 0x5a, 0xd2, 0x6e, 0xc3, 0xff, 0xde,
+		0xb4, 0x95, 0x0d, 0xa6, 0xf8, 0x6e, 0x8f, 0xef, 0xe7, 0x7b, 0xb3, 0x29,
+		0x7e, 0xec, 0x77, 0xd9, 0xb8, 0xa6, 0x44, 0x73, 0x6a, 0xbb, 0xca, 0x97,
+		0x2d, 0x0e, 0xae, 0xf2, 0xb6, 0x73, 0xa7, 0x23, 0xe2, 0x38, 0x9e, 0xbf,
+		0xc2, 0xd6, 0x9f, 0x5f, 0x07, 0x58, 0xdf, 0xc1, 0x1e, 0xf7, 0xa8, 0x6c,
+		0x5d, 0x97, 0xde, 0x05, 0xde, 0x74, 0x16, 0x71, 0x69, 0x48, 0xc3, 0xf0,
+		0x77, 0x49, 0x60, 0x03, 0xaf, 0x65, 0xe0, 0x49, 0x82, 0x45, 0x2a, 0xf3,
+		0x8d, 0x82, 0x58, 0x42, 0xa5, 0x06, 0xb4, 0x15, 0x99, 0xc9, 0x02, 0xc3,
+		0x77, 0x85, 0xdb, 0x33, 0x08, 0x65, 0x68, 0x15, 0x84, 0x3d, 0xa6, 0x72,
+		0x29, 0x91, 0xd0, 0x92, 0xe7, 0xd2, 0x20, 0x7e, 0x8b, 0x16, 0x9a, 0xb8,
+		0xa1, 0xab, 0xe7, 0x7f, 0x7d, 0x9f, 0x50, 0x8c, 0x0d, 0xce, 0x0c, 0x93,
+		0xe8, 0x89, 0xf5, 0x76, 0xe3, 0x64, 0xa4, 0x05, 0x97, 0xf8, 0xd0, 0x62,
+		0xc3, 0xf5, 0x0e, 0x6b, 0xda, 0xbd, 0x04, 0x7c, 0x94, 0xdc, 0x0b, 0x1d,
+		0xd0, 0x6b, 0x21, 0xdc, 0x45, 0xc3, 0x47, 0x5c, 0xff, 0x87, 0x5b, 0x6d,
+		0xf4, 0xfc, 0x5b, 0x54, 0xa8, 0x84, 0xb6, 0x0f, 0x8b, 0x16, 0xc3, 0x26,
+		0x42, 0x4c, 0xaa, 0x6e, 0x6f, 0xf8, 0x6b, 0x18, 0x3c, 0x2f, 0x4e, 0xcc,
+		0x85, 0x30, 0x90, 0x01, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0009_album_art.sql",
+	)
+}
+
+func res_postgres_migrations_0010_cue_tracks_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x95, 0x90,
+		0xb1, 0x4e, 0xc3, 0x30, 0x14, 0x45, 0xf7, 0x7c, 0xc5, 0x95, 0xa7, 0x52,
+		0x21, 0x35, 0xac, 0x64, 0x32, 0x89, 0x8b, 0x22, 0x19, 0x07, 0x52, 0x5b,
+		0xea, 0x66, 0x59, 0xe0, 0xa4, 0x56, 0xa9, 0x13, 0x62, 0x97, 0xfe, 0x3e,
+		0x31, 0x42, 0x91, 0x40, 0x0c, 0xb0, 0xbc, 0xe9, 0xdc, 0xa3, 0x7b, 0xdf,
+		0x66, 0x8d, 0x8b, 0x79, 0xb7, 0xa3, 0x1b, 0x2d, 0xc6, 0x21, 0xc4, 0x7e,
+		0xb2, 0x01, 0x27, 0xd7, 0x4f, 0x26, 0xba, 0xc1, 0x23, 0xcf, 0x6f, 0xf2,
+		0x5b, 0x94, 0x8a, 0x21, 0x1c, 0xac, 0x8d, 0x88, 0x93, 0x79, 0x3e, 0x06,
+		0x5c, 0x5c, 0x3c, 0x38, 0x0f, 0x83, 0xe0, 0x7c, 0xff, 0x6a, 0xd1, 0xb9,
+		0xf9, 0xac, 0x37, 0x19, 0xe5, 0x92, 0xb5, 0x90, 0xf4, 0x8e, 0x33, 0x90,
+		0x30, 0xf8, 0x3e, 0x10, 0xd0, 0xaa, 0x42, 0xd9, 0x70, 0xf5, 0x20, 0x50,
+		0x6f, 0x21, 0x1a, 0x09, 0xb6, 0xaf, 0x77, 0x72, 0x37, 0x03, 0xd1, 0x4c,
+		0x51, 0x0f, 0x5d, 0x17, 0x6c, 0x24, 0xa8, 0x85, 0x64, 0xf7, 0x73, 0x3a,
+		0x11, 0x42, 0x71, 0x8e, 0x8a, 0x6d, 0xa9, 0xe2, 0x12, 0x79, 0xf1, 0x4f,
+		0xaf, 0xf5, 0x2f, 0x7f, 0xb2, 0x56, 0x6d, 0xf3, 0x38, 0x03, 0x15, 0xdb,
+		0x27, 0xc3, 0xd2, 0x2a, 0xe9, 0xf5, 0xd9, 0xbb, 0xb7, 0xb3, 0xd5, 0x69,
+		0x98, 0x30, 0x27, 0x4b, 0x8a, 0xac, 0x6c, 0x19, 0x95, 0x0c, 0x4a, 0xd4,
+		0x4f, 0xf3, 0x3b, 0x96, 0xd8, 0xb7, 0x41, 0xbf, 0x45, 0xf5, 0xe7, 0xcc,
+		0xe6, 0xab, 0x4f, 0x23, 0x96, 0x01, 0x2b, 0x92, 0x10, 0xed, 0x93, 0xfe,
+		0xfa, 0xc7, 0x37, 0xae, 0x8a, 0xec, 0x03, 0x89, 0xe0, 0x7f, 0x19, 0x9a,
+		0x01, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0010_cue_tracks.sql",
+	)
+}
+
+func res_postgres_migrations_0011_song_fingerprints_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x5d, 0x8d,
+		0xb1, 0x0e, 0x82, 0x30, 0x14, 0x45, 0x77, 0xbe, 0xe2, 0xa6, 0x0b, 0xca,
+		0x02, 0xac, 0x32, 0x55, 0x79, 0x24, 0x24, 0xb5, 0x24, 0x50, 0x12, 0x36,
+		0xc3, 0x50, 0x9b, 0x0e, 0x96, 0xa6, 0x25, 0xfa, 0xfb, 0x46, 0x06, 0x23,
+		0xce, 0xf7, 0xdc, 0x73, 0xf2, 0x0c, 0xaf, 0xf9, 0xa9, 0xbd, 0xf5, 0x1a,
+		0x7e, 0x89, 0xab, 0x09, 0x3a, 0xe2, 0x61, 0x4d, 0x98, 0x57, 0xbb, 0x38,
+		0x14, 0x45, 0x59, 0x9e, 0x10, 0x17, 0x67, 0x70, 0xb7, 0xce, 0xe8, 0xe0,
+		0x83, 0x75, 0x6b, 0x44, 0x96, 0x27, 0x5c, 0x28, 0xea, 0xa1, 0xf8, 0x59,
+		0x10, 0xd8, 0x87, 0x88, 0x0c, 0xbc, 0xae, 0x71, 0xe9, 0xc4, 0x78, 0x95,
+		0x68, 0x1b, 0xc8, 0x4e, 0x81, 0xa6, 0x76, 0x50, 0x03, 0xd8, 0xcf, 0x9b,
+		0x41, 0xd1, 0xa4, 0xb6, 0x55, 0x8e, 0x42, 0xa0, 0xa6, 0x86, 0x8f, 0x42,
+		0x21, 0x4d, 0xab, 0xe4, 0xd2, 0x13, 0x57, 0x84, 0x56, 0xd6, 0x34, 0xfd,
+		0x2b, 0xb6, 0xc6, 0x6d, 0x27, 0xea, 0xe4, 0x37, 0x7d, 0xd8, 0x25, 0x8e,
+		0x55, 0xf2, 0x06, 0x3c, 0xa8, 0x8c, 0xd5, 0xda, 0x00, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0011_song_fingerprints.sql",
+	)
+}
+
+func res_postgres_migrations_0012_search_unaccent_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xad, 0x50,
+		0x5d, 0x6b, 0xc2, 0x30, 0x14, 0x7d, 0xf7, 0x57, 0x5c, 0xf2, 0xd2, 0x56,
+		0x1c, 0xba, 0x3d, 0x4e, 0xf6, 0x50, 0x6a, 0xd4, 0x80, 0x4b, 0x4b, 0x1b,
+		0x99, 0x7b, 0x92, 0x10, 0xa3, 0x0d, 0xd4, 0xb6, 0x24, 0x51, 0xf7, 0xf3,
+		0x97, 0x6a, 0xeb, 0x9c, 0xb0, 0xc1, 0x60, 0x2f, 0x21, 0xf7, 0xe4, 0xdc,
+		0x9c, 0x8f, 0x61, 0x1f, 0x4e, 0xfc, 0x28, 0x6b, 0x55, 0x4b, 0xa8, 0x2b,
+		0x63, 0x77, 0x5a, 0x1a, 0xd8, 0xab, 0x9d, 0xe6, 0x56, 0x55, 0x25, 0x8c,
+		0x46, 0x8f, 0x4f, 0xcf, 0xb0, 0x3d, 0x14, 0xc5, 0x83, 0x95, 0x1f, 0x16,
+		0x8c, 0xe4, 0x5a, 0xe4, 0x70, 0xca, 0x95, 0x3b, 0xd5, 0xae, 0xac, 0x1a,
+		0xfa, 0x46, 0x71, 0xa1, 0x95, 0x55, 0xc2, 0x40, 0x7f, 0xd8, 0x8b, 0x52,
+		0x1c, 0x32, 0x0c, 0x78, 0xc5, 0x30, 0xcd, 0x48, 0x4c, 0x81, 0x4c, 0x81,
+		0xc6, 0xcc, 0x01, 0x24, 0x63, 0x19, 0xa0, 0x43, 0xc9, 0x85, 0x90, 0xa5,
+		0x45, 0xe3, 0x8e, 0xca, 0x1c, 0x17, 0x32, 0x1c, 0xa6, 0xd1, 0x1c, 0xa2,
+		0x98, 0x4e, 0xc9, 0x6c, 0x99, 0x86, 0xac, 0x59, 0x45, 0x9d, 0xb7, 0xf5,
+		0x45, 0x18, 0x81, 0x1f, 0xc5, 0xc9, 0x3b, 0xbc, 0x80, 0x51, 0xfb, 0xba,
+		0x90, 0xc1, 0xb8, 0x17, 0x2e, 0x18, 0x4e, 0xff, 0xf4, 0xc5, 0x65, 0xe3,
+		0x35, 0x4c, 0x12, 0x42, 0x67, 0x30, 0x8d, 0x53, 0x40, 0xdc, 0x08, 0xa5,
+		0xf2, 0x53, 0xa5, 0x37, 0x68, 0xd0, 0x4e, 0xdd, 0x90, 0x7f, 0xbb, 0xac,
+		0xcf, 0x6f, 0x35, 0xd7, 0xf6, 0x0b, 0xea, 0xa6, 0x33, 0x11, 0xde, 0x08,
+		0x9b, 0xdf, 0x84, 0x74, 0xf8, 0xc5, 0xaa, 0x8b, 0x3b, 0x49, 0xe3, 0x04,
+		0x08, 0x9d, 0xe0, 0x55, 0xd3, 0x49, 0xd7, 0x87, 0xdb, 0x56, 0xc6, 0x9a,
+		0xce, 0xde, 0x4f, 0xb4, 0x6d, 0x55, 0x6c, 0xa4, 0xbe, 0xa1, 0xb5, 0xe5,
+		0x5d, 0x89, 0xb7, 0x1d, 0xdf, 0xfd, 0x09, 0x4d, 0x0f, 0x2d, 0x86, 0x60,
+		0x99, 0x35, 0xb9, 0x67, 0x84, 0x82, 0x6f, 0xab, 0xb5, 0x35, 0x47, 0x29,
+		0x6c, 0xa5, 0x7d, 0xef, 0xae, 0x28, 0x6f, 0xe0, 0x9a, 0x0c, 0x17, 0x38,
+		0x8b, 0xb0, 0x8f, 0xac, 0xb2, 0x2e, 0xc2, 0x00, 0x3c, 0x2f, 0x08, 0x82,
+		0xdf, 0xb5, 0xef, 0x8c, 0x9e, 0xb5, 0x5b, 0xec, 0x5f, 0xb4, 0x3f, 0x01,
+		0xd5, 0xda, 0xe3, 0xc6, 0xb2, 0x02, 0x00, 0x00,
+	},
+		"res/postgres/migrations/0012_search_unaccent.sql",
+	)
+}
+
+func res_sqlite_migrations_0001_playlists_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x8d, 0x91,
+		0xc1, 0x4e, 0xc3, 0x30, 0x10, 0x44, 0xcf, 0xe4, 0x2b, 0x56, 0x3e, 0x41,
+		0x55, 0xa9, 0xe1, 0x0a, 0xa7, 0x00, 0x0b, 0xb2, 0x48, 0x1d, 0x48, 0x1d,
+		0x29, 0x3d, 0x59, 0xa1, 0xb5, 0xaa, 0x95, 0x4c, 0x92, 0xc6, 0x0e, 0x88,
+		0xbf, 0x27, 0x86, 0xba, 0x14, 0x14, 0x28, 0x3e, 0x5a, 0xb3, 0xb3, 0x33,
+		0x6f, 0x67, 0x13, 0x78, 0xad, 0x5e, 0x74, 0x4b, 0xad, 0x06, 0xbb, 0x35,
+		0xe4, 0x34, 0x3c, 0xd3, 0xa6, 0xab, 0x1c, 0x35, 0x35, 0xc4, 0x71, 0x7c,
+		0x7e, 0x01, 0xbd, 0xd5, 0x1d, 0xb4, 0xa6, 0x7a, 0x33, 0x64, 0x9d, 0x85,
+		0xc9, 0x2c, 0xba, 0xce, 0x31, 0x91, 0x08, 0x32, 0xb9, 0x4a, 0x11, 0xf8,
+		0x2d, 0x88, 0x4c, 0x02, 0x96, 0x7c, 0x21, 0x17, 0xc0, 0x82, 0x50, 0xe9,
+		0xda, 0x75, 0xa4, 0x2d, 0x83, 0xd3, 0xe8, 0x84, 0xd1, 0x9a, 0xc1, 0xfe,
+		0x71, 0x21, 0xf1, 0x0e, 0x73, 0x78, 0xc8, 0xf9, 0x3c, 0xc9, 0x97, 0x70,
+		0x8f, 0x4b, 0x48, 0x0a, 0x99, 0x71, 0x31, 0x18, 0xcf, 0x51, 0xc8, 0xe9,
+		0x30, 0xb1, 0xf7, 0xf1, 0xa3, 0x61, 0xc2, 0x2f, 0x12, 0x45, 0x9a, 0x7a,
+		0x81, 0x6d, 0xea, 0x8d, 0x0a, 0xbe, 0x63, 0x82, 0xb6, 0xb1, 0xe4, 0x6b,
+		0xb0, 0x31, 0x41, 0x74, 0x76, 0x19, 0x6a, 0x70, 0x71, 0x83, 0xe5, 0xb1,
+		0x1a, 0x2a, 0x7c, 0xf0, 0x61, 0x63, 0x26, 0x46, 0x7b, 0x7e, 0xcb, 0xfc,
+		0xe5, 0xff, 0x27, 0xa6, 0x9f, 0x7c, 0xfe, 0xc5, 0xc6, 0x5f, 0xe4, 0x57,
+		0x2e, 0x8e, 0x9c, 0xd1, 0xde, 0x4d, 0x62, 0xf9, 0x49, 0xb2, 0x7f, 0x32,
+		0xb4, 0x62, 0xe3, 0x90, 0x56, 0x9d, 0xae, 0x9c, 0x1e, 0xb1, 0x3a, 0x00,
+		0x54, 0x08, 0xfe, 0x58, 0x1c, 0xe1, 0x64, 0x55, 0x5f, 0xd3, 0xb6, 0xd7,
+		0xca, 0x67, 0xe3, 0x6b, 0xb5, 0x4b, 0x71, 0x48, 0xea, 0x03, 0x51, 0x88,
+		0x3e, 0x85, 0x5d, 0xd0, 0x61, 0xcd, 0x3b, 0x65, 0xc1, 0x87, 0x72, 0x84,
+		0x02, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0001_playlists.sql",
+	)
+}
+
+func res_sqlite_migrations_0002_plays_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x7d, 0x8f,
+		0x5d, 0x6b, 0xc2, 0x30, 0x14, 0x86, 0xaf, 0xd7, 0x5f, 0xf1, 0x92, 0xab,
+		0x4d, 0x04, 0xcb, 0x2e, 0xb7, 0xab, 0xce, 0x9d, 0x49, 0x58, 0x4d, 0xb7,
+		0x9a, 0x82, 0x5e, 0x95, 0x82, 0x41, 0x03, 0xf6, 0xc3, 0x26, 0x55, 0xfc,
+		0xf7, 0x4b, 0x70, 0xb5, 0x63, 0x8a, 0xb9, 0x49, 0xe0, 0xbc, 0x79, 0xde,
+		0xe7, 0x4c, 0x46, 0x38, 0x16, 0x07, 0xd5, 0xe8, 0x46, 0xc1, 0xec, 0x77,
+		0xda, 0x2a, 0x94, 0x7a, 0xd3, 0x16, 0x56, 0xd7, 0x15, 0xc2, 0x30, 0x7c,
+		0x7e, 0x81, 0xa9, 0xab, 0x0d, 0x9a, 0x5d, 0x71, 0xc2, 0x56, 0x1b, 0x5b,
+		0xb7, 0x27, 0x8c, 0x26, 0xc1, 0x34, 0xa5, 0x48, 0x12, 0x64, 0xf4, 0x16,
+		0x13, 0xf8, 0x07, 0x44, 0x22, 0x41, 0x4b, 0xbe, 0x90, 0x0b, 0x30, 0x9f,
+		0x35, 0x0c, 0x8f, 0xc1, 0x03, 0xd3, 0x6b, 0x86, 0xdf, 0xc3, 0x85, 0xa4,
+		0x19, 0xa5, 0xf8, 0x4a, 0xf9, 0x3c, 0x4a, 0x57, 0xf8, 0xa4, 0x15, 0xa2,
+		0x4c, 0x26, 0x5c, 0x38, 0xd6, 0x9c, 0x84, 0x1c, 0xbb, 0x7c, 0x67, 0x54,
+		0x9b, 0x9f, 0x3f, 0xf5, 0x79, 0x4f, 0x16, 0x59, 0x1c, 0xfb, 0xb1, 0x57,
+		0xb9, 0x33, 0xb6, 0xba, 0x54, 0xc6, 0x16, 0x65, 0xc3, 0xae, 0xc6, 0xc1,
+		0xd3, 0x6b, 0xef, 0x9c, 0x09, 0xfe, 0x9d, 0x39, 0x69, 0xf1, 0x4e, 0xcb,
+		0x9b, 0xea, 0x79, 0x57, 0xe9, 0x7d, 0xa7, 0x72, 0x2f, 0xc3, 0xd7, 0xb9,
+		0x2f, 0x75, 0xd7, 0x1f, 0x78, 0x22, 0x86, 0x25, 0x2f, 0xca, 0x63, 0x5c,
+		0xf4, 0xdc, 0x73, 0x48, 0x0f, 0xc5, 0x77, 0x1a, 0xcf, 0x1d, 0xff, 0xc8,
+		0x3d, 0xce, 0x11, 0x7e, 0x00, 0x0e, 0x72, 0xf2, 0x2f, 0xa7, 0x01, 0x00,
+		0x00,
+	},
+		"res/sqlite/migrations/0002_plays.sql",
+	)
+}
+
+func res_sqlite_migrations_0003_stars_ratings_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xcd, 0x50,
+		0x4f, 0x4b, 0xc3, 0x30, 0x1c, 0x3d, 0xdb, 0x4f, 0xf1, 0xc8, 0x49, 0xc7,
+		0x64, 0x05, 0x6f, 0x7a, 0xaa, 0x1a, 0x25, 0xd8, 0xa5, 0xda, 0xa5, 0xd0,
+		0x9d, 0x42, 0xb0, 0x61, 0x04, 0x5c, 0xed, 0x92, 0x56, 0xf1, 0xdb, 0x9b,
+		0x74, 0xab, 0x0e, 0xec, 0xa0, 0x47, 0x73, 0x4a, 0xf2, 0xde, 0xef, 0xf7,
+		0xfe, 0x2c, 0x66, 0xf8, 0x54, 0x1f, 0xba, 0x31, 0x8d, 0x86, 0xdb, 0xbd,
+		0x99, 0x56, 0x63, 0x6b, 0x36, 0x56, 0xb5, 0xe6, 0xbd, 0x46, 0x1c, 0xc7,
+		0x57, 0xd7, 0x68, 0xb4, 0xbd, 0xec, 0x9c, 0xb6, 0x70, 0xad, 0xb2, 0x0e,
+		0xaa, 0xae, 0x10, 0xf0, 0x7a, 0xe3, 0x30, 0x5b, 0x44, 0x77, 0x39, 0x4d,
+		0x04, 0x85, 0x48, 0x6e, 0x53, 0x0a, 0xf6, 0x00, 0x9e, 0x09, 0xd0, 0x92,
+		0xad, 0xc4, 0x0a, 0xe4, 0x40, 0x23, 0x38, 0x8f, 0xce, 0x88, 0xa9, 0x08,
+		0x0e, 0x87, 0x71, 0x41, 0x1f, 0x69, 0x8e, 0xe7, 0x9c, 0x2d, 0x93, 0x7c,
+		0x8d, 0x27, 0xba, 0x46, 0x52, 0x88, 0x8c, 0x71, 0xbf, 0x6d, 0x49, 0xb9,
+		0x98, 0x7b, 0x7e, 0x90, 0x94, 0xfb, 0xa1, 0x81, 0x1f, 0x76, 0xf3, 0x22,
+		0x4d, 0x03, 0xec, 0x9d, 0x6e, 0x65, 0xfb, 0xd5, 0x68, 0x02, 0x41, 0x4b,
+		0xf1, 0x17, 0x3b, 0x3d, 0xba, 0xb7, 0x45, 0x30, 0x02, 0x47, 0x17, 0x37,
+		0x43, 0xa2, 0x82, 0xb3, 0x97, 0xc2, 0x47, 0xe2, 0xf7, 0xb4, 0x3c, 0x11,
+		0x4c, 0x76, 0xb5, 0xd9, 0x75, 0x5a, 0x06, 0xab, 0xac, 0x92, 0x41, 0x56,
+		0x78, 0x47, 0xfd, 0x85, 0x79, 0xf9, 0x8c, 0x1f, 0x77, 0xf0, 0x93, 0x68,
+		0x8e, 0x23, 0xf7, 0xc3, 0xc3, 0xff, 0xff, 0x6a, 0x8f, 0xb6, 0xd9, 0xd7,
+		0xff, 0xdf, 0xba, 0x7c, 0xb5, 0x5a, 0xb5, 0x7a, 0x14, 0x9e, 0xd8, 0x65,
+		0x1f, 0x6b, 0x4a, 0x93, 0x43, 0xfe, 0x29, 0x3d, 0x7e, 0x03, 0x6d, 0x12,
+		0xeb, 0x25, 0xd7, 0x02, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0003_stars_ratings.sql",
+	)
+}
+
+func res_sqlite_migrations_0004_search_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xed, 0x56,
+		0x4d, 0x73, 0xda, 0x30, 0x10, 0x3d, 0xc3, 0xaf, 0xd8, 0xf1, 0x25, 0xc0,
+		0xa4, 0x85, 0x76, 0xda, 0x1e, 0x9a, 0xe9, 0xc1, 0x09, 0x82, 0xba, 0x43,
+		0x4c, 0xc7, 0x16, 0x69, 0x6f, 0x8c, 0x8b, 0x05, 0xd5, 0xd4, 0x1f, 0xd4,
+		0x16, 0x81, 0xe9, 0xaf, 0xaf, 0x2c, 0x5b, 0x60, 0x29, 0xb6, 0x07, 0x02,
+		0xe9, 0xa9, 0x37, 0x5b, 0xda, 0x8f, 0xb7, 0xef, 0xed, 0xae, 0xdd, 0xef,
+		0xc1, 0xd6, 0x7b, 0x24, 0x6b, 0xba, 0x26, 0x90, 0xfe, 0x0e, 0x28, 0x23,
+		0x10, 0xd2, 0x55, 0xe2, 0x31, 0x1a, 0x47, 0x30, 0x18, 0x0c, 0xde, 0x7d,
+		0x84, 0xe5, 0x26, 0x08, 0x5e, 0x31, 0xb2, 0x63, 0x90, 0x12, 0x2f, 0x59,
+		0xfc, 0x04, 0x1a, 0xf9, 0x64, 0x07, 0xbd, 0x7e, 0xfb, 0xce, 0x41, 0x26,
+		0x46, 0xf0, 0x60, 0x39, 0x78, 0x66, 0x4e, 0x00, 0x9b, 0xb7, 0x13, 0x04,
+		0xd6, 0x08, 0xec, 0x29, 0x06, 0xf4, 0xdd, 0x72, 0xb1, 0x0b, 0x86, 0x17,
+		0xfc, 0xd8, 0x84, 0xe9, 0x3c, 0x77, 0x35, 0x60, 0xe6, 0x5a, 0xf6, 0x18,
+		0x96, 0x2c, 0x7d, 0xdf, 0x31, 0x18, 0x65, 0x01, 0x31, 0xae, 0xb9, 0x4d,
+		0xc2, 0x68, 0xca, 0xf8, 0x13, 0x8b, 0x7f, 0x91, 0x88, 0xfe, 0x21, 0xf0,
+		0x09, 0xae, 0x36, 0x11, 0x5d, 0xc4, 0x3e, 0xf9, 0xf0, 0x06, 0x12, 0x12,
+		0xc6, 0x8f, 0x64, 0xee, 0x53, 0x6f, 0x91, 0x50, 0x46, 0x17, 0x29, 0xbc,
+		0xbd, 0xea, 0xde, 0x1c, 0x97, 0x5d, 0x44, 0x6e, 0x4e, 0x7f, 0xf1, 0xa4,
+		0xcb, 0x38, 0xf0, 0x49, 0xf2, 0x8f, 0x93, 0xa6, 0x71, 0xb4, 0x3a, 0x96,
+		0xe6, 0x5c, 0x94, 0xec, 0x61, 0x45, 0xa2, 0x44, 0xdc, 0x2d, 0xe2, 0x30,
+		0x24, 0xd1, 0x73, 0x35, 0xc0, 0x8e, 0x35, 0x1e, 0x23, 0xa7, 0x51, 0xfb,
+		0x39, 0x8d, 0x52, 0x92, 0x30, 0x03, 0xcc, 0x11, 0xce, 0x4c, 0x6d, 0x17,
+		0x39, 0x18, 0xa6, 0xb6, 0x34, 0x33, 0xe0, 0x16, 0x8d, 0x2d, 0xbb, 0xdd,
+		0x2a, 0x6e, 0x2c, 0x1b, 0x4f, 0x9f, 0xb4, 0x4f, 0xc7, 0x48, 0xe2, 0x2d,
+		0xf5, 0x33, 0xc4, 0x7a, 0x59, 0xdd, 0x76, 0xab, 0xf5, 0x60, 0x4e, 0x66,
+		0xc8, 0x85, 0x4e, 0x44, 0xb6, 0xaf, 0x0d, 0x61, 0x26, 0x9e, 0xa4, 0x69,
+		0xc7, 0x45, 0x13, 0x74, 0x87, 0xa5, 0x2f, 0x8c, 0x9c, 0xe9, 0xfd, 0xbe,
+		0x47, 0x0c, 0xf8, 0xf6, 0x19, 0x39, 0x08, 0x32, 0x3f, 0x5e, 0xbb, 0x70,
+		0xcc, 0xaf, 0xe6, 0xfc, 0xa4, 0xcb, 0x8b, 0x45, 0xf6, 0xf0, 0xb4, 0x8a,
+		0x7d, 0x12, 0x10, 0x46, 0x64, 0xc5, 0x43, 0x9e, 0x9c, 0x7b, 0x56, 0x54,
+		0x5c, 0xdc, 0x14, 0x70, 0xd4, 0x8a, 0x0b, 0x50, 0x79, 0xd9, 0x1c, 0x17,
+		0xef, 0x2d, 0x51, 0xda, 0x71, 0x70, 0x94, 0xf6, 0x6f, 0x50, 0x40, 0x52,
+		0x50, 0x29, 0x81, 0x36, 0x43, 0x4f, 0x34, 0xe8, 0x42, 0x23, 0xef, 0x47,
+		0x32, 0xa7, 0x42, 0xad, 0xa7, 0x4e, 0x83, 0xaa, 0x72, 0xa7, 0x41, 0x3d,
+		0x8b, 0x3c, 0x75, 0x8c, 0xeb, 0xc9, 0x2b, 0xec, 0xaa, 0xc9, 0xd3, 0x77,
+		0xc1, 0xcb, 0x90, 0xa7, 0x41, 0xad, 0x25, 0x4f, 0x87, 0xaa, 0x90, 0xa7,
+		0x43, 0x3d, 0x8b, 0xbc, 0xf2, 0x3a, 0xaa, 0xa7, 0x4e, 0x58, 0x55, 0x13,
+		0xa7, 0xee, 0xb3, 0x86, 0xb9, 0x6f, 0x5c, 0x67, 0x2f, 0xbc, 0x14, 0xae,
+		0x79, 0xf8, 0x56, 0x4d, 0x80, 0x62, 0xc4, 0x2b, 0xfc, 0xb3, 0x9b, 0xdc,
+		0x3d, 0x3f, 0x90, 0xa8, 0xc5, 0xcb, 0x1e, 0xf9, 0xe9, 0x34, 0x6f, 0xd6,
+		0xbe, 0x77, 0x90, 0x7d, 0xf6, 0x75, 0x68, 0x16, 0xb2, 0xab, 0x34, 0x2b,
+		0xa2, 0xab, 0x34, 0xd7, 0x4b, 0xfe, 0x5f, 0x9c, 0x33, 0xc5, 0xa9, 0x9d,
+		0xc9, 0x0b, 0x88, 0x23, 0xb0, 0xf4, 0x7b, 0x60, 0x89, 0x1f, 0x33, 0x2f,
+		0x08, 0x80, 0xec, 0x38, 0x0b, 0x34, 0x5a, 0x01, 0xff, 0xa1, 0x0b, 0xd3,
+		0xec, 0x5f, 0xed, 0xf9, 0x5f, 0x55, 0xc9, 0x60, 0x41, 0x5a, 0x21, 0xd3,
+		0xe1, 0x55, 0x73, 0x39, 0x1c, 0xa9, 0x64, 0xb7, 0x5b, 0x13, 0x34, 0xc2,
+		0xf0, 0x65, 0x6a, 0x95, 0xb7, 0x78, 0xe9, 0x6b, 0x58, 0x16, 0x8f, 0x97,
+		0x57, 0x0a, 0x27, 0x8a, 0x3c, 0xf5, 0x9b, 0x24, 0x61, 0x97, 0x4f, 0xb5,
+		0xfe, 0xd1, 0x82, 0x1e, 0xb1, 0xab, 0x1b, 0x82, 0xca, 0xdd, 0xaa, 0x05,
+		0xbd, 0xd8, 0xa8, 0xc8, 0xd4, 0x79, 0xbf, 0x48, 0x15, 0xe4, 0x5b, 0xad,
+		0x08, 0x95, 0x4a, 0x49, 0xaf, 0x7d, 0x1e, 0x79, 0x20, 0xf3, 0x95, 0x1b,
+		0xb0, 0x41, 0x39, 0xe9, 0xd6, 0x20, 0x9c, 0xea, 0x5c, 0xcc, 0x9d, 0xe2,
+		0x2b, 0x27, 0x4e, 0xb8, 0x96, 0x7a, 0xec, 0xa6, 0xfd, 0x17, 0xc3, 0x50,
+		0xed, 0x21, 0x9d, 0x0c, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0004_search.sql",
+	)
+}
+
+func res_sqlite_migrations_0005_smart_playlists_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x85, 0x90,
+		0xcd, 0x6e, 0xc2, 0x30, 0x10, 0x84, 0xcf, 0xcd, 0x53, 0xac, 0x7c, 0x6a,
+		0x11, 0x12, 0xb9, 0xf4, 0x52, 0xd4, 0x83, 0x21, 0xa6, 0xb2, 0x1a, 0x1c,
+		0x1a, 0x6c, 0x09, 0x4e, 0x96, 0x4b, 0x5c, 0x64, 0x29, 0x7f, 0xc4, 0x4e,
+		0x2b, 0xde, 0xbe, 0x0e, 0x0d, 0x41, 0xa2, 0x54, 0xdd, 0xe3, 0xee, 0xce,
+		0xec, 0xb7, 0x33, 0x19, 0xc1, 0x97, 0xfa, 0xd4, 0xb5, 0xa9, 0x35, 0xd8,
+		0x43, 0x6e, 0x9c, 0x86, 0xc2, 0xec, 0x1b, 0xe5, 0x4c, 0x55, 0x42, 0x18,
+		0x86, 0x8f, 0x4f, 0x60, 0x0b, 0xd5, 0x38, 0xa8, 0x73, 0x75, 0xcc, 0x8d,
+		0x75, 0x76, 0x0c, 0xaa, 0xcc, 0xc0, 0x56, 0xe5, 0x1e, 0x54, 0x96, 0xe9,
+		0x0c, 0x9c, 0x29, 0x34, 0x8c, 0x26, 0x01, 0x8e, 0x39, 0x49, 0x81, 0xe3,
+		0x59, 0x4c, 0x00, 0x75, 0x73, 0x8b, 0x00, 0x47, 0x11, 0xcc, 0x93, 0x58,
+		0x2c, 0x19, 0xa0, 0xd3, 0x36, 0x02, 0xca, 0x38, 0x79, 0xf1, 0x8b, 0x2c,
+		0xe1, 0xc0, 0x44, 0x1c, 0x43, 0x44, 0x16, 0x58, 0xc4, 0x1c, 0xc2, 0x69,
+		0x20, 0x56, 0x11, 0xe6, 0x17, 0xf5, 0x9a, 0xf0, 0x41, 0xf6, 0x0c, 0x28,
+		0x57, 0xd6, 0xc9, 0xa2, 0xca, 0xcc, 0x87, 0xf1, 0x9d, 0x69, 0x30, 0x4f,
+		0x49, 0xb7, 0xfd, 0x73, 0x91, 0x2e, 0x4e, 0x8e, 0x64, 0x43, 0xd7, 0x7c,
+		0xed, 0x1d, 0x3a, 0x68, 0x39, 0x40, 0x23, 0xb8, 0x0f, 0xee, 0x90, 0xf1,
+		0x3e, 0xe7, 0x3a, 0x63, 0xac, 0x52, 0xba, 0xc4, 0xe9, 0x16, 0x5e, 0xc9,
+		0x16, 0xb0, 0xe0, 0x09, 0x65, 0xde, 0x76, 0x49, 0x18, 0x1f, 0x7b, 0x41,
+		0x6b, 0x75, 0x23, 0x7b, 0xd5, 0x35, 0x77, 0x37, 0x77, 0xc6, 0xe5, 0xba,
+		0xf7, 0xe4, 0x64, 0x73, 0xd2, 0xd4, 0xed, 0x7b, 0x6e, 0x76, 0x08, 0xfe,
+		0xd2, 0x1c, 0x5a, 0xdd, 0x1c, 0xaf, 0x34, 0xb6, 0x6a, 0xdc, 0x19, 0xed,
+		0xd2, 0x2b, 0xf7, 0x32, 0x37, 0x85, 0x71, 0xbf, 0x33, 0xeb, 0xe6, 0xbb,
+		0x46, 0x2b, 0xa7, 0x6f, 0xb3, 0x05, 0x0f, 0x43, 0x3a, 0x82, 0xd1, 0x37,
+		0xe1, 0xe3, 0x61, 0x11, 0xd9, 0xfc, 0x13, 0x92, 0x6c, 0x4b, 0xe3, 0xe9,
+		0x64, 0xf7, 0x35, 0xcd, 0x64, 0xff, 0x5c, 0xc2, 0x6e, 0xa5, 0x39, 0x44,
+		0x33, 0x86, 0x3e, 0x05, 0x7f, 0xf2, 0x1b, 0x2a, 0xaa, 0xcc, 0x45, 0x4d,
+		0x02, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0005_smart_playlists.sql",
+	)
+}
+
+func res_sqlite_migrations_0006_song_discs_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xa5, 0x8e,
+		0xc1, 0x0a, 0xc2, 0x30, 0x00, 0x43, 0xef, 0x7e, 0x45, 0xe8, 0x71, 0x08,
+		0xeb, 0xc9, 0x83, 0x9e, 0xaa, 0xed, 0x44, 0xa8, 0x1d, 0x48, 0x77, 0x96,
+		0xba, 0x95, 0x51, 0xdc, 0xba, 0xd9, 0x56, 0xfd, 0x7d, 0xdd, 0xfc, 0x81,
+		0x81, 0xb7, 0x10, 0x92, 0x97, 0xe4, 0x19, 0xde, 0xe6, 0x65, 0x47, 0x37,
+		0x5a, 0xc4, 0x47, 0xe7, 0x92, 0x45, 0xef, 0xda, 0x60, 0x92, 0x1b, 0x3c,
+		0x28, 0xa5, 0x9b, 0x2d, 0xe2, 0xe0, 0x5b, 0x34, 0x2e, 0xd6, 0xf0, 0xcf,
+		0xfe, 0x66, 0x43, 0x5c, 0xc3, 0xf8, 0xe6, 0xe7, 0x4c, 0x22, 0x05, 0x53,
+		0xdf, 0x91, 0x86, 0x64, 0xba, 0x88, 0x2c, 0x5f, 0x31, 0xa9, 0xc5, 0x05,
+		0x9a, 0xed, 0xa5, 0x00, 0x99, 0xca, 0x91, 0x80, 0x71, 0x8e, 0x43, 0x29,
+		0xab, 0xb3, 0x02, 0x99, 0x8a, 0x04, 0x27, 0xa5, 0xc5, 0xf1, 0x9b, 0x53,
+		0xa5, 0x86, 0xaa, 0xa4, 0x04, 0x17, 0x05, 0xab, 0xa4, 0x06, 0xdd, 0x2d,
+		0x22, 0x5c, 0xe7, 0xc1, 0xff, 0x38, 0xf3, 0xf3, 0x25, 0xa0, 0x0f, 0xc3,
+		0x9f, 0x7f, 0x95, 0x26, 0x01, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0006_song_discs.sql",
+	)
+}
+
+func res_sqlite_migrations_0007_libraries_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xad, 0x90,
+		0x4d, 0x4b, 0xc3, 0x40, 0x14, 0x45, 0xd7, 0xe6, 0x57, 0x5c, 0x66, 0x55,
+		0x8b, 0xd0, 0xec, 0x04, 0x5d, 0x8d, 0xcd, 0x54, 0x06, 0x27, 0x13, 0x4d,
+		0x67, 0x20, 0x5d, 0x85, 0x91, 0x8c, 0x75, 0x20, 0x5f, 0x4d, 0x52, 0xc5,
+		0x7f, 0xef, 0xb4, 0x69, 0x4b, 0x15, 0xdd, 0x88, 0xcb, 0x07, 0xef, 0x9d,
+		0x73, 0xdf, 0x9d, 0x4d, 0xf1, 0x6e, 0xde, 0x6c, 0xeb, 0x5a, 0x8b, 0x7e,
+		0x53, 0xba, 0xc1, 0xa2, 0x72, 0xeb, 0xce, 0x0c, 0xae, 0xa9, 0x11, 0x86,
+		0xe1, 0xf5, 0x0d, 0x2a, 0x5b, 0x38, 0x83, 0xd2, 0x3d, 0x77, 0xa6, 0x73,
+		0xb6, 0xc7, 0x74, 0x16, 0xcc, 0x53, 0x46, 0x15, 0x83, 0xa2, 0x77, 0x82,
+		0x81, 0x2f, 0x20, 0x13, 0x05, 0x96, 0xf1, 0xa5, 0x5a, 0x82, 0x9c, 0x16,
+		0x09, 0x26, 0xc1, 0x05, 0x71, 0x05, 0x01, 0xc0, 0xa5, 0x62, 0xf7, 0x2c,
+		0xc5, 0x63, 0xca, 0x63, 0x9a, 0xae, 0xf0, 0xc0, 0x56, 0xa0, 0x5a, 0x25,
+		0x5c, 0x7a, 0x54, 0xcc, 0xa4, 0xba, 0xf2, 0xab, 0xb5, 0xa9, 0x2c, 0x81,
+		0x62, 0xd9, 0x7e, 0x6a, 0xcd, 0xf0, 0x3a, 0x4e, 0xc1, 0xe5, 0xed, 0xd1,
+		0xa8, 0x25, 0x7f, 0xd2, 0x5e, 0x29, 0x23, 0x96, 0xfd, 0x2a, 0xce, 0xb7,
+		0xb5, 0xdb, 0x6c, 0x6d, 0x3e, 0xf2, 0x12, 0xf9, 0x35, 0xd3, 0xa8, 0xf1,
+		0x48, 0x2a, 0x94, 0x4f, 0x34, 0xfe, 0x40, 0x4c, 0x37, 0x10, 0xd0, 0x28,
+		0xc2, 0x3c, 0x11, 0x3a, 0x3e, 0x9d, 0x7c, 0xe4, 0xbb, 0xfc, 0xc7, 0xf4,
+		0x3b, 0x9b, 0xd4, 0x42, 0x20, 0x62, 0x0b, 0xaa, 0x85, 0x42, 0xf8, 0x8d,
+		0xf2, 0xd2, 0x94, 0x85, 0xed, 0xfa, 0x7f, 0x20, 0xf5, 0x4d, 0xbd, 0xfe,
+		0x23, 0xe7, 0x50, 0xd5, 0x8f, 0x1d, 0xed, 0xb1, 0xf9, 0x81, 0xc4, 0x8b,
+		0xb1, 0x9d, 0x83, 0x6b, 0x72, 0x6e, 0xf0, 0xfd, 0x7c, 0x02, 0x64, 0xfe,
+		0x2a, 0x38, 0x1b, 0x02, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0007_libraries.sql",
+	)
+}
+
+func res_sqlite_migrations_0008_art_source_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x25, 0xca,
+		0x41, 0x0a, 0xc2, 0x30, 0x10, 0x05, 0xd0, 0x7d, 0x4f, 0xf1, 0xc9, 0xb2,
+		0x9b, 0x66, 0x29, 0xba, 0x8a, 0x26, 0x8a, 0x30, 0xa6, 0x20, 0x93, 0x03,
+		0x44, 0x33, 0x48, 0xa0, 0xb5, 0x35, 0x8d, 0x7a, 0x7d, 0x0b, 0xae, 0xdf,
+		0xeb, 0x5a, 0x7c, 0xe3, 0x47, 0xe6, 0x3c, 0x0b, 0x96, 0xd7, 0x90, 0xab,
+		0x60, 0xcc, 0x8f, 0x12, 0x6b, 0x9e, 0x9e, 0xd0, 0x5a, 0x6f, 0xb6, 0x90,
+		0xf1, 0x26, 0x29, 0x49, 0x42, 0x2c, 0x15, 0xcb, 0xf4, 0x2e, 0x77, 0x41,
+		0xdb, 0x35, 0x86, 0xd8, 0x5d, 0xc1, 0x66, 0x4f, 0x0e, 0x6a, 0x25, 0x05,
+		0x63, 0x2d, 0x0e, 0x3d, 0x85, 0x8b, 0x87, 0xfa, 0x3f, 0x85, 0xb3, 0x67,
+		0x77, 0x5a, 0x9f, 0xef, 0x19, 0x3e, 0x10, 0xc1, 0xba, 0xa3, 0x09, 0xc4,
+		0xd0, 0xbb, 0xe6, 0x07, 0xaa, 0x33, 0xef, 0x72, 0x7c, 0x00, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0008_art_source.sql",
+	)
+}
+
+func res_sqlite_migrations_0009_album_art_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x7d, 0x90,
+		0xb1, 0x6e, 0xc2, 0x30, 0x10, 0x86, 0xe7, 0xe6, 0x29, 0x7e, 0x79, 0xa2,
+		0xa8, 0x12, 0x59, 0x69, 0x27, 0x97, 0x1c, 0x95, 0x55, 0xc7, 0xa9, 0xc2,
+		0x45, 0x82, 0x29, 0x72, 0x85, 0x85, 0x2c, 0x05, 0x48, 0x93, 0x40, 0xd5,
+		0xb7, 0xaf, 0x49, 0xd2, 0x76, 0x00, 0xd5, 0xdb, 0xf9, 0x7e, 0x7f, 0xf7,
+		0xf9, 0x66, 0x53, 0x7c, 0xda, 0xb3, 0xab, 0x7d, 0xed, 0xd0, 0x7e, 0x54,
+		0xbe, 0x73, 0xd8, 0xfb, 0x5d, 0x63, 0x3b, 0x7f, 0x3c, 0x20, 0x8e, 0xe3,
+		0xf9, 0x23, 0x6c, 0xf5, 0x7e, 0xda, 0xc3, 0x36, 0x1d, 0xec, 0x61, 0x8b,
+		0x9d, 0xad, 0x2a, 0xd7, 0x78, 0xd7, 0x62, 0x3a, 0x8b, 0xa4, 0x66, 0xca,
+		0xc1, 0xf2, 0x59, 0x13, 0x44, 0x9f, 0x6b, 0x05, 0x64, 0x92, 0x60, 0x91,
+		0xe9, 0x22, 0x35, 0xe1, 0xae, 0xe9, 0x4a, 0xbf, 0x15, 0x50, 0x86, 0xe9,
+		0x25, 0x44, 0x4d, 0xc6, 0x30, 0x85, 0xd6, 0x48, 0x68, 0x29, 0x0b, 0xcd,
+		0x88, 0x9f, 0xa2, 0x45, 0x4e, 0x92, 0x69, 0xa4, 0xa8, 0x65, 0x9f, 0xa1,
+		0xb5, 0x5a, 0xf1, 0x6a, 0x64, 0x96, 0xc3, 0xcc, 0x2f, 0x81, 0x49, 0x74,
+		0x27, 0x2e, 0xb8, 0xe1, 0xfc, 0x40, 0xdf, 0x72, 0x95, 0xca, 0x7c, 0x83,
+		0x57, 0xda, 0x40, 0x16, 0x9c, 0x29, 0x13, 0x90, 0x29, 0x19, 0x7e, 0x08,
+		0xf1, 0x81, 0x70, 0xcb, 0xa1, 0xef, 0x8e, 0x7e, 0xb8, 0xd9, 0xad, 0x8f,
+		0xad, 0xbf, 0x2c, 0xe2, 0xfa, 0x6d, 0x74, 0xff, 0xeb, 0xad, 0x4c, 0x42,
+		0xeb, 0x7f, 0xbd, 0xcb, 0xbe, 0x52, 0x61, 0x4c, 0x66, 0xae, 0xbf, 0xf4,
+		0x67, 0x18, 0x98, 0xdf, 0x88, 0xae, 0x96, 0x43, 0x8f, 0x01, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0009_album_art.sql",
+	)
+}
+
+func res_sqlite_migrations_0010_cue_tracks_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x8d, 0x8f,
+		0xb1, 0x4e, 0xc3, 0x30, 0x14, 0x45, 0xf7, 0x7c, 0xc5, 0x95, 0x27, 0xa8,
+		0x90, 0x1a, 0x56, 0x32, 0x99, 0xd8, 0x45, 0x91, 0x8c, 0x0d, 0xa9, 0x23,
+		0x75, 0xb3, 0x2c, 0x70, 0x52, 0x8b, 0xd6, 0x69, 0x63, 0x97, 0xfe, 0x3e,
+		0x0e, 0xaa, 0x3a, 0x30, 0x00, 0xcb, 0x1b, 0x9e, 0xee, 0x3d, 0xba, 0x67,
+		0xb9, 0xc0, 0xd9, 0x7e, 0xba, 0x83, 0x3f, 0x38, 0xc4, 0xe3, 0xce, 0x27,
+		0x87, 0xbd, 0x1f, 0x26, 0x9b, 0xfc, 0x18, 0x50, 0x96, 0xf7, 0xe5, 0x03,
+		0xea, 0x8e, 0x23, 0x6e, 0x9d, 0x4b, 0x48, 0x93, 0x7d, 0xfb, 0x88, 0x38,
+		0xfb, 0xb4, 0xf5, 0x01, 0x16, 0xd1, 0x87, 0x61, 0xe7, 0xd0, 0xfb, 0x7c,
+		0x16, 0xcb, 0x82, 0x0a, 0xcd, 0x5b, 0x68, 0xfa, 0x28, 0x38, 0x48, 0x1c,
+		0xc3, 0x10, 0x09, 0x28, 0x63, 0xa8, 0x95, 0xe8, 0x9e, 0x65, 0x7e, 0x25,
+		0x3b, 0x25, 0x33, 0xf6, 0x7d, 0x74, 0x89, 0xa0, 0x91, 0x9a, 0x3f, 0xe5,
+		0xbc, 0x54, 0x1a, 0xb2, 0x13, 0x02, 0x8c, 0xaf, 0x68, 0x27, 0x34, 0xca,
+		0xea, 0x4f, 0x92, 0x0b, 0xef, 0xff, 0xe2, 0xb0, 0x56, 0xbd, 0xe4, 0x00,
+		0xe3, 0x1b, 0x34, 0x2b, 0xf0, 0x4d, 0xb3, 0xd6, 0xeb, 0x0b, 0xd0, 0x9c,
+		0x82, 0x3f, 0x9e, 0x9c, 0x99, 0xc7, 0x4b, 0xbb, 0x77, 0xa4, 0x2a, 0xea,
+		0x96, 0x53, 0xcd, 0xd1, 0xc9, 0xe6, 0x35, 0x2b, 0x5f, 0x6b, 0x33, 0xf8,
+		0xd7, 0xaa, 0xf9, 0x16, 0x53, 0x97, 0x3d, 0x4a, 0x5e, 0x27, 0xdf, 0x90,
+		0x39, 0x62, 0xc2, 0x8c, 0xbf, 0xfb, 0xe1, 0x7f, 0x5b, 0x15, 0x5f, 0xe6,
+		0x30, 0x6f, 0xd2, 0x7c, 0x01, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0010_cue_tracks.sql",
+	)
+}
+
+func res_sqlite_migrations_0011_song_fingerprints_sql() ([]byte, error) {
+	return bindata_read([]byte{
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x65, 0x8f,
+		0x4f, 0x6f, 0x82, 0x40, 0x14, 0xc4, 0xcf, 0xf2, 0x29, 0x26, 0x7b, 0xf1,
+		0x4f, 0x4c, 0xd5, 0x6b, 0x49, 0x0f, 0x28, 0x0f, 0x4b, 0xb2, 0x2e, 0x0d,
+		0x2c, 0xad, 0x37, 0x43, 0xca, 0x6a, 0x37, 0x51, 0xa0, 0x2c, 0xd5, 0xaf,
+		0xdf, 0x45, 0xd1, 0x54, 0x7b, 0xd8, 0xe4, 0x65, 0xe7, 0xcd, 0xef, 0xcd,
+		0x4c, 0x46, 0x38, 0x65, 0x47, 0x55, 0xe9, 0x4a, 0xc1, 0x7c, 0xef, 0x75,
+		0xa3, 0x70, 0xd0, 0xbb, 0x3a, 0x6b, 0x74, 0x59, 0x60, 0x3a, 0x9d, 0xcd,
+		0x9e, 0x61, 0xca, 0x62, 0x87, 0xad, 0x2e, 0x76, 0xaa, 0xae, 0x6a, 0x5d,
+		0x34, 0x66, 0x8c, 0xac, 0xc8, 0xb1, 0x2d, 0xf7, 0xb9, 0xaa, 0x61, 0x54,
+		0x56, 0x7f, 0x7e, 0xe1, 0xa7, 0xca, 0xb3, 0x46, 0x19, 0xfb, 0x5b, 0xe3,
+		0x50, 0x1e, 0xd5, 0x55, 0x37, 0x18, 0x4d, 0x1c, 0x8f, 0x4b, 0x8a, 0x21,
+		0xbd, 0x39, 0x27, 0xb0, 0x16, 0x67, 0x18, 0x3c, 0xdf, 0xc7, 0x22, 0xe2,
+		0xe9, 0x4a, 0x80, 0xfd, 0x81, 0x33, 0x48, 0x5a, 0x4b, 0x88, 0xc8, 0xbe,
+		0x94, 0x73, 0xf8, 0x14, 0x78, 0x29, 0x97, 0xe8, 0xf7, 0x5d, 0x67, 0x11,
+		0x93, 0x27, 0x09, 0xa1, 0xf0, 0x69, 0x8d, 0x30, 0x38, 0x2f, 0xd1, 0x3a,
+		0x4c, 0x64, 0xd2, 0x51, 0x37, 0x77, 0xa0, 0x48, 0xdc, 0x8e, 0x0d, 0xee,
+		0x4e, 0x0c, 0x6f, 0x28, 0x19, 0x87, 0xcb, 0xa5, 0x8d, 0xf6, 0x00, 0xeb,
+		0xa2, 0x6f, 0x2e, 0xdd, 0x36, 0x97, 0x6e, 0x36, 0x72, 0xd0, 0xd6, 0x48,
+		0xdf, 0xfc, 0xd6, 0xda, 0xd2, 0xbb, 0x3d, 0x86, 0x39, 0x2d, 0x43, 0xe1,
+		0xf4, 0x7c, 0xe2, 0x64, 0xa5, 0x20, 0x8e, 0x56, 0x8f, 0x10, 0x86, 0x8f,
+		0x57, 0x8a, 0x6d, 0xfd, 0xba, 0x3c, 0xe9, 0x9c, 0xe1, 0x05, 0x56, 0x7e,
+		0x62, 0x76, 0x74, 0x9d, 0x5e, 0x28, 0x12, 0x8a, 0xa5, 0x2d, 0x26, 0xa3,
+		0xff, 0xbe, 0x41, 0x67, 0x19, 0x83, 0x35, 0xba, 0xd9, 0x2b, 0x36, 0xc4,
+		0xbb, 0xc7, 0x53, 0x4a, 0x30, 0x28, 0xd4, 0xe9, 0x8c, 0x18, 0xe3, 0x3c,
+		0x75, 0xb2, 0xeb, 0x90, 0xf0, 0x5d, 0xe7, 0x17, 0x20, 0xd1, 0xc5, 0x46,
+		0xda, 0x01, 0x00, 0x00,
+	},
+		"res/sqlite/migrations/0011_song_fingerprints.sql",
+	)
+}
+
 func res_sqlite_wavepipe_db() ([]byte, error) {
 	return bindata_read([]byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xec, 0x9a,
-		0xcf, 0x8f, 0xdb, 0x44, 0x14, 0xc7, 0x3d, 0x76, 0x36, 0xee, 0x66, 0x7f,
-		0x01, 0x29, 0xb2, 0x56, 0xd5, 0x4a, 0x96, 0x4f, 0x35, 0xdd, 0x9e, 0xaa,
-		0xaa, 0x42, 0xe2, 0x40, 0x00, 0x0b, 0x45, 0x6c, 0xb3, 0xed, 0x92, 0x88,
-		0xee, 0xa1, 0x8a, 0xbc, 0xc9, 0x64, 0xd7, 0xc4, 0xb1, 0x53, 0xdb, 0x2b,
-		0xba, 0xdc, 0x1c, 0x71, 0x81, 0x0b, 0x17, 0x10, 0xff, 0x12, 0x77, 0xc4,
-		0xbf, 0x00, 0xea, 0x91, 0x0b, 0x17, 0x2e, 0xcc, 0x0f, 0xcf, 0xc6, 0x76,
-		0x26, 0xdb, 0xa8, 0x52, 0x15, 0xa9, 0x7e, 0x1f, 0x25, 0x4e, 0xfc, 0xfc,
-		0x3c, 0x7e, 0xf3, 0x9d, 0x19, 0x4f, 0xf2, 0x3c, 0x5f, 0x3f, 0x3d, 0xf2,
-		0x12, 0x6c, 0x8e, 0xc2, 0x68, 0xe2, 0x26, 0xe6, 0x03, 0xa5, 0xa6, 0x20,
-		0xa4, 0x7c, 0x6a, 0x9a, 0x8a, 0xa2, 0x20, 0xf2, 0xbe, 0xad, 0xcc, 0xd9,
-		0x25, 0xef, 0x5a, 0x6e, 0x1f, 0x29, 0xaf, 0x07, 0x29, 0xf7, 0xff, 0x52,
-		0x37, 0xc8, 0x17, 0x4d, 0xfb, 0x87, 0xee, 0xdf, 0xd6, 0xfe, 0xd3, 0xfe,
-		0xe5, 0x5f, 0x01, 0x00, 0x58, 0x07, 0xef, 0x6d, 0x93, 0xcd, 0xd6, 0x16,
-		0xd9, 0x34, 0xea, 0x3b, 0xd4, 0x50, 0x5b, 0x6f, 0x3c, 0x00, 0x00, 0xac,
-		0x07, 0x18, 0xff, 0x00, 0x50, 0x5d, 0x1a, 0x74, 0x03, 0xe3, 0x1f, 0x00,
-		0x2a, 0x09, 0xcc, 0xff, 0x00, 0x50, 0x5d, 0x60, 0xfe, 0x07, 0x80, 0xea,
-		0x02, 0xf3, 0x3f, 0x00, 0x54, 0x17, 0x98, 0xff, 0x01, 0xa0, 0xba, 0xc0,
-		0xfc, 0x0f, 0x00, 0xd5, 0x85, 0x8e, 0xff, 0xba, 0xf2, 0x4a, 0x51, 0x5e,
-		0xa1, 0x5f, 0xd1, 0xdf, 0x6a, 0xa0, 0x7d, 0xa4, 0xfd, 0xbc, 0xee, 0x98,
-		0xde, 0x0a, 0xa9, 0x8d, 0x74, 0x63, 0x7f, 0x1f, 0xcd, 0xee, 0x27, 0xee,
-		0x99, 0x8f, 0x5d, 0xff, 0xec, 0x72, 0x12, 0xf3, 0xad, 0xfa, 0xf9, 0x89,
-		0xd3, 0xea, 0x3a, 0x66, 0xb7, 0xf5, 0xd9, 0x91, 0x63, 0x5a, 0xdc, 0x68,
-		0x99, 0x77, 0x1b, 0x9b, 0x96, 0x37, 0xb4, 0xcc, 0x8c, 0x76, 0xa7, 0xeb,
-		0x7c, 0xe9, 0x9c, 0x98, 0x4f, 0x4e, 0xda, 0x8f, 0x5b, 0x27, 0xa7, 0xe6,
-		0x57, 0xce, 0xa9, 0xd9, 0xea, 0x75, 0x8f, 0xdb, 0x1d, 0x72, 0xfa, 0x63,
-		0xa7, 0xd3, 0x3d, 0x24, 0xfe, 0x6e, 0x94, 0x78, 0x71, 0xd2, 0xa7, 0xa7,
-		0x09, 0xff, 0xce, 0x71, 0xd7, 0xec, 0xf4, 0x8e, 0x8e, 0xe8, 0xe1, 0xc4,
-		0x4b, 0x7c, 0xcc, 0x4b, 0xec, 0x3a, 0xcf, 0xd8, 0x19, 0x57, 0xd8, 0x8d,
-		0xac, 0xc2, 0x15, 0x1a, 0xf6, 0x13, 0xb5, 0x6e, 0xdc, 0xbb, 0x87, 0x4e,
-		0x59, 0xa4, 0xf1, 0x0b, 0xdf, 0x4b, 0x70, 0x3f, 0xc6, 0x2f, 0x2e, 0x71,
-		0x30, 0x28, 0xef, 0x6a, 0x85, 0xd8, 0x4b, 0x07, 0xef, 0x06, 0xee, 0x04,
-		0x1f, 0x92, 0x3d, 0x3b, 0xad, 0x69, 0xba, 0xe1, 0xec, 0xa3, 0xf4, 0x91,
-		0x17, 0x0c, 0xf1, 0x4b, 0x5e, 0xc5, 0xfe, 0x65, 0xe0, 0x11, 0xc7, 0x3e,
-		0x0f, 0xba, 0x3d, 0xec, 0xb3, 0xf0, 0xf8, 0xb1, 0x5a, 0x56, 0x6e, 0xaf,
-		0xd3, 0x7e, 0xda, 0x73, 0x48, 0x68, 0x5f, 0x38, 0xcf, 0x84, 0x34, 0xf2,
-		0xf3, 0x2c, 0xf3, 0xb8, 0x93, 0x13, 0x2f, 0x27, 0xc5, 0xa1, 0x99, 0x55,
-		0xdc, 0x4e, 0x3f, 0xae, 0xe9, 0x46, 0xb3, 0x89, 0x66, 0xbc, 0x6a, 0xc4,
-		0x85, 0xbc, 0x36, 0x4a, 0xf2, 0x47, 0xc9, 0x82, 0xf6, 0x2b, 0xeb, 0x3f,
-		0xf2, 0x7c, 0x52, 0x7d, 0xef, 0xfb, 0x4c, 0x64, 0x59, 0x1b, 0x30, 0x17,
-		0x2a, 0x4c, 0xb1, 0x1d, 0x7c, 0x97, 0x04, 0x3b, 0x09, 0x87, 0xde, 0xc8,
-		0xc3, 0x92, 0xd6, 0x6b, 0xd8, 0x83, 0x0d, 0xdd, 0x78, 0xd0, 0x44, 0xe9,
-		0x0e, 0x57, 0x30, 0x4a, 0x84, 0x0c, 0xb4, 0xc0, 0x0e, 0x29, 0x8f, 0x98,
-		0xea, 0x72, 0xd5, 0x16, 0x7d, 0x33, 0xb1, 0x58, 0x55, 0x73, 0x11, 0xd9,
-		0x61, 0x5d, 0x37, 0xee, 0xdc, 0x41, 0xe9, 0x43, 0xa1, 0x0f, 0x91, 0x30,
-		0xce, 0x3e, 0xf4, 0x05, 0x9d, 0xa8, 0xb5, 0xa0, 0xd5, 0x4a, 0x1a, 0x65,
-		0xad, 0x45, 0x2b, 0xde, 0xb0, 0xe9, 0xf8, 0xdf, 0x40, 0xcf, 0x15, 0xf4,
-		0x1c, 0xfd, 0xae, 0x8e, 0xd5, 0x3f, 0xb4, 0xdf, 0xd6, 0x3d, 0x50, 0xdf,
-		0x21, 0x3c, 0x5d, 0x37, 0x1e, 0x92, 0xd6, 0xdc, 0x13, 0x5d, 0x86, 0x36,
-		0x98, 0xe8, 0x0a, 0x7c, 0xb0, 0x71, 0xdb, 0xad, 0x65, 0xfd, 0x66, 0xe1,
-		0x84, 0xeb, 0x8e, 0x93, 0xb5, 0xfd, 0xf5, 0xc8, 0x32, 0x6f, 0xb1, 0x9e,
-		0x33, 0x33, 0x58, 0xcf, 0x19, 0x85, 0xfe, 0x10, 0x47, 0x71, 0xf6, 0xb1,
-		0x59, 0xec, 0x39, 0x99, 0xf5, 0xcd, 0xee, 0x70, 0x53, 0x37, 0xc2, 0x41,
-		0xe1, 0x0e, 0xb7, 0xe4, 0xc6, 0x36, 0x75, 0x93, 0x8b, 0xac, 0x68, 0xde,
-		0xd5, 0x46, 0x9b, 0x64, 0x00, 0x11, 0x35, 0xb6, 0x98, 0x1a, 0x59, 0x10,
-		0xa2, 0x72, 0xd4, 0x3b, 0x33, 0x6d, 0x4b, 0xc5, 0x90, 0xf8, 0x73, 0x2d,
-		0xe6, 0xb5, 0xe1, 0x97, 0xb4, 0xd3, 0x56, 0x43, 0x37, 0x0e, 0x0e, 0xd0,
-		0xac, 0xc7, 0xef, 0x9f, 0x38, 0x8e, 0xbd, 0x30, 0x88, 0xc5, 0xe7, 0x4e,
-		0x51, 0x0c, 0x61, 0x2e, 0xa9, 0xb1, 0x92, 0x16, 0x97, 0x31, 0x8e, 0x96,
-		0xde, 0xeb, 0x07, 0xbe, 0x47, 0x84, 0xb2, 0xe6, 0x82, 0xe0, 0x97, 0x53,
-		0x2f, 0xa2, 0x22, 0xc9, 0xbc, 0xc7, 0xf8, 0xca, 0xca, 0x69, 0x75, 0xbe,
-		0x45, 0xb4, 0x3a, 0x10, 0x5a, 0x89, 0x18, 0x45, 0xe5, 0x89, 0xb3, 0x30,
-		0xed, 0x4a, 0xc5, 0x92, 0x9c, 0xc0, 0xc5, 0xca, 0xd5, 0x96, 0x5d, 0xd2,
-		0x86, 0xff, 0xff, 0x00, 0x50, 0x5d, 0xe0, 0xff, 0x3f, 0x00, 0x54, 0x17,
-		0x98, 0xff, 0x01, 0xa0, 0xba, 0xc0, 0xfc, 0x0f, 0x00, 0xd5, 0x85, 0x8e,
-		0x7f, 0x84, 0x7e, 0x52, 0xc8, 0x0b, 0x58, 0x2b, 0x3f, 0xc4, 0xdb, 0xba,
-		0x61, 0x18, 0xe8, 0x47, 0x87, 0xa7, 0xad, 0xc2, 0xe0, 0x3c, 0x66, 0x9b,
-		0xbd, 0x52, 0xc2, 0x8a, 0xda, 0xde, 0x3c, 0x43, 0xce, 0x12, 0xf4, 0xfd,
-		0x85, 0x3c, 0x57, 0x3e, 0x17, 0x45, 0xf3, 0xd5, 0x92, 0xb4, 0x60, 0xc9,
-		0x45, 0x3c, 0xe7, 0x58, 0xe6, 0x72, 0xe6, 0x25, 0x91, 0x9b, 0x60, 0xeb,
-		0x86, 0x52, 0x06, 0x17, 0x6e, 0x10, 0x60, 0x3f, 0xbe, 0x21, 0x96, 0x41,
-		0x38, 0x99, 0xf0, 0x34, 0x5a, 0x21, 0xb9, 0xb8, 0x24, 0x89, 0xbf, 0x6a,
-		0xfa, 0x3f, 0xb9, 0x9a, 0x62, 0x1e, 0xbc, 0xd4, 0x85, 0x65, 0x14, 0x6f,
-		0xac, 0xdc, 0x39, 0x0e, 0x22, 0x3c, 0x17, 0x7f, 0xc5, 0x87, 0x08, 0xcc,
-		0x05, 0x07, 0xe7, 0xd7, 0x89, 0x51, 0x79, 0xe1, 0xb1, 0x3b, 0x99, 0x92,
-		0x20, 0x85, 0x7a, 0xaf, 0x79, 0x90, 0x94, 0xbf, 0x3e, 0x51, 0x7c, 0x30,
-		0xb6, 0xca, 0x1d, 0xa2, 0xf4, 0x98, 0xa9, 0xf0, 0xa8, 0x09, 0x7e, 0xff,
-		0x03, 0x40, 0x75, 0x81, 0xdf, 0xff, 0x00, 0x50, 0x5d, 0x60, 0xfe, 0x07,
-		0x80, 0xea, 0x42, 0xe7, 0x7f, 0x4d, 0xbd, 0x50, 0xd4, 0x0b, 0xf5, 0x4f,
-		0xed, 0x97, 0x75, 0x47, 0x03, 0xbc, 0xab, 0x8c, 0x77, 0x74, 0xe3, 0x91,
-		0x81, 0xd2, 0x0f, 0xf9, 0x82, 0x16, 0x9a, 0xc3, 0x28, 0xaf, 0x89, 0x63,
-		0xc6, 0xf7, 0xe5, 0xeb, 0x59, 0x64, 0xfe, 0xd9, 0x8a, 0x96, 0x2c, 0x1d,
-		0x92, 0x5f, 0x45, 0x97, 0x7e, 0xb2, 0xcb, 0x52, 0x29, 0xb3, 0x6f, 0x58,
-		0x2a, 0x85, 0x2e, 0xd3, 0x89, 0xd9, 0xe6, 0x83, 0x62, 0x2a, 0x85, 0xd9,
-		0x64, 0xa9, 0x94, 0x95, 0x57, 0xff, 0x2c, 0xa6, 0x20, 0xa6, 0x6e, 0x1c,
-		0x7f, 0x17, 0x46, 0xc3, 0xa2, 0x35, 0x0a, 0x7d, 0xbc, 0x90, 0x74, 0x11,
-		0x19, 0x83, 0xd1, 0xa4, 0x9f, 0x84, 0x63, 0x1c, 0x88, 0x35, 0x79, 0xdf,
-		0xee, 0x71, 0xad, 0x9a, 0x4c, 0x2b, 0x16, 0xa4, 0xa8, 0xbb, 0xb8, 0x26,
-		0x33, 0x36, 0xa5, 0x5a, 0x49, 0xfd, 0xb9, 0x56, 0xa2, 0xbe, 0xf3, 0xd0,
-		0xed, 0xff, 0x03, 0x00, 0x00, 0xff, 0xff, 0x42, 0xae, 0x50, 0x25, 0x00,
-		0x50, 0x00, 0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0xed, 0xdd,
+		0xcd, 0x73, 0xdc, 0xe6, 0x7d, 0xc0, 0xf1, 0x85, 0x48, 0x11, 0x7c, 0x11,
+		0x45, 0xd9, 0xb4, 0x82, 0xc8, 0x8c, 0x22, 0x78, 0x13, 0x85, 0xdc, 0x68,
+		0x25, 0x59, 0xa6, 0x65, 0x59, 0x96, 0xdd, 0x84, 0x12, 0xd7, 0xf2, 0x4e,
+		0xe8, 0xa5, 0x45, 0xed, 0x5a, 0x56, 0x67, 0x9c, 0xed, 0x72, 0x17, 0xa4,
+		0x51, 0xed, 0x0b, 0xb5, 0x00, 0x6d, 0x51, 0x8a, 0x93, 0x82, 0x4a, 0xdc,
+		0xa6, 0x97, 0x5c, 0x7b, 0x6f, 0x93, 0xc9, 0xa5, 0xd3, 0x43, 0xef, 0xbd,
+		0xf6, 0xda, 0x99, 0xe4, 0xd4, 0xe9, 0xa9, 0xff, 0x40, 0xa7, 0x33, 0x1d,
+		0x77, 0x3a, 0x3e, 0xa4, 0x0f, 0xde, 0x96, 0x00, 0xf6, 0xd9, 0xe5, 0x4a,
+		0x8a, 0xd2, 0x0e, 0xf2, 0xfd, 0x8c, 0x48, 0x2d, 0x1e, 0x3c, 0xc0, 0xf3,
+		0x7b, 0x1e, 0x3c, 0x0f, 0x80, 0x07, 0xb0, 0xd6, 0xb7, 0x6f, 0xad, 0x99,
+		0xb6, 0xa1, 0x6f, 0x75, 0xba, 0xad, 0x9a, 0xad, 0x2f, 0x67, 0x4e, 0x64,
+		0x14, 0x25, 0xf3, 0x7d, 0x5d, 0xcf, 0x64, 0x32, 0x63, 0xe2, 0xe7, 0xad,
+		0xcc, 0x81, 0x55, 0xf1, 0x33, 0x1e, 0x59, 0x56, 0xc4, 0xcf, 0x4c, 0x66,
+		0xb8, 0xb1, 0xcc, 0x85, 0xbf, 0x7e, 0xf1, 0xa8, 0xf8, 0x70, 0x64, 0xee,
+		0x4b, 0x77, 0xf9, 0xf5, 0xb9, 0xaf, 0xfc, 0x0f, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0xe0, 0x0f, 0xe0, 0xa5, 0x8b, 0xe2, 0xd7, 0x8b, 0x27, 0x66, 0xdd,
+		0xcf, 0x27, 0xfe, 0x8f, 0x63, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xcf,
+		0x15, 0xf3, 0x7f, 0x00, 0x00, 0x00, 0x00, 0x00, 0xd2, 0x8f, 0xf9, 0x3f,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0xe9, 0xc7, 0xfc, 0x1f, 0x00, 0x00, 0x00,
+		0x00, 0x80, 0xf4, 0x63, 0xfe, 0x0f, 0x00, 0x00, 0x00, 0x00, 0x40, 0xfa,
+		0x31, 0xff, 0x07, 0x00, 0x00, 0x00, 0x00, 0x20, 0xfd, 0x98, 0xff, 0x03,
+		0x00, 0x00, 0x00, 0x00, 0x90, 0x7e, 0xcc, 0xff, 0x01, 0x00, 0x00, 0x00,
+		0x00, 0x48, 0x3f, 0xe6, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa4, 0x1f,
+		0xf3, 0x7f, 0x00, 0x00, 0x00, 0x00, 0x00, 0xd2, 0x8f, 0xf9, 0x3f, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0xe9, 0xc7, 0xfc, 0x1f, 0x00, 0x00, 0x00, 0x00,
+		0x80, 0xf4, 0x63, 0xfe, 0x0f, 0x00, 0x00, 0x00, 0x00, 0x40, 0xfa, 0x31,
+		0xff, 0x07, 0x00, 0x00, 0x00, 0x00, 0x20, 0xfd, 0x98, 0xff, 0x03, 0x00,
+		0x00, 0x00, 0x00, 0x90, 0x7e, 0xcc, 0xff, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x48, 0x3f, 0x77, 0xfe, 0x7f, 0x22, 0xf3, 0xc3, 0xcc, 0xdc, 0x85, 0xe3,
+		0xff, 0x76, 0x5c, 0x3d, 0xf6, 0x9f, 0xc7, 0xfe, 0x62, 0xe6, 0x1f, 0x66,
+		0xce, 0x4d, 0xb7, 0xa6, 0xbe, 0x98, 0xfc, 0x47, 0xf5, 0x37, 0xea, 0xdc,
+		0xd1, 0xdf, 0x28, 0xff, 0xad, 0x9c, 0x16, 0x19, 0x3e, 0x7c, 0x9a, 0xbd,
+		0x3b, 0x6f, 0x9f, 0x50, 0x35, 0x4d, 0x53, 0xf6, 0xef, 0xd8, 0xb5, 0xcd,
+		0xa6, 0xb1, 0x6b, 0x19, 0x5d, 0xcb, 0xfb, 0xf5, 0xc2, 0x8d, 0x8d, 0xc2,
+		0x4a, 0xb9, 0xa0, 0x97, 0x57, 0xae, 0xaf, 0x15, 0xf4, 0xac, 0x97, 0x96,
+		0xd5, 0x97, 0xa6, 0xa7, 0xb2, 0x66, 0x23, 0xab, 0x1f, 0x28, 0x96, 0xca,
+		0x85, 0x9b, 0x85, 0x0d, 0xfd, 0x83, 0x8d, 0xe2, 0xfb, 0x2b, 0x1b, 0x77,
+		0xf5, 0x1f, 0x14, 0xee, 0xea, 0x2b, 0x95, 0xf2, 0x7a, 0xb1, 0x24, 0x76,
+		0xf0, 0x7e, 0xa1, 0x54, 0xce, 0x8b, 0x4d, 0xdc, 0xad, 0xdb, 0xb5, 0x96,
+		0xe1, 0x6f, 0x58, 0x2e, 0x7c, 0xe4, 0xa5, 0xee, 0xd4, 0x2c, 0xeb, 0xb3,
+		0x4e, 0xb7, 0x11, 0x4f, 0xed, 0x76, 0x9a, 0x46, 0xb5, 0x57, 0x46, 0xb0,
+		0x7b, 0x77, 0x45, 0xb3, 0x66, 0xd9, 0x5b, 0xad, 0xaa, 0xdd, 0xb9, 0x67,
+		0xb4, 0xb3, 0x5e, 0xf6, 0xe9, 0x9c, 0x73, 0x67, 0xce, 0x0b, 0xff, 0xf1,
+		0xac, 0x17, 0xbe, 0x65, 0xd7, 0xba, 0x96, 0xf7, 0xeb, 0x44, 0x3c, 0x7c,
+		0x2f, 0xad, 0x2f, 0xfc, 0x91, 0x63, 0x0f, 0xe2, 0x09, 0xf3, 0x97, 0xd6,
+		0xcb, 0x7a, 0xa9, 0xb2, 0xb6, 0xe6, 0xae, 0x36, 0x6d, 0x43, 0xc4, 0xb4,
+		0xb7, 0x63, 0xf8, 0x21, 0xf5, 0xaf, 0x1b, 0xbc, 0x69, 0xbd, 0x6b, 0xd4,
+		0x6c, 0x43, 0xba, 0x7a, 0x3a, 0xf7, 0x97, 0x77, 0x8f, 0x7b, 0x35, 0xfb,
+		0xc5, 0x0b, 0x7e, 0xcd, 0x3a, 0xed, 0x6d, 0xcb, 0xfb, 0x35, 0x97, 0xa8,
+		0x99, 0x9b, 0x26, 0x3b, 0x30, 0xa3, 0xd5, 0xae, 0xd6, 0x68, 0x18, 0x91,
+		0xcd, 0x92, 0x71, 0xe8, 0xab, 0x85, 0x77, 0x57, 0x2a, 0x6b, 0x65, 0xfd,
+		0x55, 0x2f, 0x73, 0x73, 0x73, 0xb7, 0xd5, 0x77, 0x6c, 0x62, 0x75, 0xaa,
+		0x75, 0xed, 0xaa, 0xa4, 0x85, 0x13, 0x59, 0x4c, 0xeb, 0x20, 0x97, 0x2c,
+		0xcb, 0xa6, 0x69, 0x77, 0x45, 0xd3, 0x64, 0x87, 0xec, 0xa5, 0xfe, 0x49,
+		0xad, 0xdd, 0x36, 0x9a, 0xd6, 0x90, 0x58, 0xea, 0x9d, 0x56, 0xcb, 0x68,
+		0xdb, 0xe1, 0x5e, 0xc2, 0x0e, 0xd6, 0x30, 0xad, 0x7a, 0xa4, 0xa1, 0x86,
+		0x57, 0xd9, 0xcd, 0x2c, 0xba, 0x9c, 0x5d, 0x6b, 0x66, 0x0f, 0xcf, 0x6c,
+		0xb4, 0x1b, 0xd5, 0xce, 0xd6, 0x96, 0x65, 0xd8, 0x23, 0x64, 0xde, 0x32,
+		0x45, 0x3f, 0xef, 0x1f, 0x14, 0x5e, 0xb2, 0x65, 0x3e, 0x34, 0x06, 0xb7,
+		0x8e, 0x97, 0xc5, 0xed, 0x72, 0x7e, 0x1b, 0xca, 0xb3, 0xb4, 0xb7, 0x8d,
+		0xee, 0x4e, 0xd7, 0xf4, 0xab, 0x1f, 0xeb, 0x99, 0xbd, 0x30, 0x16, 0x17,
+		0xbd, 0xac, 0x9d, 0x66, 0xa3, 0xd7, 0xc3, 0xe5, 0x7b, 0xdb, 0x36, 0xda,
+		0x5d, 0xe3, 0xa0, 0xc9, 0xc2, 0x50, 0xdd, 0x01, 0x59, 0x6d, 0x75, 0x1a,
+		0xe6, 0x96, 0xe9, 0xf6, 0x21, 0xd9, 0x96, 0x4d, 0xa3, 0xbd, 0x6d, 0x7f,
+		0x32, 0xb4, 0x3b, 0x34, 0xcd, 0xcd, 0x6e, 0xad, 0xbb, 0x17, 0x06, 0x30,
+		0xbc, 0xd1, 0xac, 0x5a, 0x6b, 0x47, 0x54, 0x3e, 0xec, 0x1c, 0xb2, 0xfd,
+		0xb9, 0x43, 0xdd, 0x3e, 0x38, 0x0c, 0xc3, 0xf7, 0x67, 0x9b, 0x76, 0x53,
+		0x52, 0x33, 0xd1, 0xfb, 0xea, 0xf7, 0xfa, 0x46, 0x45, 0x6f, 0xcd, 0x41,
+		0x87, 0x18, 0xbe, 0xf7, 0x3d, 0xa3, 0xd6, 0xed, 0xef, 0x69, 0xd3, 0xb9,
+		0xfd, 0xab, 0xb3, 0xaa, 0x76, 0xee, 0x9c, 0xf2, 0xd3, 0x9c, 0x3f, 0xb6,
+		0x5b, 0x6e, 0xc4, 0x3b, 0xcd, 0xda, 0x5e, 0x53, 0x0c, 0x0c, 0x2b, 0xb1,
+		0x78, 0x3c, 0x31, 0xde, 0xe3, 0x6b, 0xfb, 0x47, 0xfe, 0x93, 0x9e, 0xd4,
+		0xa4, 0x8d, 0x18, 0x6d, 0x97, 0xde, 0xe9, 0x7a, 0x77, 0xb3, 0x69, 0xd6,
+		0x07, 0xf7, 0x92, 0xfb, 0xbb, 0x46, 0x77, 0x2f, 0xb1, 0x8d, 0xd5, 0xe9,
+		0xf6, 0x8d, 0x3f, 0xf7, 0x8c, 0x55, 0x6d, 0x9a, 0x2d, 0xd3, 0x96, 0xf7,
+		0x99, 0xc8, 0x69, 0x51, 0x72, 0x5e, 0x74, 0x56, 0x8e, 0xa9, 0xda, 0xe9,
+		0xd3, 0xca, 0x7e, 0xc5, 0x6f, 0x3b, 0xc3, 0xb2, 0xcc, 0x4e, 0xdb, 0x0a,
+		0xff, 0x9e, 0x4d, 0xb4, 0x56, 0x90, 0x9c, 0x68, 0xa6, 0x27, 0x6b, 0x23,
+		0x69, 0x90, 0x4d, 0xd3, 0x3f, 0xb5, 0x84, 0xb5, 0x32, 0x1e, 0xec, 0x98,
+		0xde, 0x20, 0x91, 0xe5, 0xbe, 0x67, 0xec, 0x1d, 0x8c, 0x72, 0x51, 0x87,
+		0x8f, 0x67, 0x54, 0x6d, 0x61, 0x41, 0x79, 0xec, 0x9f, 0xdb, 0x45, 0x6f,
+		0x16, 0xc3, 0xd5, 0x0a, 0xfe, 0x3a, 0x16, 0xaf, 0x41, 0x90, 0xfa, 0xff,
+		0xed, 0xda, 0xe5, 0x87, 0x35, 0xe8, 0x18, 0x7d, 0x30, 0xad, 0x6a, 0x67,
+		0xce, 0x28, 0xfb, 0x1d, 0xaf, 0x7e, 0xbd, 0xce, 0xda, 0xfb, 0x30, 0x13,
+		0xaf, 0xe3, 0xa0, 0xde, 0xfc, 0xec, 0x87, 0xa9, 0xd7, 0x8f, 0xfb, 0x3a,
+		0xf1, 0xd0, 0x9e, 0x27, 0xa9, 0x52, 0x63, 0x4a, 0xd5, 0xce, 0x9f, 0x57,
+		0xf6, 0x1f, 0xc5, 0xaa, 0x54, 0x15, 0x9d, 0xa0, 0x6b, 0x1a, 0x56, 0x72,
+		0x79, 0x5a, 0x5e, 0xc1, 0x70, 0xb5, 0xe4, 0x7a, 0x3d, 0x52, 0x5d, 0x7b,
+		0xfb, 0x19, 0x54, 0x5f, 0x6f, 0x6c, 0x0d, 0x3b, 0x95, 0xef, 0x74, 0x2c,
+		0xd3, 0x16, 0x23, 0x62, 0xd0, 0x91, 0xbb, 0x36, 0xe9, 0xdf, 0x0e, 0x56,
+		0x7a, 0xd5, 0xf4, 0xea, 0x66, 0x4d, 0xf5, 0x57, 0xe8, 0xf9, 0xf4, 0xc9,
+		0x48, 0x0d, 0xe4, 0x07, 0xb4, 0xe5, 0xde, 0xe5, 0xb5, 0x76, 0xa4, 0x07,
+		0x49, 0x51, 0xbd, 0x7e, 0xe7, 0xdc, 0xf2, 0xa2, 0xf7, 0xaf, 0x2c, 0xa2,
+		0xb9, 0x7b, 0x1f, 0x26, 0xe3, 0xb5, 0xe8, 0xa5, 0x47, 0x6b, 0x32, 0x52,
+		0x25, 0xfc, 0xeb, 0xf6, 0xc1, 0x8d, 0xac, 0x7b, 0x8d, 0x0b, 0xc6, 0x76,
+		0x69, 0xc2, 0x1b, 0xdb, 0xfb, 0x96, 0x17, 0x83, 0x7f, 0x75, 0xb5, 0x82,
+		0xbf, 0xd4, 0x78, 0xf9, 0x41, 0xea, 0x53, 0x9e, 0xc3, 0x77, 0x6a, 0x5d,
+		0xd1, 0x9d, 0x62, 0xf7, 0x00, 0x03, 0x4f, 0xdd, 0xb5, 0x83, 0x8b, 0x70,
+		0xef, 0xea, 0x1d, 0xb9, 0xee, 0x0e, 0xbe, 0x8c, 0x4d, 0xe7, 0x3a, 0x47,
+		0xbd, 0xfa, 0x38, 0x97, 0xbd, 0xfa, 0xf8, 0x37, 0x6f, 0x56, 0xf0, 0xd7,
+		0x44, 0xbc, 0x3e, 0x41, 0x6a, 0xac, 0x3e, 0x23, 0x55, 0x25, 0x88, 0xd9,
+		0x6f, 0xc1, 0xfd, 0xf9, 0x71, 0x55, 0x9b, 0x9f, 0x57, 0x7e, 0x3a, 0x1b,
+		0x96, 0x28, 0xfe, 0x1c, 0xed, 0x2b, 0xe9, 0xe9, 0xef, 0x79, 0x47, 0xbd,
+		0xc5, 0xea, 0xbf, 0x39, 0x1b, 0xe5, 0x8e, 0xe7, 0x89, 0x6e, 0x67, 0x3a,
+		0xbb, 0xdd, 0xba, 0xd1, 0x37, 0x7e, 0x64, 0x87, 0xc1, 0xd9, 0x18, 0x53,
+		0xb5, 0x53, 0xa7, 0x94, 0xfd, 0x9f, 0xf8, 0xad, 0xe2, 0xde, 0x8a, 0x5b,
+		0xfe, 0xef, 0xf1, 0x44, 0xdb, 0x78, 0x89, 0x4f, 0x37, 0x38, 0x23, 0xf7,
+		0xe6, 0x87, 0xdc, 0x16, 0x84, 0x4d, 0x12, 0xb9, 0xc7, 0x89, 0x74, 0xc2,
+		0xc8, 0x34, 0x60, 0x58, 0xa5, 0x3e, 0x38, 0x32, 0xe1, 0xde, 0x07, 0xdd,
+		0xf5, 0x2f, 0xe5, 0xf7, 0x9b, 0xe2, 0xa2, 0x53, 0xb5, 0x0c, 0x71, 0x23,
+		0xd1, 0xae, 0x27, 0x17, 0xc7, 0x62, 0x95, 0x4c, 0xac, 0x5c, 0x72, 0x0f,
+		0x55, 0x5e, 0x2c, 0xe5, 0x9c, 0x75, 0x45, 0xd5, 0x16, 0x17, 0x95, 0xfd,
+		0x8f, 0x0f, 0x9a, 0xa9, 0xba, 0x5d, 0x6b, 0x8a, 0xab, 0xec, 0x5e, 0x6c,
+		0xe1, 0x88, 0xa4, 0xd1, 0xc2, 0x75, 0xc9, 0xb6, 0x1b, 0xad, 0xe9, 0x7a,
+		0x93, 0xa3, 0xe1, 0xf3, 0xa2, 0xe1, 0x67, 0xe5, 0xfe, 0x93, 0x9a, 0x3b,
+		0xff, 0x3f, 0x32, 0xf7, 0x1f, 0x99, 0xb9, 0xaf, 0xc4, 0x2f, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x90, 0x06, 0xd3, 0xd3, 0x63, 0x99, 0x97, 0x83, 0xcf,
+		0x63, 0xca, 0x58, 0xe6, 0x98, 0x3b, 0xff, 0x3f, 0x93, 0xf9, 0xfb, 0xcc,
+		0xdc, 0x5f, 0x1d, 0xff, 0xea, 0xf8, 0x8f, 0x8f, 0x67, 0x66, 0xbf, 0x98,
+		0x9d, 0x39, 0xf6, 0x68, 0xe6, 0x77, 0x33, 0x3f, 0x9e, 0x51, 0xa6, 0xbf,
+		0x98, 0xfa, 0x72, 0xea, 0xe1, 0xe4, 0xff, 0x4c, 0xfe, 0x68, 0x52, 0x55,
+		0x3f, 0x9f, 0xf8, 0x72, 0xe2, 0xd1, 0xc4, 0xf8, 0xd1, 0x5f, 0x1c, 0x9d,
+		0x1c, 0xff, 0xdb, 0xf1, 0x37, 0xc7, 0xfe, 0x65, 0xec, 0xcf, 0x8e, 0xfc,
+		0xd3, 0x91, 0x96, 0xf2, 0xef, 0x4a, 0x45, 0x6c, 0xfe, 0x87, 0xe1, 0x9c,
+		0xbd, 0xa8, 0x6a, 0x77, 0x16, 0x14, 0xc7, 0x30, 0xdb, 0x0d, 0xe3, 0x41,
+		0xf0, 0xf2, 0xa2, 0xba, 0xdb, 0x36, 0xef, 0xef, 0x1a, 0x55, 0xf7, 0xd1,
+		0x6f, 0xb1, 0x51, 0x75, 0x5f, 0x2d, 0x94, 0xbd, 0xd7, 0x97, 0xe2, 0x43,
+		0xb1, 0x11, 0x64, 0x7a, 0x2d, 0x78, 0x20, 0x53, 0x29, 0x15, 0x6f, 0x55,
+		0x0a, 0x7a, 0xb1, 0xb4, 0x5a, 0xf8, 0xa8, 0xf7, 0xfa, 0x63, 0xf8, 0x1e,
+		0xb2, 0xfa, 0x7a, 0x29, 0xfa, 0xa6, 0xa4, 0xf7, 0x8c, 0x39, 0xaf, 0x47,
+		0xde, 0x71, 0x84, 0x0b, 0x22, 0x3d, 0xe7, 0x4c, 0x5f, 0x50, 0xb5, 0x9b,
+		0x67, 0x14, 0xe7, 0x9a, 0x17, 0x67, 0xef, 0x05, 0x44, 0xa2, 0x1c, 0xef,
+		0x79, 0x57, 0x6f, 0xe5, 0x25, 0x69, 0x84, 0x43, 0xb7, 0xf5, 0x23, 0x8b,
+		0xbe, 0xdf, 0x88, 0xc6, 0xe6, 0x67, 0xc9, 0x39, 0x93, 0xe7, 0x55, 0xed,
+		0xc6, 0x79, 0xc5, 0x39, 0x1f, 0x0b, 0x26, 0x7c, 0x59, 0xd0, 0x7b, 0xd9,
+		0x57, 0x6c, 0x24, 0x57, 0xbd, 0x1a, 0x44, 0x94, 0x08, 0x45, 0xb2, 0x65,
+		0x3c, 0x90, 0xc8, 0x7b, 0x88, 0xd8, 0x3b, 0x85, 0xdc, 0x7a, 0x7e, 0x42,
+		0x3b, 0xab, 0x29, 0xf7, 0x7b, 0x81, 0x58, 0x55, 0xf7, 0x81, 0xbc, 0x5f,
+		0xb2, 0x75, 0xb1, 0xbf, 0xb8, 0x70, 0xfd, 0xc1, 0xfe, 0xbd, 0x9d, 0x86,
+		0x4f, 0xf1, 0x73, 0xce, 0x99, 0x73, 0xaa, 0x56, 0xd1, 0x14, 0xa7, 0x1a,
+		0xd9, 0x65, 0xbc, 0xa1, 0xfc, 0x1d, 0x54, 0x7b, 0x4f, 0xf6, 0xbd, 0x3c,
+		0x17, 0x06, 0xb6, 0xf5, 0x61, 0x9b, 0x27, 0x22, 0x89, 0x34, 0x77, 0x18,
+		0x94, 0xd7, 0xf2, 0x61, 0xee, 0x5c, 0xfb, 0xbb, 0xaa, 0x76, 0x45, 0xf4,
+		0x84, 0x79, 0x2f, 0xc0, 0xde, 0x2b, 0x81, 0xb0, 0x14, 0xf7, 0xc9, 0x62,
+		0x2f, 0xf1, 0xbc, 0x34, 0x2a, 0xe9, 0x36, 0x7e, 0x18, 0xd1, 0x37, 0x0c,
+		0xfe, 0x4b, 0x83, 0xdc, 0x56, 0x4e, 0xd5, 0x96, 0xc5, 0x08, 0x99, 0xf1,
+		0xca, 0x0b, 0x5e, 0x01, 0x84, 0x5b, 0xba, 0x0f, 0xea, 0x83, 0xa4, 0xbc,
+		0xb4, 0x2c, 0x49, 0x7e, 0xbf, 0xa4, 0x83, 0x77, 0x09, 0xfe, 0xd3, 0xfe,
+		0x9c, 0xb9, 0xa4, 0x6a, 0x97, 0x45, 0x39, 0x73, 0x5e, 0x39, 0xc1, 0xa3,
+		0xf9, 0x70, 0x3b, 0xaf, 0xe7, 0x05, 0x69, 0xe7, 0xa4, 0x05, 0xc9, 0x36,
+		0xf0, 0x4b, 0x3a, 0x78, 0xca, 0x1f, 0x76, 0xe0, 0xfa, 0xa2, 0xa8, 0xd2,
+		0xbc, 0xe2, 0xcc, 0x86, 0x45, 0x85, 0x5b, 0xb9, 0x8f, 0xd1, 0x4b, 0xa2,
+		0xd6, 0x22, 0xe9, 0xbb, 0x83, 0x4a, 0x49, 0xe6, 0xed, 0x15, 0xe2, 0x16,
+		0x70, 0xf0, 0x1c, 0x3e, 0xe7, 0x8c, 0x7f, 0x47, 0xd5, 0x0a, 0xa7, 0x14,
+		0xe7, 0x8a, 0x5f, 0x8a, 0xf7, 0x94, 0x3b, 0xdc, 0xd8, 0x8f, 0x29, 0x1c,
+		0x75, 0xfe, 0xba, 0x9c, 0xbc, 0xc4, 0x21, 0xdb, 0x05, 0x45, 0x87, 0xcf,
+		0xcf, 0x23, 0x4f, 0xc3, 0x0f, 0x06, 0xab, 0x75, 0x56, 0x74, 0x97, 0x45,
+		0xc5, 0x39, 0x79, 0x10, 0x45, 0xf8, 0xd8, 0xb8, 0xea, 0x2d, 0x15, 0x1b,
+		0xb1, 0xc4, 0xa5, 0xf8, 0x98, 0x91, 0x6e, 0x10, 0x29, 0x37, 0xf2, 0x08,
+		0xfa, 0xe0, 0x89, 0x72, 0x6e, 0xf7, 0xdb, 0xa2, 0x81, 0x97, 0x15, 0x27,
+		0xf2, 0xdf, 0x02, 0x55, 0x2d, 0xd1, 0xaa, 0xf5, 0x4f, 0xaa, 0xf5, 0x4e,
+		0x7b, 0xcb, 0xdc, 0x96, 0x24, 0x2d, 0xc6, 0x1e, 0x70, 0x2f, 0xca, 0x72,
+		0x2c, 0xdd, 0x8b, 0x3e, 0xd2, 0xce, 0xeb, 0x9f, 0xe6, 0xf4, 0x3b, 0xc5,
+		0xf2, 0x7b, 0xeb, 0x95, 0xb2, 0xbe, 0xb1, 0x7e, 0xa7, 0xb8, 0xba, 0xf7,
+		0x2d, 0xd1, 0x85, 0x2e, 0x8b, 0xba, 0xf6, 0x17, 0xdb, 0xe8, 0xd4, 0xdd,
+		0x37, 0x28, 0xb2, 0xb4, 0xef, 0x0c, 0x29, 0x38, 0xc8, 0xb2, 0xb8, 0x64,
+		0x36, 0x64, 0x4f, 0xd5, 0xf3, 0xba, 0xf5, 0x50, 0xbf, 0xbe, 0xb6, 0x7e,
+		0x5d, 0x1c, 0xed, 0xac, 0x5f, 0xf6, 0x39, 0x69, 0x95, 0x6d, 0x71, 0x02,
+		0x93, 0xa5, 0x9d, 0x1d, 0x5e, 0x69, 0x37, 0xcb, 0xe0, 0xb2, 0xeb, 0xaf,
+		0x8a, 0x9f, 0x4b, 0xe2, 0xe7, 0x35, 0xf1, 0xb3, 0x2c, 0x7e, 0x5e, 0xcf,
+		0x39, 0xea, 0x2b, 0xde, 0x6b, 0x5f, 0x67, 0xa5, 0x3f, 0x0c, 0xb3, 0xf1,
+		0x20, 0xb9, 0xfc, 0xed, 0x21, 0xc5, 0x8b, 0xd5, 0x8b, 0x4b, 0x96, 0xb1,
+		0x6d, 0x36, 0xf2, 0xba, 0x6d, 0x74, 0x5b, 0x79, 0x7d, 0x67, 0xbb, 0xdd,
+		0xc9, 0x47, 0x43, 0x88, 0xae, 0xce, 0x25, 0x8e, 0x85, 0xa5, 0xab, 0xda,
+		0xc5, 0x8b, 0xf2, 0x63, 0x51, 0xb3, 0x6b, 0x7d, 0x09, 0xdf, 0x1a, 0x76,
+		0x14, 0xc4, 0xfa, 0xc1, 0xcd, 0xb0, 0xd9, 0xec, 0xd4, 0xef, 0xf9, 0x47,
+		0xe1, 0xd1, 0x19, 0xd1, 0xd9, 0xaf, 0x84, 0x85, 0x86, 0xe7, 0x9e, 0x58,
+		0x27, 0x92, 0x26, 0x66, 0xe3, 0x85, 0x4b, 0xf3, 0x1c, 0xda, 0xfb, 0x7e,
+		0xf2, 0x4d, 0x55, 0xbb, 0x7a, 0x55, 0x71, 0x4e, 0xc9, 0x0a, 0x0f, 0x3a,
+		0x92, 0x3c, 0xf5, 0x95, 0xa1, 0xc5, 0x8f, 0xdc, 0x07, 0x1f, 0x9e, 0xf6,
+		0x03, 0x98, 0x1b, 0x50, 0x7b, 0xb7, 0x37, 0xc9, 0x53, 0xf5, 0xc3, 0xea,
+		0x7f, 0x58, 0x47, 0xcc, 0x39, 0xb3, 0xdf, 0x50, 0xb5, 0x4b, 0x97, 0x14,
+		0xa7, 0x20, 0x2b, 0x5c, 0xf4, 0xa5, 0xfe, 0x94, 0x33, 0x43, 0x0b, 0x7d,
+		0xc6, 0xee, 0xb7, 0xb7, 0xe0, 0x9f, 0x81, 0xe4, 0x07, 0x43, 0xf4, 0x27,
+		0x49, 0xd2, 0x37, 0x87, 0x1f, 0x86, 0x91, 0x3b, 0xe1, 0xcb, 0xd1, 0x4e,
+		0x18, 0x5e, 0x97, 0x62, 0x7d, 0x49, 0x9a, 0x78, 0x3a, 0x5e, 0xbc, 0x34,
+		0xcf, 0xe1, 0x9d, 0xf0, 0x54, 0xb4, 0x13, 0x26, 0xf6, 0x11, 0xf4, 0x24,
+		0x79, 0xea, 0x37, 0x86, 0x16, 0x3f, 0x7a, 0x27, 0xfc, 0x7a, 0xb4, 0x13,
+		0xf6, 0x57, 0xc2, 0xed, 0x49, 0xf2, 0xd4, 0x85, 0xc3, 0xea, 0x3f, 0x42,
+		0x27, 0xd4, 0xa2, 0x9d, 0x30, 0xb1, 0x07, 0xd1, 0xa3, 0xfa, 0x53, 0x5e,
+		0x1e, 0x5a, 0xe8, 0xb3, 0x76, 0xc2, 0xaf, 0x45, 0x3b, 0x61, 0xb2, 0x45,
+		0x45, 0x7f, 0x92, 0x24, 0x9d, 0x1a, 0x7e, 0x18, 0x46, 0xed, 0x84, 0x0f,
+		0x4e, 0xfa, 0x97, 0xa3, 0xf9, 0xc8, 0xdb, 0xf7, 0x44, 0x1f, 0x94, 0xa4,
+		0x7d, 0x3d, 0x51, 0xb8, 0x24, 0xcb, 0xa1, 0x3d, 0xf0, 0x47, 0x2f, 0xf9,
+		0xdd, 0x5f, 0x93, 0x94, 0x1c, 0x76, 0x40, 0x59, 0xa2, 0x36, 0xac, 0xec,
+		0x91, 0xbb, 0xdf, 0xa3, 0xf9, 0xd8, 0xe0, 0x4b, 0x56, 0xc0, 0xeb, 0x7d,
+		0xb2, 0xc4, 0xaf, 0x1d, 0x52, 0xf3, 0x51, 0xae, 0xc4, 0x62, 0x96, 0xf6,
+		0xa2, 0x7f, 0xd1, 0xbb, 0x21, 0x29, 0xdd, 0xed, 0x7e, 0xc9, 0x84, 0x93,
+		0xc3, 0x4a, 0x7d, 0xc6, 0xce, 0xf7, 0xe9, 0x0b, 0xfe, 0x58, 0x90, 0x1e,
+		0x07, 0xb7, 0xef, 0xf5, 0xa5, 0xcc, 0x0f, 0x3d, 0x02, 0xa3, 0xf6, 0xbc,
+		0x69, 0x77, 0x5e, 0xcd, 0xbf, 0xff, 0x07, 0x00, 0x00, 0x00, 0x00, 0x20,
+		0xd5, 0xf8, 0xfe, 0x3f, 0x00, 0x00, 0x00, 0x00, 0x00, 0xd2, 0x8f, 0xf9,
+		0x3f, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe9, 0xe7, 0xfe, 0xf7, 0xff, 0xca,
+		0xdc, 0x7f, 0x65, 0xc4, 0x1f, 0x00, 0x00, 0x00, 0x00, 0x00, 0x90, 0x22,
+		0x33, 0x63, 0x0b, 0xca, 0xa7, 0x46, 0xd7, 0xfd, 0x5f, 0x17, 0x8d, 0xf3,
+		0xfd, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa4, 0x4e, 0xdf, 0xf7, 0xff,
+		0xf3, 0xfd, 0x7f, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa4, 0x1f, 0xff, 0xfe,
+		0x1f, 0x00, 0x00, 0x00, 0x00, 0x80, 0xf4, 0x63, 0xfe, 0x0f, 0x00, 0x00,
+		0x00, 0x00, 0x40, 0xfa, 0xf1, 0xfd, 0x7f, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xa4, 0x13, 0xdf, 0xff, 0x07, 0x00, 0x00, 0x00, 0x00, 0x40, 0xaa, 0xf1,
+		0xfd, 0x7f, 0x00, 0x00, 0x00, 0x00, 0x00, 0xfc, 0x11, 0xe2, 0xdf, 0xff,
+		0x03, 0x00, 0x00, 0x00, 0x00, 0x90, 0x7e, 0xcc, 0xff, 0x01, 0x00, 0x00,
+		0x00, 0x00, 0x48, 0x3f, 0xbe, 0xff, 0x0f, 0x00, 0x00, 0x00, 0x00, 0x80,
+		0x74, 0xe2, 0xfb, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x48, 0x35, 0xbe,
+		0xff, 0x0f, 0x00, 0x00, 0x00, 0x00, 0x80, 0x3f, 0x42, 0xfc, 0xfb, 0x7f,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0xd2, 0x8f, 0xf9, 0x3f, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0xe9, 0xc7, 0xf7, 0xff, 0x01, 0x00, 0x00, 0x00, 0x00, 0x90,
+		0x4e, 0xd1, 0xef, 0xff, 0xe3, 0xdf, 0xff, 0x03, 0x00, 0x00, 0x00, 0x00,
+		0x90, 0x7e, 0xcc, 0xff, 0x01, 0x00, 0x00, 0x00, 0x00, 0x48, 0x3f, 0xe6,
+		0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa4, 0x1f, 0xf3, 0x7f, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0xd2, 0x8f, 0xf9, 0x3f, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xe9, 0xc7, 0xfc, 0x1f, 0x00, 0x00, 0x00, 0x00, 0x80, 0xf4, 0x63, 0xfe,
+		0x0f, 0x00, 0x00, 0x00, 0x00, 0x40, 0xfa, 0x31, 0xff, 0x07, 0x00, 0x00,
+		0x00, 0x00, 0x20, 0xfd, 0x98, 0xff, 0x03, 0x00, 0x00, 0x00, 0x00, 0x90,
+		0x7e, 0xcc, 0xff, 0x01, 0x00, 0x00, 0x00, 0x00, 0x48, 0x3f, 0xe6, 0xff,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0xa4, 0x1f, 0xf3, 0x7f, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0xd2, 0x6f, 0x76, 0xf2, 0x57, 0x99, 0x93, 0x99, 0x4a, 0x66,
+		0xf2, 0x1d, 0xf5, 0x6f, 0xd4, 0x23, 0x13, 0xbf, 0x9e, 0x78, 0xef, 0xe8,
+		0xaf, 0x8f, 0xbe, 0x3d, 0xfe, 0xcb, 0xf1, 0x93, 0x63, 0x3f, 0x3b, 0xf2,
+		0x5b, 0xe5, 0x9f, 0x95, 0xd3, 0x99, 0xca, 0xdc, 0xad, 0xe3, 0x3f, 0x9f,
+		0xfd, 0xf9, 0xb1, 0x7f, 0x9d, 0xf9, 0xd3, 0xa9, 0xbf, 0x9b, 0xfc, 0xdd,
+		0x08, 0x3b, 0x75, 0x0a, 0xef, 0xa8, 0x0b, 0x57, 0x16, 0x26, 0xf7, 0x6f,
+		0xd8, 0x5d, 0x73, 0x7b, 0xdb, 0xe8, 0xd6, 0xba, 0xb6, 0x69, 0xd9, 0x56,
+		0xd5, 0x32, 0x6a, 0xdd, 0xfa, 0x27, 0x55, 0xb3, 0x6d, 0x19, 0x5d, 0x3b,
+		0x48, 0xbc, 0xb1, 0x51, 0x58, 0x29, 0x17, 0xf4, 0xf2, 0x46, 0xf1, 0xe6,
+		0xcd, 0xc2, 0x86, 0x9e, 0x95, 0xe6, 0xcd, 0xea, 0x2b, 0xef, 0x96, 0xc5,
+		0xda, 0x62, 0xe9, 0x76, 0x61, 0xa3, 0xac, 0xaf, 0x97, 0x7a, 0xf9, 0xb2,
+		0xfa, 0xf5, 0xc2, 0xcd, 0x62, 0x69, 0x7a, 0x2a, 0x58, 0x55, 0x2c, 0x95,
+		0xd7, 0x93, 0x3b, 0xc9, 0xea, 0x4b, 0xd9, 0x6e, 0xe7, 0x33, 0xb3, 0x91,
+		0xcd, 0xeb, 0x59, 0xdb, 0xb4, 0x9b, 0x46, 0x36, 0xa7, 0x7f, 0xb8, 0xb2,
+		0x56, 0x29, 0xdc, 0xd6, 0x97, 0xda, 0xc6, 0x67, 0x17, 0xb2, 0xde, 0x2a,
+		0xef, 0x53, 0xb0, 0xfa, 0xda, 0x74, 0xa1, 0xb4, 0xea, 0x2c, 0xbe, 0xad,
+		0x2e, 0x5c, 0x3e, 0x35, 0xb9, 0x3f, 0x13, 0xd6, 0xa3, 0xb9, 0xb9, 0xdb,
+		0xea, 0x85, 0xd6, 0x30, 0x9a, 0x86, 0x6d, 0xf8, 0x69, 0x7d, 0xb5, 0x90,
+		0xe4, 0x0c, 0x2b, 0xb1, 0x5a, 0x58, 0x2b, 0x88, 0xbc, 0x5e, 0x25, 0xbc,
+		0x6c, 0xbd, 0x3a, 0x04, 0x6b, 0xde, 0xdd, 0x58, 0x7f, 0x3f, 0xb1, 0x8b,
+		0xac, 0x7e, 0xe7, 0xbd, 0xc2, 0x46, 0x41, 0x0f, 0xea, 0xa1, 0xbf, 0xa3,
+		0x77, 0x9a, 0x0d, 0x2f, 0x6e, 0x2f, 0xd2, 0xfd, 0xe9, 0x6b, 0x5e, 0xa4,
+		0x8f, 0x6f, 0x49, 0x23, 0x0d, 0x1a, 0x7c, 0x84, 0x48, 0x07, 0x37, 0x77,
+		0x3c, 0xd2, 0x78, 0x6b, 0xc7, 0x23, 0xed, 0x6b, 0xec, 0x7c, 0x78, 0x40,
+		0xb2, 0xb9, 0xe9, 0xa9, 0xa9, 0xa1, 0x0d, 0x9f, 0xd7, 0x97, 0x6e, 0x8b,
+		0x46, 0xb8, 0x51, 0x0e, 0xb7, 0x0d, 0x1b, 0x23, 0x3c, 0xda, 0x41, 0x33,
+		0xf8, 0x6d, 0xe0, 0x6d, 0xe8, 0xaf, 0xaa, 0x8a, 0x94, 0x5c, 0x70, 0xd8,
+		0x2e, 0xbc, 0xa5, 0x6a, 0x67, 0xcf, 0x4e, 0xee, 0xbf, 0x62, 0xd7, 0x36,
+		0x9b, 0x86, 0xd5, 0x69, 0x6f, 0x87, 0xc1, 0x45, 0x3f, 0x07, 0x0d, 0xf1,
+		0x61, 0x71, 0xa3, 0x5c, 0x59, 0x59, 0xd3, 0xcb, 0x2b, 0xd7, 0xd7, 0xc4,
+		0x8e, 0xa3, 0x39, 0xb2, 0x7a, 0xe5, 0x76, 0xb1, 0x74, 0x53, 0xdf, 0xb2,
+		0xad, 0xcb, 0x4b, 0x7d, 0x75, 0xc9, 0x07, 0x15, 0x77, 0x3f, 0x6c, 0x1b,
+		0xed, 0xae, 0xb7, 0xae, 0xde, 0x69, 0xb5, 0x8c, 0xb6, 0xbb, 0xd2, 0xee,
+		0xdc, 0x33, 0xda, 0xe6, 0x43, 0x43, 0x84, 0xb9, 0xb8, 0xdb, 0x36, 0xeb,
+		0x9d, 0x86, 0xf1, 0xc6, 0x25, 0xbd, 0x6b, 0xb4, 0x3a, 0x9f, 0x1a, 0xd5,
+		0x86, 0x59, 0xab, 0x77, 0x4d, 0xdb, 0xac, 0x5b, 0xfa, 0x6b, 0x8b, 0x39,
+		0x67, 0xf6, 0xaa, 0xaa, 0xe5, 0x72, 0x93, 0xce, 0x1d, 0x2f, 0xe0, 0x2d,
+		0x71, 0x68, 0x8d, 0x6e, 0x18, 0x44, 0x7c, 0x49, 0x1e, 0x74, 0x3c, 0x8f,
+		0x3c, 0xec, 0x27, 0x8a, 0xe7, 0xcd, 0x68, 0x3c, 0xf1, 0xc1, 0x14, 0x5f,
+		0x92, 0xc7, 0x93, 0x1c, 0x7e, 0xcf, 0x1a, 0xcf, 0x4b, 0x57, 0x54, 0x6d,
+		0x71, 0x71, 0xd2, 0x31, 0xfd, 0x78, 0xa2, 0xdd, 0x2d, 0xb6, 0x30, 0x20,
+		0x9a, 0x78, 0xf7, 0x1c, 0x7e, 0x4c, 0x9f, 0x20, 0xac, 0x3f, 0x7f, 0x43,
+		0xd5, 0xae, 0x68, 0x8a, 0x33, 0x6f, 0xb6, 0x1b, 0xc6, 0x83, 0x5d, 0xcb,
+		0x3d, 0x02, 0x62, 0x93, 0xfb, 0xbb, 0x46, 0xd5, 0x5d, 0x68, 0xd7, 0x5a,
+		0x86, 0x97, 0xf8, 0x56, 0x10, 0x56, 0xa5, 0x54, 0xbc, 0x55, 0x29, 0x88,
+		0x31, 0xb3, 0x5a, 0xf8, 0x48, 0xcf, 0x4a, 0xf3, 0x67, 0xbd, 0xb1, 0xe6,
+		0xad, 0x72, 0xc7, 0x51, 0x2f, 0x39, 0xe7, 0x9c, 0xbe, 0xac, 0x6a, 0xb7,
+		0x45, 0x61, 0x1f, 0x7b, 0x85, 0x59, 0xe2, 0xec, 0x19, 0xdb, 0xb8, 0xd8,
+		0xa8, 0x9a, 0xb6, 0xd1, 0x2a, 0xef, 0xed, 0x18, 0xde, 0x87, 0x62, 0xc3,
+		0xcb, 0x72, 0x55, 0x5a, 0xf4, 0x08, 0x5b, 0xfb, 0x81, 0x78, 0x19, 0xc3,
+		0x40, 0xaa, 0xfe, 0x90, 0x76, 0xd7, 0x57, 0x6d, 0x91, 0xb3, 0xb7, 0xe0,
+		0x8e, 0xbc, 0x1f, 0xbe, 0xae, 0x6a, 0x17, 0x45, 0x78, 0x63, 0x7e, 0x78,
+		0xde, 0x20, 0xda, 0x32, 0xdb, 0xe2, 0x4c, 0xb4, 0xd3, 0x35, 0xdb, 0xb6,
+		0x97, 0xf0, 0x66, 0x10, 0x4c, 0x18, 0x45, 0x32, 0x53, 0x50, 0xa6, 0x9b,
+		0xec, 0x96, 0x19, 0x5d, 0x93, 0xfb, 0x68, 0x79, 0x42, 0x3b, 0xa7, 0x29,
+		0x9f, 0x47, 0x76, 0xdf, 0x34, 0x37, 0xc5, 0x85, 0x65, 0x4f, 0xd4, 0xd4,
+		0x5d, 0xbc, 0x22, 0xdb, 0x79, 0x2f, 0x4b, 0x62, 0xd7, 0x41, 0xba, 0x17,
+		0xb9, 0x33, 0xff, 0x9a, 0xaa, 0xad, 0x8b, 0xd0, 0xdf, 0x8f, 0xec, 0x3b,
+		0x68, 0x9b, 0x2d, 0xb3, 0x69, 0x94, 0x44, 0xfb, 0x57, 0xdd, 0x86, 0xb0,
+		0xd7, 0xb7, 0xb6, 0x2c, 0xc3, 0xaf, 0xca, 0x1b, 0xf2, 0x76, 0x3d, 0x6c,
+		0xdb, 0xbe, 0x1a, 0x36, 0x8d, 0xaa, 0x77, 0x7c, 0xf3, 0x7e, 0x5b, 0xdb,
+		0xd5, 0x8e, 0x9f, 0x2f, 0xe7, 0x64, 0x2f, 0x89, 0xe3, 0x7d, 0x4e, 0x71,
+		0x6e, 0xfb, 0x51, 0xb5, 0xdc, 0x95, 0x3b, 0xcd, 0xda, 0x5e, 0xd3, 0x1b,
+		0x58, 0xf1, 0x63, 0xe7, 0xf5, 0xe2, 0x44, 0x96, 0xcb, 0xf2, 0x08, 0x0f,
+		0xdf, 0x4f, 0x10, 0x63, 0x3c, 0x63, 0xa2, 0x0f, 0x04, 0x17, 0xc9, 0xed,
+		0x57, 0x55, 0x6d, 0xf9, 0xb4, 0xe2, 0xcc, 0xf8, 0x31, 0x1a, 0x96, 0x65,
+		0x76, 0xda, 0xbd, 0x9d, 0xde, 0x33, 0xf6, 0xc2, 0xa4, 0x65, 0x79, 0x30,
+		0xfd, 0x1b, 0x04, 0x85, 0x07, 0x2b, 0xdc, 0x52, 0xdd, 0xc4, 0x9c, 0xb8,
+		0xa7, 0xb8, 0xfb, 0x5c, 0x6e, 0x7f, 0x9c, 0xec, 0xaa, 0xba, 0xb0, 0xac,
+		0x4d, 0xee, 0x1f, 0x0d, 0xae, 0x9b, 0xd1, 0xd3, 0x7f, 0x70, 0xd9, 0xf6,
+		0x92, 0x92, 0x57, 0x4d, 0x49, 0x3e, 0xc9, 0xe5, 0x3d, 0x38, 0xd2, 0xb2,
+		0xab, 0x7b, 0xfc, 0x3a, 0x33, 0xf4, 0xe2, 0xfe, 0x78, 0xf9, 0x86, 0x17,
+		0xe4, 0x17, 0x8b, 0xb2, 0x20, 0x77, 0x77, 0x1a, 0xb5, 0x51, 0x82, 0xf4,
+		0xf3, 0x85, 0x41, 0x56, 0x3e, 0x58, 0x5d, 0xf9, 0xfd, 0x05, 0x19, 0xbf,
+		0x1d, 0x88, 0x6f, 0x35, 0xe4, 0x6e, 0x60, 0xe8, 0x15, 0xf4, 0x39, 0xdf,
+		0x2a, 0xe4, 0xc5, 0xee, 0xa7, 0x06, 0xec, 0x20, 0xb8, 0xd5, 0x91, 0x6c,
+		0xef, 0xae, 0xf1, 0x37, 0xf7, 0x13, 0xc2, 0xa8, 0xbd, 0x85, 0x5e, 0xe4,
+		0xfe, 0x2d, 0xd9, 0xe7, 0xd7, 0xbd, 0xa3, 0xf6, 0xb3, 0x6b, 0xb2, 0xa3,
+		0xe6, 0xdf, 0x67, 0x1d, 0x7e, 0xd4, 0x06, 0xde, 0x8f, 0xc5, 0x8f, 0x1a,
+		0xed, 0xdf, 0xd7, 0xfe, 0x4e, 0x7e, 0xc5, 0x9f, 0x84, 0xbc, 0x10, 0xb4,
+		0x7f, 0xfc, 0x26, 0x29, 0x18, 0xb4, 0x41, 0x62, 0xf2, 0x18, 0x48, 0xf3,
+		0x4a, 0x06, 0x78, 0x90, 0x4f, 0x3e, 0x7a, 0x92, 0x77, 0x65, 0x43, 0x07,
+		0xb9, 0xf3, 0xf9, 0xf7, 0xbd, 0x70, 0x1f, 0x2f, 0xcb, 0xc3, 0xf5, 0x87,
+		0xef, 0x68, 0xe1, 0x0e, 0x1c, 0xea, 0xbf, 0xa7, 0x70, 0xe3, 0xdd, 0x2d,
+		0xb9, 0xdd, 0x53, 0xce, 0xb5, 0x0a, 0xdf, 0x8b, 0xcf, 0x19, 0x13, 0x75,
+		0xf2, 0x07, 0xc2, 0x68, 0xf5, 0x1f, 0x38, 0x68, 0x92, 0xf5, 0x7f, 0x1e,
+		0xf5, 0xc8, 0xff, 0x49, 0xbc, 0xdb, 0x25, 0xe6, 0xb3, 0xc1, 0xa4, 0x71,
+		0xa4, 0xb9, 0xef, 0xe0, 0x69, 0x63, 0x62, 0xee, 0x1b, 0x9f, 0x37, 0x26,
+		0x6e, 0xbe, 0x87, 0x76, 0x3b, 0xde, 0xff, 0x03, 0x00, 0x00, 0x00, 0x00,
+		0x90, 0x7e, 0xcc, 0xff, 0x01, 0x00, 0x00, 0x00, 0x00, 0x48, 0x3f, 0xe6,
+		0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa4, 0x1f, 0xf3, 0x7f, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0xd2, 0x8f, 0xf9, 0x3f, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0xe9, 0xc7, 0xfc, 0x1f, 0x00, 0x00, 0x00, 0x00, 0x80, 0xf4, 0xfb, 0x5f,
+		0xd9, 0x0c, 0x46, 0xb2, 0x00, 0xa0, 0x03, 0x00,
 	},
 		"res/sqlite/wavepipe.db",
 	)
@@ -125,21 +1058,21 @@ func res_sqlite_wavepipe_db() ([]byte, error) {
 
 func res_web_index_html() ([]byte, error) {
 	return bindata_read([]byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x4c, 0x8e,
-		0xb1, 0xae, 0xc2, 0x30, 0x0c, 0x45, 0xe7, 0xd7, 0xaf, 0xb8, 0xdd, 0xfa,
-		0xa6, 0x8a, 0x15, 0x45, 0x61, 0x28, 0x0c, 0x0c, 0x08, 0x06, 0x10, 0x62,
-		0x74, 0x1b, 0x43, 0x2a, 0x92, 0x26, 0x2a, 0x16, 0x55, 0xff, 0x9e, 0x94,
-		0xaa, 0x12, 0x93, 0x7d, 0xad, 0x63, 0xdd, 0xa3, 0xf2, 0xed, 0xb1, 0x3a,
-		0xdf, 0x4e, 0x3b, 0x58, 0xf1, 0x4e, 0x67, 0x6a, 0x19, 0x4c, 0x46, 0x67,
-		0x7f, 0x4a, 0x5a, 0x71, 0xac, 0x07, 0x7a, 0x73, 0x6c, 0x23, 0xab, 0x72,
-		0xce, 0x99, 0x2a, 0x67, 0x40, 0xd5, 0xc1, 0x8c, 0x13, 0x67, 0x57, 0x3f,
-		0x50, 0x0a, 0xe9, 0x14, 0xf5, 0x95, 0x3a, 0x81, 0x04, 0x58, 0x76, 0x11,
-		0x9e, 0x9e, 0x0c, 0xea, 0x40, 0x03, 0xbf, 0x82, 0x67, 0x0c, 0x5c, 0xe3,
-		0xb2, 0xc7, 0x3d, 0xf4, 0x58, 0x3e, 0x37, 0x40, 0x15, 0x3a, 0xa1, 0x46,
-		0x70, 0x20, 0x91, 0x35, 0xbc, 0x71, 0x34, 0x5a, 0xee, 0x51, 0x90, 0xfc,
-		0xe3, 0xe1, 0xa9, 0x75, 0x28, 0x4c, 0x48, 0x7b, 0x13, 0x7c, 0xae, 0xca,
-		0x38, 0x99, 0xcc, 0x0a, 0xa9, 0xf5, 0x6b, 0xfe, 0x09, 0x00, 0x00, 0xff,
-		0xff, 0xb9, 0x4e, 0xb3, 0x57, 0xd1, 0x00, 0x00, 0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x00, 0xff, 0x4d, 0x8e,
+		0x31, 0x0b, 0xc2, 0x30, 0x10, 0x85, 0x67, 0xfb, 0x2b, 0x5e, 0xb7, 0x3a,
+		0x15, 0x57, 0x09, 0x71, 0xa8, 0x0e, 0x0e, 0xa2, 0x83, 0x22, 0x8e, 0xd7,
+		0xe6, 0x34, 0xc5, 0xa4, 0x09, 0xf5, 0xb0, 0xf4, 0xdf, 0x9b, 0x5a, 0x04,
+		0xa7, 0xbb, 0xf7, 0xf8, 0x8e, 0xfb, 0x54, 0xbe, 0x3d, 0x56, 0xe7, 0xdb,
+		0x69, 0x07, 0x2b, 0xde, 0xe9, 0x4c, 0xfd, 0x06, 0x93, 0xd1, 0xd9, 0x42,
+		0x49, 0x2b, 0x8e, 0xf5, 0x40, 0x6f, 0x8e, 0x6d, 0x64, 0x55, 0xce, 0x39,
+		0x53, 0xe5, 0x0c, 0xa8, 0x3a, 0x98, 0x71, 0xe2, 0xec, 0xea, 0x0f, 0x4a,
+		0x21, 0x55, 0x51, 0x5f, 0xa9, 0x13, 0x48, 0x80, 0x65, 0x17, 0xe1, 0xe9,
+		0xc9, 0xa0, 0x0e, 0x34, 0xf0, 0x2b, 0x78, 0xc6, 0xc0, 0x35, 0x2e, 0x7b,
+		0xdc, 0x43, 0x8f, 0xdf, 0xe5, 0x06, 0xa8, 0x42, 0x27, 0xd4, 0x08, 0x0e,
+		0x24, 0xb2, 0x86, 0x37, 0x8e, 0x46, 0xcb, 0x3d, 0x0a, 0x92, 0x25, 0x1e,
+		0x9e, 0x5a, 0x87, 0xc2, 0x84, 0xb4, 0x37, 0xc1, 0xe7, 0xaa, 0x8c, 0x93,
+		0xc9, 0xac, 0x90, 0xbe, 0x7e, 0xcd, 0x3f, 0xb9, 0x4e, 0xb3, 0x57, 0xd1,
+		0x00, 0x00, 0x00,
 	},
 		"res/web/index.html",
 	)
@@ -167,6 +1100,29 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() ([]byte, error){
-	"res/sqlite/wavepipe.db": res_sqlite_wavepipe_db,
-	"res/web/index.html":     res_web_index_html,
+	"res/postgres/migrations/0001_schema.sql":            res_postgres_migrations_0001_schema_sql,
+	"res/postgres/migrations/0002_plays.sql":             res_postgres_migrations_0002_plays_sql,
+	"res/postgres/migrations/0003_stars_ratings.sql":     res_postgres_migrations_0003_stars_ratings_sql,
+	"res/postgres/migrations/0004_search.sql":            res_postgres_migrations_0004_search_sql,
+	"res/postgres/migrations/0005_smart_playlists.sql":   res_postgres_migrations_0005_smart_playlists_sql,
+	"res/postgres/migrations/0006_song_discs.sql":        res_postgres_migrations_0006_song_discs_sql,
+	"res/postgres/migrations/0007_libraries.sql":         res_postgres_migrations_0007_libraries_sql,
+	"res/postgres/migrations/0008_art_source.sql":        res_postgres_migrations_0008_art_source_sql,
+	"res/postgres/migrations/0009_album_art.sql":         res_postgres_migrations_0009_album_art_sql,
+	"res/postgres/migrations/0010_cue_tracks.sql":        res_postgres_migrations_0010_cue_tracks_sql,
+	"res/postgres/migrations/0011_song_fingerprints.sql": res_postgres_migrations_0011_song_fingerprints_sql,
+	"res/postgres/migrations/0012_search_unaccent.sql":   res_postgres_migrations_0012_search_unaccent_sql,
+	"res/sqlite/migrations/0001_playlists.sql":           res_sqlite_migrations_0001_playlists_sql,
+	"res/sqlite/migrations/0002_plays.sql":               res_sqlite_migrations_0002_plays_sql,
+	"res/sqlite/migrations/0003_stars_ratings.sql":       res_sqlite_migrations_0003_stars_ratings_sql,
+	"res/sqlite/migrations/0004_search.sql":              res_sqlite_migrations_0004_search_sql,
+	"res/sqlite/migrations/0005_smart_playlists.sql":     res_sqlite_migrations_0005_smart_playlists_sql,
+	"res/sqlite/migrations/0006_song_discs.sql":          res_sqlite_migrations_0006_song_discs_sql,
+	"res/sqlite/migrations/0007_libraries.sql":           res_sqlite_migrations_0007_libraries_sql,
+	"res/sqlite/migrations/0008_art_source.sql":          res_sqlite_migrations_0008_art_source_sql,
+	"res/sqlite/migrations/0009_album_art.sql":           res_sqlite_migrations_0009_album_art_sql,
+	"res/sqlite/migrations/0010_cue_tracks.sql":          res_sqlite_migrations_0010_cue_tracks_sql,
+	"res/sqlite/migrations/0011_song_fingerprints.sql":   res_sqlite_migrations_0011_song_fingerprints_sql,
+	"res/sqlite/wavepipe.db":                             res_sqlite_wavepipe_db,
+	"res/web/index.html":                                 res_web_index_html,
 }
@@ -0,0 +1,304 @@
+package data
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// cueFramesPerSecond is the number of frames in each second of a CUE sheet timestamp
+const cueFramesPerSecond = 75
+
+var (
+	// ErrCueSheetNoTracks is returned when a CUE sheet does not describe any tracks
+	ErrCueSheetNoTracks = errors.New("cue: sheet contains no tracks")
+)
+
+// CueSheet represents a CUE sheet, which describes the tracks stored in one or more audio files,
+// such as an album ripped to a single file
+type CueSheet struct {
+	Title     string
+	Performer string
+	Genre     string
+	Year      int
+	Files     []CueFile
+}
+
+// CueFile represents an audio file referenced by a CUE sheet, and the tracks it contains
+type CueFile struct {
+	Name   string
+	Tracks []CueTrack
+}
+
+// CueTrack represents a single track of a CUE sheet, which begins at an offset in milliseconds
+// within its file
+type CueTrack struct {
+	Number    int
+	Title     string
+	Performer string
+	Start     int
+}
+
+// ParseCueSheet parses a CUE sheet.  Sheets which are not valid UTF-8 are decoded as ISO-8859-1,
+// which is common for sheets created by older rippers.
+func ParseCueSheet(r io.Reader) (*CueSheet, error) {
+	c := new(CueSheet)
+
+	var file *CueFile
+	var track *CueTrack
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(decodeCueLine(scanner.Bytes()), "\ufeff"))
+		fields := splitCueLine(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		// Commands before the first track describe the whole sheet
+		switch strings.ToUpper(fields[0]) {
+		case "FILE":
+			if len(fields) < 2 {
+				continue
+			}
+
+			c.Files = append(c.Files, CueFile{Name: fields[1]})
+			file = &c.Files[len(c.Files)-1]
+			track = nil
+		case "TRACK":
+			if file == nil || len(fields) < 2 {
+				continue
+			}
+
+			number, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("cue: invalid track number: %s", fields[1])
+			}
+
+			// Tracks without an index are skipped once the sheet is parsed
+			file.Tracks = append(file.Tracks, CueTrack{Number: number, Start: -1})
+			track = &file.Tracks[len(file.Tracks)-1]
+		case "INDEX":
+			// Index 01 marks the beginning of a track, after any pregap
+			if track == nil || len(fields) < 3 || fields[1] != "01" {
+				continue
+			}
+
+			start, err := parseCueTime(fields[2])
+			if err != nil {
+				return nil, err
+			}
+			track.Start = start
+		case "TITLE":
+			if len(fields) < 2 {
+				continue
+			}
+
+			if track != nil {
+				track.Title = fields[1]
+			} else {
+				c.Title = fields[1]
+			}
+		case "PERFORMER":
+			if len(fields) < 2 {
+				continue
+			}
+
+			if track != nil {
+				track.Performer = fields[1]
+			} else {
+				c.Performer = fields[1]
+			}
+		case "REM":
+			if track != nil || len(fields) < 3 {
+				continue
+			}
+
+			switch strings.ToUpper(fields[1]) {
+			case "GENRE":
+				c.Genre = fields[2]
+			case "DATE":
+				if len(fields[2]) >= 4 {
+					c.Year, _ = strconv.Atoi(fields[2][0:4])
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Remove tracks without an index, and files without tracks
+	files := make([]CueFile, 0, len(c.Files))
+	for _, f := range c.Files {
+		tracks := make([]CueTrack, 0, len(f.Tracks))
+		for _, t := range f.Tracks {
+			if t.Start >= 0 {
+				tracks = append(tracks, t)
+			}
+		}
+
+		if len(tracks) > 0 {
+			f.Tracks = tracks
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		return nil, ErrCueSheetNoTracks
+	}
+	c.Files = files
+
+	return c, nil
+}
+
+// File returns the file in this CUE sheet with the input name.  Rippers often reference a file
+// which was later converted to another format, so if no name matches exactly, a file with the
+// same name and a different extension is returned.
+func (c CueSheet) File(name string) (CueFile, bool) {
+	for _, f := range c.Files {
+		if strings.EqualFold(path.Base(f.Name), name) {
+			return f, true
+		}
+	}
+
+	stem := strings.TrimSuffix(name, path.Ext(name))
+	for _, f := range c.Files {
+		base := path.Base(f.Name)
+		if strings.EqualFold(strings.TrimSuffix(base, path.Ext(base)), stem) {
+			return f, true
+		}
+	}
+
+	return CueFile{}, false
+}
+
+// Songs splits a song read from an audio file into a song for each track this CUE sheet
+// describes within the file.  Tracks inherit the song's tags and properties, which are overridden
+// by the sheet's tags.  If the sheet does not describe the file, no songs are returned.
+func (c CueSheet) Songs(song *Song) []Song {
+	file, ok := c.File(path.Base(song.FileName))
+	if !ok {
+		return nil
+	}
+
+	songs := make([]Song, 0, len(file.Tracks))
+	for i, t := range file.Tracks {
+		s := *song
+		s.StartOffset = t.Start
+
+		// Tracks end where the next begins, and the last track ends with the file
+		end := song.Length * 1000
+		s.EndOffset = 0
+		if i+1 < len(file.Tracks) {
+			end = file.Tracks[i+1].Start
+			s.EndOffset = end
+		}
+
+		// Skip tracks which do not fit in the file, such as those described by a sheet for
+		// another copy of the album
+		if end <= t.Start {
+			continue
+		}
+		s.Length = (end - t.Start) / 1000
+
+		s.Title = t.Title
+		if s.Title == "" {
+			s.Title = fmt.Sprintf("Track %02d", t.Number)
+		}
+
+		// Prefer the track's performer, and use the sheet's performer for the album
+		if t.Performer != "" {
+			s.Artist = t.Performer
+		} else if c.Performer != "" {
+			s.Artist = c.Performer
+		}
+		if c.Performer != "" {
+			s.AlbumArtist = c.Performer
+		}
+
+		if c.Title != "" {
+			s.Album = c.Title
+		}
+		if c.Genre != "" {
+			s.Genre = c.Genre
+		}
+		if c.Year > 0 {
+			s.Year = c.Year
+		}
+
+		s.Track = t.Number
+		s.TrackTotal = len(file.Tracks)
+		songs = append(songs, s)
+	}
+
+	return songs
+}
+
+// decodeCueLine decodes a line of a CUE sheet, as UTF-8 if it is valid, or as ISO-8859-1
+func decodeCueLine(line []byte) string {
+	if utf8.Valid(line) {
+		return string(line)
+	}
+
+	runes := make([]rune, len(line))
+	for i, b := range line {
+		runes[i] = rune(b)
+	}
+
+	return string(runes)
+}
+
+// splitCueLine splits a line of a CUE sheet into its fields, which are separated by spaces, and
+// may be quoted to contain spaces
+func splitCueLine(line string) []string {
+	fields := make([]string, 0)
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return fields
+		}
+
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return append(fields, line[1:])
+			}
+
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			return append(fields, line)
+		}
+
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
+
+// parseCueTime parses a CUE sheet timestamp, in the form minutes:seconds:frames, into
+// milliseconds
+func parseCueTime(value string) (int, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("cue: invalid timestamp: %s", value)
+	}
+
+	var times [3]int
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("cue: invalid timestamp: %s", value)
+		}
+		times[i] = n
+	}
+
+	return (times[0]*60+times[1])*1000 + times[2]*1000/cueFramesPerSecond, nil
+}
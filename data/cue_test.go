@@ -0,0 +1,179 @@
+package data
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// testCueSheet is a CUE sheet describing three tracks in a single file, with a pregap before
+// the second track, encoded as ISO-8859-1
+var testCueSheet = "\xef\xbb\xbfREM GENRE Jazz\r\n" +
+	"REM DATE 1959-08-17\r\n" +
+	"PERFORMER \"Band\"\r\n" +
+	"TITLE \"Caf\xe9 Sessions\"\r\n" +
+	"FILE \"Band - Caf\xe9 Sessions.wav\" WAVE\r\n" +
+	"  TRACK 01 AUDIO\r\n" +
+	"    TITLE \"Opening\"\r\n" +
+	"    INDEX 01 00:00:00\r\n" +
+	"  TRACK 02 AUDIO\r\n" +
+	"    TITLE \"Second Song\"\r\n" +
+	"    PERFORMER \"Band feat. Guest\"\r\n" +
+	"    INDEX 00 03:00:00\r\n" +
+	"    INDEX 01 03:02:37\r\n" +
+	"  TRACK 03 AUDIO\r\n" +
+	"    INDEX 01 07:30:00\r\n"
+
+// TestParseCueSheet verifies that CUE sheets are parsed into their files and tracks
+func TestParseCueSheet(t *testing.T) {
+	c, err := ParseCueSheet(bytes.NewReader([]byte(testCueSheet)))
+	if err != nil {
+		t.Fatalf("Could not parse CUE sheet: %s", err.Error())
+	}
+
+	if c.Title != "Café Sessions" || c.Performer != "Band" || c.Genre != "Jazz" || c.Year != 1959 {
+		t.Fatalf("Unexpected CUE sheet: %v", c)
+	}
+	if len(c.Files) != 1 || c.Files[0].Name != "Band - Café Sessions.wav" || len(c.Files[0].Tracks) != 3 {
+		t.Fatalf("Unexpected CUE sheet files: %v", c.Files)
+	}
+
+	// Index 01 is the start of each track, in milliseconds
+	track := c.Files[0].Tracks[1]
+	if track.Number != 2 || track.Title != "Second Song" || track.Performer != "Band feat. Guest" || track.Start != 182493 {
+		t.Fatalf("Unexpected CUE sheet track: %v", track)
+	}
+
+	// Verify files are matched by name, falling back to a different extension
+	for _, name := range []string{"Band - Café Sessions.wav", "band - café sessions.WAV", "Band - Café Sessions.flac"} {
+		if _, ok := c.File(name); !ok {
+			t.Fatalf("CUE sheet file not found: %s", name)
+		}
+	}
+	if _, ok := c.File("Other.flac"); ok {
+		t.Fatalf("Unexpected CUE sheet file for another name")
+	}
+
+	// Verify sheets without tracks, or with invalid timestamps, are rejected
+	if _, err := ParseCueSheet(bytes.NewReader([]byte("TITLE \"Empty\"\n"))); err != ErrCueSheetNoTracks {
+		t.Fatalf("Unexpected error for empty CUE sheet: %v", err)
+	}
+	if _, err := ParseCueSheet(bytes.NewReader([]byte("FILE \"a.wav\" WAVE\nTRACK 01 AUDIO\nINDEX 01 1:2\n"))); err == nil {
+		t.Fatalf("No error for invalid CUE sheet timestamp")
+	}
+}
+
+// TestCueSheetSongs verifies that a song is split into a song for each track of a CUE sheet,
+// and that each track streams its section of the file
+func TestCueSheetSongs(t *testing.T) {
+	c, err := ParseCueSheet(bytes.NewReader([]byte(testCueSheet)))
+	if err != nil {
+		t.Fatalf("Could not parse CUE sheet: %s", err.Error())
+	}
+
+	song := &Song{
+		Artist:     "Tagged Artist",
+		Bitrate:    1411,
+		FileName:   "/music/Band - Café Sessions.flac",
+		FileSize:   1411 * 600 * 1000 / 8,
+		FileTypeID: FLAC,
+		Length:     600,
+		Title:      "Tagged Title",
+	}
+	songs := c.Songs(song)
+
+	tests := []struct {
+		title  string
+		artist string
+		start  int
+		end    int
+		length int
+	}{
+		{"Opening", "Band", 0, 182493, 182},
+		{"Second Song", "Band feat. Guest", 182493, 450000, 267},
+		{"Track 03", "Band", 450000, 0, 150},
+	}
+	if len(songs) != len(tests) {
+		t.Fatalf("Unexpected number of songs: %d != %d", len(songs), len(tests))
+	}
+
+	for i, test := range tests {
+		s := songs[i]
+		if s.Title != test.title || s.Artist != test.artist || s.StartOffset != test.start || s.EndOffset != test.end || s.Length != test.length {
+			t.Fatalf("Unexpected song %d: %q, %q, %d-%d, %d seconds", i, s.Title, s.Artist, s.StartOffset, s.EndOffset, s.Length)
+		}
+		if s.Album != "Café Sessions" || s.AlbumArtist != "Band" || s.Track != i+1 || s.TrackTotal != 3 || s.Year != 1959 {
+			t.Fatalf("Unexpected song %d tags: %v", i, s)
+		}
+		if !s.IsCueTrack() {
+			t.Fatalf("Song %d is not a CUE track", i)
+		}
+	}
+
+	// The last track streams through the end of the file
+	if size := songs[2].StreamSize(); size != 1411*150*1000/8 {
+		t.Fatalf("Unexpected stream size: %d", size)
+	}
+	if size := song.StreamSize(); size != song.FileSize {
+		t.Fatalf("Unexpected stream size for whole file: %d", size)
+	}
+
+	// Verify CUE tracks in FLAC files cannot be streamed directly, because the file's header
+	// would be missing
+	if _, err := songs[1].Stream(); err != ErrSongCannotSplit {
+		t.Fatalf("Unexpected error streaming FLAC CUE track: %v", err)
+	}
+
+	// Verify files the sheet does not describe are not split
+	if songs := c.Songs(&Song{FileName: "/music/Other.flac", Length: 600}); len(songs) != 0 {
+		t.Fatalf("Unexpected songs for another file: %v", songs)
+	}
+}
+
+// TestCueTrackStream verifies that a CUE track in an MP3 file streams only its section of the
+// file, and that closing the stream closes the file
+func TestCueTrackStream(t *testing.T) {
+	// Create a temporary file, containing 10 seconds of 8kbps audio
+	file, err := ioutil.TempFile("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %s", err.Error())
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(bytes.Repeat([]byte("0123456789"), 1000)); err != nil {
+		t.Fatalf("Could not write temporary file: %s", err.Error())
+	}
+	file.Close()
+
+	song := Song{
+		Bitrate:     8,
+		EndOffset:   5000,
+		FileName:    file.Name(),
+		FileSize:    10000,
+		FileTypeID:  MP3,
+		StartOffset: 2000,
+	}
+
+	stream, err := song.Stream()
+	if err != nil {
+		t.Fatalf("Could not stream CUE track: %s", err.Error())
+	}
+
+	// Verify only the track's bytes are streamed
+	buf, err := ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("Could not read CUE track: %s", err.Error())
+	}
+	if int64(len(buf)) != song.StreamSize() || len(buf) != 3000 {
+		t.Fatalf("Unexpected CUE track length: %d", len(buf))
+	}
+
+	// Verify the file is closed, so closing it again fails
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Could not close CUE track stream: %s", err.Error())
+	}
+	if err := stream.Close(); err == nil {
+		t.Fatalf("CUE track file was not closed")
+	}
+}
@@ -3,15 +3,34 @@ package data
 // DB is the current database backend
 var DB dbBackend
 
+// Column lists shared by SQL database backends, which join items with their related titles
+// and calculate play statistics
+const (
+	// albumColumns selects all album columns, the album's artist title, and its total play count
+	albumColumns = "albums.*,artists.title AS artist," +
+		"(SELECT COUNT(*) FROM plays JOIN songs ON plays.song_id = songs.id WHERE songs.album_id = albums.id) AS play_count"
+
+	// songColumns selects all song columns, the song's artist, album, and album artist titles, its
+	// total play count, and the time it was last played
+	songColumns = "songs.*,artists.title AS artist,albums.title AS album," +
+		"(SELECT album_artists.title FROM artists AS album_artists WHERE album_artists.id = albums.artist_id) AS album_artist," +
+		"(SELECT COUNT(*) FROM plays WHERE plays.song_id = songs.id) AS play_count," +
+		"(SELECT COALESCE(MAX(plays.timestamp), 0) FROM plays WHERE plays.song_id = songs.id) AS last_played"
+)
+
 // dbBackend represents the database backend that the program will connect to
 type dbBackend interface {
 	Open() error
 	Close() error
 	Setup() error
 	DSN(string)
+	Migrate() error
+	SchemaVersion() (int, error)
+	MovePath(string, string) error
 
 	ArtInPath(string) ([]Art, error)
-	ArtNotInPath(string) ([]Art, error)
+	ArtNotInPath(int, string) ([]Art, error)
+	OrphanEmbeddedArt() ([]Art, error)
 	CountArt() (int64, error)
 	DeleteArt(*Art) error
 	LoadArt(*Art) error
@@ -20,7 +39,7 @@ type dbBackend interface {
 	AllArtists() ([]Artist, error)
 	AllArtistsByTitle() ([]Artist, error)
 	LimitArtists(int, int) ([]Artist, error)
-	SearchArtists(string) ([]Artist, error)
+	SearchArtists(Query, int, int) ([]Artist, int64, error)
 	CountArtists() (int64, error)
 	PurgeOrphanArtists() (int, error)
 	DeleteArtist(*Artist) error
@@ -30,38 +49,89 @@ type dbBackend interface {
 	AllAlbums() ([]Album, error)
 	LimitAlbums(int, int) ([]Album, error)
 	AlbumsForArtist(int) ([]Album, error)
-	SearchAlbums(string) ([]Album, error)
+	GalleryForAlbum(int) ([]Art, error)
+	SetAlbumGallery(int, []int) error
+	SearchAlbums(Query, int, int) ([]Album, int64, error)
 	CountAlbums() (int64, error)
 	PurgeOrphanAlbums() (int, error)
 	DeleteAlbum(*Album) error
 	LoadAlbum(*Album) error
 	SaveAlbum(*Album) error
+	UpdateAlbum(*Album) error
 
 	AllFolders() ([]Folder, error)
 	LimitFolders(int, int) ([]Folder, error)
 	Subfolders(int) ([]Folder, error)
 	FoldersInPath(string) ([]Folder, error)
-	FoldersNotInPath(string) ([]Folder, error)
-	SearchFolders(string) ([]Folder, error)
+	FoldersNotInPath(int, string) ([]Folder, error)
+	SearchFolders(Query, int, int) ([]Folder, int64, error)
 	CountFolders() (int64, error)
 	DeleteFolder(*Folder) error
 	LoadFolder(*Folder) error
 	SaveFolder(*Folder) error
+	UpdateFolder(*Folder) error
+
+	AllLibraries() ([]Library, error)
+	DeleteLibrary(*Library) error
+	LoadLibrary(*Library) error
+	SaveLibrary(*Library) error
+	UpdateLibrary(*Library) error
+
+	PlaysForUser(int) ([]Play, error)
+	LimitPlaysForUser(int, int, int) ([]Play, error)
+	DeletePlay(*Play) error
+	LoadPlay(*Play) error
+	SavePlay(*Play) error
+
+	PlaylistsForUser(int) ([]Playlist, error)
+	DeletePlaylist(*Playlist) error
+	LoadPlaylist(*Playlist) error
+	SavePlaylist(*Playlist) error
+	UpdatePlaylist(*Playlist) error
+
+	EntriesForPlaylist(int) ([]PlaylistEntry, error)
+	SongsForPlaylist(int) ([]Song, error)
+	AppendPlaylistEntries(int, []int) error
+	RemovePlaylistEntries(int, []int) error
+	ReorderPlaylistEntries(int, []int) error
+
+	RatingsForUser(int) ([]Rating, error)
+	DeleteRating(*Rating) error
+	LoadRating(*Rating) error
+	SaveRating(*Rating) error
+	UpdateRating(*Rating) error
+
+	SmartPlaylistsForUser(int) ([]SmartPlaylist, error)
+	DeleteSmartPlaylist(*SmartPlaylist) error
+	LoadSmartPlaylist(*SmartPlaylist) error
+	SaveSmartPlaylist(*SmartPlaylist) error
+	UpdateSmartPlaylist(*SmartPlaylist) error
+	SmartPlaylistSongs(Query, string, int) ([]Song, error)
+	CountSmartPlaylistSongs(Query, string, int) (int64, int64, error)
 
 	AllSongs() ([]Song, error)
 	LimitSongs(int, int) ([]Song, error)
 	RandomSongs(int) ([]Song, error)
-	SearchSongs(string) ([]Song, error)
+	SearchSongs(Query, int, int) ([]Song, int64, error)
 	SongsForAlbum(int) ([]Song, error)
 	SongsForArtist(int) ([]Song, error)
 	SongsForFolder(int) ([]Song, error)
+	SongsForFingerprint(string) ([]Song, error)
 	SongsInPath(string) ([]Song, error)
-	SongsNotInPath(string) ([]Song, error)
+	SongsNotInPath(int, string) ([]Song, error)
+	SongFilesInPath(string) ([]SongFile, error)
 	CountSongs() (int64, error)
 	DeleteSong(*Song) error
 	LoadSong(*Song) error
 	SaveSong(*Song) error
+	SaveSongs([]Song) error
 	UpdateSong(*Song) error
+	UpdateSongs([]Song) error
+
+	StarsForUser(int) ([]Star, error)
+	DeleteStar(*Star) error
+	LoadStar(*Star) error
+	SaveStar(*Star) error
 
 	AllUsers() ([]User, error)
 	DeleteUser(*User) error
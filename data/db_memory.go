@@ -0,0 +1,2543 @@
+package data
+
+import (
+	"database/sql"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MemoryBackend represents an ephemeral, in-memory database backend.  It mimics the semantics
+// of SqliteBackend, including unique constraints and LIKE-style path queries, but its contents
+// are lost when the program exits.  It is primarily useful for tests.
+type MemoryBackend struct {
+	mutex sync.RWMutex
+
+	albumGallery    map[int][]int
+	albums          []Album
+	art             []Art
+	artists         []Artist
+	folders         []Folder
+	libraries       []Library
+	playlistEntries []PlaylistEntry
+	playlists       []Playlist
+	plays           []Play
+	ratings         []Rating
+	sessions        []Session
+	smartPlaylists  []SmartPlaylist
+	songs           []Song
+	stars           []Star
+	users           []User
+
+	// lastID tracks the last ID assigned in each table, so that IDs are never reused,
+	// just as with AUTOINCREMENT in sqlite
+	lastID map[string]int
+}
+
+// DSN performs no action for the memory backend, because it has no data source
+func (m *MemoryBackend) DSN(dsn string) {}
+
+// Setup performs no action for the memory backend
+func (m *MemoryBackend) Setup() error {
+	return nil
+}
+
+// Open initializes a new, empty in-memory database
+func (m *MemoryBackend) Open() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.albumGallery = make(map[int][]int)
+	m.albums = make([]Album, 0)
+	m.art = make([]Art, 0)
+	m.artists = make([]Artist, 0)
+	m.folders = make([]Folder, 0)
+	m.libraries = make([]Library, 0)
+	m.playlistEntries = make([]PlaylistEntry, 0)
+	m.playlists = make([]Playlist, 0)
+	m.plays = make([]Play, 0)
+	m.ratings = make([]Rating, 0)
+	m.sessions = make([]Session, 0)
+	m.smartPlaylists = make([]SmartPlaylist, 0)
+	m.songs = make([]Song, 0)
+	m.stars = make([]Star, 0)
+	m.users = make([]User, 0)
+	m.lastID = make(map[string]int)
+
+	return nil
+}
+
+// Close performs no action for the memory backend
+func (m *MemoryBackend) Close() error {
+	return nil
+}
+
+// Migrate performs no action for the memory backend, because it is always created
+// using the latest schema
+func (m *MemoryBackend) Migrate() error {
+	return nil
+}
+
+// SchemaVersion returns the latest schema version, because the memory backend is always
+// created using the latest schema
+func (m *MemoryBackend) SchemaVersion() (int, error) {
+	migrations, err := loadMigrations("res/sqlite/migrations/")
+	return len(migrations), err
+}
+
+// MovePath updates the paths of all songs, art, and folders at the specified path, or beneath
+// it, so that they reside at a new path and keep their IDs.  No items may already exist at
+// the new path.
+func (m *MemoryBackend) MovePath(from string, to string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	move := func(p string) string {
+		if p == from || strings.HasPrefix(p, from+"/") {
+			return to + p[len(from):]
+		}
+
+		return p
+	}
+
+	for i := range m.songs {
+		m.songs[i].FileName = move(m.songs[i].FileName)
+	}
+	for i := range m.art {
+		m.art[i].FileName = move(m.art[i].FileName)
+	}
+	for i := range m.folders {
+		m.folders[i].Path = move(m.folders[i].Path)
+	}
+
+	return nil
+}
+
+// ArtInPath loads a slice of all Art structs contained within the specified file path
+func (m *MemoryBackend) ArtInPath(path string) ([]Art, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	art := make([]Art, 0)
+	for _, a := range m.art {
+		if sqlLike(a.FileName, path+"%") {
+			art = append(art, a)
+		}
+	}
+
+	return art, nil
+}
+
+// ArtNotInPath loads a slice of all art file Art structs belonging to the specified library, or
+// to no library, which are NOT contained within the specified file path
+func (m *MemoryBackend) ArtNotInPath(libraryID int, path string) ([]Art, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	art := make([]Art, 0)
+	for _, a := range m.art {
+		if a.Source == ArtSourceFile && (a.LibraryID == 0 || a.LibraryID == libraryID) && !sqlLike(a.FileName, path+"%") {
+			art = append(art, a)
+		}
+	}
+
+	return art, nil
+}
+
+// OrphanEmbeddedArt loads a slice of all embedded Art structs which are no longer used by any song
+func (m *MemoryBackend) OrphanEmbeddedArt() ([]Art, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Collect all art IDs referenced by a song
+	referenced := make(map[int]struct{})
+	for _, s := range m.songs {
+		referenced[s.ArtID] = struct{}{}
+	}
+
+	art := make([]Art, 0)
+	for _, a := range m.art {
+		if _, ok := referenced[a.ID]; a.Source == ArtSourceEmbedded && !ok {
+			art = append(art, a)
+		}
+	}
+
+	return art, nil
+}
+
+// CountArt fetches the total number of Art structs from the database
+func (m *MemoryBackend) CountArt() (int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return int64(len(m.art)), nil
+}
+
+// DeleteArt removes Art from the database
+func (m *MemoryBackend) DeleteArt(a *Art) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this art by its ID
+	art := make([]Art, 0, len(m.art))
+	for _, row := range m.art {
+		if row.ID != a.ID {
+			art = append(art, row)
+		}
+	}
+	m.art = art
+
+	// Update any songs and albums using this art ID to have a zero ID
+	for i := range m.songs {
+		if m.songs[i].ArtID == a.ID {
+			m.songs[i].ArtID = 0
+		}
+	}
+	for i := range m.albums {
+		if m.albums[i].ArtID == a.ID {
+			m.albums[i].ArtID = 0
+		}
+	}
+
+	// Remove this art from all galleries
+	for albumID, artIDs := range m.albumGallery {
+		gallery := make([]int, 0, len(artIDs))
+		for _, artID := range artIDs {
+			if artID != a.ID {
+				gallery = append(gallery, artID)
+			}
+		}
+		m.albumGallery[albumID] = gallery
+	}
+
+	return nil
+}
+
+// LoadArt loads Art from the database, populating the parameter struct
+func (m *MemoryBackend) LoadArt(a *Art) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the art via ID if available, or via file name
+	for _, row := range m.art {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.FileName == a.FileName) {
+			*a = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveArt attempts to save Art to the database
+func (m *MemoryBackend) SaveArt(a *Art) error {
+	m.mutex.Lock()
+
+	// Insert new art, unless the file name already exists
+	exists := false
+	for _, row := range m.art {
+		if row.FileName == a.FileName {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *a
+		row.ID = m.nextID("art")
+		m.art = append(m.art, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := m.LoadArt(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllArtists loads a slice of all Artist structs from the database
+func (m *MemoryBackend) AllArtists() ([]Artist, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	artists := make([]Artist, len(m.artists))
+	copy(artists, m.artists)
+	return artists, nil
+}
+
+// AllArtistsByTitle loads a slice of all Artist structs from the database, sorted alphabetically by title
+func (m *MemoryBackend) AllArtistsByTitle() ([]Artist, error) {
+	artists, err := m.AllArtists()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Stable(artistsByTitle(artists))
+	return artists, nil
+}
+
+// LimitArtists loads a slice of Artist structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (m *MemoryBackend) LimitArtists(offset int, count int) ([]Artist, error) {
+	artists, err := m.AllArtists()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := limitRange(len(artists), offset, count)
+	return artists[start:end], nil
+}
+
+// SearchArtists loads a slice of Artist structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  The total number of matching
+// artists is also returned.
+func (m *MemoryBackend) SearchArtists(q Query, offset int, count int) ([]Artist, int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Artist, 0), 0, nil
+	}
+
+	// Filter artists by their songs, and rank the remainder
+	ids := m.querySongs(q, func(s Song) int {
+		return s.ArtistID
+	})
+	all := make([]Artist, 0)
+	for _, a := range m.artists {
+		if ids == nil || ids[a.ID] {
+			all = append(all, a)
+		}
+	}
+	indexes, total := rankSearch(searchTerms(q.Text), len(all), func(i int) []string {
+		return []string{all[i].Title}
+	}, []float64{1}, offset, count)
+
+	artists := make([]Artist, 0)
+	for _, i := range indexes {
+		artists = append(artists, all[i])
+	}
+
+	return artists, total, nil
+}
+
+// CountArtists fetches the total number of Artist structs from the database
+func (m *MemoryBackend) CountArtists() (int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return int64(len(m.artists)), nil
+}
+
+// PurgeOrphanArtists deletes all artists who are "orphaned", meaning that they no
+// longer have any songs or albums which reference their ID
+func (m *MemoryBackend) PurgeOrphanArtists() (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Collect all artist IDs referenced by a song, or by an album as its album artist
+	referenced := make(map[int]struct{})
+	for _, s := range m.songs {
+		referenced[s.ArtistID] = struct{}{}
+	}
+	for _, a := range m.albums {
+		referenced[a.ArtistID] = struct{}{}
+	}
+
+	// Remove all artists which are not referenced
+	artists := make([]Artist, 0, len(m.artists))
+	total := 0
+	for _, a := range m.artists {
+		if _, ok := referenced[a.ID]; !ok {
+			total++
+			continue
+		}
+
+		artists = append(artists, a)
+	}
+	m.artists = artists
+
+	return total, nil
+}
+
+// DeleteArtist removes an Artist from the database
+func (m *MemoryBackend) DeleteArtist(a *Artist) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this artist by its ID if available, or by its title
+	artists := make([]Artist, 0, len(m.artists))
+	for _, row := range m.artists {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.Title == a.Title) {
+			continue
+		}
+
+		artists = append(artists, row)
+	}
+	m.artists = artists
+
+	return nil
+}
+
+// LoadArtist loads an Artist from the database, populating the parameter struct
+func (m *MemoryBackend) LoadArtist(a *Artist) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the artist via ID if available, or via title
+	for _, row := range m.artists {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.Title == a.Title) {
+			*a = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveArtist attempts to save an Artist to the database
+func (m *MemoryBackend) SaveArtist(a *Artist) error {
+	m.mutex.Lock()
+
+	// Insert new artist, unless the title already exists
+	exists := false
+	for _, row := range m.artists {
+		if row.Title == a.Title {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *a
+		row.ID = m.nextID("artists")
+		m.artists = append(m.artists, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := m.LoadArtist(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllAlbums loads a slice of all Album structs from the database
+func (m *MemoryBackend) AllAlbums() ([]Album, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.albumFilter(func(a Album) bool {
+		return true
+	}), nil
+}
+
+// LimitAlbums loads a slice of Album structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (m *MemoryBackend) LimitAlbums(offset int, count int) ([]Album, error) {
+	albums, err := m.AllAlbums()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := limitRange(len(albums), offset, count)
+	return albums[start:end], nil
+}
+
+// AlbumsForArtist loads a slice of all Album structs with matching artist ID
+func (m *MemoryBackend) AlbumsForArtist(ID int) ([]Album, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.albumFilter(func(a Album) bool {
+		return a.ArtistID == ID
+	}), nil
+}
+
+// GalleryForAlbum loads a slice of all Art structs in the gallery of the album with the
+// matching ID, ordered by their position in the gallery
+func (m *MemoryBackend) GalleryForAlbum(ID int) ([]Art, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Join each art ID with its art, skipping any art which no longer exists
+	art := make([]Art, 0)
+	for _, artID := range m.albumGallery[ID] {
+		for _, a := range m.art {
+			if a.ID == artID {
+				art = append(art, a)
+				break
+			}
+		}
+	}
+
+	return art, nil
+}
+
+// SetAlbumGallery replaces the gallery of the album with the matching ID with the art with the
+// input IDs, in order
+func (m *MemoryBackend) SetAlbumGallery(ID int, artIDs []int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	gallery := make([]int, len(artIDs))
+	copy(gallery, artIDs)
+	m.albumGallery[ID] = gallery
+
+	return nil
+}
+
+// SearchAlbums loads a slice of Album structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  Album titles are weighted above
+// artist titles.  The total number of matching albums is also returned.
+func (m *MemoryBackend) SearchAlbums(q Query, offset int, count int) ([]Album, int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Album, 0), 0, nil
+	}
+
+	// Filter albums by their songs, and rank the remainder
+	ids := m.querySongs(q, func(s Song) int {
+		return s.AlbumID
+	})
+	all := m.albumFilter(func(a Album) bool {
+		return ids == nil || ids[a.ID]
+	})
+	indexes, total := rankSearch(searchTerms(q.Text), len(all), func(i int) []string {
+		return []string{all[i].Title, all[i].Artist}
+	}, []float64{10, 5}, offset, count)
+
+	albums := make([]Album, 0)
+	for _, i := range indexes {
+		albums = append(albums, all[i])
+	}
+
+	return albums, total, nil
+}
+
+// CountAlbums fetches the total number of Album structs from the database
+func (m *MemoryBackend) CountAlbums() (int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return int64(len(m.albums)), nil
+}
+
+// PurgeOrphanAlbums deletes all albums who are "orphaned", meaning that they no
+// longer have any songs which reference their ID
+func (m *MemoryBackend) PurgeOrphanAlbums() (int, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Collect all album IDs referenced by a song
+	referenced := make(map[int]struct{})
+	for _, s := range m.songs {
+		referenced[s.AlbumID] = struct{}{}
+	}
+
+	// Remove all albums which are not referenced
+	albums := make([]Album, 0, len(m.albums))
+	total := 0
+	for _, a := range m.albums {
+		if _, ok := referenced[a.ID]; !ok {
+			delete(m.albumGallery, a.ID)
+			total++
+			continue
+		}
+
+		albums = append(albums, a)
+	}
+	m.albums = albums
+
+	return total, nil
+}
+
+// DeleteAlbum removes an Album from the database
+func (m *MemoryBackend) DeleteAlbum(a *Album) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this album by its ID if available, or by its artist ID and title
+	albums := make([]Album, 0, len(m.albums))
+	for _, row := range m.albums {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.ArtistID == a.ArtistID && row.Title == a.Title) {
+			delete(m.albumGallery, row.ID)
+			continue
+		}
+
+		albums = append(albums, row)
+	}
+	m.albums = albums
+
+	return nil
+}
+
+// LoadAlbum loads an Album from the database, populating the parameter struct
+func (m *MemoryBackend) LoadAlbum(a *Album) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the album via ID if available, or via artist ID and title
+	albums := m.albumFilter(func(row Album) bool {
+		return (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.ArtistID == a.ArtistID && row.Title == a.Title)
+	})
+	if len(albums) == 0 {
+		return sql.ErrNoRows
+	}
+
+	*a = albums[0]
+	return nil
+}
+
+// SaveAlbum attempts to save an Album to the database
+func (m *MemoryBackend) SaveAlbum(a *Album) error {
+	m.mutex.Lock()
+
+	// Insert new album, unless the artist ID and title already exist
+	exists := false
+	for _, row := range m.albums {
+		if row.ArtistID == a.ArtistID && row.Title == a.Title {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *a
+		row.ID = m.nextID("albums")
+		row.Artist = ""
+		row.PlayCount = 0
+		m.albums = append(m.albums, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := m.LoadAlbum(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateAlbum attempts to update an Album in the database
+func (m *MemoryBackend) UpdateAlbum(a *Album) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to update this album by its ID
+	for i := range m.albums {
+		if m.albums[i].ID == a.ID {
+			m.albums[i].ArtID = a.ArtID
+			m.albums[i].Year = a.Year
+		}
+	}
+
+	return nil
+}
+
+// AllFolders loads a slice of all Folder structs from the database
+func (m *MemoryBackend) AllFolders() ([]Folder, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.folderFilter(func(f Folder) bool {
+		return true
+	}), nil
+}
+
+// LimitFolders loads a slice of Folder structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (m *MemoryBackend) LimitFolders(offset int, count int) ([]Folder, error) {
+	folders, err := m.AllFolders()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := limitRange(len(folders), offset, count)
+	return folders[start:end], nil
+}
+
+// Subfolders loads a slice of all Folder structs residing directly beneath this one from the database
+func (m *MemoryBackend) Subfolders(parentID int) ([]Folder, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.folderFilter(func(f Folder) bool {
+		return f.ParentID == parentID
+	}), nil
+}
+
+// FoldersInPath loads a slice of all Folder structs contained within the specified file path
+func (m *MemoryBackend) FoldersInPath(path string) ([]Folder, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.folderFilter(func(f Folder) bool {
+		return sqlLike(f.Path, path+"%")
+	}), nil
+}
+
+// FoldersNotInPath loads a slice of all Folder structs belonging to the specified library, or to
+// no library, which are NOT contained within the specified file path
+func (m *MemoryBackend) FoldersNotInPath(libraryID int, path string) ([]Folder, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.folderFilter(func(f Folder) bool {
+		return (f.LibraryID == 0 || f.LibraryID == libraryID) && !sqlLike(f.Path, path+"%")
+	}), nil
+}
+
+// SearchFolders loads a slice of Folder structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  The total number of matching
+// folders is also returned.
+func (m *MemoryBackend) SearchFolders(q Query, offset int, count int) ([]Folder, int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Folder, 0), 0, nil
+	}
+
+	// Filter folders by their songs, and rank the remainder
+	ids := m.querySongs(q, func(s Song) int {
+		return s.FolderID
+	})
+	all := m.folderFilter(func(f Folder) bool {
+		return ids == nil || ids[f.ID]
+	})
+	indexes, total := rankSearch(searchTerms(q.Text), len(all), func(i int) []string {
+		return []string{all[i].Title}
+	}, []float64{1}, offset, count)
+
+	folders := make([]Folder, 0)
+	for _, i := range indexes {
+		folders = append(folders, all[i])
+	}
+
+	return folders, total, nil
+}
+
+// CountFolders fetches the total number of Folder structs from the database
+func (m *MemoryBackend) CountFolders() (int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return int64(len(m.folders)), nil
+}
+
+// DeleteFolder removes a Folder from the database
+func (m *MemoryBackend) DeleteFolder(f *Folder) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this folder by its ID if available, or by its path
+	folders := make([]Folder, 0, len(m.folders))
+	for _, row := range m.folders {
+		if (f.ID != 0 && row.ID == f.ID) || (f.ID == 0 && row.Path == f.Path) {
+			continue
+		}
+
+		folders = append(folders, row)
+	}
+	m.folders = folders
+
+	return nil
+}
+
+// LoadFolder loads a Folder from the database, populating the parameter struct
+func (m *MemoryBackend) LoadFolder(f *Folder) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the folder via ID if available, or via path
+	for _, row := range m.folders {
+		if (f.ID != 0 && row.ID == f.ID) || (f.ID == 0 && row.Path == f.Path) {
+			*f = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveFolder attempts to save a Folder to the database
+func (m *MemoryBackend) SaveFolder(f *Folder) error {
+	m.mutex.Lock()
+
+	// Insert new folder, unless the path already exists
+	exists := false
+	for _, row := range m.folders {
+		if row.Path == f.Path {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *f
+		row.ID = m.nextID("folders")
+		m.folders = append(m.folders, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if f.ID == 0 {
+		if err := m.LoadFolder(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateFolder attempts to update a Folder in the database
+func (m *MemoryBackend) UpdateFolder(f *Folder) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Update existing folder
+	for i, row := range m.folders {
+		if row.ID == f.ID {
+			m.folders[i] = *f
+			break
+		}
+	}
+
+	return nil
+}
+
+// AllLibraries loads a slice of all Library structs from the database, ordered by name
+func (m *MemoryBackend) AllLibraries() ([]Library, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	libraries := make([]Library, len(m.libraries))
+	copy(libraries, m.libraries)
+
+	sort.Stable(librariesByName(libraries))
+	return libraries, nil
+}
+
+// DeleteLibrary removes a Library from the database, releasing all media indexed within it
+func (m *MemoryBackend) DeleteLibrary(l *Library) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this library by its ID if available, or by its name
+	libraries := make([]Library, 0, len(m.libraries))
+	for _, row := range m.libraries {
+		if (l.ID != 0 && row.ID == l.ID) || (l.ID == 0 && row.Name == l.Name) {
+			m.releaseLibrary(row.ID)
+			continue
+		}
+
+		libraries = append(libraries, row)
+	}
+	m.libraries = libraries
+
+	return nil
+}
+
+// LoadLibrary loads a Library from the database, populating the parameter struct
+func (m *MemoryBackend) LoadLibrary(l *Library) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the library via ID if available, or via name
+	for _, row := range m.libraries {
+		if (l.ID != 0 && row.ID == l.ID) || (l.ID == 0 && row.Name == l.Name) {
+			*l = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveLibrary attempts to save a Library to the database, claiming any unowned media
+// under its path
+func (m *MemoryBackend) SaveLibrary(l *Library) error {
+	m.mutex.Lock()
+
+	// Insert new library, unless the name already exists
+	index := -1
+	for i, row := range m.libraries {
+		if row.Name == l.Name {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		row := *l
+		row.ID = m.nextID("libraries")
+		m.libraries = append(m.libraries, row)
+		index = len(m.libraries) - 1
+	}
+
+	// Claim unowned media for the stored library
+	m.claimLibrary(m.libraries[index])
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if l.ID == 0 {
+		if err := m.LoadLibrary(l); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateLibrary updates a Library in the database, claiming any unowned media under its path
+func (m *MemoryBackend) UpdateLibrary(l *Library) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Find the library to update, ignoring the update if it would violate the unique
+	// name constraint
+	index := -1
+	for i, row := range m.libraries {
+		if row.ID == l.ID {
+			index = i
+			continue
+		}
+
+		if row.Name == l.Name {
+			return nil
+		}
+	}
+
+	// Update existing library
+	if index != -1 {
+		m.libraries[index].Name = l.Name
+		m.libraries[index].Path = l.Path
+	}
+
+	m.claimLibrary(*l)
+	return nil
+}
+
+// PlaysForUser loads a slice of all Play structs which belong to the specified user ID,
+// ordered from most to least recent
+func (m *MemoryBackend) PlaysForUser(userID int) ([]Play, error) {
+	return m.LimitPlaysForUser(userID, 0, -1)
+}
+
+// LimitPlaysForUser loads a slice of Play structs which belong to the specified user ID,
+// ordered from most to least recent, with offset and limit
+func (m *MemoryBackend) LimitPlaysForUser(userID int, offset int, count int) ([]Play, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	plays := make([]Play, 0)
+	for _, p := range m.plays {
+		if p.UserID == userID {
+			plays = append(plays, p)
+		}
+	}
+
+	sort.Sort(playsByTimestamp(plays))
+	start, end := limitRange(len(plays), offset, count)
+	return plays[start:end], nil
+}
+
+// DeletePlay removes a Play from the database
+func (m *MemoryBackend) DeletePlay(p *Play) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this play by its ID if available, or by its user ID, song ID, and timestamp
+	plays := make([]Play, 0, len(m.plays))
+	for _, row := range m.plays {
+		if (p.ID != 0 && row.ID == p.ID) || (p.ID == 0 && row.UserID == p.UserID && row.SongID == p.SongID && row.Timestamp == p.Timestamp) {
+			continue
+		}
+
+		plays = append(plays, row)
+	}
+	m.plays = plays
+
+	return nil
+}
+
+// LoadPlay loads a Play from the database, populating the parameter struct
+func (m *MemoryBackend) LoadPlay(p *Play) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the play via ID if available, or via user ID, song ID, and timestamp
+	for _, row := range m.plays {
+		if (p.ID != 0 && row.ID == p.ID) || (p.ID == 0 && row.UserID == p.UserID && row.SongID == p.SongID && row.Timestamp == p.Timestamp) {
+			*p = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SavePlay attempts to save a Play to the database
+func (m *MemoryBackend) SavePlay(p *Play) error {
+	m.mutex.Lock()
+
+	// Insert new play, unless the user ID, song ID, and timestamp already exist
+	exists := false
+	for _, row := range m.plays {
+		if row.UserID == p.UserID && row.SongID == p.SongID && row.Timestamp == p.Timestamp {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *p
+		row.ID = m.nextID("plays")
+		m.plays = append(m.plays, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if p.ID == 0 {
+		if err := m.LoadPlay(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaylistsForUser loads a slice of all Playlist structs which are owned by the specified
+// user ID, as well as all public playlists owned by other users
+func (m *MemoryBackend) PlaylistsForUser(userID int) ([]Playlist, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	playlists := make([]Playlist, 0)
+	for _, p := range m.playlists {
+		if p.UserID == userID || p.Public {
+			playlists = append(playlists, p)
+		}
+	}
+
+	sort.Stable(playlistsByTitle(playlists))
+	return playlists, nil
+}
+
+// DeletePlaylist removes a Playlist and all of its entries from the database
+func (m *MemoryBackend) DeletePlaylist(p *Playlist) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this playlist by its ID if available, or by its user ID and title
+	removed := make(map[int]struct{})
+	playlists := make([]Playlist, 0, len(m.playlists))
+	for _, row := range m.playlists {
+		if (p.ID != 0 && row.ID == p.ID) || (p.ID == 0 && row.UserID == p.UserID && row.Title == p.Title) {
+			removed[row.ID] = struct{}{}
+			continue
+		}
+
+		playlists = append(playlists, row)
+	}
+	m.playlists = playlists
+
+	// Remove all entries belonging to the removed playlist
+	entries := make([]PlaylistEntry, 0, len(m.playlistEntries))
+	for _, e := range m.playlistEntries {
+		if _, ok := removed[e.PlaylistID]; !ok {
+			entries = append(entries, e)
+		}
+	}
+	m.playlistEntries = entries
+
+	return nil
+}
+
+// LoadPlaylist loads a Playlist from the database, populating the parameter struct
+func (m *MemoryBackend) LoadPlaylist(p *Playlist) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the playlist via ID if available, or via user ID and title
+	for _, row := range m.playlists {
+		if (p.ID != 0 && row.ID == p.ID) || (p.ID == 0 && row.UserID == p.UserID && row.Title == p.Title) {
+			*p = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SavePlaylist attempts to save a Playlist to the database
+func (m *MemoryBackend) SavePlaylist(p *Playlist) error {
+	m.mutex.Lock()
+
+	// Insert new playlist, unless the user ID and title already exist
+	exists := false
+	for _, row := range m.playlists {
+		if row.UserID == p.UserID && row.Title == p.Title {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *p
+		row.ID = m.nextID("playlists")
+		m.playlists = append(m.playlists, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if p.ID == 0 {
+		if err := m.LoadPlaylist(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdatePlaylist updates a Playlist in the database
+func (m *MemoryBackend) UpdatePlaylist(p *Playlist) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Find the playlist to update, ignoring the update if it would violate the
+	// unique user ID and title constraint
+	index := -1
+	for i, row := range m.playlists {
+		if row.ID == p.ID {
+			index = i
+			continue
+		}
+
+		if row.UserID == p.UserID && row.Title == p.Title {
+			return nil
+		}
+	}
+
+	// Update existing playlist
+	if index != -1 {
+		m.playlists[index].Title = p.Title
+		m.playlists[index].Public = p.Public
+	}
+
+	return nil
+}
+
+// EntriesForPlaylist loads a slice of all PlaylistEntry structs which have the matching
+// playlist ID, ordered by their position in the playlist
+func (m *MemoryBackend) EntriesForPlaylist(ID int) ([]PlaylistEntry, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.entriesForPlaylist(ID), nil
+}
+
+// SongsForPlaylist loads a slice of all Song structs contained in the playlist with the
+// matching ID, ordered by their position in the playlist
+func (m *MemoryBackend) SongsForPlaylist(ID int) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Join each entry with its song, skipping any songs which no longer exist
+	songs := make([]Song, 0)
+	for _, e := range m.entriesForPlaylist(ID) {
+		songs = append(songs, m.songFilter(func(s Song) bool {
+			return s.ID == e.SongID
+		})...)
+	}
+
+	return songs, nil
+}
+
+// AppendPlaylistEntries adds the songs with the input IDs to the end of the playlist with
+// the matching ID
+func (m *MemoryBackend) AppendPlaylistEntries(ID int, songIDs []int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Find the next free position in the playlist
+	position := len(m.entriesForPlaylist(ID))
+
+	// Insert all songs in order, after the last entry
+	for _, songID := range songIDs {
+		m.playlistEntries = append(m.playlistEntries, PlaylistEntry{
+			ID:         m.nextID("playlist_entries"),
+			PlaylistID: ID,
+			SongID:     songID,
+			Position:   position,
+		})
+		position++
+	}
+
+	return nil
+}
+
+// RemovePlaylistEntries removes the entries with the input IDs from the playlist with the
+// matching ID, and renumbers the remaining entries so their positions remain contiguous
+func (m *MemoryBackend) RemovePlaylistEntries(ID int, entryIDs []int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Remove all specified entries
+	removed := make(map[int]struct{}, len(entryIDs))
+	for _, entryID := range entryIDs {
+		removed[entryID] = struct{}{}
+	}
+
+	entries := make([]PlaylistEntry, 0, len(m.playlistEntries))
+	for _, e := range m.playlistEntries {
+		if _, ok := removed[e.ID]; ok && e.PlaylistID == ID {
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+	m.playlistEntries = entries
+
+	// Close any gaps left by removed entries
+	for i, e := range m.entriesForPlaylist(ID) {
+		m.setEntryPosition(ID, e.ID, i)
+	}
+
+	return nil
+}
+
+// ReorderPlaylistEntries rearranges the entries of the playlist with the matching ID, so that
+// they appear in the order of the input entry IDs.  All entries in the playlist must be specified.
+func (m *MemoryBackend) ReorderPlaylistEntries(ID int, entryIDs []int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Verify that every entry appears exactly once in the new order
+	if !samePlaylistEntries(m.entriesForPlaylist(ID), entryIDs) {
+		return ErrPlaylistEntries
+	}
+
+	// Renumber all entries using their index in the new order
+	for i, entryID := range entryIDs {
+		m.setEntryPosition(ID, entryID, i)
+	}
+
+	return nil
+}
+
+// RatingsForUser loads a slice of all Rating structs which belong to the specified user ID,
+// ordered by item type and ID
+func (m *MemoryBackend) RatingsForUser(userID int) ([]Rating, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	ratings := make([]Rating, 0)
+	for _, a := range m.ratings {
+		if a.UserID == userID {
+			ratings = append(ratings, a)
+		}
+	}
+
+	sort.Sort(ratingsByItem(ratings))
+	return ratings, nil
+}
+
+// DeleteRating removes a Rating from the database
+func (m *MemoryBackend) DeleteRating(a *Rating) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this rating by its ID if available, or by its user ID, item type, and item ID
+	ratings := make([]Rating, 0, len(m.ratings))
+	for _, row := range m.ratings {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID) {
+			continue
+		}
+
+		ratings = append(ratings, row)
+	}
+	m.ratings = ratings
+
+	return nil
+}
+
+// LoadRating loads a Rating from the database, populating the parameter struct
+func (m *MemoryBackend) LoadRating(a *Rating) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the rating via ID if available, or via user ID, item type, and item ID
+	for _, row := range m.ratings {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID) {
+			*a = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveRating attempts to save a Rating to the database
+func (m *MemoryBackend) SaveRating(a *Rating) error {
+	m.mutex.Lock()
+
+	// Insert new rating, unless the user ID, item type, and item ID already exist
+	exists := false
+	for _, row := range m.ratings {
+		if row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *a
+		row.ID = m.nextID("ratings")
+		m.ratings = append(m.ratings, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := m.LoadRating(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateRating updates a Rating in the database
+func (m *MemoryBackend) UpdateRating(a *Rating) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to update this rating by its ID if available, or by its user ID, item type, and item ID
+	for i, row := range m.ratings {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID) {
+			m.ratings[i].Rating = a.Rating
+			break
+		}
+	}
+
+	return nil
+}
+
+// SmartPlaylistsForUser loads a slice of all SmartPlaylist structs which are owned by the
+// specified user ID, as well as all public smart playlists owned by other users
+func (m *MemoryBackend) SmartPlaylistsForUser(userID int) ([]SmartPlaylist, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	playlists := make([]SmartPlaylist, 0)
+	for _, p := range m.smartPlaylists {
+		if p.UserID == userID || p.Public {
+			playlists = append(playlists, p)
+		}
+	}
+
+	sort.Stable(smartPlaylistsByTitle(playlists))
+	return playlists, nil
+}
+
+// DeleteSmartPlaylist removes a SmartPlaylist from the database
+func (m *MemoryBackend) DeleteSmartPlaylist(p *SmartPlaylist) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this smart playlist by its ID if available, or by its user ID and title
+	playlists := make([]SmartPlaylist, 0, len(m.smartPlaylists))
+	for _, row := range m.smartPlaylists {
+		if (p.ID != 0 && row.ID == p.ID) || (p.ID == 0 && row.UserID == p.UserID && row.Title == p.Title) {
+			continue
+		}
+
+		playlists = append(playlists, row)
+	}
+	m.smartPlaylists = playlists
+
+	return nil
+}
+
+// LoadSmartPlaylist loads a SmartPlaylist from the database, populating the parameter struct
+func (m *MemoryBackend) LoadSmartPlaylist(p *SmartPlaylist) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the smart playlist via ID if available, or via user ID and title
+	for _, row := range m.smartPlaylists {
+		if (p.ID != 0 && row.ID == p.ID) || (p.ID == 0 && row.UserID == p.UserID && row.Title == p.Title) {
+			*p = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveSmartPlaylist attempts to save a SmartPlaylist to the database
+func (m *MemoryBackend) SaveSmartPlaylist(p *SmartPlaylist) error {
+	m.mutex.Lock()
+
+	// Insert new smart playlist, unless the user ID and title already exist
+	exists := false
+	for _, row := range m.smartPlaylists {
+		if row.UserID == p.UserID && row.Title == p.Title {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *p
+		row.ID = m.nextID("smart_playlists")
+		m.smartPlaylists = append(m.smartPlaylists, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if p.ID == 0 {
+		if err := m.LoadSmartPlaylist(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSmartPlaylist updates a SmartPlaylist in the database
+func (m *MemoryBackend) UpdateSmartPlaylist(p *SmartPlaylist) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Find the smart playlist to update, ignoring the update if it would violate the
+	// unique user ID and title constraint
+	index := -1
+	for i, row := range m.smartPlaylists {
+		if row.ID == p.ID {
+			index = i
+			continue
+		}
+
+		if row.UserID == p.UserID && row.Title == p.Title {
+			return nil
+		}
+	}
+
+	// Update existing smart playlist
+	if index != -1 {
+		m.smartPlaylists[index].Title = p.Title
+		m.smartPlaylists[index].Public = p.Public
+		m.smartPlaylists[index].Query = p.Query
+		m.smartPlaylists[index].Sort = p.Sort
+		m.smartPlaylists[index].Limit = p.Limit
+	}
+
+	return nil
+}
+
+// SmartPlaylistSongs loads a slice of Song structs from the database which match the specified
+// search query, in the specified smart playlist sort order, up to the specified limit.  Unlike
+// SearchSongs, an empty query matches every song.
+func (m *MemoryBackend) SmartPlaylistSongs(q Query, order string, limit int) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.smartPlaylistSongs(q, order, limit), nil
+}
+
+// CountSmartPlaylistSongs counts the songs in the database which match the specified search
+// query, in the specified smart playlist sort order, up to the specified limit.  The total
+// length of the songs in seconds is also returned.
+func (m *MemoryBackend) CountSmartPlaylistSongs(q Query, order string, limit int) (int64, int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// The sort order only matters when it determines which songs are within the limit
+	if limit == 0 {
+		order = ""
+	}
+
+	songs := m.smartPlaylistSongs(q, order, limit)
+	return int64(len(songs)), int64(SongSlice(songs).Length()), nil
+}
+
+// smartPlaylistSongs filters songs using a search query, and sorts them in a smart playlist
+// sort order, up to the specified limit
+func (m *MemoryBackend) smartPlaylistSongs(q Query, order string, limit int) []Song {
+	songs, _ := m.rankSongs(q, 0, -1)
+	sortSmartPlaylist(songs, order)
+
+	if limit > 0 && len(songs) > limit {
+		songs = songs[:limit]
+	}
+
+	return songs
+}
+
+// AllSongs loads a slice of all Song structs from the database
+func (m *MemoryBackend) AllSongs() ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.songFilter(func(s Song) bool {
+		return true
+	}), nil
+}
+
+// LimitSongs loads a slice of Song structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (m *MemoryBackend) LimitSongs(offset int, count int) ([]Song, error) {
+	songs, err := m.AllSongs()
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := limitRange(len(songs), offset, count)
+	return songs[start:end], nil
+}
+
+// RandomSongs loads a slice of 'n' random song structs from the database
+func (m *MemoryBackend) RandomSongs(n int) ([]Song, error) {
+	songs, err := m.AllSongs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Shuffle songs, and take the first n
+	random := make([]Song, len(songs))
+	for i, j := range rand.Perm(len(songs)) {
+		random[i] = songs[j]
+	}
+
+	_, end := limitRange(len(random), 0, n)
+	return random[:end], nil
+}
+
+// SearchSongs loads a slice of Song structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  Song titles are weighted highest,
+// followed by artist and album titles, genre, and comment.  The total number of matching songs
+// is also returned.
+func (m *MemoryBackend) SearchSongs(q Query, offset int, count int) ([]Song, int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Song, 0), 0, nil
+	}
+
+	songs, total := m.rankSongs(q, offset, count)
+	return songs, total, nil
+}
+
+// rankSongs filters songs using a search query, and ranks the remainder by relevance, within
+// the range specified by the input offset and count.  The total number of matching songs is
+// also returned.  If the query is empty, all songs match in their original order.
+func (m *MemoryBackend) rankSongs(q Query, offset int, count int) ([]Song, int64) {
+	all := m.songFilter(m.queryFilter(q))
+	indexes, total := rankSearch(searchTerms(q.Text), len(all), func(i int) []string {
+		return []string{all[i].Title, all[i].Artist, all[i].Album, all[i].Genre, all[i].Comment}
+	}, []float64{10, 5, 5, 2, 1}, offset, count)
+
+	songs := make([]Song, 0)
+	for _, i := range indexes {
+		songs = append(songs, all[i])
+	}
+
+	return songs, total
+}
+
+// SongsForAlbum loads a slice of all Song structs which have the matching album ID, ordered
+// by disc number, and then by track number
+func (m *MemoryBackend) SongsForAlbum(ID int) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	songs := m.songFilter(func(s Song) bool {
+		return s.AlbumID == ID
+	})
+
+	sort.Stable(songsByDisc(songs))
+	return songs, nil
+}
+
+// SongsForArtist loads a slice of all Song structs which have the matching artist ID
+func (m *MemoryBackend) SongsForArtist(ID int) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.songFilter(func(s Song) bool {
+		return s.ArtistID == ID
+	}), nil
+}
+
+// SongsForFolder loads a slice of all Song structs which have the matching folder ID
+func (m *MemoryBackend) SongsForFolder(ID int) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.songFilter(func(s Song) bool {
+		return s.FolderID == ID
+	}), nil
+}
+
+// SongsForFingerprint loads a slice of all Song structs which have the matching fingerprint
+func (m *MemoryBackend) SongsForFingerprint(fingerprint string) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.songFilter(func(s Song) bool {
+		return s.Fingerprint == fingerprint
+	}), nil
+}
+
+// SongsInPath loads a slice of all Song structs residing under the specified
+// filesystem path from the database
+func (m *MemoryBackend) SongsInPath(path string) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.songFilter(func(s Song) bool {
+		return sqlLike(s.FileName, path+"%")
+	}), nil
+}
+
+// SongsNotInPath loads a slice of all Song structs belonging to the specified library, or to no
+// library, that do not reside under the specified filesystem path from the database
+func (m *MemoryBackend) SongsNotInPath(libraryID int, path string) ([]Song, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.songFilter(func(s Song) bool {
+		return (s.LibraryID == 0 || s.LibraryID == libraryID) && !sqlLike(s.FileName, path+"%")
+	}), nil
+}
+
+// SongFilesInPath loads a slice of SongFile structs for all songs residing under the
+// specified filesystem path from the database
+func (m *MemoryBackend) SongFilesInPath(path string) ([]SongFile, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	files := make([]SongFile, 0)
+	for _, s := range m.songs {
+		if sqlLike(s.FileName, path+"%") {
+			files = append(files, SongFile{
+				FileName:     s.FileName,
+				FileSize:     s.FileSize,
+				Fingerprint:  s.Fingerprint,
+				LastModified: s.LastModified,
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// CountSongs fetches the total number of Song structs from the database
+func (m *MemoryBackend) CountSongs() (int64, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return int64(len(m.songs)), nil
+}
+
+// DeleteSong removes a Song from the database, along with any playlist entries for it
+func (m *MemoryBackend) DeleteSong(a *Song) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this song by its ID if available, or by its file name and offset
+	deleted := make(map[int]struct{})
+	songs := make([]Song, 0, len(m.songs))
+	for _, row := range m.songs {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.FileName == a.FileName && row.StartOffset == a.StartOffset) {
+			deleted[row.ID] = struct{}{}
+			continue
+		}
+
+		songs = append(songs, row)
+	}
+	m.songs = songs
+
+	// Remove the song from any playlists which contain it
+	playlists := make(map[int]struct{})
+	entries := make([]PlaylistEntry, 0, len(m.playlistEntries))
+	for _, e := range m.playlistEntries {
+		if _, ok := deleted[e.SongID]; ok {
+			playlists[e.PlaylistID] = struct{}{}
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+	m.playlistEntries = entries
+
+	// Close any gaps left by removed entries
+	for playlistID := range playlists {
+		for i, e := range m.entriesForPlaylist(playlistID) {
+			m.setEntryPosition(playlistID, e.ID, i)
+		}
+	}
+
+	return nil
+}
+
+// LoadSong loads a Song from the database, populating the parameter struct
+func (m *MemoryBackend) LoadSong(a *Song) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the song via ID if available, or via file name and offset
+	songs := m.songFilter(func(row Song) bool {
+		return (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.FileName == a.FileName && row.StartOffset == a.StartOffset)
+	})
+	if len(songs) == 0 {
+		return sql.ErrNoRows
+	}
+
+	*a = songs[0]
+	return nil
+}
+
+// SaveSong attempts to save a Song to the database
+func (m *MemoryBackend) SaveSong(a *Song) error {
+	// Insert new song
+	if err := m.SaveSongs([]Song{*a}); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := m.LoadSong(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveSongs attempts to save a batch of Songs to the database.  Song IDs are not loaded, so they
+// must be reloaded by file name and offset if needed.
+func (m *MemoryBackend) SaveSongs(songs []Song) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Track file names and offsets which already exist
+	type songKey struct {
+		fileName    string
+		startOffset int
+	}
+	keys := make(map[songKey]bool, len(m.songs))
+	for _, row := range m.songs {
+		keys[songKey{row.FileName, row.StartOffset}] = true
+	}
+
+	// Insert new songs, unless the file name and offset already exist
+	for _, row := range songs {
+		key := songKey{row.FileName, row.StartOffset}
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+
+		row.ID = m.nextID("songs")
+		row.Artist = ""
+		row.Album = ""
+		row.AlbumArtist = ""
+		row.PlayCount = 0
+		row.LastPlayed = 0
+		m.songs = append(m.songs, row)
+	}
+
+	return nil
+}
+
+// UpdateSong attempts to update a Song in the database
+func (m *MemoryBackend) UpdateSong(a *Song) error {
+	return m.UpdateSongs([]Song{*a})
+}
+
+// UpdateSongs attempts to update a batch of Songs in the database
+func (m *MemoryBackend) UpdateSongs(songs []Song) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Map song IDs to their index
+	indexes := make(map[int]int, len(m.songs))
+	for i, row := range m.songs {
+		indexes[row.ID] = i
+	}
+
+	// Update existing songs, keeping their file name, offset, and added time
+	for _, song := range songs {
+		i, ok := indexes[song.ID]
+		if !ok {
+			continue
+		}
+
+		song.Added = m.songs[i].Added
+		song.FileName = m.songs[i].FileName
+		song.StartOffset = m.songs[i].StartOffset
+		song.Artist = ""
+		song.Album = ""
+		song.AlbumArtist = ""
+		song.PlayCount = 0
+		song.LastPlayed = 0
+		m.songs[i] = song
+	}
+
+	return nil
+}
+
+// StarsForUser loads a slice of all Star structs which belong to the specified user ID,
+// ordered from most to least recent
+func (m *MemoryBackend) StarsForUser(userID int) ([]Star, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	stars := make([]Star, 0)
+	for _, a := range m.stars {
+		if a.UserID == userID {
+			stars = append(stars, a)
+		}
+	}
+
+	sort.Sort(starsByCreated(stars))
+	return stars, nil
+}
+
+// DeleteStar removes a Star from the database
+func (m *MemoryBackend) DeleteStar(a *Star) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this star by its ID if available, or by its user ID, item type, and item ID
+	stars := make([]Star, 0, len(m.stars))
+	for _, row := range m.stars {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID) {
+			continue
+		}
+
+		stars = append(stars, row)
+	}
+	m.stars = stars
+
+	return nil
+}
+
+// LoadStar loads a Star from the database, populating the parameter struct
+func (m *MemoryBackend) LoadStar(a *Star) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the star via ID if available, or via user ID, item type, and item ID
+	for _, row := range m.stars {
+		if (a.ID != 0 && row.ID == a.ID) || (a.ID == 0 && row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID) {
+			*a = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveStar attempts to save a Star to the database
+func (m *MemoryBackend) SaveStar(a *Star) error {
+	m.mutex.Lock()
+
+	// Insert new star, unless the user ID, item type, and item ID already exist
+	exists := false
+	for _, row := range m.stars {
+		if row.UserID == a.UserID && row.ItemType == a.ItemType && row.ItemID == a.ItemID {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *a
+		row.ID = m.nextID("stars")
+		m.stars = append(m.stars, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := m.LoadStar(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllUsers loads a slice of all User structs from the database
+func (m *MemoryBackend) AllUsers() ([]User, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	users := make([]User, len(m.users))
+	copy(users, m.users)
+	return users, nil
+}
+
+// DeleteUser removes a User from the database
+func (m *MemoryBackend) DeleteUser(u *User) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this user by its ID if available, or by its username
+	users := make([]User, 0, len(m.users))
+	for _, row := range m.users {
+		if (u.ID != 0 && row.ID == u.ID) || (u.ID == 0 && row.Username == u.Username) {
+			continue
+		}
+
+		users = append(users, row)
+	}
+	m.users = users
+
+	return nil
+}
+
+// LoadUser loads a User from the database, populating the parameter struct
+func (m *MemoryBackend) LoadUser(u *User) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the user via ID if available, or via username
+	for _, row := range m.users {
+		if (u.ID != 0 && row.ID == u.ID) || (u.ID == 0 && row.Username == u.Username) {
+			*u = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveUser attempts to save a User to the database
+func (m *MemoryBackend) SaveUser(u *User) error {
+	m.mutex.Lock()
+
+	// Insert new user, unless the username already exists
+	exists := false
+	for _, row := range m.users {
+		if row.Username == u.Username {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *u
+		row.ID = m.nextID("users")
+		m.users = append(m.users, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if u.ID == 0 {
+		if err := m.LoadUser(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateUser updates a User in the database
+func (m *MemoryBackend) UpdateUser(u *User) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to update this user by its ID if available, or by its username
+	index := -1
+	for i, row := range m.users {
+		if (u.ID != 0 && row.ID == u.ID) || (u.ID == 0 && row.Username == u.Username) {
+			index = i
+			continue
+		}
+
+		// Ignore the update if it would violate the unique username constraint
+		if row.Username == u.Username {
+			return nil
+		}
+	}
+
+	if index != -1 {
+		m.users[index].Username = u.Username
+		m.users[index].Password = u.Password
+		m.users[index].RoleID = u.RoleID
+		m.users[index].LastFMToken = u.LastFMToken
+	}
+
+	return nil
+}
+
+// SessionsForUser loads a slice of all Sessions for a given User from the database
+func (m *MemoryBackend) SessionsForUser(userID int) ([]Session, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	sessions := make([]Session, 0)
+	for _, s := range m.sessions {
+		if s.UserID == userID {
+			sessions = append(sessions, s)
+		}
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession removes a Session from the database
+func (m *MemoryBackend) DeleteSession(u *Session) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to delete this session by its ID if available, or by its key
+	sessions := make([]Session, 0, len(m.sessions))
+	for _, row := range m.sessions {
+		if (u.ID != 0 && row.ID == u.ID) || (u.ID == 0 && row.Key == u.Key) {
+			continue
+		}
+
+		sessions = append(sessions, row)
+	}
+	m.sessions = sessions
+
+	return nil
+}
+
+// LoadSession loads a Session from the database, populating the parameter struct
+func (m *MemoryBackend) LoadSession(u *Session) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	// Load the session via ID if available, or via key
+	for _, row := range m.sessions {
+		if (u.ID != 0 && row.ID == u.ID) || (u.ID == 0 && row.Key == u.Key) {
+			*u = row
+			return nil
+		}
+	}
+
+	return sql.ErrNoRows
+}
+
+// SaveSession attempts to save a Session to the database
+func (m *MemoryBackend) SaveSession(u *Session) error {
+	m.mutex.Lock()
+
+	// Insert new session, unless the key already exists
+	exists := false
+	for _, row := range m.sessions {
+		if row.Key == u.Key {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		row := *u
+		row.ID = m.nextID("sessions")
+		m.sessions = append(m.sessions, row)
+	}
+	m.mutex.Unlock()
+
+	// If no ID, reload to grab it
+	if u.ID == 0 {
+		if err := m.LoadSession(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSession updates a Session in the database
+func (m *MemoryBackend) UpdateSession(u *Session) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	// Attempt to update this session by its ID if available, or by its key
+	for i, row := range m.sessions {
+		if (u.ID != 0 && row.ID == u.ID) || (u.ID == 0 && row.Key == u.Key) {
+			m.sessions[i].Expire = u.Expire
+		}
+	}
+
+	return nil
+}
+
+// nextID generates the next unique ID for the specified table.  The caller must hold
+// the write lock.
+func (m *MemoryBackend) nextID(table string) int {
+	m.lastID[table]++
+	return m.lastID[table]
+}
+
+// claimLibrary assigns all art, folders, and songs under a library's path which do not yet
+// belong to any library to that library.  The caller must hold the write lock.
+func (m *MemoryBackend) claimLibrary(l Library) {
+	for i, a := range m.art {
+		if a.LibraryID == 0 && sqlLike(a.FileName, l.Path+"%") {
+			m.art[i].LibraryID = l.ID
+		}
+	}
+	for i, f := range m.folders {
+		if f.LibraryID == 0 && sqlLike(f.Path, l.Path+"%") {
+			m.folders[i].LibraryID = l.ID
+		}
+	}
+	for i, s := range m.songs {
+		if s.LibraryID == 0 && sqlLike(s.FileName, l.Path+"%") {
+			m.songs[i].LibraryID = l.ID
+		}
+	}
+}
+
+// releaseLibrary removes the library ID from all art, folders, and songs which belong to
+// a library.  The caller must hold the write lock.
+func (m *MemoryBackend) releaseLibrary(libraryID int) {
+	for i, a := range m.art {
+		if a.LibraryID == libraryID {
+			m.art[i].LibraryID = 0
+		}
+	}
+	for i, f := range m.folders {
+		if f.LibraryID == libraryID {
+			m.folders[i].LibraryID = 0
+		}
+	}
+	for i, s := range m.songs {
+		if s.LibraryID == libraryID {
+			m.songs[i].LibraryID = 0
+		}
+	}
+}
+
+// albumFilter returns all albums matching the input filter, joined with their artist and
+// play count.  Albums without a matching artist are omitted, as with an SQL join.
+func (m *MemoryBackend) albumFilter(filter func(Album) bool) []Album {
+	// Count plays of each album's songs
+	songAlbums := make(map[int]int, len(m.songs))
+	for _, s := range m.songs {
+		songAlbums[s.ID] = s.AlbumID
+	}
+	playCounts := make(map[int]int)
+	for _, p := range m.plays {
+		if albumID, ok := songAlbums[p.SongID]; ok {
+			playCounts[albumID]++
+		}
+	}
+
+	albums := make([]Album, 0)
+	for _, a := range m.albums {
+		if !filter(a) {
+			continue
+		}
+
+		for _, artist := range m.artists {
+			if artist.ID == a.ArtistID {
+				a.Artist = artist.Title
+				a.PlayCount = playCounts[a.ID]
+				albums = append(albums, a)
+				break
+			}
+		}
+	}
+
+	return albums
+}
+
+// folderFilter returns all folders matching the input filter
+func (m *MemoryBackend) folderFilter(filter func(Folder) bool) []Folder {
+	folders := make([]Folder, 0)
+	for _, f := range m.folders {
+		if filter(f) {
+			folders = append(folders, f)
+		}
+	}
+
+	return folders
+}
+
+// querySongs returns the set of IDs selected by the input function from all songs matching
+// a query's filters, or nil if the query has no filters
+func (m *MemoryBackend) querySongs(q Query, id func(Song) int) map[int]bool {
+	if len(q.filters) == 0 {
+		return nil
+	}
+
+	ids := make(map[int]bool)
+	for _, s := range m.songFilter(m.queryFilter(q)) {
+		ids[id(s)] = true
+	}
+
+	return ids
+}
+
+// queryFilter returns a song filter which matches a query's filters, using the song ratings
+// of the querying user
+func (m *MemoryBackend) queryFilter(q Query) func(Song) bool {
+	ratings := make(map[int]int)
+	for _, r := range m.ratings {
+		if r.UserID == q.UserID && r.ItemType == ItemSong {
+			ratings[r.ItemID] = r.Rating
+		}
+	}
+
+	return func(s Song) bool {
+		return q.matchSong(s, ratings[s.ID])
+	}
+}
+
+// songFilter returns all songs matching the input filter, joined with their artist, album,
+// and play statistics.  Songs without a matching artist or album are omitted, as with an SQL join.
+func (m *MemoryBackend) songFilter(filter func(Song) bool) []Song {
+	// Index artist and album titles by ID
+	artists := make(map[int]string, len(m.artists))
+	for _, a := range m.artists {
+		artists[a.ID] = a.Title
+	}
+	albums := make(map[int]Album, len(m.albums))
+	for _, a := range m.albums {
+		albums[a.ID] = a
+	}
+
+	// Count plays and find the most recent play of each song
+	playCounts := make(map[int]int)
+	lastPlayed := make(map[int]int64)
+	for _, p := range m.plays {
+		playCounts[p.SongID]++
+		if p.Timestamp > lastPlayed[p.SongID] {
+			lastPlayed[p.SongID] = p.Timestamp
+		}
+	}
+
+	songs := make([]Song, 0)
+	for _, s := range m.songs {
+		artist, ok := artists[s.ArtistID]
+		if !ok {
+			continue
+		}
+		album, ok := albums[s.AlbumID]
+		if !ok {
+			continue
+		}
+
+		// Filter after joining, so that joined fields may be matched
+		s.Artist = artist
+		s.Album = album.Title
+		s.AlbumArtist = artists[album.ArtistID]
+		s.PlayCount = playCounts[s.ID]
+		s.LastPlayed = lastPlayed[s.ID]
+		if filter(s) {
+			songs = append(songs, s)
+		}
+	}
+
+	return songs
+}
+
+// entriesForPlaylist returns all entries of the playlist with the matching ID, ordered by position
+func (m *MemoryBackend) entriesForPlaylist(ID int) []PlaylistEntry {
+	entries := make([]PlaylistEntry, 0)
+	for _, e := range m.playlistEntries {
+		if e.PlaylistID == ID {
+			entries = append(entries, e)
+		}
+	}
+
+	sort.Stable(entriesByPosition(entries))
+	return entries
+}
+
+// setEntryPosition sets the position of the entry with the matching entry and playlist IDs
+func (m *MemoryBackend) setEntryPosition(ID int, entryID int, position int) {
+	for i, e := range m.playlistEntries {
+		if e.ID == entryID && e.PlaylistID == ID {
+			m.playlistEntries[i].Position = position
+			return
+		}
+	}
+}
+
+// limitRange returns the start and end indices of a slice of the specified length, using the
+// semantics of an SQL limit with an offset and item count.  A negative count selects all items.
+func limitRange(length int, offset int, count int) (int, int) {
+	// Clamp offset within slice
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > length {
+		offset = length
+	}
+
+	// Clamp end within slice
+	end := length
+	if count >= 0 && offset+count < length {
+		end = offset + count
+	}
+
+	return offset, end
+}
+
+// sqlLike determines if the input value matches the input pattern, using the semantics of
+// sqlite's LIKE operator: '%' matches any sequence of characters, '_' matches any single
+// character, and ASCII characters are compared case-insensitively.
+func sqlLike(value string, pattern string) bool {
+	// Empty pattern only matches empty value
+	if pattern == "" {
+		return value == ""
+	}
+
+	p, size := utf8.DecodeRuneInString(pattern)
+	switch p {
+	case '%':
+		// Collapse consecutive wildcards, then try every possible suffix of value
+		rest := strings.TrimLeft(pattern, "%")
+		if rest == "" {
+			return true
+		}
+
+		for i := range value {
+			if sqlLike(value[i:], rest) {
+				return true
+			}
+		}
+
+		return false
+	case '_':
+		// Match exactly one character
+		if value == "" {
+			return false
+		}
+
+		_, vSize := utf8.DecodeRuneInString(value)
+		return sqlLike(value[vSize:], pattern[size:])
+	default:
+		// Match a literal character, ignoring ASCII case
+		if value == "" {
+			return false
+		}
+
+		v, vSize := utf8.DecodeRuneInString(value)
+		if asciiLower(v) != asciiLower(p) {
+			return false
+		}
+
+		return sqlLike(value[vSize:], pattern[size:])
+	}
+}
+
+// asciiLower converts an ASCII upper case character to lower case, leaving all others unchanged
+func asciiLower(r rune) rune {
+	if r >= 'A' && r <= 'Z' {
+		return r + ('a' - 'A')
+	}
+
+	return r
+}
+
+// artistsByTitle allows sorting of artists by title
+type artistsByTitle []Artist
+
+// Len returns the number of artists
+func (a artistsByTitle) Len() int {
+	return len(a)
+}
+
+// Swap swaps two artists by index
+func (a artistsByTitle) Swap(i, j int) {
+	a[i], a[j] = a[j], a[i]
+}
+
+// Less compares two artists by title
+func (a artistsByTitle) Less(i, j int) bool {
+	return a[i].Title < a[j].Title
+}
+
+// librariesByName allows sorting of libraries by name
+type librariesByName []Library
+
+// Len returns the number of libraries
+func (l librariesByName) Len() int {
+	return len(l)
+}
+
+// Swap swaps two libraries by index
+func (l librariesByName) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
+
+// Less compares two libraries by name
+func (l librariesByName) Less(i, j int) bool {
+	return l[i].Name < l[j].Name
+}
+
+// playlistsByTitle allows sorting of playlists by title
+type playlistsByTitle []Playlist
+
+// Len returns the number of playlists
+func (p playlistsByTitle) Len() int {
+	return len(p)
+}
+
+// Swap swaps two playlists by index
+func (p playlistsByTitle) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// Less compares two playlists by title
+func (p playlistsByTitle) Less(i, j int) bool {
+	return p[i].Title < p[j].Title
+}
+
+// smartPlaylistsByTitle allows sorting of smart playlists by title
+type smartPlaylistsByTitle []SmartPlaylist
+
+// Len returns the number of smart playlists
+func (p smartPlaylistsByTitle) Len() int {
+	return len(p)
+}
+
+// Swap swaps two smart playlists by index
+func (p smartPlaylistsByTitle) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// Less compares two smart playlists by title
+func (p smartPlaylistsByTitle) Less(i, j int) bool {
+	return p[i].Title < p[j].Title
+}
+
+// songsByDisc allows sorting of songs by disc number, and then by track number
+type songsByDisc []Song
+
+// Len returns the number of songs
+func (s songsByDisc) Len() int {
+	return len(s)
+}
+
+// Swap swaps two songs by index
+func (s songsByDisc) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less compares two songs by disc number, then track number, then ID
+func (s songsByDisc) Less(i, j int) bool {
+	if s[i].Disc != s[j].Disc {
+		return s[i].Disc < s[j].Disc
+	}
+	if s[i].Track != s[j].Track {
+		return s[i].Track < s[j].Track
+	}
+
+	return s[i].ID < s[j].ID
+}
+
+// entriesByPosition allows sorting of playlist entries by position
+type entriesByPosition []PlaylistEntry
+
+// Len returns the number of entries
+func (e entriesByPosition) Len() int {
+	return len(e)
+}
+
+// Swap swaps two entries by index
+func (e entriesByPosition) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+}
+
+// Less compares two entries by position
+func (e entriesByPosition) Less(i, j int) bool {
+	return e[i].Position < e[j].Position
+}
+
+// playsByTimestamp allows sorting of plays from most to least recent
+type playsByTimestamp []Play
+
+// Len returns the number of plays
+func (p playsByTimestamp) Len() int {
+	return len(p)
+}
+
+// Swap swaps two plays by index
+func (p playsByTimestamp) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+}
+
+// Less orders plays by descending timestamp, then by descending ID
+func (p playsByTimestamp) Less(i, j int) bool {
+	if p[i].Timestamp != p[j].Timestamp {
+		return p[i].Timestamp > p[j].Timestamp
+	}
+
+	return p[i].ID > p[j].ID
+}
+
+// ratingsByItem allows sorting of ratings by item type and ID
+type ratingsByItem []Rating
+
+// Len returns the number of ratings
+func (r ratingsByItem) Len() int {
+	return len(r)
+}
+
+// Swap swaps two ratings by index
+func (r ratingsByItem) Swap(i, j int) {
+	r[i], r[j] = r[j], r[i]
+}
+
+// Less orders ratings by item type, then by item ID
+func (r ratingsByItem) Less(i, j int) bool {
+	if r[i].ItemType != r[j].ItemType {
+		return r[i].ItemType < r[j].ItemType
+	}
+
+	return r[i].ItemID < r[j].ItemID
+}
+
+// starsByCreated allows sorting of stars from most to least recent
+type starsByCreated []Star
+
+// Len returns the number of stars
+func (s starsByCreated) Len() int {
+	return len(s)
+}
+
+// Swap swaps two stars by index
+func (s starsByCreated) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less orders stars by descending creation time, then by descending ID
+func (s starsByCreated) Less(i, j int) bool {
+	if s[i].Created != s[j].Created {
+		return s[i].Created > s[j].Created
+	}
+
+	return s[i].ID > s[j].ID
+}
@@ -0,0 +1,2098 @@
+package data
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	// Include postgres driver
+	_ "github.com/lib/pq"
+)
+
+// PostgresBackend represents a PostgreSQL-based database backend.  Unlike sqlite, a single
+// PostgreSQL database may be shared by several wavepipe instances at once.
+type PostgresBackend struct {
+	ConnString string
+	db         *sqlx.DB
+}
+
+// Search vectors used to match and rank full-text search queries.  The artist and folder
+// vectors match the expressions of their search indexes.  The wavepipe_search configuration
+// removes diacritics from words, so that accented titles are matched by plain queries.
+const (
+	artistsVector = "to_tsvector('wavepipe_search', COALESCE(artists.title, ''))"
+	foldersVector = "to_tsvector('wavepipe_search', COALESCE(folders.title, ''))"
+	albumsVector  = "(setweight(to_tsvector('wavepipe_search', COALESCE(albums.title, '')), 'A') || " +
+		"setweight(to_tsvector('wavepipe_search', COALESCE(artists.title, '')), 'B'))"
+	songsVector = "(setweight(to_tsvector('wavepipe_search', COALESCE(songs.title, '')), 'A') || " +
+		"setweight(to_tsvector('wavepipe_search', COALESCE(artists.title, '') || ' ' || COALESCE(albums.title, '')), 'B') || " +
+		"setweight(to_tsvector('wavepipe_search', COALESCE(songs.genre, '')), 'C') || " +
+		"setweight(to_tsvector('wavepipe_search', COALESCE(songs.comment, '')), 'D'))"
+)
+
+// DSN sets the ConnString for use with postgres
+func (p *PostgresBackend) DSN(connString string) {
+	p.ConnString = connString
+}
+
+// Setup performs no action for postgres, because the database must be created by an administrator.
+// All tables are created by the initial migration.
+func (p *PostgresBackend) Setup() error {
+	return nil
+}
+
+// Open initializes a new postgres sqlx database connection
+func (p *PostgresBackend) Open() error {
+	// Open connection using connection string
+	db, err := sqlx.Open("postgres", p.ConnString)
+	if err != nil {
+		return err
+	}
+
+	// Verify the database is reachable, because opening does not connect
+	if err := db.Ping(); err != nil {
+		return err
+	}
+
+	// Store database instance for duration of run
+	p.db = db
+	return nil
+}
+
+// Close closes the current postgres sqlx database connection
+func (p *PostgresBackend) Close() error {
+	return p.db.Close()
+}
+
+// Migrate applies any embedded postgres migrations which are newer than the current database
+// schema version.  All pending migrations are applied in a single transaction, while holding a
+// lock on the schema version table, so that several instances may start at once.
+func (p *PostgresBackend) Migrate() error {
+	// Load all embedded postgres migrations
+	migrations, err := loadMigrations("res/postgres/migrations/")
+	if err != nil {
+		return err
+	}
+
+	// Ensure the schema version table exists
+	if _, err := p.db.Exec("CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL);"); err != nil {
+		return err
+	}
+
+	tx, err := p.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	// Block any other instances from migrating until this transaction completes
+	if _, err := tx.Exec("LOCK TABLE schema_version IN EXCLUSIVE MODE;"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Check the current database schema version
+	var version int
+	if err := tx.Get(&version, "SELECT COALESCE(MAX(version), 0) FROM schema_version;"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Refuse to touch a database which was created by a newer version of wavepipe
+	if version > len(migrations) {
+		tx.Rollback()
+		log.Printf("db: database schema version %d, latest supported version %d", version, len(migrations))
+		return ErrSchemaTooNew
+	}
+
+	// Apply all migrations which are newer than the current version
+	for _, m := range migrations[version:] {
+		log.Printf("db: applying migration %04d: %s", m.Version, m.Description)
+
+		// Run the migration script, rolling back on failure
+		if _, err := tx.Exec(m.Script); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		// Record the new schema version along with the migration
+		if _, err := tx.Exec("INSERT INTO schema_version (version) VALUES ($1);", m.Version); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SchemaVersion returns the current schema version of the postgres database
+func (p *PostgresBackend) SchemaVersion() (int, error) {
+	var version int
+	err := p.db.Get(&version, "SELECT COALESCE(MAX(version), 0) FROM schema_version;")
+	return version, err
+}
+
+// MovePath updates the paths of all songs, art, and folders at the specified path, or beneath
+// it, so that they reside at a new path and keep their IDs.  No items may already exist at
+// the new path.
+func (p *PostgresBackend) MovePath(from string, to string) error {
+	// postgres counts characters, rather than bytes, to find the remainder of each path
+	prefix := from + "/"
+	length := utf8.RuneCountInString(prefix)
+
+	tx := p.db.MustBegin()
+	tx.Exec("UPDATE songs SET file_name = $1 || substr(file_name, $2) WHERE file_name = $3 OR substr(file_name, 1, $2) = $4;",
+		to, length, from, prefix)
+	tx.Exec("UPDATE art SET file_name = $1 || substr(file_name, $2) WHERE file_name = $3 OR substr(file_name, 1, $2) = $4;",
+		to, length, from, prefix)
+	tx.Exec("UPDATE folders SET path = $1 || substr(path, $2) WHERE path = $3 OR substr(path, 1, $2) = $4;",
+		to, length, from, prefix)
+	return tx.Commit()
+}
+
+// ArtInPath loads a slice of all Art structs contained within the specified file path
+func (p *PostgresBackend) ArtInPath(path string) ([]Art, error) {
+	return p.artQuery("SELECT * FROM art WHERE file_name LIKE $1;", path+"%")
+}
+
+// ArtNotInPath loads a slice of all art file Art structs belonging to the specified library, or
+// to no library, which are NOT contained within the specified file path
+func (p *PostgresBackend) ArtNotInPath(libraryID int, path string) ([]Art, error) {
+	return p.artQuery("SELECT * FROM art WHERE source = $1 AND library_id IN (0, $2) AND file_name NOT LIKE $3;",
+		ArtSourceFile, libraryID, path+"%")
+}
+
+// OrphanEmbeddedArt loads a slice of all embedded Art structs which are no longer used by any song
+func (p *PostgresBackend) OrphanEmbeddedArt() ([]Art, error) {
+	return p.artQuery("SELECT * FROM art WHERE source = $1 AND NOT EXISTS "+
+		"(SELECT 1 FROM songs WHERE songs.art_id = art.id);", ArtSourceEmbedded)
+}
+
+// CountArt fetches the total number of Art structs from the database
+func (p *PostgresBackend) CountArt() (int64, error) {
+	return p.integerQuery("SELECT COUNT(*) AS int FROM art;")
+}
+
+// DeleteArt removes Art from the database
+func (p *PostgresBackend) DeleteArt(a *Art) error {
+	// Attempt to delete this art by its ID
+	tx := p.db.MustBegin()
+	tx.Exec("DELETE FROM art WHERE id = $1;", a.ID)
+
+	// Update any songs and albums using this art ID to have a zero ID, and remove it from galleries
+	tx.Exec("UPDATE songs SET art_id = 0 WHERE art_id = $1;", a.ID)
+	tx.Exec("UPDATE albums SET art_id = 0 WHERE art_id = $1;", a.ID)
+	tx.Exec("DELETE FROM album_gallery WHERE art_id = $1;", a.ID)
+	return tx.Commit()
+}
+
+// LoadArt loads Art from the database, populating the parameter struct
+func (p *PostgresBackend) LoadArt(a *Art) error {
+	// Load the artist via ID if available
+	if a.ID != 0 {
+		if err := p.db.Get(a, "SELECT * FROM art WHERE id = $1;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via file name
+	if err := p.db.Get(a, "SELECT * FROM art WHERE file_name = $1;", a.FileName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveArt attempts to save Art to the database
+func (p *PostgresBackend) SaveArt(a *Art) error {
+	// Insert new artist
+	query := "INSERT INTO art (file_name, file_size, last_modified, library_id, source) VALUES ($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, a.FileName, a.FileSize, a.LastModified, a.LibraryID, a.Source)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := p.LoadArt(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllArtists loads a slice of all Artist structs from the database
+func (p *PostgresBackend) AllArtists() ([]Artist, error) {
+	return p.artistQuery("SELECT * FROM artists;")
+}
+
+// AllArtistsByTitle loads a slice of all Artist structs from the database, sorted alphabetically by title
+func (p *PostgresBackend) AllArtistsByTitle() ([]Artist, error) {
+	return p.artistQuery("SELECT * FROM artists ORDER BY title;")
+}
+
+// LimitArtists loads a slice of Artist structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (p *PostgresBackend) LimitArtists(offset int, count int) ([]Artist, error) {
+	return p.artistQuery("SELECT * FROM artists ORDER BY artists.id LIMIT $2 OFFSET $1;", offset, count)
+}
+
+// SearchArtists loads a slice of Artist structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  The total number of matching
+// artists is also returned.
+func (p *PostgresBackend) SearchArtists(q Query, offset int, count int) ([]Artist, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Artist, 0), 0, nil
+	}
+
+	// Count all matching artists
+	clause, order, args := pgSearch(q, "artists", "artists", artistsVector, "artist_id")
+	total, err := p.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	artists, err := p.artistQuery("SELECT artists.* FROM "+clause+" ORDER BY "+order+" "+pgLimitOffset(len(args))+";",
+		append(args, offset, pgLimit(count))...)
+	return artists, total, err
+}
+
+// CountArtists fetches the total number of Artist structs from the database
+func (p *PostgresBackend) CountArtists() (int64, error) {
+	return p.integerQuery("SELECT COUNT(*) AS int FROM artists;")
+}
+
+// PurgeOrphanArtists deletes all artists who are "orphaned", meaning that they no
+// longer have any songs or albums which reference their ID
+func (p *PostgresBackend) PurgeOrphanArtists() (int, error) {
+	// Select all artists without a song referencing their artist ID, who are also not the
+	// album artist of any album
+	rows, err := p.db.Queryx("SELECT artists.id FROM artists LEFT JOIN songs ON " +
+		"artists.id = songs.artist_id WHERE songs.artist_id IS NULL AND " +
+		"artists.id NOT IN (SELECT albums.artist_id FROM albums);")
+	if err != nil && err != sql.ErrNoRows {
+		return -1, err
+	}
+	defer rows.Close()
+
+	// Open a transaction to remove all orphaned artists
+	tx := p.db.MustBegin()
+
+	// Iterate all rows
+	artist := new(Artist)
+	total := 0
+	for rows.Next() {
+		// Scan ID into struct
+		if err := rows.StructScan(artist); err != nil {
+			return -1, err
+		}
+
+		// Remove artist
+		tx.Exec("DELETE FROM artists WHERE id = $1;", artist.ID)
+		total++
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return -1, err
+	}
+
+	return total, tx.Commit()
+}
+
+// DeleteArtist removes an Artist from the database
+func (p *PostgresBackend) DeleteArtist(a *Artist) error {
+	// Attempt to delete this artist by its ID, if available
+	tx := p.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("DELETE FROM artists WHERE id = $1;", a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the artist by its title
+	tx.Exec("DELETE FROM artists WHERE title = $1;", a.Title)
+	return tx.Commit()
+}
+
+// LoadArtist loads an Artist from the database, populating the parameter struct
+func (p *PostgresBackend) LoadArtist(a *Artist) error {
+	// Load the artist via ID if available
+	if a.ID != 0 {
+		if err := p.db.Get(a, "SELECT * FROM artists WHERE id = $1;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via title
+	if err := p.db.Get(a, "SELECT * FROM artists WHERE title = $1;", a.Title); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveArtist attempts to save an Artist to the database
+func (p *PostgresBackend) SaveArtist(a *Artist) error {
+	// Insert new artist
+	query := "INSERT INTO artists (title) VALUES ($1) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, a.Title)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := p.LoadArtist(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllAlbums loads a slice of all Album structs from the database
+func (p *PostgresBackend) AllAlbums() ([]Album, error) {
+	return p.albumQuery("SELECT " + albumColumns + " FROM albums " +
+		"JOIN artists ON albums.artist_id = artists.id;")
+}
+
+// LimitAlbums loads a slice of Album structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (p *PostgresBackend) LimitAlbums(offset int, count int) ([]Album, error) {
+	return p.albumQuery("SELECT "+albumColumns+" FROM albums "+
+		"JOIN artists ON albums.artist_id = artists.id ORDER BY albums.id LIMIT $2 OFFSET $1;", offset, count)
+}
+
+// AlbumsForArtist loads a slice of all Album structs with matching artist ID
+func (p *PostgresBackend) AlbumsForArtist(ID int) ([]Album, error) {
+	return p.albumQuery("SELECT "+albumColumns+" FROM albums "+
+		"JOIN artists ON albums.artist_id = artists.id WHERE albums.artist_id = $1;", ID)
+}
+
+// GalleryForAlbum loads a slice of all Art structs in the gallery of the album with the
+// matching ID, ordered by their position in the gallery
+func (p *PostgresBackend) GalleryForAlbum(ID int) ([]Art, error) {
+	return p.artQuery("SELECT art.* FROM album_gallery JOIN art ON album_gallery.art_id = art.id "+
+		"WHERE album_gallery.album_id = $1 ORDER BY album_gallery.position;", ID)
+}
+
+// SetAlbumGallery replaces the gallery of the album with the matching ID with the art with the
+// input IDs, in order
+func (p *PostgresBackend) SetAlbumGallery(ID int, artIDs []int) error {
+	// Remove the existing gallery
+	tx := p.db.MustBegin()
+	tx.Exec("DELETE FROM album_gallery WHERE album_id = $1;", ID)
+
+	// Insert all art in order
+	query := "INSERT INTO album_gallery (album_id, art_id, position) VALUES ($1, $2, $3);"
+	for i, artID := range artIDs {
+		tx.Exec(query, ID, artID, i)
+	}
+
+	return tx.Commit()
+}
+
+// SearchAlbums loads a slice of Album structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  Album titles are weighted above
+// artist titles.  The total number of matching albums is also returned.
+func (p *PostgresBackend) SearchAlbums(q Query, offset int, count int) ([]Album, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Album, 0), 0, nil
+	}
+
+	// Count all matching albums
+	clause, order, args := pgSearch(q, "albums", "albums JOIN artists ON albums.artist_id = artists.id", albumsVector, "album_id")
+	total, err := p.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	albums, err := p.albumQuery("SELECT "+albumColumns+" FROM "+clause+" ORDER BY "+order+" "+pgLimitOffset(len(args))+";",
+		append(args, offset, pgLimit(count))...)
+	return albums, total, err
+}
+
+// CountAlbums fetches the total number of Album structs from the database
+func (p *PostgresBackend) CountAlbums() (int64, error) {
+	return p.integerQuery("SELECT COUNT(*) AS int FROM albums;")
+}
+
+// PurgeOrphanAlbums deletes all albums who are "orphaned", meaning that they no
+// longer have any songs which reference their ID
+func (p *PostgresBackend) PurgeOrphanAlbums() (int, error) {
+	// Select all albums without a song referencing their album ID
+	rows, err := p.db.Queryx("SELECT albums.id FROM albums LEFT JOIN songs ON " +
+		"albums.id = songs.album_id WHERE songs.album_id IS NULL;")
+	if err != nil && err != sql.ErrNoRows {
+		return -1, err
+	}
+	defer rows.Close()
+
+	// Open a transaction to remove all orphaned albums
+	tx := p.db.MustBegin()
+
+	// Iterate all rows
+	album := new(Album)
+	total := 0
+	for rows.Next() {
+		// Scan ID into struct
+		if err := rows.StructScan(album); err != nil {
+			return -1, err
+		}
+
+		// Remove album, and its gallery
+		tx.Exec("DELETE FROM albums WHERE id = $1;", album.ID)
+		tx.Exec("DELETE FROM album_gallery WHERE album_id = $1;", album.ID)
+		total++
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return -1, err
+	}
+
+	return total, tx.Commit()
+}
+
+// DeleteAlbum removes a Album from the database
+func (p *PostgresBackend) DeleteAlbum(a *Album) error {
+	// Attempt to delete this album by its ID, if available
+	tx := p.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("DELETE FROM album_gallery WHERE album_id = $1;", a.ID)
+		tx.Exec("DELETE FROM albums WHERE id = $1;", a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the album by its artist ID and title
+	tx.Exec("DELETE FROM album_gallery WHERE album_id IN "+
+		"(SELECT id FROM albums WHERE artist_id = $1 AND title = $2);", a.ArtistID, a.Title)
+	tx.Exec("DELETE FROM albums WHERE artist_id = $1 AND title = $2;", a.ArtistID, a.Title)
+	return tx.Commit()
+}
+
+// LoadAlbum loads an Album from the database, populating the parameter struct
+func (p *PostgresBackend) LoadAlbum(a *Album) error {
+	// Load the album via ID if available
+	if a.ID != 0 {
+		if err := p.db.Get(a, "SELECT "+albumColumns+" FROM albums "+
+			"JOIN artists ON albums.artist_id = artists.id WHERE albums.id = $1;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via artist ID and title
+	if err := p.db.Get(a, "SELECT "+albumColumns+" FROM albums "+
+		"JOIN artists ON albums.artist_id = artists.id WHERE albums.artist_id = $1 AND albums.title = $2;", a.ArtistID, a.Title); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveAlbum attempts to save an Album to the database
+func (p *PostgresBackend) SaveAlbum(a *Album) error {
+	// Insert new album
+	query := "INSERT INTO albums (art_id, artist_id, title, year) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, a.ArtID, a.ArtistID, a.Title, a.Year)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := p.LoadAlbum(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateAlbum attempts to update an Album in the database
+func (p *PostgresBackend) UpdateAlbum(a *Album) error {
+	// Attempt to update this album by its ID
+	query := "UPDATE albums SET art_id = $1, year = $2 WHERE id = $3;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, a.ArtID, a.Year, a.ID)
+	return tx.Commit()
+}
+
+// AllFolders loads a slice of all Folder structs from the database
+func (p *PostgresBackend) AllFolders() ([]Folder, error) {
+	return p.folderQuery("SELECT * FROM folders;")
+}
+
+// LimitFolders loads a slice of Folder structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (p *PostgresBackend) LimitFolders(offset int, count int) ([]Folder, error) {
+	return p.folderQuery("SELECT * FROM folders ORDER BY folders.id LIMIT $2 OFFSET $1;", offset, count)
+}
+
+// Subfolders loads a slice of all Folder structs residing directly beneath this one from the database
+func (p *PostgresBackend) Subfolders(parentID int) ([]Folder, error) {
+	return p.folderQuery("SELECT * FROM folders WHERE parent_id = $1;", parentID)
+}
+
+// FoldersInPath loads a slice of all Folder structs contained within the specified file path
+func (p *PostgresBackend) FoldersInPath(path string) ([]Folder, error) {
+	return p.folderQuery("SELECT * FROM folders WHERE path LIKE $1;", path+"%")
+}
+
+// FoldersNotInPath loads a slice of all Folder structs belonging to the specified library, or to
+// no library, which are NOT contained within the specified file path
+func (p *PostgresBackend) FoldersNotInPath(libraryID int, path string) ([]Folder, error) {
+	return p.folderQuery("SELECT * FROM folders WHERE library_id IN (0, $1) AND path NOT LIKE $2;", libraryID, path+"%")
+}
+
+// SearchFolders loads a slice of Folder structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  The total number of matching
+// folders is also returned.
+func (p *PostgresBackend) SearchFolders(q Query, offset int, count int) ([]Folder, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Folder, 0), 0, nil
+	}
+
+	// Count all matching folders
+	clause, order, args := pgSearch(q, "folders", "folders", foldersVector, "folder_id")
+	total, err := p.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	folders, err := p.folderQuery("SELECT folders.* FROM "+clause+" ORDER BY "+order+" "+pgLimitOffset(len(args))+";",
+		append(args, offset, pgLimit(count))...)
+	return folders, total, err
+}
+
+// CountFolders fetches the total number of Folder structs from the database
+func (p *PostgresBackend) CountFolders() (int64, error) {
+	return p.integerQuery("SELECT COUNT(*) AS int FROM folders;")
+}
+
+// DeleteFolder removes a Folder from the database
+func (p *PostgresBackend) DeleteFolder(f *Folder) error {
+	// Attempt to delete this folder by its ID, if available
+	tx := p.db.MustBegin()
+	if f.ID != 0 {
+		tx.Exec("DELETE FROM folders WHERE id = $1;", f.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the folder by its path
+	tx.Exec("DELETE FROM folders WHERE path = $1;", f.Path)
+	return tx.Commit()
+}
+
+// LoadFolder loads a Folder from the database, populating the parameter struct
+func (p *PostgresBackend) LoadFolder(f *Folder) error {
+	// Load the folder via ID if available
+	if f.ID != 0 {
+		if err := p.db.Get(f, "SELECT * FROM folders WHERE id = $1;", f.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via path
+	if err := p.db.Get(f, "SELECT * FROM folders WHERE path = $1;", f.Path); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveFolder attempts to save an Folder to the database
+func (p *PostgresBackend) SaveFolder(f *Folder) error {
+	// Insert new folder
+	query := "INSERT INTO folders (parent_id, title, path, library_id) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, f.ParentID, f.Title, f.Path, f.LibraryID)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if f.ID == 0 {
+		if err := p.LoadFolder(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateFolder attempts to update a Folder in the database
+func (p *PostgresBackend) UpdateFolder(f *Folder) error {
+	// Update existing folder
+	tx := p.db.MustBegin()
+	tx.Exec("UPDATE folders SET parent_id = $1, title = $2, path = $3, library_id = $4 WHERE id = $5;",
+		f.ParentID, f.Title, f.Path, f.LibraryID, f.ID)
+	return tx.Commit()
+}
+
+// AllLibraries loads a slice of all Library structs from the database, ordered by name
+func (p *PostgresBackend) AllLibraries() ([]Library, error) {
+	return p.libraryQuery("SELECT * FROM libraries ORDER BY name;")
+}
+
+// DeleteLibrary removes a Library from the database, releasing all media indexed within it
+func (p *PostgresBackend) DeleteLibrary(l *Library) error {
+	// Delete this library by its ID if available, or else by its name
+	where, arg := "id = $1", interface{}(l.ID)
+	if l.ID == 0 {
+		where, arg = "name = $1", l.Name
+	}
+
+	// Release this library's media, so that another library may claim it
+	tx := p.db.MustBegin()
+	for _, table := range []string{"art", "folders", "songs"} {
+		tx.Exec("UPDATE "+table+" SET library_id = 0 WHERE library_id = (SELECT id FROM libraries WHERE "+where+");", arg)
+	}
+
+	tx.Exec("DELETE FROM libraries WHERE "+where+";", arg)
+	return tx.Commit()
+}
+
+// LoadLibrary loads a Library from the database, populating the parameter struct
+func (p *PostgresBackend) LoadLibrary(l *Library) error {
+	// Load the library via ID if available
+	if l.ID != 0 {
+		if err := p.db.Get(l, "SELECT * FROM libraries WHERE id = $1;", l.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via name
+	if err := p.db.Get(l, "SELECT * FROM libraries WHERE name = $1;", l.Name); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveLibrary attempts to save a Library to the database, claiming any unowned media
+// under its path
+func (p *PostgresBackend) SaveLibrary(l *Library) error {
+	// Insert new library
+	tx := p.db.MustBegin()
+	tx.Exec("INSERT INTO libraries (name, path) VALUES ($1, $2) ON CONFLICT DO NOTHING;", l.Name, l.Path)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if l.ID == 0 {
+		if err := p.LoadLibrary(l); err != nil {
+			return err
+		}
+	}
+
+	return p.claimLibrary(l)
+}
+
+// UpdateLibrary updates a Library in the database, claiming any unowned media under its path
+func (p *PostgresBackend) UpdateLibrary(l *Library) error {
+	// Update existing library
+	tx := p.db.MustBegin()
+	tx.Exec("UPDATE libraries SET name = $1, path = $2 WHERE id = $3;", l.Name, l.Path, l.ID)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return p.claimLibrary(l)
+}
+
+// PlaysForUser loads a slice of all Play structs which belong to the specified user ID,
+// ordered from most to least recent
+func (p *PostgresBackend) PlaysForUser(userID int) ([]Play, error) {
+	return p.playQuery("SELECT * FROM plays WHERE user_id = $1 ORDER BY timestamp DESC, id DESC;", userID)
+}
+
+// LimitPlaysForUser loads a slice of Play structs which belong to the specified user ID,
+// ordered from most to least recent, with offset and limit
+func (p *PostgresBackend) LimitPlaysForUser(userID int, offset int, count int) ([]Play, error) {
+	return p.playQuery("SELECT * FROM plays WHERE user_id = $1 ORDER BY timestamp DESC, id DESC LIMIT $3 OFFSET $2;",
+		userID, offset, count)
+}
+
+// DeletePlay removes a Play from the database
+func (p *PostgresBackend) DeletePlay(pl *Play) error {
+	// Attempt to delete this play by its ID, if available
+	tx := p.db.MustBegin()
+	if pl.ID != 0 {
+		tx.Exec("DELETE FROM plays WHERE id = $1;", pl.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the play by its user ID, song ID, and timestamp
+	tx.Exec("DELETE FROM plays WHERE user_id = $1 AND song_id = $2 AND timestamp = $3;", pl.UserID, pl.SongID, pl.Timestamp)
+	return tx.Commit()
+}
+
+// LoadPlay loads a Play from the database, populating the parameter struct
+func (p *PostgresBackend) LoadPlay(pl *Play) error {
+	// Load the play via ID if available
+	if pl.ID != 0 {
+		if err := p.db.Get(pl, "SELECT * FROM plays WHERE id = $1;", pl.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID, song ID, and timestamp
+	if err := p.db.Get(pl, "SELECT * FROM plays WHERE user_id = $1 AND song_id = $2 AND timestamp = $3;",
+		pl.UserID, pl.SongID, pl.Timestamp); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SavePlay attempts to save a Play to the database
+func (p *PostgresBackend) SavePlay(pl *Play) error {
+	// Insert new play
+	query := "INSERT INTO plays (user_id, song_id, timestamp) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, pl.UserID, pl.SongID, pl.Timestamp)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if pl.ID == 0 {
+		if err := p.LoadPlay(pl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PlaylistsForUser loads a slice of all Playlist structs which are owned by the specified
+// user ID, as well as all public playlists owned by other users
+func (p *PostgresBackend) PlaylistsForUser(userID int) ([]Playlist, error) {
+	return p.playlistQuery("SELECT * FROM playlists WHERE user_id = $1 OR public = TRUE ORDER BY title;", userID)
+}
+
+// DeletePlaylist removes a Playlist and all of its entries from the database
+func (p *PostgresBackend) DeletePlaylist(pl *Playlist) error {
+	// Attempt to delete this playlist by its ID, if available
+	tx := p.db.MustBegin()
+	if pl.ID != 0 {
+		tx.Exec("DELETE FROM playlist_entries WHERE playlist_id = $1;", pl.ID)
+		tx.Exec("DELETE FROM playlists WHERE id = $1;", pl.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the playlist by its user ID and title
+	tx.Exec("DELETE FROM playlist_entries WHERE playlist_id = (SELECT id FROM playlists WHERE user_id = $1 AND title = $2);",
+		pl.UserID, pl.Title)
+	tx.Exec("DELETE FROM playlists WHERE user_id = $1 AND title = $2;", pl.UserID, pl.Title)
+	return tx.Commit()
+}
+
+// LoadPlaylist loads a Playlist from the database, populating the parameter struct
+func (p *PostgresBackend) LoadPlaylist(pl *Playlist) error {
+	// Load the playlist via ID if available
+	if pl.ID != 0 {
+		if err := p.db.Get(pl, "SELECT * FROM playlists WHERE id = $1;", pl.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID and title
+	if err := p.db.Get(pl, "SELECT * FROM playlists WHERE user_id = $1 AND title = $2;", pl.UserID, pl.Title); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SavePlaylist attempts to save a Playlist to the database
+func (p *PostgresBackend) SavePlaylist(pl *Playlist) error {
+	// Insert new playlist
+	query := "INSERT INTO playlists (user_id, title, public, created) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, pl.UserID, pl.Title, pl.Public, pl.Created)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if pl.ID == 0 {
+		if err := p.LoadPlaylist(pl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdatePlaylist updates a Playlist in the database
+func (p *PostgresBackend) UpdatePlaylist(pl *Playlist) error {
+	// Update existing playlist
+	tx := p.db.MustBegin()
+	tx.Exec("UPDATE playlists SET title = $1, public = $2 WHERE id = $3;", pl.Title, pl.Public, pl.ID)
+	return tx.Commit()
+}
+
+// EntriesForPlaylist loads a slice of all PlaylistEntry structs which have the matching
+// playlist ID, ordered by their position in the playlist
+func (p *PostgresBackend) EntriesForPlaylist(ID int) ([]PlaylistEntry, error) {
+	return p.playlistEntryQuery("SELECT * FROM playlist_entries WHERE playlist_id = $1 ORDER BY position;", ID)
+}
+
+// SongsForPlaylist loads a slice of all Song structs contained in the playlist with the
+// matching ID, ordered by their position in the playlist
+func (p *PostgresBackend) SongsForPlaylist(ID int) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM playlist_entries "+
+		"JOIN songs ON playlist_entries.song_id = songs.id JOIN artists ON songs.artist_id = artists.id "+
+		"JOIN albums ON songs.album_id = albums.id WHERE playlist_entries.playlist_id = $1 "+
+		"ORDER BY playlist_entries.position;", ID)
+}
+
+// AppendPlaylistEntries adds the songs with the input IDs to the end of the playlist with
+// the matching ID
+func (p *PostgresBackend) AppendPlaylistEntries(ID int, songIDs []int) error {
+	// Find the next free position in the playlist
+	position, err := p.integerQuery("SELECT COUNT(*) AS int FROM playlist_entries WHERE playlist_id = $1;", ID)
+	if err != nil {
+		return err
+	}
+
+	// Insert all songs in order, after the last entry
+	query := "INSERT INTO playlist_entries (playlist_id, song_id, position) VALUES ($1, $2, $3);"
+	tx := p.db.MustBegin()
+	for _, songID := range songIDs {
+		tx.Exec(query, ID, songID, position)
+		position++
+	}
+
+	return tx.Commit()
+}
+
+// RemovePlaylistEntries removes the entries with the input IDs from the playlist with the
+// matching ID, and renumbers the remaining entries so their positions remain contiguous
+func (p *PostgresBackend) RemovePlaylistEntries(ID int, entryIDs []int) error {
+	// Remove all specified entries
+	tx := p.db.MustBegin()
+	for _, entryID := range entryIDs {
+		tx.Exec("DELETE FROM playlist_entries WHERE id = $1 AND playlist_id = $2;", entryID, ID)
+	}
+
+	// Close any gaps left by removed entries
+	if err := p.renumberPlaylistEntries(tx, ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// renumberPlaylistEntries renumbers the entries of the playlist with the matching ID within
+// the input transaction, so their positions remain contiguous
+func (p *PostgresBackend) renumberPlaylistEntries(tx *sqlx.Tx, ID int) error {
+	// Fetch remaining entries in their current order
+	entries := make([]PlaylistEntry, 0)
+	if err := tx.Select(&entries, "SELECT * FROM playlist_entries WHERE playlist_id = $1 ORDER BY position;", ID); err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		tx.Exec("UPDATE playlist_entries SET position = $1 WHERE id = $2;", i, e.ID)
+	}
+
+	return nil
+}
+
+// ReorderPlaylistEntries rearranges the entries of the playlist with the matching ID, so that
+// they appear in the order of the input entry IDs.  All entries in the playlist must be specified.
+func (p *PostgresBackend) ReorderPlaylistEntries(ID int, entryIDs []int) error {
+	// Fetch the current entries, to verify the new order is complete
+	entries, err := p.EntriesForPlaylist(ID)
+	if err != nil {
+		return err
+	}
+
+	// Verify that every entry appears exactly once in the new order
+	if !samePlaylistEntries(entries, entryIDs) {
+		return ErrPlaylistEntries
+	}
+
+	// Renumber all entries using their index in the new order
+	tx := p.db.MustBegin()
+	for i, entryID := range entryIDs {
+		tx.Exec("UPDATE playlist_entries SET position = $1 WHERE id = $2 AND playlist_id = $3;", i, entryID, ID)
+	}
+
+	return tx.Commit()
+}
+
+// RatingsForUser loads a slice of all Rating structs which belong to the specified user ID,
+// ordered by item type and ID
+func (p *PostgresBackend) RatingsForUser(userID int) ([]Rating, error) {
+	return p.ratingQuery("SELECT * FROM ratings WHERE user_id = $1 ORDER BY item_type, item_id;", userID)
+}
+
+// DeleteRating removes a Rating from the database
+func (p *PostgresBackend) DeleteRating(a *Rating) error {
+	// Attempt to delete this rating by its ID, if available
+	tx := p.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("DELETE FROM ratings WHERE id = $1;", a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the rating by its user ID, item type, and item ID
+	tx.Exec("DELETE FROM ratings WHERE user_id = $1 AND item_type = $2 AND item_id = $3;", a.UserID, a.ItemType, a.ItemID)
+	return tx.Commit()
+}
+
+// LoadRating loads a Rating from the database, populating the parameter struct
+func (p *PostgresBackend) LoadRating(a *Rating) error {
+	// Load the rating via ID if available
+	if a.ID != 0 {
+		if err := p.db.Get(a, "SELECT * FROM ratings WHERE id = $1;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID, item type, and item ID
+	if err := p.db.Get(a, "SELECT * FROM ratings WHERE user_id = $1 AND item_type = $2 AND item_id = $3;",
+		a.UserID, a.ItemType, a.ItemID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveRating attempts to save a Rating to the database
+func (p *PostgresBackend) SaveRating(a *Rating) error {
+	// Insert new rating
+	query := "INSERT INTO ratings (user_id, item_type, item_id, rating) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, a.UserID, a.ItemType, a.ItemID, a.Rating)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := p.LoadRating(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateRating updates a Rating in the database
+func (p *PostgresBackend) UpdateRating(a *Rating) error {
+	// Attempt to update this rating by its ID, if available
+	tx := p.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("UPDATE ratings SET rating = $1 WHERE id = $2;", a.Rating, a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to update the rating by its user ID, item type, and item ID
+	tx.Exec("UPDATE ratings SET rating = $1 WHERE user_id = $2 AND item_type = $3 AND item_id = $4;",
+		a.Rating, a.UserID, a.ItemType, a.ItemID)
+	return tx.Commit()
+}
+
+// SmartPlaylistsForUser loads a slice of all SmartPlaylist structs which are owned by the
+// specified user ID, as well as all public smart playlists owned by other users
+func (p *PostgresBackend) SmartPlaylistsForUser(userID int) ([]SmartPlaylist, error) {
+	return p.smartPlaylistQuery("SELECT * FROM smart_playlists WHERE user_id = $1 OR public = TRUE ORDER BY title;", userID)
+}
+
+// DeleteSmartPlaylist removes a SmartPlaylist from the database
+func (p *PostgresBackend) DeleteSmartPlaylist(pl *SmartPlaylist) error {
+	// Attempt to delete this smart playlist by its ID, if available
+	tx := p.db.MustBegin()
+	if pl.ID != 0 {
+		tx.Exec("DELETE FROM smart_playlists WHERE id = $1;", pl.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the smart playlist by its user ID and title
+	tx.Exec("DELETE FROM smart_playlists WHERE user_id = $1 AND title = $2;", pl.UserID, pl.Title)
+	return tx.Commit()
+}
+
+// LoadSmartPlaylist loads a SmartPlaylist from the database, populating the parameter struct
+func (p *PostgresBackend) LoadSmartPlaylist(pl *SmartPlaylist) error {
+	// Load the smart playlist via ID if available
+	if pl.ID != 0 {
+		if err := p.db.Get(pl, "SELECT * FROM smart_playlists WHERE id = $1;", pl.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID and title
+	if err := p.db.Get(pl, "SELECT * FROM smart_playlists WHERE user_id = $1 AND title = $2;", pl.UserID, pl.Title); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSmartPlaylist attempts to save a SmartPlaylist to the database
+func (p *PostgresBackend) SaveSmartPlaylist(pl *SmartPlaylist) error {
+	// Insert new smart playlist
+	query := "INSERT INTO smart_playlists (user_id, title, public, query, sort, song_limit, created) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, pl.UserID, pl.Title, pl.Public, pl.Query, pl.Sort, pl.Limit, pl.Created)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if pl.ID == 0 {
+		if err := p.LoadSmartPlaylist(pl); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSmartPlaylist updates a SmartPlaylist in the database
+func (p *PostgresBackend) UpdateSmartPlaylist(pl *SmartPlaylist) error {
+	// Update existing smart playlist
+	tx := p.db.MustBegin()
+	tx.Exec("UPDATE smart_playlists SET title = $1, public = $2, query = $3, sort = $4, song_limit = $5 WHERE id = $6;",
+		pl.Title, pl.Public, pl.Query, pl.Sort, pl.Limit, pl.ID)
+	return tx.Commit()
+}
+
+// SmartPlaylistSongs loads a slice of Song structs from the database which match the specified
+// search query, in the specified smart playlist sort order, up to the specified limit.  Unlike
+// SearchSongs, an empty query matches every song.
+func (p *PostgresBackend) SmartPlaylistSongs(q Query, sort string, limit int) ([]Song, error) {
+	clause, order, args := pgSmartPlaylistSearch(q, sort)
+	return p.songQuery("SELECT "+songColumns+" FROM "+clause+" ORDER BY "+order+" LIMIT $"+strconv.Itoa(len(args)+1)+";",
+		append(args, pgSmartPlaylistLimit(limit))...)
+}
+
+// CountSmartPlaylistSongs counts the songs in the database which match the specified search
+// query, in the specified smart playlist sort order, up to the specified limit.  The total
+// length of the songs in seconds is also returned.
+func (p *PostgresBackend) CountSmartPlaylistSongs(q Query, sort string, limit int) (int64, int64, error) {
+	clause, order, args := pgSmartPlaylistSearch(q, sort)
+
+	result := struct {
+		Count  int64 `db:"count"`
+		Length int64 `db:"length"`
+	}{}
+	err := p.db.Get(&result, "SELECT COUNT(*) AS count, COALESCE(SUM(length), 0) AS length FROM "+
+		"(SELECT songs.length FROM "+clause+" ORDER BY "+order+" LIMIT $"+strconv.Itoa(len(args)+1)+") AS matches;",
+		append(args, pgSmartPlaylistLimit(limit))...)
+	return result.Count, result.Length, err
+}
+
+// AllSongs loads a slice of all Song structs from the database
+func (p *PostgresBackend) AllSongs() ([]Song, error) {
+	return p.songQuery("SELECT " + songColumns + " FROM songs " +
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id;")
+}
+
+// LimitSongs loads a slice of Song structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (p *PostgresBackend) LimitSongs(offset int, count int) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"ORDER BY songs.id LIMIT $2 OFFSET $1;", offset, count)
+}
+
+// RandomSongs loads a slice of 'n' random song structs from the database
+func (p *PostgresBackend) RandomSongs(n int) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"ORDER BY RANDOM() LIMIT $1;", n)
+}
+
+// SearchSongs loads a slice of Song structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  Song titles are weighted highest,
+// followed by artist and album titles, genre, and comment.  The total number of matching songs
+// is also returned.
+func (p *PostgresBackend) SearchSongs(q Query, offset int, count int) ([]Song, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Song, 0), 0, nil
+	}
+
+	// Count all matching songs
+	clause, order, args := pgSongSearch(q)
+	total, err := p.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	songs, err := p.songQuery("SELECT "+songColumns+" FROM "+clause+" ORDER BY "+order+" "+pgLimitOffset(len(args))+";",
+		append(args, offset, pgLimit(count))...)
+	return songs, total, err
+}
+
+// SongsForAlbum loads a slice of all Song structs which have the matching album ID, ordered
+// by disc number, and then by track number
+func (p *PostgresBackend) SongsForAlbum(ID int) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.album_id = $1 ORDER BY songs.disc, songs.track, songs.id;", ID)
+}
+
+// SongsForArtist loads a slice of all Song structs which have the matching artist ID
+func (p *PostgresBackend) SongsForArtist(ID int) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.artist_id = $1;", ID)
+}
+
+// SongsForFolder loads a slice of all Song structs which have the matching folder ID
+func (p *PostgresBackend) SongsForFolder(ID int) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.folder_id = $1;", ID)
+}
+
+// SongsForFingerprint loads a slice of all Song structs which have the matching fingerprint
+func (p *PostgresBackend) SongsForFingerprint(fingerprint string) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.fingerprint = $1;", fingerprint)
+}
+
+// SongsInPath loads a slice of all Song structs residing under the specified
+// filesystem path from the database
+func (p *PostgresBackend) SongsInPath(path string) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.file_name LIKE $1;", path+"%")
+}
+
+// SongsNotInPath loads a slice of all Song structs belonging to the specified library, or to no
+// library, that do not reside under the specified filesystem path from the database
+func (p *PostgresBackend) SongsNotInPath(libraryID int, path string) ([]Song, error) {
+	return p.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.library_id IN (0, $1) AND songs.file_name NOT LIKE $2;", libraryID, path+"%")
+}
+
+// SongFilesInPath loads a slice of SongFile structs for all songs residing under the
+// specified filesystem path from the database
+func (p *PostgresBackend) SongFilesInPath(path string) ([]SongFile, error) {
+	files := make([]SongFile, 0)
+	err := p.db.Select(&files, "SELECT file_name,file_size,fingerprint,last_modified FROM songs WHERE file_name LIKE $1;", path+"%")
+	return files, err
+}
+
+// CountSongs fetches the total number of Artist structs from the database
+func (p *PostgresBackend) CountSongs() (int64, error) {
+	return p.integerQuery("SELECT COUNT(*) AS int FROM songs;")
+}
+
+// DeleteSong removes a Song from the database, along with any playlist entries for it
+func (p *PostgresBackend) DeleteSong(a *Song) error {
+	// Use this song's ID if available, else look it up by its file name and offset
+	tx := p.db.MustBegin()
+	ID := a.ID
+	if ID == 0 {
+		if err := tx.Get(&ID, "SELECT id FROM songs WHERE file_name = $1 AND start_offset = $2;", a.FileName, a.StartOffset); err != nil {
+			tx.Rollback()
+			if err == sql.ErrNoRows {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	// Remove the song from any playlists which contain it, closing the gaps it leaves
+	playlistIDs := make([]int, 0)
+	if err := tx.Select(&playlistIDs, "SELECT DISTINCT playlist_id FROM playlist_entries WHERE song_id = $1;", ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Exec("DELETE FROM playlist_entries WHERE song_id = $1;", ID)
+	for _, playlistID := range playlistIDs {
+		if err := p.renumberPlaylistEntries(tx, playlistID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	tx.Exec("DELETE FROM songs WHERE id = $1;", ID)
+	return tx.Commit()
+}
+
+// LoadSong loads a Song from the database, populating the parameter struct
+func (p *PostgresBackend) LoadSong(a *Song) error {
+	// Load the song via ID if available
+	if a.ID != 0 {
+		if err := p.db.Get(a, "SELECT "+songColumns+" FROM songs "+
+			"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+			"WHERE songs.id = $1;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via file name and offset
+	if err := p.db.Get(a, "SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.file_name = $1 AND songs.start_offset = $2;", a.FileName, a.StartOffset); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSong attempts to save a Song to the database
+func (p *PostgresBackend) SaveSong(a *Song) error {
+	// Insert new song
+	if err := p.SaveSongs([]Song{*a}); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := p.LoadSong(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveSongs attempts to save a batch of Songs to the database in a single transaction.  Song IDs
+// are not loaded, so they must be reloaded by file name and offset if needed.
+func (p *PostgresBackend) SaveSongs(songs []Song) error {
+	// Insert new songs
+	query := "INSERT INTO songs (added, album_id, art_id, artist_id, bitrate, channels, comment, disc, disc_total, end_offset, " +
+		"file_name, file_size, file_type_id, fingerprint, folder_id, genre, last_modified, length, library_id, sample_rate, " +
+		"start_offset, title, track, track_total, year) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, " +
+		"$16, $17, $18, $19, $20, $21, $22, $23, $24, $25) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	for _, a := range songs {
+		tx.Exec(query, a.Added, a.AlbumID, a.ArtID, a.ArtistID, a.Bitrate, a.Channels, a.Comment, a.Disc, a.DiscTotal, a.EndOffset,
+			a.FileName, a.FileSize, a.FileTypeID, a.Fingerprint, a.FolderID, a.Genre, a.LastModified, a.Length, a.LibraryID,
+			a.SampleRate, a.StartOffset, a.Title, a.Track, a.TrackTotal, a.Year)
+	}
+
+	// Commit transaction
+	return tx.Commit()
+}
+
+// UpdateSong attempts to update a Song in the database
+func (p *PostgresBackend) UpdateSong(a *Song) error {
+	return p.UpdateSongs([]Song{*a})
+}
+
+// UpdateSongs attempts to update a batch of Songs in the database in a single transaction
+func (p *PostgresBackend) UpdateSongs(songs []Song) error {
+	// Update existing songs
+	query := "UPDATE songs SET album_id = $1, art_id = $2, artist_id = $3, bitrate = $4, channels = $5, comment = $6, " +
+		"disc = $7, disc_total = $8, end_offset = $9, file_size = $10, fingerprint = $11, folder_id = $12,  genre = $13, " +
+		"last_modified = $14, length = $15, library_id = $16, sample_rate = $17, title = $18, track = $19, track_total = $20, " +
+		"year = $21 WHERE id = $22;"
+	tx := p.db.MustBegin()
+	for _, a := range songs {
+		tx.Exec(query, a.AlbumID, a.ArtID, a.ArtistID, a.Bitrate, a.Channels, a.Comment, a.Disc, a.DiscTotal, a.EndOffset,
+			a.FileSize, a.Fingerprint, a.FolderID, a.Genre, a.LastModified, a.Length, a.LibraryID, a.SampleRate, a.Title, a.Track,
+			a.TrackTotal, a.Year, a.ID)
+	}
+
+	// Commit transaction
+	return tx.Commit()
+}
+
+// StarsForUser loads a slice of all Star structs which belong to the specified user ID,
+// ordered from most to least recent
+func (p *PostgresBackend) StarsForUser(userID int) ([]Star, error) {
+	return p.starQuery("SELECT * FROM stars WHERE user_id = $1 ORDER BY created DESC, id DESC;", userID)
+}
+
+// DeleteStar removes a Star from the database
+func (p *PostgresBackend) DeleteStar(a *Star) error {
+	// Attempt to delete this star by its ID, if available
+	tx := p.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("DELETE FROM stars WHERE id = $1;", a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the star by its user ID, item type, and item ID
+	tx.Exec("DELETE FROM stars WHERE user_id = $1 AND item_type = $2 AND item_id = $3;", a.UserID, a.ItemType, a.ItemID)
+	return tx.Commit()
+}
+
+// LoadStar loads a Star from the database, populating the parameter struct
+func (p *PostgresBackend) LoadStar(a *Star) error {
+	// Load the star via ID if available
+	if a.ID != 0 {
+		if err := p.db.Get(a, "SELECT * FROM stars WHERE id = $1;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID, item type, and item ID
+	if err := p.db.Get(a, "SELECT * FROM stars WHERE user_id = $1 AND item_type = $2 AND item_id = $3;",
+		a.UserID, a.ItemType, a.ItemID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveStar attempts to save a Star to the database
+func (p *PostgresBackend) SaveStar(a *Star) error {
+	// Insert new star
+	query := "INSERT INTO stars (user_id, item_type, item_id, created) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, a.UserID, a.ItemType, a.ItemID, a.Created)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := p.LoadStar(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllUsers loads a slice of all User structs from the database
+func (p *PostgresBackend) AllUsers() ([]User, error) {
+	return p.userQuery("SELECT * FROM users;")
+}
+
+// DeleteUser removes a User from the database
+func (p *PostgresBackend) DeleteUser(u *User) error {
+	// Attempt to delete this user by its ID, if available
+	tx := p.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("DELETE FROM users WHERE id = $1;", u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the user by its username
+	tx.Exec("DELETE FROM users WHERE username = $1;", u.Username)
+	return tx.Commit()
+}
+
+// LoadUser loads a User from the database, populating the parameter struct
+func (p *PostgresBackend) LoadUser(u *User) error {
+	// Load the user via ID if available
+	if u.ID != 0 {
+		if err := p.db.Get(u, "SELECT * FROM users WHERE id = $1;", u.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via username
+	if err := p.db.Get(u, "SELECT * FROM users WHERE username = $1;", u.Username); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveUser attempts to save a User to the database
+func (p *PostgresBackend) SaveUser(u *User) error {
+	// Insert new user
+	query := "INSERT INTO users (username, password, role_id, lastfm_token) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, u.Username, u.Password, u.RoleID, u.LastFMToken)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if u.ID == 0 {
+		if err := p.LoadUser(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateUser updates a User in the database
+func (p *PostgresBackend) UpdateUser(u *User) error {
+	// Attempt to update this user by its ID, if available
+	tx := p.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("UPDATE users SET username = $1, password = $2, role_id = $3, lastfm_token = $4 WHERE id = $5;",
+			u.Username, u.Password, u.RoleID, u.LastFMToken, u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to update the user by its username
+	tx.Exec("UPDATE users SET password = $1, role_id = $2, lastfm_token = $3 WHERE username = $4;",
+		u.Password, u.RoleID, u.LastFMToken, u.Username)
+	return tx.Commit()
+}
+
+// SessionsForUser loads a slice of all Sessions for a given User from the database
+func (p *PostgresBackend) SessionsForUser(userID int) ([]Session, error) {
+	return p.sessionQuery("SELECT * FROM sessions WHERE user_id = $1", userID)
+}
+
+// DeleteSession removes a Session from the database
+func (p *PostgresBackend) DeleteSession(u *Session) error {
+	// Attempt to delete this session by its ID, if available
+	tx := p.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("DELETE FROM sessions WHERE id = $1;", u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the session by its key
+	tx.Exec("DELETE FROM sessions WHERE key = $1;", u.Key)
+	return tx.Commit()
+}
+
+// LoadSession loads a Session from the database, populating the parameter struct
+func (p *PostgresBackend) LoadSession(u *Session) error {
+	// Load the session via ID if available
+	if u.ID != 0 {
+		if err := p.db.Get(u, "SELECT * FROM sessions WHERE id = $1;", u.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via key
+	if err := p.db.Get(u, "SELECT * FROM sessions WHERE key = $1;", u.Key); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSession attempts to save a Session to the database
+func (p *PostgresBackend) SaveSession(u *Session) error {
+	// Insert new session
+	query := "INSERT INTO sessions (user_id, client, expire, key) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING;"
+	tx := p.db.MustBegin()
+	tx.Exec(query, u.UserID, u.Client, u.Expire, u.Key)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if u.ID == 0 {
+		if err := p.LoadSession(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSession updates a Session in the database
+func (p *PostgresBackend) UpdateSession(u *Session) error {
+	// Attempt to update this session by its ID, if available
+	tx := p.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("UPDATE sessions SET expire = $1 WHERE id = $2;", u.Expire, u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to update the session by its key
+	tx.Exec("UPDATE sessions SET expire = $1 WHERE key = $2;", u.Expire, u.Key)
+	return tx.Commit()
+}
+
+// albumQuery loads a slice of Album structs matching the input query
+func (p *PostgresBackend) albumQuery(query string, args ...interface{}) ([]Album, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	albums := make([]Album, 0)
+	a := Album{}
+	for rows.Next() {
+		// Scan album into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		albums = append(albums, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return albums, nil
+}
+
+// artQuery loads a slice of Art structs matching the input query
+func (p *PostgresBackend) artQuery(query string, args ...interface{}) ([]Art, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	art := make([]Art, 0)
+	a := Art{}
+	for rows.Next() {
+		// Scan artist into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		art = append(art, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return art, nil
+}
+
+// artistQuery loads a slice of Artist structs matching the input query
+func (p *PostgresBackend) artistQuery(query string, args ...interface{}) ([]Artist, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	artists := make([]Artist, 0)
+	a := Artist{}
+	for rows.Next() {
+		// Scan artist into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		artists = append(artists, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return artists, nil
+}
+
+// folderQuery loads a slice of Folder structs matching the input query
+func (p *PostgresBackend) folderQuery(query string, args ...interface{}) ([]Folder, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	folders := make([]Folder, 0)
+	a := Folder{}
+	for rows.Next() {
+		// Scan folder into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		folders = append(folders, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// libraryQuery loads a slice of Library structs matching the input query
+func (p *PostgresBackend) libraryQuery(query string, args ...interface{}) ([]Library, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	libraries := make([]Library, 0)
+	a := Library{}
+	for rows.Next() {
+		// Scan library into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		libraries = append(libraries, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return libraries, nil
+}
+
+// playQuery loads a slice of Play structs matching the input query
+func (p *PostgresBackend) playQuery(query string, args ...interface{}) ([]Play, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	plays := make([]Play, 0)
+	a := Play{}
+	for rows.Next() {
+		// Scan play into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		plays = append(plays, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return plays, nil
+}
+
+// playlistQuery loads a slice of Playlist structs matching the input query
+func (p *PostgresBackend) playlistQuery(query string, args ...interface{}) ([]Playlist, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	playlists := make([]Playlist, 0)
+	a := Playlist{}
+	for rows.Next() {
+		// Scan playlist into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		playlists = append(playlists, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+// playlistEntryQuery loads a slice of PlaylistEntry structs matching the input query
+func (p *PostgresBackend) playlistEntryQuery(query string, args ...interface{}) ([]PlaylistEntry, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	entries := make([]PlaylistEntry, 0)
+	a := PlaylistEntry{}
+	for rows.Next() {
+		// Scan playlist entry into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		entries = append(entries, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ratingQuery loads a slice of Rating structs matching the input query
+func (p *PostgresBackend) ratingQuery(query string, args ...interface{}) ([]Rating, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	ratings := make([]Rating, 0)
+	a := Rating{}
+	for rows.Next() {
+		// Scan rating into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		ratings = append(ratings, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// smartPlaylistQuery loads a slice of SmartPlaylist structs matching the input query
+func (p *PostgresBackend) smartPlaylistQuery(query string, args ...interface{}) ([]SmartPlaylist, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	playlists := make([]SmartPlaylist, 0)
+	a := SmartPlaylist{}
+	for rows.Next() {
+		// Scan smart playlist into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		playlists = append(playlists, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+// songQuery loads a slice of Song structs matching the input query
+func (p *PostgresBackend) songQuery(query string, args ...interface{}) ([]Song, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	songs := make([]Song, 0)
+	a := Song{}
+	for rows.Next() {
+		// Scan song into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		songs = append(songs, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return songs, nil
+}
+
+// starQuery loads a slice of Star structs matching the input query
+func (p *PostgresBackend) starQuery(query string, args ...interface{}) ([]Star, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	stars := make([]Star, 0)
+	a := Star{}
+	for rows.Next() {
+		// Scan star into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		stars = append(stars, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stars, nil
+}
+
+// userQuery loads a slice of User structs matching the input query
+func (p *PostgresBackend) userQuery(query string, args ...interface{}) ([]User, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	users := make([]User, 0)
+	a := User{}
+	for rows.Next() {
+		// Scan user into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		users = append(users, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// sessionQuery loads a slice of Session structs matching the input query
+func (p *PostgresBackend) sessionQuery(query string, args ...interface{}) ([]Session, error) {
+	// Perform input query with arguments
+	rows, err := p.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	sessions := make([]Session, 0)
+	a := Session{}
+	for rows.Next() {
+		// Scan session into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		sessions = append(sessions, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// integerQuery returns a single integer value from the input query
+func (p *PostgresBackend) integerQuery(query string, args ...interface{}) (int64, error) {
+	// Perform query and fetch result
+	result := struct {
+		Int int64 `db:"int"`
+	}{0}
+	if err := p.db.Get(&result, query, args...); err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	return result.Int, nil
+}
+
+// claimLibrary assigns all art, folders, and songs under a library's path which do not yet
+// belong to any library to that library
+func (p *PostgresBackend) claimLibrary(l *Library) error {
+	tx := p.db.MustBegin()
+	tx.Exec("UPDATE art SET library_id = $1 WHERE library_id = 0 AND file_name LIKE $2;", l.ID, l.Path+"%")
+	tx.Exec("UPDATE folders SET library_id = $1 WHERE library_id = 0 AND path LIKE $2;", l.ID, l.Path+"%")
+	tx.Exec("UPDATE songs SET library_id = $1 WHERE library_id = 0 AND file_name LIKE $2;", l.ID, l.Path+"%")
+	return tx.Commit()
+}
+
+// pgSearch builds the FROM and WHERE clauses, ORDER BY clause, and arguments which select items
+// in the specified table matching a search query.  Free text is matched and ranked against the
+// search vector.  Filters are applied to songs, and items are matched using the specified songs
+// column, or directly if the table is songs.
+func pgSearch(q Query, table string, from string, vector string, column string) (string, string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	order := table + ".id"
+
+	// Match free text using the search vector
+	if match := tsQuery(q.Text); match != "" {
+		conditions = append(conditions, vector+" @@ to_tsquery('wavepipe_search', $1)")
+		args = append(args, match)
+		order = "ts_rank(" + vector + ", to_tsquery('wavepipe_search', $1)) DESC, " + order
+	}
+
+	// Match filters against songs, numbering parameters after the search vector's
+	n := len(args)
+	filters, filterArgs := q.sqlConditions("strpos(lower({column}), lower({param})) > 0", func() string {
+		n++
+		return "$" + strconv.Itoa(n)
+	})
+	if len(filters) > 0 {
+		where := strings.Join(filters, " AND ")
+		if column == "" {
+			conditions = append(conditions, where)
+		} else {
+			conditions = append(conditions, table+".id IN (SELECT songs."+column+" FROM songs "+
+				"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+				"WHERE "+where+")")
+		}
+		args = append(args, filterArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return from, order, args
+	}
+
+	return from + " WHERE " + strings.Join(conditions, " AND "), order, args
+}
+
+// pgSongSearch builds the clauses and arguments which select songs matching a search query,
+// ranked against the songs search vector
+func pgSongSearch(q Query) (string, string, []interface{}) {
+	return pgSearch(q, "songs", "songs JOIN artists ON songs.artist_id = artists.id "+
+		"JOIN albums ON songs.album_id = albums.id", songsVector, "")
+}
+
+// pgSmartPlaylistSearch builds the clauses and arguments which select songs matching a search
+// query, ordered first by the specified smart playlist sort order.  Text is compared by byte
+// value, as with the other backends.
+func pgSmartPlaylistSearch(q Query, sort string) (string, string, []interface{}) {
+	clause, order, args := pgSongSearch(q)
+	if sorted := smartPlaylistOrder(sort, `lower({column}) COLLATE "C"`); sorted != "" {
+		order = sorted + ", " + order
+	}
+
+	return clause, order, args
+}
+
+// pgSmartPlaylistLimit converts a smart playlist limit into a postgres LIMIT value, where zero
+// means no limit
+func pgSmartPlaylistLimit(limit int) interface{} {
+	if limit == 0 {
+		return nil
+	}
+
+	return limit
+}
+
+// pgLimitOffset returns a postgres LIMIT and OFFSET clause, whose parameters follow the
+// specified number of arguments, with the offset first
+func pgLimitOffset(n int) string {
+	return "LIMIT $" + strconv.Itoa(n+2) + " OFFSET $" + strconv.Itoa(n+1)
+}
+
+// pgLimit converts a count into a postgres LIMIT value, where a negative count means no limit
+func pgLimit(count int) interface{} {
+	if count < 0 {
+		return nil
+	}
+
+	return count
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"os"
+	"testing"
+)
+
+// envPostgres is the name of the environment variable which contains the connection
+// string of a local postgres instance used for testing
+const envPostgres = "WAVEPIPE_POSTGRES"
+
+// postgresTables are the tables emptied before and after running the conformance suite
+// against postgres, so that it starts with an empty database
+const postgresTables = "album_gallery, albums, art, artists, folders, libraries, playlist_entries, " +
+	"playlists, plays, ratings, sessions, smart_playlists, songs, stars, users"
+
+// testPostgresBackend opens and migrates the postgres backend set in the environment, and
+// empties all of its tables, returning the backend and a function which empties and closes
+// it.  If no postgres instance is available, nil is returned.
+func testPostgresBackend(t *testing.T) (*PostgresBackend, func()) {
+	connString := os.Getenv(envPostgres)
+	if connString == "" {
+		return nil, func() {}
+	}
+
+	db := new(PostgresBackend)
+	db.DSN(connString)
+	if err := db.Open(); err != nil {
+		t.Fatalf("Could not open database connection: %s", err.Error())
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Could not migrate database: %s", err.Error())
+	}
+
+	// Empty all tables, resetting their IDs
+	truncate := func() error {
+		_, err := db.db.Exec("TRUNCATE " + postgresTables + " RESTART IDENTITY CASCADE;")
+		return err
+	}
+	if err := truncate(); err != nil {
+		t.Fatalf("Could not empty database: %s", err.Error())
+	}
+
+	return db, func() {
+		truncate()
+		db.Close()
+	}
+}
+
+// TestPostgresBackend verifies that the postgres backend can be migrated, and that
+// items can be saved, loaded, and deleted from the database
+func TestPostgresBackend(t *testing.T) {
+	// Skip test if no postgres instance is available
+	connString := os.Getenv(envPostgres)
+	if connString == "" {
+		t.Skipf("%s not set, skipping postgres tests", envPostgres)
+	}
+
+	// Load database configuration
+	DB = new(PostgresBackend)
+	DB.DSN(connString)
+	if err := DB.Open(); err != nil {
+		t.Fatalf("Could not open database connection: %s", err.Error())
+	}
+	defer DB.Close()
+
+	// Migrate twice, to verify migrations are only applied once
+	for i := 0; i < 2; i++ {
+		if err := DB.Migrate(); err != nil {
+			t.Fatalf("Could not migrate database: %s", err.Error())
+		}
+	}
+
+	// Verify schema version is latest
+	migrations, err := loadMigrations("res/postgres/migrations/")
+	if err != nil {
+		t.Fatalf("Could not load migrations: %s", err.Error())
+	}
+	if version, err := DB.SchemaVersion(); err != nil || version != len(migrations) {
+		t.Fatalf("Unexpected schema version: %d != %d (%v)", version, len(migrations), err)
+	}
+
+	// Attempt to save the artist twice, verifying duplicates are ignored
+	artist := Artist{Title: "TestPostgresArtist"}
+	if err := artist.Save(); err != nil {
+		t.Fatalf("Could not save artist: %s", err.Error())
+	}
+	duplicate := Artist{Title: artist.Title}
+	if err := duplicate.Save(); err != nil {
+		t.Fatalf("Could not save duplicate artist: %s", err.Error())
+	}
+	if duplicate.ID != artist.ID {
+		t.Fatalf("Duplicate artist ID mismatch: %d != %d", duplicate.ID, artist.ID)
+	}
+
+	// Attempt to save an album and song by the artist
+	album := Album{ArtistID: artist.ID, Title: "TestPostgresAlbum", Year: 2014}
+	if err := album.Save(); err != nil {
+		t.Fatalf("Could not save album: %s", err.Error())
+	}
+	song := Song{
+		AlbumID:  album.ID,
+		ArtistID: artist.ID,
+		FileName: "/mem/postgres",
+		Title:    "TestPostgresSong",
+	}
+	if err := song.Save(); err != nil {
+		t.Fatalf("Could not save song: %s", err.Error())
+	}
+
+	// Verify song is loaded with joined artist and album titles
+	if err := song.Load(); err != nil {
+		t.Fatalf("Could not load song: %s", err.Error())
+	}
+	if song.Artist != artist.Title || song.Album != album.Title {
+		t.Fatalf("Unexpected song artist and album: %s - %s", song.Artist, song.Album)
+	}
+
+	// Verify case-insensitive search
+	songs, _, err := DB.SearchSongs(Query{Text: "testpostgres"}, 0, -1)
+	if err != nil {
+		t.Fatalf("Could not search songs: %s", err.Error())
+	}
+	if len(songs) == 0 {
+		t.Fatalf("Search found no songs")
+	}
+
+	// Verify limit and offset ordering
+	if _, err := DB.LimitSongs(0, 1); err != nil {
+		t.Fatalf("Could not limit songs: %s", err.Error())
+	}
+
+	// Attempt to delete all items
+	if err := song.Delete(); err != nil {
+		t.Fatalf("Could not delete song: %s", err.Error())
+	}
+	if err := album.Delete(); err != nil {
+		t.Fatalf("Could not delete album: %s", err.Error())
+	}
+	if err := artist.Delete(); err != nil {
+		t.Fatalf("Could not delete artist: %s", err.Error())
+	}
+}
@@ -2,9 +2,13 @@ package data
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/mdlayher/wavepipe/common"
 
@@ -14,6 +18,10 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// ErrSqliteNoFTS5 is returned when wavepipe is built without the sqlite FTS5 extension, which
+// its search index requires
+var ErrSqliteNoFTS5 = errors.New("sqlite: FTS5 extension unavailable, wavepipe must be built with -tags sqlite_fts5")
+
 // SqliteBackend represents a sqlite3-based database backend
 type SqliteBackend struct {
 	Path string
@@ -95,6 +103,19 @@ func (s *SqliteBackend) Open() error {
 		return err
 	}
 
+	// Verify the FTS5 extension is available, because without it, the search index triggers
+	// cause every insert to fail
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("CREATE VIRTUAL TABLE temp.fts5_probe USING fts5(a);")
+	tx.Rollback()
+	if err != nil {
+		db.Close()
+		return ErrSqliteNoFTS5
+	}
+
 	// Store database instance for duration of run
 	s.db = db
 	return nil
@@ -105,14 +126,97 @@ func (s *SqliteBackend) Close() error {
 	return s.db.Close()
 }
 
+// Migrate applies any embedded sqlite migrations which are newer than the current database
+// schema version.  Each migration is applied in its own transaction, along with its version.
+func (s *SqliteBackend) Migrate() error {
+	// Load all embedded sqlite migrations
+	migrations, err := loadMigrations("res/sqlite/migrations/")
+	if err != nil {
+		return err
+	}
+
+	// Check the current database schema version
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	// Refuse to touch a database which was created by a newer version of wavepipe
+	if version > len(migrations) {
+		log.Printf("db: database schema version %d, latest supported version %d", version, len(migrations))
+		return ErrSchemaTooNew
+	}
+
+	// Apply all migrations which are newer than the current version
+	for _, m := range migrations[version:] {
+		log.Printf("db: applying migration %04d: %s", m.Version, m.Description)
+
+		tx, err := s.db.Beginx()
+		if err != nil {
+			return err
+		}
+
+		// Run the migration script, rolling back on failure
+		if _, err := tx.Exec(m.Script); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		// Record the new schema version along with the migration
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d;", m.Version)); err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the current schema version of the sqlite database
+func (s *SqliteBackend) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.Get(&version, "PRAGMA user_version;")
+	return version, err
+}
+
+// MovePath updates the paths of all songs, art, and folders at the specified path, or beneath
+// it, so that they reside at a new path and keep their IDs.  No items may already exist at
+// the new path.
+func (s *SqliteBackend) MovePath(from string, to string) error {
+	// sqlite counts characters, rather than bytes, to find the remainder of each path
+	prefix := from + "/"
+	length := utf8.RuneCountInString(prefix)
+
+	tx := s.db.MustBegin()
+	tx.Exec("UPDATE songs SET `file_name` = ? || substr(file_name, ?) WHERE file_name = ? OR substr(file_name, 1, ?) = ?;",
+		to, length, from, length, prefix)
+	tx.Exec("UPDATE art SET `file_name` = ? || substr(file_name, ?) WHERE file_name = ? OR substr(file_name, 1, ?) = ?;",
+		to, length, from, length, prefix)
+	tx.Exec("UPDATE folders SET `path` = ? || substr(path, ?) WHERE path = ? OR substr(path, 1, ?) = ?;",
+		to, length, from, length, prefix)
+	return tx.Commit()
+}
+
 // ArtInPath loads a slice of all Art structs contained within the specified file path
 func (s *SqliteBackend) ArtInPath(path string) ([]Art, error) {
 	return s.artQuery("SELECT * FROM art WHERE file_name LIKE ?;", path+"%")
 }
 
-// ArtNotInPath loads a slice of all Art structs NOT contained within the specified file path
-func (s *SqliteBackend) ArtNotInPath(path string) ([]Art, error) {
-	return s.artQuery("SELECT * FROM art WHERE file_name NOT LIKE ?;", path+"%")
+// ArtNotInPath loads a slice of all art file Art structs belonging to the specified library, or
+// to no library, which are NOT contained within the specified file path
+func (s *SqliteBackend) ArtNotInPath(libraryID int, path string) ([]Art, error) {
+	return s.artQuery("SELECT * FROM art WHERE source = ? AND library_id IN (0, ?) AND file_name NOT LIKE ?;",
+		ArtSourceFile, libraryID, path+"%")
+}
+
+// OrphanEmbeddedArt loads a slice of all embedded Art structs which are no longer used by any song
+func (s *SqliteBackend) OrphanEmbeddedArt() ([]Art, error) {
+	return s.artQuery("SELECT * FROM art WHERE source = ? AND NOT EXISTS "+
+		"(SELECT 1 FROM songs WHERE songs.art_id = art.id);", ArtSourceEmbedded)
 }
 
 // CountArt fetches the total number of Art structs from the database
@@ -126,8 +230,10 @@ func (s *SqliteBackend) DeleteArt(a *Art) error {
 	tx := s.db.MustBegin()
 	tx.Exec("DELETE FROM art WHERE id = ?;", a.ID)
 
-	// Update any songs using this art ID to have a zero ID
+	// Update any songs and albums using this art ID to have a zero ID, and remove it from galleries
 	tx.Exec("UPDATE songs SET art_id = 0 WHERE art_id = ?;", a.ID)
+	tx.Exec("UPDATE albums SET art_id = 0 WHERE art_id = ?;", a.ID)
+	tx.Exec("DELETE FROM album_gallery WHERE art_id = ?;", a.ID)
 	return tx.Commit()
 }
 
@@ -153,9 +259,9 @@ func (s *SqliteBackend) LoadArt(a *Art) error {
 // SaveArt attempts to save Art to the database
 func (s *SqliteBackend) SaveArt(a *Art) error {
 	// Insert new artist
-	query := "INSERT INTO art (`file_name`, `file_size`, `last_modified`) VALUES (?, ?, ?);"
+	query := "INSERT INTO art (`file_name`, `file_size`, `last_modified`, `library_id`, `source`) VALUES (?, ?, ?, ?, ?);"
 	tx := s.db.MustBegin()
-	tx.Exec(query, a.FileName, a.FileSize, a.LastModified)
+	tx.Exec(query, a.FileName, a.FileSize, a.LastModified, a.LibraryID, a.Source)
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
@@ -185,13 +291,28 @@ func (s *SqliteBackend) AllArtistsByTitle() ([]Artist, error) {
 // LimitArtists loads a slice of Artist structs from the database using SQL limit, where the first parameter
 // specifies an offset and the second specifies an item count
 func (s *SqliteBackend) LimitArtists(offset int, count int) ([]Artist, error) {
-	return s.artistQuery("SELECT * FROM artists LIMIT ?, ?;", offset, count)
+	return s.artistQuery("SELECT * FROM artists ORDER BY artists.id LIMIT ?, ?;", offset, count)
 }
 
-// SearchArtists loads a slice of all Artist structs from the database which contain
-// titles that match the specified search query
-func (s *SqliteBackend) SearchArtists(query string) ([]Artist, error) {
-	return s.artistQuery("SELECT * FROM artists WHERE title LIKE ?;", "%"+query+"%")
+// SearchArtists loads a slice of Artist structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  The total number of matching
+// artists is also returned.
+func (s *SqliteBackend) SearchArtists(q Query, offset int, count int) ([]Artist, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Artist, 0), 0, nil
+	}
+
+	// Count all matching artists
+	clause, order, args := sqliteSearch(q, "artists", "artists", "rank", "artist_id")
+	total, err := s.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	artists, err := s.artistQuery("SELECT artists.* FROM "+clause+" ORDER BY "+order+" LIMIT ?, ?;",
+		append(args, offset, count)...)
+	return artists, total, err
 }
 
 // CountArtists fetches the total number of Artist structs from the database
@@ -200,11 +321,13 @@ func (s *SqliteBackend) CountArtists() (int64, error) {
 }
 
 // PurgeOrphanArtists deletes all artists who are "orphaned", meaning that they no
-// longer have any songs which reference their ID
+// longer have any songs or albums which reference their ID
 func (s *SqliteBackend) PurgeOrphanArtists() (int, error) {
-	// Select all artists without a song referencing their artist ID
+	// Select all artists without a song referencing their artist ID, who are also not the
+	// album artist of any album
 	rows, err := s.db.Queryx("SELECT artists.id FROM artists LEFT JOIN songs ON " +
-		"artists.id = songs.artist_id WHERE songs.artist_id IS NULL;")
+		"artists.id = songs.artist_id WHERE songs.artist_id IS NULL AND " +
+		"artists.id NOT IN (SELECT albums.artist_id FROM albums);")
 	if err != nil && err != sql.ErrNoRows {
 		return -1, err
 	}
@@ -292,28 +415,66 @@ func (s *SqliteBackend) SaveArtist(a *Artist) error {
 
 // AllAlbums loads a slice of all Album structs from the database
 func (s *SqliteBackend) AllAlbums() ([]Album, error) {
-	return s.albumQuery("SELECT albums.*,artists.title AS artist FROM albums " +
+	return s.albumQuery("SELECT " + albumColumns + " FROM albums " +
 		"JOIN artists ON albums.artist_id = artists.id;")
 }
 
 // LimitAlbums loads a slice of Album structs from the database using SQL limit, where the first parameter
 // specifies an offset and the second specifies an item count
 func (s *SqliteBackend) LimitAlbums(offset int, count int) ([]Album, error) {
-	return s.albumQuery("SELECT albums.*,artists.title AS artist FROM albums "+
-		"JOIN artists ON albums.artist_id = artists.id LIMIT ?, ?;", offset, count)
+	return s.albumQuery("SELECT "+albumColumns+" FROM albums "+
+		"JOIN artists ON albums.artist_id = artists.id ORDER BY albums.id LIMIT ?, ?;", offset, count)
 }
 
 // AlbumsForArtist loads a slice of all Album structs with matching artist ID
 func (s *SqliteBackend) AlbumsForArtist(ID int) ([]Album, error) {
-	return s.albumQuery("SELECT albums.*,artists.title AS artist FROM albums "+
+	return s.albumQuery("SELECT "+albumColumns+" FROM albums "+
 		"JOIN artists ON albums.artist_id = artists.id WHERE albums.artist_id = ?;", ID)
 }
 
-// SearchAlbums loads a slice of all Album structs from the database which contain
-// titles that match the specified search query
-func (s *SqliteBackend) SearchAlbums(query string) ([]Album, error) {
-	return s.albumQuery("SELECT albums.*,artists.title AS artist FROM albums "+
-		"JOIN artists ON albums.artist_id = artists.id WHERE albums.title LIKE ?;", "%"+query+"%")
+// GalleryForAlbum loads a slice of all Art structs in the gallery of the album with the
+// matching ID, ordered by their position in the gallery
+func (s *SqliteBackend) GalleryForAlbum(ID int) ([]Art, error) {
+	return s.artQuery("SELECT art.* FROM album_gallery JOIN art ON album_gallery.art_id = art.id "+
+		"WHERE album_gallery.album_id = ? ORDER BY album_gallery.position;", ID)
+}
+
+// SetAlbumGallery replaces the gallery of the album with the matching ID with the art with the
+// input IDs, in order
+func (s *SqliteBackend) SetAlbumGallery(ID int, artIDs []int) error {
+	// Remove the existing gallery
+	tx := s.db.MustBegin()
+	tx.Exec("DELETE FROM album_gallery WHERE album_id = ?;", ID)
+
+	// Insert all art in order
+	query := "INSERT INTO album_gallery (`album_id`, `art_id`, `position`) VALUES (?, ?, ?);"
+	for i, artID := range artIDs {
+		tx.Exec(query, ID, artID, i)
+	}
+
+	return tx.Commit()
+}
+
+// SearchAlbums loads a slice of Album structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  Album titles are weighted above
+// artist titles.  The total number of matching albums is also returned.
+func (s *SqliteBackend) SearchAlbums(q Query, offset int, count int) ([]Album, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Album, 0), 0, nil
+	}
+
+	// Count all matching albums
+	clause, order, args := sqliteSearch(q, "albums", "albums JOIN artists ON albums.artist_id = artists.id",
+		"bm25(albums_search, 10.0, 5.0)", "album_id")
+	total, err := s.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	albums, err := s.albumQuery("SELECT "+albumColumns+" FROM "+clause+" ORDER BY "+order+" LIMIT ?, ?;",
+		append(args, offset, count)...)
+	return albums, total, err
 }
 
 // CountAlbums fetches the total number of Album structs from the database
@@ -344,8 +505,9 @@ func (s *SqliteBackend) PurgeOrphanAlbums() (int, error) {
 			return -1, err
 		}
 
-		// Remove album
+		// Remove album, and its gallery
 		tx.Exec("DELETE FROM albums WHERE id = ?;", album.ID)
+		tx.Exec("DELETE FROM album_gallery WHERE album_id = ?;", album.ID)
 		total++
 	}
 
@@ -362,11 +524,14 @@ func (s *SqliteBackend) DeleteAlbum(a *Album) error {
 	// Attempt to delete this album by its ID, if available
 	tx := s.db.MustBegin()
 	if a.ID != 0 {
+		tx.Exec("DELETE FROM album_gallery WHERE album_id = ?;", a.ID)
 		tx.Exec("DELETE FROM albums WHERE id = ?;", a.ID)
 		return tx.Commit()
 	}
 
 	// Else, attempt to remove the album by its artist ID and title
+	tx.Exec("DELETE FROM album_gallery WHERE album_id IN "+
+		"(SELECT id FROM albums WHERE artist_id = ? AND title = ?);", a.ArtistID, a.Title)
 	tx.Exec("DELETE FROM albums WHERE artist_id = ? AND title = ?;", a.ArtistID, a.Title)
 	return tx.Commit()
 }
@@ -375,7 +540,7 @@ func (s *SqliteBackend) DeleteAlbum(a *Album) error {
 func (s *SqliteBackend) LoadAlbum(a *Album) error {
 	// Load the album via ID if available
 	if a.ID != 0 {
-		if err := s.db.Get(a, "SELECT albums.*,artists.title AS artist FROM albums "+
+		if err := s.db.Get(a, "SELECT "+albumColumns+" FROM albums "+
 			"JOIN artists ON albums.artist_id = artists.id WHERE albums.id = ?;", a.ID); err != nil {
 			return err
 		}
@@ -384,7 +549,7 @@ func (s *SqliteBackend) LoadAlbum(a *Album) error {
 	}
 
 	// Load via artist ID and title
-	if err := s.db.Get(a, "SELECT albums.*,artists.title AS artist FROM albums "+
+	if err := s.db.Get(a, "SELECT "+albumColumns+" FROM albums "+
 		"JOIN artists ON albums.artist_id = artists.id WHERE albums.artist_id = ? AND albums.title = ?;", a.ArtistID, a.Title); err != nil {
 		return err
 	}
@@ -395,9 +560,9 @@ func (s *SqliteBackend) LoadAlbum(a *Album) error {
 // SaveAlbum attempts to save an Album to the database
 func (s *SqliteBackend) SaveAlbum(a *Album) error {
 	// Insert new album
-	query := "INSERT INTO albums (`artist_id`, `title`, `year`) VALUES (?, ?, ?);"
+	query := "INSERT INTO albums (`art_id`, `artist_id`, `title`, `year`) VALUES (?, ?, ?, ?);"
 	tx := s.db.MustBegin()
-	tx.Exec(query, a.ArtistID, a.Title, a.Year)
+	tx.Exec(query, a.ArtID, a.ArtistID, a.Title, a.Year)
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
@@ -414,6 +579,15 @@ func (s *SqliteBackend) SaveAlbum(a *Album) error {
 	return nil
 }
 
+// UpdateAlbum attempts to update an Album in the database
+func (s *SqliteBackend) UpdateAlbum(a *Album) error {
+	// Attempt to update this album by its ID
+	query := "UPDATE albums SET `art_id` = ?, `year` = ? WHERE id = ?;"
+	tx := s.db.MustBegin()
+	tx.Exec(query, a.ArtID, a.Year, a.ID)
+	return tx.Commit()
+}
+
 // AllFolders loads a slice of all Folder structs from the database
 func (s *SqliteBackend) AllFolders() ([]Folder, error) {
 	return s.folderQuery("SELECT * FROM folders;")
@@ -422,7 +596,7 @@ func (s *SqliteBackend) AllFolders() ([]Folder, error) {
 // LimitFolders loads a slice of Folder structs from the database using SQL limit, where the first parameter
 // specifies an offset and the second specifies an item count
 func (s *SqliteBackend) LimitFolders(offset int, count int) ([]Folder, error) {
-	return s.folderQuery("SELECT * FROM folders LIMIT ?, ?;", offset, count)
+	return s.folderQuery("SELECT * FROM folders ORDER BY folders.id LIMIT ?, ?;", offset, count)
 }
 
 // Subfolders loads a slice of all Folder structs residing directly beneath this one from the database
@@ -435,15 +609,31 @@ func (s *SqliteBackend) FoldersInPath(path string) ([]Folder, error) {
 	return s.folderQuery("SELECT * FROM folders WHERE path LIKE ?;", path+"%")
 }
 
-// FoldersNotInPath loads a slice of all Folder structs NOT contained within the specified file path
-func (s *SqliteBackend) FoldersNotInPath(path string) ([]Folder, error) {
-	return s.folderQuery("SELECT * FROM folders WHERE path NOT LIKE ?;", path+"%")
+// FoldersNotInPath loads a slice of all Folder structs belonging to the specified library, or to
+// no library, which are NOT contained within the specified file path
+func (s *SqliteBackend) FoldersNotInPath(libraryID int, path string) ([]Folder, error) {
+	return s.folderQuery("SELECT * FROM folders WHERE library_id IN (0, ?) AND path NOT LIKE ?;", libraryID, path+"%")
 }
 
-// SearchFolders loads a slice of all Folder structs from the database which contain
-// titles that match the specified search query
-func (s *SqliteBackend) SearchFolders(query string) ([]Folder, error) {
-	return s.folderQuery("SELECT * FROM folders WHERE title LIKE ?;", "%"+query+"%")
+// SearchFolders loads a slice of Folder structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  The total number of matching
+// folders is also returned.
+func (s *SqliteBackend) SearchFolders(q Query, offset int, count int) ([]Folder, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Folder, 0), 0, nil
+	}
+
+	// Count all matching folders
+	clause, order, args := sqliteSearch(q, "folders", "folders", "rank", "folder_id")
+	total, err := s.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	folders, err := s.folderQuery("SELECT folders.* FROM "+clause+" ORDER BY "+order+" LIMIT ?, ?;",
+		append(args, offset, count)...)
+	return folders, total, err
 }
 
 // CountFolders fetches the total number of Folder structs from the database
@@ -487,9 +677,9 @@ func (s *SqliteBackend) LoadFolder(f *Folder) error {
 // SaveFolder attempts to save an Folder to the database
 func (s *SqliteBackend) SaveFolder(f *Folder) error {
 	// Insert new folder
-	query := "INSERT INTO folders (`parent_id`, `title`, `path`) VALUES (?, ?, ?);"
+	query := "INSERT INTO folders (`parent_id`, `title`, `path`, `library_id`) VALUES (?, ?, ?, ?);"
 	tx := s.db.MustBegin()
-	tx.Exec(query, f.ParentID, f.Title, f.Path)
+	tx.Exec(query, f.ParentID, f.Title, f.Path, f.LibraryID)
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
@@ -506,123 +696,63 @@ func (s *SqliteBackend) SaveFolder(f *Folder) error {
 	return nil
 }
 
-// AllSongs loads a slice of all Song structs from the database
-func (s *SqliteBackend) AllSongs() ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs " +
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id;")
-}
-
-// LimitSongs loads a slice of Song structs from the database using SQL limit, where the first parameter
-// specifies an offset and the second specifies an item count
-func (s *SqliteBackend) LimitSongs(offset int, count int) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"LIMIT ?, ?;", offset, count)
-}
-
-// RandomSongs loads a slice of 'n' random song structs from the database
-func (s *SqliteBackend) RandomSongs(n int) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"ORDER BY RANDOM() LIMIT ?;", n)
-}
-
-// SearchSongs loads a slice of all Song structs from the database which contain
-// titles that match the specified search query
-func (s *SqliteBackend) SearchSongs(query string) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.title LIKE ?;", "%"+query+"%")
-}
-
-// SongsForAlbum loads a slice of all Song structs which have the matching album ID
-func (s *SqliteBackend) SongsForAlbum(ID int) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.album_id = ?;", ID)
-}
-
-// SongsForArtist loads a slice of all Song structs which have the matching artist ID
-func (s *SqliteBackend) SongsForArtist(ID int) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.artist_id = ?;", ID)
-}
-
-// SongsForFolder loads a slice of all Song structs which have the matching folder ID
-func (s *SqliteBackend) SongsForFolder(ID int) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.folder_id = ?;", ID)
-}
-
-// SongsInPath loads a slice of all Song structs residing under the specified
-// filesystem path from the database
-func (s *SqliteBackend) SongsInPath(path string) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.file_name LIKE ?;", path+"%")
+// UpdateFolder attempts to update a Folder in the database
+func (s *SqliteBackend) UpdateFolder(f *Folder) error {
+	// Update existing folder
+	tx := s.db.MustBegin()
+	tx.Exec("UPDATE folders SET `parent_id` = ?, `title` = ?, `path` = ?, `library_id` = ? WHERE id = ?;",
+		f.ParentID, f.Title, f.Path, f.LibraryID, f.ID)
+	return tx.Commit()
 }
 
-// SongsNotInPath loads a slice of all Song structs that do not reside under the specified
-// filesystem path from the database
-func (s *SqliteBackend) SongsNotInPath(path string) ([]Song, error) {
-	return s.songQuery("SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.file_name NOT LIKE ?;", path+"%")
+// AllLibraries loads a slice of all Library structs from the database, ordered by name
+func (s *SqliteBackend) AllLibraries() ([]Library, error) {
+	return s.libraryQuery("SELECT * FROM libraries ORDER BY name;")
 }
 
-// CountSongs fetches the total number of Artist structs from the database
-func (s *SqliteBackend) CountSongs() (int64, error) {
-	return s.integerQuery("SELECT COUNT(*) AS int FROM songs;")
-}
+// DeleteLibrary removes a Library from the database, releasing all media indexed within it
+func (s *SqliteBackend) DeleteLibrary(l *Library) error {
+	// Delete this library by its ID if available, or else by its name
+	where, arg := "id = ?", interface{}(l.ID)
+	if l.ID == 0 {
+		where, arg = "name = ?", l.Name
+	}
 
-// DeleteSong removes a Song from the database
-func (s *SqliteBackend) DeleteSong(a *Song) error {
-	// Attempt to delete this song by its ID, if available
+	// Release this library's media, so that another library may claim it
 	tx := s.db.MustBegin()
-	if a.ID != 0 {
-		tx.Exec("DELETE FROM songs WHERE id = ?;", a.ID)
-		return tx.Commit()
+	for _, table := range []string{"art", "folders", "songs"} {
+		tx.Exec("UPDATE "+table+" SET library_id = 0 WHERE library_id = (SELECT id FROM libraries WHERE "+where+");", arg)
 	}
 
-	// Else, attempt to remove the song by its file name
-	tx.Exec("DELETE FROM songs WHERE file_name = ?;", a.FileName)
+	tx.Exec("DELETE FROM libraries WHERE "+where+";", arg)
 	return tx.Commit()
 }
 
-// LoadSong loads a Song from the database, populating the parameter struct
-func (s *SqliteBackend) LoadSong(a *Song) error {
-	// Load the song via ID if available
-	if a.ID != 0 {
-		if err := s.db.Get(a, "SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-			"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-			"WHERE songs.id = ?;", a.ID); err != nil {
+// LoadLibrary loads a Library from the database, populating the parameter struct
+func (s *SqliteBackend) LoadLibrary(l *Library) error {
+	// Load the library via ID if available
+	if l.ID != 0 {
+		if err := s.db.Get(l, "SELECT * FROM libraries WHERE id = ?;", l.ID); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	// Load via file name
-	if err := s.db.Get(a, "SELECT songs.*,artists.title AS artist,albums.title AS album FROM songs "+
-		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
-		"WHERE songs.file_name = ?;", a.FileName); err != nil {
+	// Load via name
+	if err := s.db.Get(l, "SELECT * FROM libraries WHERE name = ?;", l.Name); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// SaveSong attempts to save a Song to the database
-func (s *SqliteBackend) SaveSong(a *Song) error {
-	// Insert new song
-	query := "INSERT INTO songs (`album_id`, `art_id`, `artist_id`, `bitrate`, `channels`, `comment`, `file_name`, " +
-		"`file_size`, `file_type_id`, `folder_id`, `genre`, `last_modified`, `length`, `sample_rate`, `title`, `track`, `year`) " +
-		" VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+// SaveLibrary attempts to save a Library to the database, claiming any unowned media
+// under its path
+func (s *SqliteBackend) SaveLibrary(l *Library) error {
+	// Insert new library
 	tx := s.db.MustBegin()
-	tx.Exec(query, a.AlbumID, a.ArtID, a.ArtistID, a.Bitrate, a.Channels, a.Comment, a.FileName, a.FileSize, a.FileTypeID,
-		a.FolderID, a.Genre, a.LastModified, a.Length, a.SampleRate, a.Title, a.Track, a.Year)
+	tx.Exec("INSERT INTO libraries (`name`, `path`) VALUES (?, ?);", l.Name, l.Path)
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
@@ -630,73 +760,82 @@ func (s *SqliteBackend) SaveSong(a *Song) error {
 	}
 
 	// If no ID, reload to grab it
-	if a.ID == 0 {
-		if err := s.LoadSong(a); err != nil {
+	if l.ID == 0 {
+		if err := s.LoadLibrary(l); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return s.claimLibrary(l)
 }
 
-// UpdateSong attempts to update a Song in the database
-func (s *SqliteBackend) UpdateSong(a *Song) error {
-	// Update existing song
-	query := "UPDATE songs SET `album_id` = ?, `art_id` = ?, `artist_id` = ?, `bitrate` = ?, `channels` = ?, `comment` = ?, " +
-		"`file_size` = ?, `folder_id` = ?,  `genre` = ?, `last_modified` = ?, `length` = ?, `sample_rate` = ?, " +
-		"`title` = ?, `track` = ?, `year` = ? WHERE `id` = ?;"
+// UpdateLibrary updates a Library in the database, claiming any unowned media under its path
+func (s *SqliteBackend) UpdateLibrary(l *Library) error {
+	// Update existing library
 	tx := s.db.MustBegin()
-	tx.Exec(query, a.AlbumID, a.ArtID, a.ArtistID, a.Bitrate, a.Channels, a.Comment, a.FileSize,
-		a.FolderID, a.Genre, a.LastModified, a.Length, a.SampleRate, a.Title, a.Track, a.Year, a.ID)
+	tx.Exec("UPDATE libraries SET `name` = ?, `path` = ? WHERE id = ?;", l.Name, l.Path, l.ID)
 
 	// Commit transaction
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return s.claimLibrary(l)
 }
 
-// AllUsers loads a slice of all User structs from the database
-func (s *SqliteBackend) AllUsers() ([]User, error) {
-	return s.userQuery("SELECT * FROM users;")
+// PlaysForUser loads a slice of all Play structs which belong to the specified user ID,
+// ordered from most to least recent
+func (s *SqliteBackend) PlaysForUser(userID int) ([]Play, error) {
+	return s.playQuery("SELECT * FROM plays WHERE user_id = ? ORDER BY timestamp DESC, id DESC;", userID)
 }
 
-// DeleteUser removes a User from the database
-func (s *SqliteBackend) DeleteUser(u *User) error {
-	// Attempt to delete this user by its ID, if available
+// LimitPlaysForUser loads a slice of Play structs which belong to the specified user ID,
+// ordered from most to least recent, with offset and limit
+func (s *SqliteBackend) LimitPlaysForUser(userID int, offset int, count int) ([]Play, error) {
+	return s.playQuery("SELECT * FROM plays WHERE user_id = ? ORDER BY timestamp DESC, id DESC LIMIT ?, ?;",
+		userID, offset, count)
+}
+
+// DeletePlay removes a Play from the database
+func (s *SqliteBackend) DeletePlay(p *Play) error {
+	// Attempt to delete this play by its ID, if available
 	tx := s.db.MustBegin()
-	if u.ID != 0 {
-		tx.Exec("DELETE FROM users WHERE id = ?;", u.ID)
+	if p.ID != 0 {
+		tx.Exec("DELETE FROM plays WHERE id = ?;", p.ID)
 		return tx.Commit()
 	}
 
-	// Else, attempt to remove the user by its username
-	tx.Exec("DELETE FROM users WHERE username = ?;", u.Username)
+	// Else, attempt to remove the play by its user ID, song ID, and timestamp
+	tx.Exec("DELETE FROM plays WHERE user_id = ? AND song_id = ? AND timestamp = ?;", p.UserID, p.SongID, p.Timestamp)
 	return tx.Commit()
 }
 
-// LoadUser loads a User from the database, populating the parameter struct
-func (s *SqliteBackend) LoadUser(u *User) error {
-	// Load the user via ID if available
-	if u.ID != 0 {
-		if err := s.db.Get(u, "SELECT * FROM users WHERE id = ?;", u.ID); err != nil {
+// LoadPlay loads a Play from the database, populating the parameter struct
+func (s *SqliteBackend) LoadPlay(p *Play) error {
+	// Load the play via ID if available
+	if p.ID != 0 {
+		if err := s.db.Get(p, "SELECT * FROM plays WHERE id = ?;", p.ID); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	// Load via username
-	if err := s.db.Get(u, "SELECT * FROM users WHERE username = ?;", u.Username); err != nil {
+	// Load via user ID, song ID, and timestamp
+	if err := s.db.Get(p, "SELECT * FROM plays WHERE user_id = ? AND song_id = ? AND timestamp = ?;",
+		p.UserID, p.SongID, p.Timestamp); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// SaveUser attempts to save a User to the database
-func (s *SqliteBackend) SaveUser(u *User) error {
-	// Insert new user
-	query := "INSERT INTO users (`username`, `password`, `role_id`, `lastfm_token`) VALUES (?, ?, ?, ?);"
+// SavePlay attempts to save a Play to the database
+func (s *SqliteBackend) SavePlay(p *Play) error {
+	// Insert new play
+	query := "INSERT INTO plays (`user_id`, `song_id`, `timestamp`) VALUES (?, ?, ?);"
 	tx := s.db.MustBegin()
-	tx.Exec(query, u.Username, u.Password, u.RoleID, u.LastFMToken)
+	tx.Exec(query, p.UserID, p.SongID, p.Timestamp)
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
@@ -704,8 +843,8 @@ func (s *SqliteBackend) SaveUser(u *User) error {
 	}
 
 	// If no ID, reload to grab it
-	if u.ID == 0 {
-		if err := s.LoadUser(u); err != nil {
+	if p.ID == 0 {
+		if err := s.LoadPlay(p); err != nil {
 			return err
 		}
 	}
@@ -713,66 +852,54 @@ func (s *SqliteBackend) SaveUser(u *User) error {
 	return nil
 }
 
-// UpdateUser updates a User in the database
-func (s *SqliteBackend) UpdateUser(u *User) error {
-	// Attempt to update this user by its ID, if available
-	tx := s.db.MustBegin()
-	if u.ID != 0 {
-		tx.Exec("UPDATE users SET `username` = ?, `password` = ?, `role_id` = ?, `lastfm_token` = ? WHERE id = ?;",
-			u.Username, u.Password, u.RoleID, u.LastFMToken, u.ID)
-		return tx.Commit()
-	}
-
-	// Else, attempt to update the user by its username
-	tx.Exec("UPDATE users SET `password` = ?, `role_id` = ?, `lastfm_token` = ? WHERE username = ?;",
-		u.Password, u.RoleID, u.LastFMToken, u.Username)
-	return tx.Commit()
-}
-
-// SessionsForUser loads a slice of all Sessions for a given User from the database
-func (s *SqliteBackend) SessionsForUser(userID int) ([]Session, error) {
-	return s.sessionQuery("SELECT * FROM sessions WHERE user_id = ?", userID)
+// PlaylistsForUser loads a slice of all Playlist structs which are owned by the specified
+// user ID, as well as all public playlists owned by other users
+func (s *SqliteBackend) PlaylistsForUser(userID int) ([]Playlist, error) {
+	return s.playlistQuery("SELECT * FROM playlists WHERE user_id = ? OR public = 1 ORDER BY title;", userID)
 }
 
-// DeleteSession removes a Session from the database
-func (s *SqliteBackend) DeleteSession(u *Session) error {
-	// Attempt to delete this session by its ID, if available
+// DeletePlaylist removes a Playlist and all of its entries from the database
+func (s *SqliteBackend) DeletePlaylist(p *Playlist) error {
+	// Attempt to delete this playlist by its ID, if available
 	tx := s.db.MustBegin()
-	if u.ID != 0 {
-		tx.Exec("DELETE FROM sessions WHERE id = ?;", u.ID)
+	if p.ID != 0 {
+		tx.Exec("DELETE FROM playlist_entries WHERE playlist_id = ?;", p.ID)
+		tx.Exec("DELETE FROM playlists WHERE id = ?;", p.ID)
 		return tx.Commit()
 	}
 
-	// Else, attempt to remove the session by its key
-	tx.Exec("DELETE FROM sessions WHERE key = ?;", u.Key)
+	// Else, attempt to remove the playlist by its user ID and title
+	tx.Exec("DELETE FROM playlist_entries WHERE playlist_id = (SELECT id FROM playlists WHERE user_id = ? AND title = ?);",
+		p.UserID, p.Title)
+	tx.Exec("DELETE FROM playlists WHERE user_id = ? AND title = ?;", p.UserID, p.Title)
 	return tx.Commit()
 }
 
-// LoadSession loads a Session from the database, populating the parameter struct
-func (s *SqliteBackend) LoadSession(u *Session) error {
-	// Load the session via ID if available
-	if u.ID != 0 {
-		if err := s.db.Get(u, "SELECT * FROM sessions WHERE id = ?;", u.ID); err != nil {
+// LoadPlaylist loads a Playlist from the database, populating the parameter struct
+func (s *SqliteBackend) LoadPlaylist(p *Playlist) error {
+	// Load the playlist via ID if available
+	if p.ID != 0 {
+		if err := s.db.Get(p, "SELECT * FROM playlists WHERE id = ?;", p.ID); err != nil {
 			return err
 		}
 
 		return nil
 	}
 
-	// Load via key
-	if err := s.db.Get(u, "SELECT * FROM sessions WHERE key = ?;", u.Key); err != nil {
+	// Load via user ID and title
+	if err := s.db.Get(p, "SELECT * FROM playlists WHERE user_id = ? AND title = ?;", p.UserID, p.Title); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// SaveSession attempts to save a Session to the database
-func (s *SqliteBackend) SaveSession(u *Session) error {
-	// Insert new session
-	query := "INSERT INTO sessions (`user_id`, `client`, `expire`, `key`) VALUES (?, ?, ?, ?);"
+// SavePlaylist attempts to save a Playlist to the database
+func (s *SqliteBackend) SavePlaylist(p *Playlist) error {
+	// Insert new playlist
+	query := "INSERT INTO playlists (`user_id`, `title`, `public`, `created`) VALUES (?, ?, ?, ?);"
 	tx := s.db.MustBegin()
-	tx.Exec(query, u.UserID, u.Client, u.Expire, u.Key)
+	tx.Exec(query, p.UserID, p.Title, p.Public, p.Created)
 
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
@@ -780,8 +907,8 @@ func (s *SqliteBackend) SaveSession(u *Session) error {
 	}
 
 	// If no ID, reload to grab it
-	if u.ID == 0 {
-		if err := s.LoadSession(u); err != nil {
+	if p.ID == 0 {
+		if err := s.LoadPlaylist(p); err != nil {
 			return err
 		}
 	}
@@ -789,55 +916,742 @@ func (s *SqliteBackend) SaveSession(u *Session) error {
 	return nil
 }
 
-// UpdateSession updates a Session in the database
-func (s *SqliteBackend) UpdateSession(u *Session) error {
-	// Attempt to update this session by its ID, if available
+// UpdatePlaylist updates a Playlist in the database
+func (s *SqliteBackend) UpdatePlaylist(p *Playlist) error {
+	// Update existing playlist
 	tx := s.db.MustBegin()
-	if u.ID != 0 {
-		tx.Exec("UPDATE sessions SET `expire` = ? WHERE id = ?;", u.Expire, u.ID)
-		return tx.Commit()
-	}
-
-	// Else, attempt to update the session by its key
-	tx.Exec("UPDATE sessions SET `expire` = ? WHERE key = ?;", u.Expire, u.Key)
+	tx.Exec("UPDATE playlists SET `title` = ?, `public` = ? WHERE id = ?;", p.Title, p.Public, p.ID)
 	return tx.Commit()
 }
 
-// albumQuery loads a slice of Album structs matching the input query
-func (s *SqliteBackend) albumQuery(query string, args ...interface{}) ([]Album, error) {
-	// Perform input query with arguments
-	rows, err := s.db.Queryx(query, args...)
-	if err != nil && err != sql.ErrNoRows {
-		return nil, err
-	}
-	defer rows.Close()
+// EntriesForPlaylist loads a slice of all PlaylistEntry structs which have the matching
+// playlist ID, ordered by their position in the playlist
+func (s *SqliteBackend) EntriesForPlaylist(ID int) ([]PlaylistEntry, error) {
+	return s.playlistEntryQuery("SELECT * FROM playlist_entries WHERE playlist_id = ? ORDER BY position;", ID)
+}
 
-	// Iterate all rows
-	albums := make([]Album, 0)
-	a := Album{}
-	for rows.Next() {
-		// Scan album into struct
-		if err := rows.StructScan(&a); err != nil {
-			return nil, err
-		}
+// SongsForPlaylist loads a slice of all Song structs contained in the playlist with the
+// matching ID, ordered by their position in the playlist
+func (s *SqliteBackend) SongsForPlaylist(ID int) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM playlist_entries "+
+		"JOIN songs ON playlist_entries.song_id = songs.id JOIN artists ON songs.artist_id = artists.id "+
+		"JOIN albums ON songs.album_id = albums.id WHERE playlist_entries.playlist_id = ? "+
+		"ORDER BY playlist_entries.position;", ID)
+}
 
-		// Append to list
-		albums = append(albums, a)
+// AppendPlaylistEntries adds the songs with the input IDs to the end of the playlist with
+// the matching ID
+func (s *SqliteBackend) AppendPlaylistEntries(ID int, songIDs []int) error {
+	// Find the next free position in the playlist
+	position, err := s.integerQuery("SELECT COUNT(*) AS int FROM playlist_entries WHERE playlist_id = ?;", ID)
+	if err != nil {
+		return err
 	}
 
-	// Error check rows
-	if err := rows.Err(); err != nil {
-		return nil, err
+	// Insert all songs in order, after the last entry
+	query := "INSERT INTO playlist_entries (`playlist_id`, `song_id`, `position`) VALUES (?, ?, ?);"
+	tx := s.db.MustBegin()
+	for _, songID := range songIDs {
+		tx.Exec(query, ID, songID, position)
+		position++
 	}
 
-	return albums, nil
+	return tx.Commit()
 }
 
-// artQuery loads a slice of Art structs matching the input query
-func (s *SqliteBackend) artQuery(query string, args ...interface{}) ([]Art, error) {
-	// Perform input query with arguments
-	rows, err := s.db.Queryx(query, args...)
-	if err != nil && err != sql.ErrNoRows {
+// RemovePlaylistEntries removes the entries with the input IDs from the playlist with the
+// matching ID, and renumbers the remaining entries so their positions remain contiguous
+func (s *SqliteBackend) RemovePlaylistEntries(ID int, entryIDs []int) error {
+	// Remove all specified entries
+	tx := s.db.MustBegin()
+	for _, entryID := range entryIDs {
+		tx.Exec("DELETE FROM playlist_entries WHERE id = ? AND playlist_id = ?;", entryID, ID)
+	}
+
+	// Close any gaps left by removed entries
+	if err := s.renumberPlaylistEntries(tx, ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// renumberPlaylistEntries renumbers the entries of the playlist with the matching ID within
+// the input transaction, so their positions remain contiguous
+func (s *SqliteBackend) renumberPlaylistEntries(tx *sqlx.Tx, ID int) error {
+	// Fetch remaining entries in their current order
+	entries := make([]PlaylistEntry, 0)
+	if err := tx.Select(&entries, "SELECT * FROM playlist_entries WHERE playlist_id = ? ORDER BY position;", ID); err != nil {
+		return err
+	}
+
+	for i, e := range entries {
+		tx.Exec("UPDATE playlist_entries SET `position` = ? WHERE id = ?;", i, e.ID)
+	}
+
+	return nil
+}
+
+// ReorderPlaylistEntries rearranges the entries of the playlist with the matching ID, so that
+// they appear in the order of the input entry IDs.  All entries in the playlist must be specified.
+func (s *SqliteBackend) ReorderPlaylistEntries(ID int, entryIDs []int) error {
+	// Fetch the current entries, to verify the new order is complete
+	entries, err := s.EntriesForPlaylist(ID)
+	if err != nil {
+		return err
+	}
+
+	// Verify that every entry appears exactly once in the new order
+	if !samePlaylistEntries(entries, entryIDs) {
+		return ErrPlaylistEntries
+	}
+
+	// Renumber all entries using their index in the new order
+	tx := s.db.MustBegin()
+	for i, entryID := range entryIDs {
+		tx.Exec("UPDATE playlist_entries SET `position` = ? WHERE id = ? AND playlist_id = ?;", i, entryID, ID)
+	}
+
+	return tx.Commit()
+}
+
+// RatingsForUser loads a slice of all Rating structs which belong to the specified user ID,
+// ordered by item type and ID
+func (s *SqliteBackend) RatingsForUser(userID int) ([]Rating, error) {
+	return s.ratingQuery("SELECT * FROM ratings WHERE user_id = ? ORDER BY item_type, item_id;", userID)
+}
+
+// DeleteRating removes a Rating from the database
+func (s *SqliteBackend) DeleteRating(a *Rating) error {
+	// Attempt to delete this rating by its ID, if available
+	tx := s.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("DELETE FROM ratings WHERE id = ?;", a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the rating by its user ID, item type, and item ID
+	tx.Exec("DELETE FROM ratings WHERE user_id = ? AND item_type = ? AND item_id = ?;", a.UserID, a.ItemType, a.ItemID)
+	return tx.Commit()
+}
+
+// LoadRating loads a Rating from the database, populating the parameter struct
+func (s *SqliteBackend) LoadRating(a *Rating) error {
+	// Load the rating via ID if available
+	if a.ID != 0 {
+		if err := s.db.Get(a, "SELECT * FROM ratings WHERE id = ?;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID, item type, and item ID
+	if err := s.db.Get(a, "SELECT * FROM ratings WHERE user_id = ? AND item_type = ? AND item_id = ?;",
+		a.UserID, a.ItemType, a.ItemID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveRating attempts to save a Rating to the database
+func (s *SqliteBackend) SaveRating(a *Rating) error {
+	// Insert new rating
+	query := "INSERT INTO ratings (`user_id`, `item_type`, `item_id`, `rating`) VALUES (?, ?, ?, ?);"
+	tx := s.db.MustBegin()
+	tx.Exec(query, a.UserID, a.ItemType, a.ItemID, a.Rating)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := s.LoadRating(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateRating updates a Rating in the database
+func (s *SqliteBackend) UpdateRating(a *Rating) error {
+	// Attempt to update this rating by its ID, if available
+	tx := s.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("UPDATE ratings SET `rating` = ? WHERE id = ?;", a.Rating, a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to update the rating by its user ID, item type, and item ID
+	tx.Exec("UPDATE ratings SET `rating` = ? WHERE user_id = ? AND item_type = ? AND item_id = ?;",
+		a.Rating, a.UserID, a.ItemType, a.ItemID)
+	return tx.Commit()
+}
+
+// SmartPlaylistsForUser loads a slice of all SmartPlaylist structs which are owned by the
+// specified user ID, as well as all public smart playlists owned by other users
+func (s *SqliteBackend) SmartPlaylistsForUser(userID int) ([]SmartPlaylist, error) {
+	return s.smartPlaylistQuery("SELECT * FROM smart_playlists WHERE user_id = ? OR public = 1 ORDER BY title;", userID)
+}
+
+// DeleteSmartPlaylist removes a SmartPlaylist from the database
+func (s *SqliteBackend) DeleteSmartPlaylist(p *SmartPlaylist) error {
+	// Attempt to delete this smart playlist by its ID, if available
+	tx := s.db.MustBegin()
+	if p.ID != 0 {
+		tx.Exec("DELETE FROM smart_playlists WHERE id = ?;", p.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the smart playlist by its user ID and title
+	tx.Exec("DELETE FROM smart_playlists WHERE user_id = ? AND title = ?;", p.UserID, p.Title)
+	return tx.Commit()
+}
+
+// LoadSmartPlaylist loads a SmartPlaylist from the database, populating the parameter struct
+func (s *SqliteBackend) LoadSmartPlaylist(p *SmartPlaylist) error {
+	// Load the smart playlist via ID if available
+	if p.ID != 0 {
+		if err := s.db.Get(p, "SELECT * FROM smart_playlists WHERE id = ?;", p.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID and title
+	if err := s.db.Get(p, "SELECT * FROM smart_playlists WHERE user_id = ? AND title = ?;", p.UserID, p.Title); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSmartPlaylist attempts to save a SmartPlaylist to the database
+func (s *SqliteBackend) SaveSmartPlaylist(p *SmartPlaylist) error {
+	// Insert new smart playlist
+	query := "INSERT INTO smart_playlists (`user_id`, `title`, `public`, `query`, `sort`, `song_limit`, `created`) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?);"
+	tx := s.db.MustBegin()
+	tx.Exec(query, p.UserID, p.Title, p.Public, p.Query, p.Sort, p.Limit, p.Created)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if p.ID == 0 {
+		if err := s.LoadSmartPlaylist(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSmartPlaylist updates a SmartPlaylist in the database
+func (s *SqliteBackend) UpdateSmartPlaylist(p *SmartPlaylist) error {
+	// Update existing smart playlist
+	tx := s.db.MustBegin()
+	tx.Exec("UPDATE smart_playlists SET `title` = ?, `public` = ?, `query` = ?, `sort` = ?, `song_limit` = ? WHERE id = ?;",
+		p.Title, p.Public, p.Query, p.Sort, p.Limit, p.ID)
+	return tx.Commit()
+}
+
+// SmartPlaylistSongs loads a slice of Song structs from the database which match the specified
+// search query, in the specified smart playlist sort order, up to the specified limit.  Unlike
+// SearchSongs, an empty query matches every song.
+func (s *SqliteBackend) SmartPlaylistSongs(q Query, sort string, limit int) ([]Song, error) {
+	clause, order, args := sqliteSmartPlaylistSearch(q, sort)
+	return s.songQuery("SELECT "+songColumns+" FROM "+clause+" ORDER BY "+order+" LIMIT ?;",
+		append(args, sqliteLimit(limit))...)
+}
+
+// CountSmartPlaylistSongs counts the songs in the database which match the specified search
+// query, in the specified smart playlist sort order, up to the specified limit.  The total
+// length of the songs in seconds is also returned.
+func (s *SqliteBackend) CountSmartPlaylistSongs(q Query, sort string, limit int) (int64, int64, error) {
+	clause, order, args := sqliteSmartPlaylistSearch(q, sort)
+
+	result := struct {
+		Count  int64 `db:"count"`
+		Length int64 `db:"length"`
+	}{}
+	err := s.db.Get(&result, "SELECT COUNT(*) AS count, COALESCE(SUM(length), 0) AS length FROM "+
+		"(SELECT songs.length FROM "+clause+" ORDER BY "+order+" LIMIT ?);", append(args, sqliteLimit(limit))...)
+	return result.Count, result.Length, err
+}
+
+// AllSongs loads a slice of all Song structs from the database
+func (s *SqliteBackend) AllSongs() ([]Song, error) {
+	return s.songQuery("SELECT " + songColumns + " FROM songs " +
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id;")
+}
+
+// LimitSongs loads a slice of Song structs from the database using SQL limit, where the first parameter
+// specifies an offset and the second specifies an item count
+func (s *SqliteBackend) LimitSongs(offset int, count int) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"ORDER BY songs.id LIMIT ?, ?;", offset, count)
+}
+
+// RandomSongs loads a slice of 'n' random song structs from the database
+func (s *SqliteBackend) RandomSongs(n int) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"ORDER BY RANDOM() LIMIT ?;", n)
+}
+
+// SearchSongs loads a slice of Song structs from the database which match the specified
+// search query, ranked by relevance, with offset and limit.  Song titles are weighted highest,
+// followed by artist and album titles, genre, and comment.  The total number of matching songs
+// is also returned.
+func (s *SqliteBackend) SearchSongs(q Query, offset int, count int) ([]Song, int64, error) {
+	// Empty queries match nothing
+	if q.Empty() {
+		return make([]Song, 0), 0, nil
+	}
+
+	// Count all matching songs
+	clause, order, args := sqliteSongSearch(q)
+	total, err := s.integerQuery("SELECT COUNT(*) AS int FROM "+clause+";", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	songs, err := s.songQuery("SELECT "+songColumns+" FROM "+clause+" ORDER BY "+order+" LIMIT ?, ?;",
+		append(args, offset, count)...)
+	return songs, total, err
+}
+
+// SongsForAlbum loads a slice of all Song structs which have the matching album ID, ordered
+// by disc number, and then by track number
+func (s *SqliteBackend) SongsForAlbum(ID int) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.album_id = ? ORDER BY songs.disc, songs.track, songs.id;", ID)
+}
+
+// SongsForArtist loads a slice of all Song structs which have the matching artist ID
+func (s *SqliteBackend) SongsForArtist(ID int) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.artist_id = ?;", ID)
+}
+
+// SongsForFolder loads a slice of all Song structs which have the matching folder ID
+func (s *SqliteBackend) SongsForFolder(ID int) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.folder_id = ?;", ID)
+}
+
+// SongsForFingerprint loads a slice of all Song structs which have the matching fingerprint
+func (s *SqliteBackend) SongsForFingerprint(fingerprint string) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.fingerprint = ?;", fingerprint)
+}
+
+// SongsInPath loads a slice of all Song structs residing under the specified
+// filesystem path from the database
+func (s *SqliteBackend) SongsInPath(path string) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.file_name LIKE ?;", path+"%")
+}
+
+// SongsNotInPath loads a slice of all Song structs belonging to the specified library, or to no
+// library, that do not reside under the specified filesystem path from the database
+func (s *SqliteBackend) SongsNotInPath(libraryID int, path string) ([]Song, error) {
+	return s.songQuery("SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.library_id IN (0, ?) AND songs.file_name NOT LIKE ?;", libraryID, path+"%")
+}
+
+// SongFilesInPath loads a slice of SongFile structs for all songs residing under the
+// specified filesystem path from the database
+func (s *SqliteBackend) SongFilesInPath(path string) ([]SongFile, error) {
+	files := make([]SongFile, 0)
+	err := s.db.Select(&files, "SELECT file_name,file_size,fingerprint,last_modified FROM songs WHERE file_name LIKE ?;", path+"%")
+	return files, err
+}
+
+// CountSongs fetches the total number of Artist structs from the database
+func (s *SqliteBackend) CountSongs() (int64, error) {
+	return s.integerQuery("SELECT COUNT(*) AS int FROM songs;")
+}
+
+// DeleteSong removes a Song from the database, along with any playlist entries for it
+func (s *SqliteBackend) DeleteSong(a *Song) error {
+	// Use this song's ID if available, else look it up by its file name and offset
+	tx := s.db.MustBegin()
+	ID := a.ID
+	if ID == 0 {
+		if err := tx.Get(&ID, "SELECT id FROM songs WHERE file_name = ? AND start_offset = ?;", a.FileName, a.StartOffset); err != nil {
+			tx.Rollback()
+			if err == sql.ErrNoRows {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	// Remove the song from any playlists which contain it, closing the gaps it leaves
+	playlistIDs := make([]int, 0)
+	if err := tx.Select(&playlistIDs, "SELECT DISTINCT playlist_id FROM playlist_entries WHERE song_id = ?;", ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	tx.Exec("DELETE FROM playlist_entries WHERE song_id = ?;", ID)
+	for _, playlistID := range playlistIDs {
+		if err := s.renumberPlaylistEntries(tx, playlistID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	tx.Exec("DELETE FROM songs WHERE id = ?;", ID)
+	return tx.Commit()
+}
+
+// LoadSong loads a Song from the database, populating the parameter struct
+func (s *SqliteBackend) LoadSong(a *Song) error {
+	// Load the song via ID if available
+	if a.ID != 0 {
+		if err := s.db.Get(a, "SELECT "+songColumns+" FROM songs "+
+			"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+			"WHERE songs.id = ?;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via file name and offset
+	if err := s.db.Get(a, "SELECT "+songColumns+" FROM songs "+
+		"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+		"WHERE songs.file_name = ? AND songs.start_offset = ?;", a.FileName, a.StartOffset); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSong attempts to save a Song to the database
+func (s *SqliteBackend) SaveSong(a *Song) error {
+	// Insert new song
+	if err := s.SaveSongs([]Song{*a}); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := s.LoadSong(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SaveSongs attempts to save a batch of Songs to the database in a single transaction.  Song IDs
+// are not loaded, so they must be reloaded by file name and offset if needed.
+func (s *SqliteBackend) SaveSongs(songs []Song) error {
+	// Insert new songs
+	query := "INSERT INTO songs (`added`, `album_id`, `art_id`, `artist_id`, `bitrate`, `channels`, `comment`, `disc`, `disc_total`, " +
+		"`end_offset`, `file_name`, `file_size`, `file_type_id`, `fingerprint`, `folder_id`, `genre`, `last_modified`, `length`, " +
+		"`library_id`, `sample_rate`, `start_offset`, `title`, `track`, `track_total`, `year`) " +
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	tx := s.db.MustBegin()
+	for _, a := range songs {
+		tx.Exec(query, a.Added, a.AlbumID, a.ArtID, a.ArtistID, a.Bitrate, a.Channels, a.Comment, a.Disc, a.DiscTotal, a.EndOffset,
+			a.FileName, a.FileSize, a.FileTypeID, a.Fingerprint, a.FolderID, a.Genre, a.LastModified, a.Length, a.LibraryID,
+			a.SampleRate, a.StartOffset, a.Title, a.Track, a.TrackTotal, a.Year)
+	}
+
+	// Commit transaction
+	return tx.Commit()
+}
+
+// UpdateSong attempts to update a Song in the database
+func (s *SqliteBackend) UpdateSong(a *Song) error {
+	return s.UpdateSongs([]Song{*a})
+}
+
+// UpdateSongs attempts to update a batch of Songs in the database in a single transaction
+func (s *SqliteBackend) UpdateSongs(songs []Song) error {
+	// Update existing songs
+	query := "UPDATE songs SET `album_id` = ?, `art_id` = ?, `artist_id` = ?, `bitrate` = ?, `channels` = ?, `comment` = ?, " +
+		"`disc` = ?, `disc_total` = ?, `end_offset` = ?, `file_size` = ?, `fingerprint` = ?, `folder_id` = ?,  `genre` = ?, " +
+		"`last_modified` = ?, `length` = ?, `library_id` = ?, `sample_rate` = ?, `title` = ?, `track` = ?, `track_total` = ?, " +
+		"`year` = ? WHERE `id` = ?;"
+	tx := s.db.MustBegin()
+	for _, a := range songs {
+		tx.Exec(query, a.AlbumID, a.ArtID, a.ArtistID, a.Bitrate, a.Channels, a.Comment, a.Disc, a.DiscTotal, a.EndOffset,
+			a.FileSize, a.Fingerprint, a.FolderID, a.Genre, a.LastModified, a.Length, a.LibraryID, a.SampleRate, a.Title, a.Track,
+			a.TrackTotal, a.Year, a.ID)
+	}
+
+	// Commit transaction
+	return tx.Commit()
+}
+
+// StarsForUser loads a slice of all Star structs which belong to the specified user ID,
+// ordered from most to least recent
+func (s *SqliteBackend) StarsForUser(userID int) ([]Star, error) {
+	return s.starQuery("SELECT * FROM stars WHERE user_id = ? ORDER BY created DESC, id DESC;", userID)
+}
+
+// DeleteStar removes a Star from the database
+func (s *SqliteBackend) DeleteStar(a *Star) error {
+	// Attempt to delete this star by its ID, if available
+	tx := s.db.MustBegin()
+	if a.ID != 0 {
+		tx.Exec("DELETE FROM stars WHERE id = ?;", a.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the star by its user ID, item type, and item ID
+	tx.Exec("DELETE FROM stars WHERE user_id = ? AND item_type = ? AND item_id = ?;", a.UserID, a.ItemType, a.ItemID)
+	return tx.Commit()
+}
+
+// LoadStar loads a Star from the database, populating the parameter struct
+func (s *SqliteBackend) LoadStar(a *Star) error {
+	// Load the star via ID if available
+	if a.ID != 0 {
+		if err := s.db.Get(a, "SELECT * FROM stars WHERE id = ?;", a.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via user ID, item type, and item ID
+	if err := s.db.Get(a, "SELECT * FROM stars WHERE user_id = ? AND item_type = ? AND item_id = ?;",
+		a.UserID, a.ItemType, a.ItemID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveStar attempts to save a Star to the database
+func (s *SqliteBackend) SaveStar(a *Star) error {
+	// Insert new star
+	query := "INSERT INTO stars (`user_id`, `item_type`, `item_id`, `created`) VALUES (?, ?, ?, ?);"
+	tx := s.db.MustBegin()
+	tx.Exec(query, a.UserID, a.ItemType, a.ItemID, a.Created)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if a.ID == 0 {
+		if err := s.LoadStar(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AllUsers loads a slice of all User structs from the database
+func (s *SqliteBackend) AllUsers() ([]User, error) {
+	return s.userQuery("SELECT * FROM users;")
+}
+
+// DeleteUser removes a User from the database
+func (s *SqliteBackend) DeleteUser(u *User) error {
+	// Attempt to delete this user by its ID, if available
+	tx := s.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("DELETE FROM users WHERE id = ?;", u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the user by its username
+	tx.Exec("DELETE FROM users WHERE username = ?;", u.Username)
+	return tx.Commit()
+}
+
+// LoadUser loads a User from the database, populating the parameter struct
+func (s *SqliteBackend) LoadUser(u *User) error {
+	// Load the user via ID if available
+	if u.ID != 0 {
+		if err := s.db.Get(u, "SELECT * FROM users WHERE id = ?;", u.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via username
+	if err := s.db.Get(u, "SELECT * FROM users WHERE username = ?;", u.Username); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveUser attempts to save a User to the database
+func (s *SqliteBackend) SaveUser(u *User) error {
+	// Insert new user
+	query := "INSERT INTO users (`username`, `password`, `role_id`, `lastfm_token`) VALUES (?, ?, ?, ?);"
+	tx := s.db.MustBegin()
+	tx.Exec(query, u.Username, u.Password, u.RoleID, u.LastFMToken)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if u.ID == 0 {
+		if err := s.LoadUser(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateUser updates a User in the database
+func (s *SqliteBackend) UpdateUser(u *User) error {
+	// Attempt to update this user by its ID, if available
+	tx := s.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("UPDATE users SET `username` = ?, `password` = ?, `role_id` = ?, `lastfm_token` = ? WHERE id = ?;",
+			u.Username, u.Password, u.RoleID, u.LastFMToken, u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to update the user by its username
+	tx.Exec("UPDATE users SET `password` = ?, `role_id` = ?, `lastfm_token` = ? WHERE username = ?;",
+		u.Password, u.RoleID, u.LastFMToken, u.Username)
+	return tx.Commit()
+}
+
+// SessionsForUser loads a slice of all Sessions for a given User from the database
+func (s *SqliteBackend) SessionsForUser(userID int) ([]Session, error) {
+	return s.sessionQuery("SELECT * FROM sessions WHERE user_id = ?", userID)
+}
+
+// DeleteSession removes a Session from the database
+func (s *SqliteBackend) DeleteSession(u *Session) error {
+	// Attempt to delete this session by its ID, if available
+	tx := s.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("DELETE FROM sessions WHERE id = ?;", u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to remove the session by its key
+	tx.Exec("DELETE FROM sessions WHERE key = ?;", u.Key)
+	return tx.Commit()
+}
+
+// LoadSession loads a Session from the database, populating the parameter struct
+func (s *SqliteBackend) LoadSession(u *Session) error {
+	// Load the session via ID if available
+	if u.ID != 0 {
+		if err := s.db.Get(u, "SELECT * FROM sessions WHERE id = ?;", u.ID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	// Load via key
+	if err := s.db.Get(u, "SELECT * FROM sessions WHERE key = ?;", u.Key); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SaveSession attempts to save a Session to the database
+func (s *SqliteBackend) SaveSession(u *Session) error {
+	// Insert new session
+	query := "INSERT INTO sessions (`user_id`, `client`, `expire`, `key`) VALUES (?, ?, ?, ?);"
+	tx := s.db.MustBegin()
+	tx.Exec(query, u.UserID, u.Client, u.Expire, u.Key)
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// If no ID, reload to grab it
+	if u.ID == 0 {
+		if err := s.LoadSession(u); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSession updates a Session in the database
+func (s *SqliteBackend) UpdateSession(u *Session) error {
+	// Attempt to update this session by its ID, if available
+	tx := s.db.MustBegin()
+	if u.ID != 0 {
+		tx.Exec("UPDATE sessions SET `expire` = ? WHERE id = ?;", u.Expire, u.ID)
+		return tx.Commit()
+	}
+
+	// Else, attempt to update the session by its key
+	tx.Exec("UPDATE sessions SET `expire` = ? WHERE key = ?;", u.Expire, u.Key)
+	return tx.Commit()
+}
+
+// albumQuery loads a slice of Album structs matching the input query
+func (s *SqliteBackend) albumQuery(query string, args ...interface{}) ([]Album, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	albums := make([]Album, 0)
+	a := Album{}
+	for rows.Next() {
+		// Scan album into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		albums = append(albums, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return albums, nil
+}
+
+// artQuery loads a slice of Art structs matching the input query
+func (s *SqliteBackend) artQuery(query string, args ...interface{}) ([]Art, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
 	defer rows.Close()
@@ -923,6 +1737,186 @@ func (s *SqliteBackend) folderQuery(query string, args ...interface{}) ([]Folder
 	return folders, nil
 }
 
+// libraryQuery loads a slice of Library structs matching the input query
+func (s *SqliteBackend) libraryQuery(query string, args ...interface{}) ([]Library, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	libraries := make([]Library, 0)
+	a := Library{}
+	for rows.Next() {
+		// Scan library into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		libraries = append(libraries, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return libraries, nil
+}
+
+// playQuery loads a slice of Play structs matching the input query
+func (s *SqliteBackend) playQuery(query string, args ...interface{}) ([]Play, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	plays := make([]Play, 0)
+	a := Play{}
+	for rows.Next() {
+		// Scan play into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		plays = append(plays, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return plays, nil
+}
+
+// playlistQuery loads a slice of Playlist structs matching the input query
+func (s *SqliteBackend) playlistQuery(query string, args ...interface{}) ([]Playlist, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	playlists := make([]Playlist, 0)
+	a := Playlist{}
+	for rows.Next() {
+		// Scan playlist into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		playlists = append(playlists, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
+// playlistEntryQuery loads a slice of PlaylistEntry structs matching the input query
+func (s *SqliteBackend) playlistEntryQuery(query string, args ...interface{}) ([]PlaylistEntry, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	entries := make([]PlaylistEntry, 0)
+	a := PlaylistEntry{}
+	for rows.Next() {
+		// Scan playlist entry into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		entries = append(entries, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ratingQuery loads a slice of Rating structs matching the input query
+func (s *SqliteBackend) ratingQuery(query string, args ...interface{}) ([]Rating, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	ratings := make([]Rating, 0)
+	a := Rating{}
+	for rows.Next() {
+		// Scan rating into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		ratings = append(ratings, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ratings, nil
+}
+
+// smartPlaylistQuery loads a slice of SmartPlaylist structs matching the input query
+func (s *SqliteBackend) smartPlaylistQuery(query string, args ...interface{}) ([]SmartPlaylist, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	playlists := make([]SmartPlaylist, 0)
+	a := SmartPlaylist{}
+	for rows.Next() {
+		// Scan smart playlist into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		playlists = append(playlists, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return playlists, nil
+}
+
 // songQuery loads a slice of Song structs matching the input query
 func (s *SqliteBackend) songQuery(query string, args ...interface{}) ([]Song, error) {
 	// Perform input query with arguments
@@ -953,6 +1947,36 @@ func (s *SqliteBackend) songQuery(query string, args ...interface{}) ([]Song, er
 	return songs, nil
 }
 
+// starQuery loads a slice of Star structs matching the input query
+func (s *SqliteBackend) starQuery(query string, args ...interface{}) ([]Star, error) {
+	// Perform input query with arguments
+	rows, err := s.db.Queryx(query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Iterate all rows
+	stars := make([]Star, 0)
+	a := Star{}
+	for rows.Next() {
+		// Scan star into struct
+		if err := rows.StructScan(&a); err != nil {
+			return nil, err
+		}
+
+		// Append to list
+		stars = append(stars, a)
+	}
+
+	// Error check rows
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stars, nil
+}
+
 // userQuery loads a slice of User structs matching the input query
 func (s *SqliteBackend) userQuery(query string, args ...interface{}) ([]User, error) {
 	// Perform input query with arguments
@@ -1013,6 +2037,84 @@ func (s *SqliteBackend) sessionQuery(query string, args ...interface{}) ([]Sessi
 	return sessions, nil
 }
 
+// claimLibrary assigns all art, folders, and songs under a library's path which do not yet
+// belong to any library to that library
+func (s *SqliteBackend) claimLibrary(l *Library) error {
+	tx := s.db.MustBegin()
+	tx.Exec("UPDATE art SET library_id = ? WHERE library_id = 0 AND file_name LIKE ?;", l.ID, l.Path+"%")
+	tx.Exec("UPDATE folders SET library_id = ? WHERE library_id = 0 AND path LIKE ?;", l.ID, l.Path+"%")
+	tx.Exec("UPDATE songs SET library_id = ? WHERE library_id = 0 AND file_name LIKE ?;", l.ID, l.Path+"%")
+	return tx.Commit()
+}
+
+// sqliteSearch builds the FROM and WHERE clauses, ORDER BY clause, and arguments which select
+// items in the specified table matching a search query.  Free text is matched using the table's
+// search index, and ranked using the rank expression.  Filters are applied to songs, and items
+// are matched using the specified songs column, or directly if the table is songs.
+func sqliteSearch(q Query, table string, from string, rank string, column string) (string, string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	order := table + ".id"
+
+	// Match free text using the search index
+	if match := ftsQuery(q.Text); match != "" {
+		from += " JOIN " + table + "_search ON " + table + "_search.rowid = " + table + ".id"
+		conditions = append(conditions, table+"_search MATCH ?")
+		args = append(args, match)
+		order = rank + ", " + order
+	}
+
+	// Match filters against songs
+	filters, filterArgs := q.sqlConditions("instr(lower({column}), lower({param})) > 0", func() string {
+		return "?"
+	})
+	if len(filters) > 0 {
+		where := strings.Join(filters, " AND ")
+		if column == "" {
+			conditions = append(conditions, where)
+		} else {
+			conditions = append(conditions, table+".id IN (SELECT songs."+column+" FROM songs "+
+				"JOIN artists ON songs.artist_id = artists.id JOIN albums ON songs.album_id = albums.id "+
+				"WHERE "+where+")")
+		}
+		args = append(args, filterArgs...)
+	}
+
+	if len(conditions) == 0 {
+		return from, order, args
+	}
+
+	return from + " WHERE " + strings.Join(conditions, " AND "), order, args
+}
+
+// sqliteSongSearch builds the clauses and arguments which select songs matching a search
+// query, ranking song titles highest, followed by artist and album titles, genre, and comment
+func sqliteSongSearch(q Query) (string, string, []interface{}) {
+	return sqliteSearch(q, "songs", "songs JOIN artists ON songs.artist_id = artists.id "+
+		"JOIN albums ON songs.album_id = albums.id", "bm25(songs_search, 10.0, 5.0, 5.0, 2.0, 1.0)", "")
+}
+
+// sqliteSmartPlaylistSearch builds the clauses and arguments which select songs matching a
+// search query, ordered first by the specified smart playlist sort order
+func sqliteSmartPlaylistSearch(q Query, sort string) (string, string, []interface{}) {
+	clause, order, args := sqliteSongSearch(q)
+	if sorted := smartPlaylistOrder(sort, "lower({column})"); sorted != "" {
+		order = sorted + ", " + order
+	}
+
+	return clause, order, args
+}
+
+// sqliteLimit converts a smart playlist limit into a sqlite LIMIT value, where zero means no
+// limit, which sqlite specifies using a negative value
+func sqliteLimit(limit int) int {
+	if limit == 0 {
+		return -1
+	}
+
+	return limit
+}
+
 // integerQuery returns a single integer value from the input query
 func (s *SqliteBackend) integerQuery(query string, args ...interface{}) (int64, error) {
 	// Perform query and fetch result
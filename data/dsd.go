@@ -0,0 +1,195 @@
+package data
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strconv"
+)
+
+// SongFromDSD creates a new Song from a DSD audio file, in either the DSF or DSDIFF format.
+// TagLib cannot read these formats, so their properties and ID3v2 tags are read by wavepipe.
+func SongFromDSD(r io.ReadSeeker) (*Song, error) {
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	}
+	if _, err := r.Seek(0, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+
+	// Read the number of channels, the sample rate, and the number of samples in each channel
+	var channels, sampleRate int
+	var samples int64
+	var err error
+	switch string(magic) {
+	case "DSD ":
+		channels, sampleRate, samples, err = dsfProperties(r)
+	case "FRM8":
+		channels, sampleRate, samples, err = dffProperties(r)
+	default:
+		return nil, ErrSongProperties
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.Seek(0, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+	tags, err := readTags(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// At minimum, we will need an artist and title to do anything useful with this file
+	values := aliasTags(tags)
+	title := values[tagTitle]
+	artist := values[tagArtist]
+	if title == "" || artist == "" {
+		return nil, ErrSongTags
+	}
+
+	if channels == 0 || sampleRate == 0 || samples/int64(sampleRate) == 0 {
+		return nil, ErrSongProperties
+	}
+
+	// Dates begin with the year
+	year := 0
+	if date := values[tagYear]; len(date) >= 4 {
+		year, _ = strconv.Atoi(date[0:4])
+	}
+
+	// DSD audio has one bit per sample
+	return &Song{
+		Album:      values[tagAlbum],
+		Artist:     artist,
+		Bitrate:    channels * sampleRate / 1000,
+		Channels:   channels,
+		Genre:      values[tagGenre],
+		Length:     int(samples / int64(sampleRate)),
+		SampleRate: sampleRate,
+		Title:      title,
+		Year:       year,
+	}, nil
+}
+
+// dsfProperties reads the number of channels, sample rate, and number of samples per channel
+// from the format chunk of a DSF file, which follows its header
+func dsfProperties(r io.Reader) (int, int, int64, error) {
+	buf := make([]byte, 28+52)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, 0, 0, err
+	}
+
+	format := buf[28:]
+	if string(format[0:4]) != "fmt " {
+		return 0, 0, 0, ErrSongProperties
+	}
+
+	channels := int(binary.LittleEndian.Uint32(format[24:28]))
+	sampleRate := int(binary.LittleEndian.Uint32(format[28:32]))
+	samples := int64(binary.LittleEndian.Uint64(format[36:44]))
+	return channels, sampleRate, samples, nil
+}
+
+// dffProperties reads the number of channels, sample rate, and number of samples per channel
+// from the property and sound data chunks of a DSDIFF file
+func dffProperties(r io.ReadSeeker) (int, int, int64, error) {
+	// Skip the form chunk's header and type
+	if _, err := r.Seek(16, os.SEEK_SET); err != nil {
+		return 0, 0, 0, err
+	}
+
+	var channels, sampleRate int
+	header := make([]byte, 12)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return 0, 0, 0, err
+		}
+
+		size := int64(binary.BigEndian.Uint64(header[4:12]))
+		switch string(header[0:4]) {
+		case "PROP":
+			// The sound property chunk contains the sample rate and channels, as sub-chunks
+			buf, err := readBlock(r, size)
+			if err != nil {
+				return 0, 0, 0, err
+			}
+			if len(buf) < 4 || string(buf[0:4]) != "SND " {
+				return 0, 0, 0, ErrSongProperties
+			}
+			channels, sampleRate = dffSoundProperties(buf[4:])
+
+			// The property chunk may be padded to an even size
+			if _, err := r.Seek(size%2, os.SEEK_CUR); err != nil {
+				return 0, 0, 0, err
+			}
+			continue
+		case "DSD ":
+			// Uncompressed audio is interleaved, one bit per sample
+			if channels == 0 {
+				return 0, 0, 0, ErrSongProperties
+			}
+
+			return channels, sampleRate, size * 8 / int64(channels), nil
+		case "DST ":
+			// Compressed audio begins with its number of frames, and the frame rate
+			buf := make([]byte, 18)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return 0, 0, 0, err
+			}
+			if string(buf[0:4]) != "FRTE" {
+				return 0, 0, 0, ErrSongProperties
+			}
+
+			frames := int64(binary.BigEndian.Uint32(buf[12:16]))
+			rate := int64(binary.BigEndian.Uint16(buf[16:18]))
+			if rate == 0 {
+				return 0, 0, 0, ErrSongProperties
+			}
+
+			return channels, sampleRate, frames * int64(sampleRate) / rate, nil
+		}
+
+		if size < 0 {
+			return 0, 0, 0, ErrSongProperties
+		}
+		if _, err := r.Seek(size+size%2, os.SEEK_CUR); err != nil {
+			return 0, 0, 0, err
+		}
+	}
+}
+
+// dffSoundProperties reads the number of channels and sample rate from the sub-chunks of a
+// DSDIFF sound property chunk
+func dffSoundProperties(buf []byte) (int, int) {
+	var channels, sampleRate int
+	for len(buf) >= 12 {
+		size := binary.BigEndian.Uint64(buf[4:12])
+		if size > uint64(len(buf)-12) {
+			break
+		}
+
+		chunk := buf[12 : 12+size]
+		switch string(buf[0:4]) {
+		case "FS  ":
+			if len(chunk) >= 4 {
+				sampleRate = int(binary.BigEndian.Uint32(chunk[0:4]))
+			}
+		case "CHNL":
+			if len(chunk) >= 2 {
+				channels = int(binary.BigEndian.Uint16(chunk[0:2]))
+			}
+		}
+
+		// Sub-chunks are also padded to an even size
+		next := 12 + size + size%2
+		if next > uint64(len(buf)) {
+			break
+		}
+		buf = buf[next:]
+	}
+
+	return channels, sampleRate
+}
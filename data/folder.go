@@ -2,10 +2,11 @@ package data
 
 // Folder represents a filesystem folder known to wavepipe
 type Folder struct {
-	ID       int    `json:"id"`
-	ParentID int    `db:"parent_id" json:"parentId"`
-	Title    string `json:"title"`
-	Path     string `json:"path"`
+	ID        int    `json:"id"`
+	ParentID  int    `db:"parent_id" json:"parentId"`
+	Title     string `json:"title"`
+	Path      string `json:"path"`
+	LibraryID int    `db:"library_id" json:"libraryId"`
 }
 
 // Subfolders retrieves all folders with this folder as their parent ID
@@ -27,3 +28,8 @@ func (f *Folder) Load() error {
 func (f *Folder) Save() error {
 	return DB.SaveFolder(f)
 }
+
+// Update updates an existing Folder in the database
+func (f *Folder) Update() error {
+	return DB.UpdateFolder(f)
+}
@@ -13,13 +13,10 @@ var folder = Folder{
 
 // TestFolderDatabase verifies that an Folder can be saved and loaded from the database
 func TestFolderDatabase(t *testing.T) {
-	// Load database configuration
-	DB = new(SqliteBackend)
-	DB.DSN("~/.config/wavepipe/wavepipe.db")
-	if err := DB.Open(); err != nil {
-		t.Fatalf("Could not open database connection: %s", err.Error())
-	}
-	defer DB.Close()
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
 
 	// Attempt to save the folder
 	if err := folder.Save(); err != nil {
@@ -0,0 +1,49 @@
+package data
+
+import (
+	"errors"
+)
+
+// Item types which may be starred or rated by users
+const (
+	ItemAlbum  = "album"
+	ItemArtist = "artist"
+	ItemFolder = "folder"
+	ItemSong   = "song"
+)
+
+var (
+	// ErrInvalidItemType is returned when an item type is not one of the known item types
+	ErrInvalidItemType = errors.New("item: invalid item type")
+)
+
+// ValidItemType determines if the input string is one of the known item types
+func ValidItemType(itemType string) bool {
+	switch itemType {
+	case ItemAlbum, ItemArtist, ItemFolder, ItemSong:
+		return true
+	}
+
+	return false
+}
+
+// LoadItem loads the item of the specified type and ID from the database, returning a
+// pointer to an Album, Artist, Folder, or Song
+func LoadItem(itemType string, itemID int) (interface{}, error) {
+	switch itemType {
+	case ItemAlbum:
+		album := &Album{ID: itemID}
+		return album, album.Load()
+	case ItemArtist:
+		artist := &Artist{ID: itemID}
+		return artist, artist.Load()
+	case ItemFolder:
+		folder := &Folder{ID: itemID}
+		return folder, folder.Load()
+	case ItemSong:
+		song := &Song{ID: itemID}
+		return song, song.Load()
+	}
+
+	return nil, ErrInvalidItemType
+}
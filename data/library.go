@@ -0,0 +1,42 @@
+package data
+
+import (
+	"strings"
+)
+
+// Library represents a named media library, which is a root folder that wavepipe scans and
+// watches for media.  All art, folders, and songs indexed from a library carry its ID.
+type Library struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// Contains determines if the input filesystem path is this library's root folder, or
+// resides somewhere beneath it
+func (l Library) Contains(p string) bool {
+	return p == l.Path || strings.HasPrefix(p, strings.TrimSuffix(l.Path, "/")+"/")
+}
+
+// Delete removes an existing Library from the database.  Media indexed within the library
+// is released, so that it may be claimed by another library, or removed by an orphan scan.
+func (l *Library) Delete() error {
+	return DB.DeleteLibrary(l)
+}
+
+// Load pulls an existing Library from the database
+func (l *Library) Load() error {
+	return DB.LoadLibrary(l)
+}
+
+// Save creates a new Library in the database.  Media under the library's path which does not
+// belong to any library, such as media indexed before libraries existed, is claimed by it.
+func (l *Library) Save() error {
+	return DB.SaveLibrary(l)
+}
+
+// Update updates an existing Library in the database, claiming unowned media under its
+// path in the same way as Save
+func (l *Library) Update() error {
+	return DB.UpdateLibrary(l)
+}
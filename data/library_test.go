@@ -0,0 +1,54 @@
+package data
+
+import (
+	"testing"
+)
+
+// Mock library
+var library = Library{
+	Name: "TestLibrary",
+	Path: "/some/library",
+}
+
+// TestLibraryDatabase verifies that a Library can be saved and loaded from the database
+func TestLibraryDatabase(t *testing.T) {
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
+
+	// Attempt to save the library
+	if err := library.Save(); err != nil {
+		t.Fatalf("Could not save library: %s", err.Error())
+	}
+
+	// Attempt to load the library
+	if err := library.Load(); err != nil {
+		t.Fatalf("Could not load library: %s", err.Error())
+	}
+
+	// Attempt to delete the library
+	if err := library.Delete(); err != nil {
+		t.Fatalf("Could not delete library: %s", err.Error())
+	}
+}
+
+// TestLibraryContains verifies that a Library only contains paths beneath its root folder
+func TestLibraryContains(t *testing.T) {
+	tests := []struct {
+		path     string
+		contains bool
+	}{
+		{"/some/library", true},
+		{"/some/library/artist/song.mp3", true},
+		{"/some/library2/song.mp3", false},
+		{"/some", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		if contains := library.Contains(test.path); contains != test.contains {
+			t.Fatalf("Unexpected result for %q: %v != %v", test.path, contains, test.contains)
+		}
+	}
+}
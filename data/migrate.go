@@ -0,0 +1,93 @@
+package data
+
+import (
+	"errors"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrSchemaTooNew is returned when a database's schema version is newer than the latest
+	// migration known to this build of wavepipe
+	ErrSchemaTooNew = errors.New("migrate: database schema is newer than this version of wavepipe")
+
+	// ErrMigrationOrder is returned when embedded migrations are not numbered sequentially
+	ErrMigrationOrder = errors.New("migrate: migrations must be numbered sequentially, starting at 1")
+)
+
+// Migration represents a single, ordered step which upgrades a database schema from the
+// previous version to its own version
+type Migration struct {
+	Version     int
+	Description string
+	Script      string
+}
+
+// loadMigrations retrieves all embedded migrations from the specified asset directory, ordered
+// by version.  Migration files are named using their version and a short description, such
+// as "0001_playlists.sql".
+func loadMigrations(dir string) ([]Migration, error) {
+	migrations := make([]Migration, 0)
+
+	// Iterate all embedded assets, looking for files in the migrations directory
+	for _, name := range AssetNames() {
+		if path.Dir(name) != path.Clean(dir) || path.Ext(name) != ".sql" {
+			continue
+		}
+
+		// Split file name into version and description
+		base := strings.TrimSuffix(path.Base(name), ".sql")
+		pair := strings.SplitN(base, "_", 2)
+		if len(pair) != 2 {
+			return nil, ErrMigrationOrder
+		}
+
+		// Parse version number
+		version, err := strconv.Atoi(pair[0])
+		if err != nil {
+			return nil, ErrMigrationOrder
+		}
+
+		// Load migration script
+		script, err := Asset(name)
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: strings.Replace(pair[1], "_", " ", -1),
+			Script:      string(script),
+		})
+	}
+
+	// Sort migrations by version, and verify there are no gaps or duplicates
+	sort.Sort(migrationsByVersion(migrations))
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, ErrMigrationOrder
+		}
+	}
+
+	return migrations, nil
+}
+
+// migrationsByVersion allows sorting of migrations by version number
+type migrationsByVersion []Migration
+
+// Len returns the number of migrations
+func (m migrationsByVersion) Len() int {
+	return len(m)
+}
+
+// Swap swaps two migrations by index
+func (m migrationsByVersion) Swap(i, j int) {
+	m[i], m[j] = m[j], m[i]
+}
+
+// Less compares two migrations by version number
+func (m migrationsByVersion) Less(i, j int) bool {
+	return m[i].Version < m[j].Version
+}
@@ -0,0 +1,111 @@
+package data
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+// TestLoadMigrations verifies that embedded sqlite migrations are loaded in sequential order
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations("res/sqlite/migrations/")
+	if err != nil {
+		t.Fatalf("Could not load migrations: %s", err.Error())
+	}
+
+	// Verify migrations are ordered, and contain a script
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Fatalf("Unexpected migration version: %d != %d", m.Version, i+1)
+		}
+
+		if m.Script == "" {
+			t.Fatalf("Empty migration script: %04d", m.Version)
+		}
+	}
+}
+
+// TestSqliteMigrate verifies that a sqlite database is migrated to the latest schema version,
+// and that a database with a newer schema version is rejected
+func TestSqliteMigrate(t *testing.T) {
+	// Create a temporary database from the embedded asset
+	dir, err := ioutil.TempDir("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	db := new(SqliteBackend)
+	db.DSN(path.Join(dir, "wavepipe.db"))
+	if err := db.Setup(); err != nil {
+		t.Fatalf("Could not set up database: %s", err.Error())
+	}
+	if err := db.Open(); err != nil {
+		t.Fatalf("Could not open database connection: %s", err.Error())
+	}
+	defer db.Close()
+
+	// Load migrations to determine the latest version
+	migrations, err := loadMigrations("res/sqlite/migrations/")
+	if err != nil {
+		t.Fatalf("Could not load migrations: %s", err.Error())
+	}
+	latest := len(migrations)
+
+	// The embedded database should already be at the latest version
+	if version, err := db.SchemaVersion(); err != nil || version != latest {
+		t.Fatalf("Unexpected embedded schema version: %d != %d (%v)", version, latest, err)
+	}
+
+	// Rewind the schema version, and verify all migrations can be re-applied.  Columns added by
+	// migrations must be dropped first, because sqlite cannot add a column only if it is missing.
+	columns := []struct {
+		table  string
+		column string
+	}{
+		{"songs", "added"},
+		{"songs", "disc"},
+		{"songs", "disc_total"},
+		{"songs", "track_total"},
+		{"art", "library_id"},
+		{"folders", "library_id"},
+		{"songs", "library_id"},
+		{"art", "source"},
+		{"albums", "art_id"},
+		{"songs", "start_offset"},
+		{"songs", "end_offset"},
+		{"songs", "fingerprint"},
+	}
+	if _, err := db.db.Exec("DROP INDEX songs_libraryId;"); err != nil {
+		t.Fatalf("Could not drop library index: %s", err.Error())
+	}
+	if _, err := db.db.Exec("DROP INDEX songs_unique_fileName_startOffset;"); err != nil {
+		t.Fatalf("Could not drop file name index: %s", err.Error())
+	}
+	if _, err := db.db.Exec("DROP INDEX songs_fingerprint;"); err != nil {
+		t.Fatalf("Could not drop fingerprint index: %s", err.Error())
+	}
+	for _, c := range columns {
+		if _, err := db.db.Exec("ALTER TABLE " + c.table + " DROP COLUMN " + c.column + ";"); err != nil {
+			t.Fatalf("Could not drop %s.%s column: %s", c.table, c.column, err.Error())
+		}
+	}
+	if _, err := db.db.Exec("PRAGMA user_version = 0;"); err != nil {
+		t.Fatalf("Could not reset schema version: %s", err.Error())
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Could not migrate database: %s", err.Error())
+	}
+	if version, err := db.SchemaVersion(); err != nil || version != latest {
+		t.Fatalf("Unexpected migrated schema version: %d != %d (%v)", version, latest, err)
+	}
+
+	// Verify a database newer than this build is rejected
+	if _, err := db.db.Exec("PRAGMA user_version = 9999;"); err != nil {
+		t.Fatalf("Could not set schema version: %s", err.Error())
+	}
+	if err := db.Migrate(); err != ErrSchemaTooNew {
+		t.Fatalf("Unexpected error for newer schema: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package data
+
+import (
+	"time"
+)
+
+// Play represents a single play of a song by a user, at a specific time.  Plays are used
+// to build a user's listening history, and to calculate song and album play counts.
+type Play struct {
+	ID        int   `json:"id"`
+	UserID    int   `db:"user_id" json:"userId"`
+	SongID    int   `db:"song_id" json:"songId"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// NewPlay generates and saves a new play of the specified song by the specified user,
+// at the current time
+func NewPlay(userID int, songID int) (*Play, error) {
+	// Generate play
+	play := &Play{
+		UserID:    userID,
+		SongID:    songID,
+		Timestamp: time.Now().Unix(),
+	}
+
+	// Save play
+	if err := play.Save(); err != nil {
+		return nil, err
+	}
+
+	return play, nil
+}
+
+// Delete removes an existing Play from the database
+func (p *Play) Delete() error {
+	return DB.DeletePlay(p)
+}
+
+// Load pulls an existing Play from the database
+func (p *Play) Load() error {
+	return DB.LoadPlay(p)
+}
+
+// Save creates a new Play in the database
+func (p *Play) Save() error {
+	return DB.SavePlay(p)
+}
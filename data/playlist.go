@@ -0,0 +1,145 @@
+package data
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPlaylistEntries is returned when a playlist is reordered using a list of entry IDs which
+	// does not exactly match the entries currently contained in the playlist
+	ErrPlaylistEntries = errors.New("playlist: entry IDs do not match playlist entries")
+)
+
+// Playlist represents a user-created, ordered list of songs known to wavepipe.  Each playlist
+// is owned by a single user, and may be shared with other users by marking it public.
+type Playlist struct {
+	ID      int    `json:"id"`
+	UserID  int    `db:"user_id" json:"userId"`
+	Title   string `json:"title"`
+	Public  bool   `json:"public"`
+	Created int64  `json:"created"`
+}
+
+// NewPlaylist generates and saves a new playlist for the specified user
+func NewPlaylist(userID int, title string, public bool) (*Playlist, error) {
+	// Generate playlist
+	playlist := &Playlist{
+		UserID:  userID,
+		Title:   title,
+		Public:  public,
+		Created: time.Now().Unix(),
+	}
+
+	// Save playlist
+	if err := playlist.Save(); err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// CanView determines if the input user is allowed to view this playlist
+func (p Playlist) CanView(user *User) bool {
+	return p.Public || p.CanEdit(user)
+}
+
+// CanEdit determines if the input user is allowed to modify this playlist.  Only the
+// owner of a playlist, or an administrator, may modify it.
+func (p Playlist) CanEdit(user *User) bool {
+	return p.UserID == user.ID || user.RoleID == RoleAdmin
+}
+
+// Entries retrieves all entries contained in this playlist, ordered by position
+func (p *Playlist) Entries() ([]PlaylistEntry, error) {
+	return DB.EntriesForPlaylist(p.ID)
+}
+
+// Songs retrieves all songs contained in this playlist, ordered by position
+func (p *Playlist) Songs() ([]Song, error) {
+	return DB.SongsForPlaylist(p.ID)
+}
+
+// Append adds the songs with the specified IDs to the end of this playlist
+func (p *Playlist) Append(songIDs []int) error {
+	return DB.AppendPlaylistEntries(p.ID, songIDs)
+}
+
+// Remove removes the entries with the specified IDs from this playlist
+func (p *Playlist) Remove(entryIDs []int) error {
+	return DB.RemovePlaylistEntries(p.ID, entryIDs)
+}
+
+// Reorder rearranges this playlist's entries into the order specified by the input entry IDs
+func (p *Playlist) Reorder(entryIDs []int) error {
+	return DB.ReorderPlaylistEntries(p.ID, entryIDs)
+}
+
+// Delete removes an existing Playlist from the database
+func (p *Playlist) Delete() error {
+	return DB.DeletePlaylist(p)
+}
+
+// Load pulls an existing Playlist from the database
+func (p *Playlist) Load() error {
+	return DB.LoadPlaylist(p)
+}
+
+// Save creates a new Playlist in the database
+func (p *Playlist) Save() error {
+	return DB.SavePlaylist(p)
+}
+
+// Update updates an existing Playlist in the database
+func (p *Playlist) Update() error {
+	return DB.UpdatePlaylist(p)
+}
+
+// PlaylistEntry represents a single song at a specific position within a Playlist.  A song
+// may appear in a playlist more than once, so entries are identified by their own ID.
+type PlaylistEntry struct {
+	ID         int `json:"id"`
+	PlaylistID int `db:"playlist_id" json:"playlistId"`
+	SongID     int `db:"song_id" json:"songId"`
+	Position   int `json:"position"`
+}
+
+// CheckOrder verifies that the input entry IDs contain each of this playlist's entries exactly
+// once, so that they may be used to reorder it.  ErrPlaylistEntries is returned if they do not.
+func (p *Playlist) CheckOrder(entryIDs []int) error {
+	entries, err := DB.EntriesForPlaylist(p.ID)
+	if err != nil {
+		return err
+	}
+
+	if !samePlaylistEntries(entries, entryIDs) {
+		return ErrPlaylistEntries
+	}
+
+	return nil
+}
+
+// samePlaylistEntries determines if the input entry IDs contain each entry from the input
+// slice of entries exactly once, and nothing more
+func samePlaylistEntries(entries []PlaylistEntry, entryIDs []int) bool {
+	// Lengths must match for the sets to be equal
+	if len(entries) != len(entryIDs) {
+		return false
+	}
+
+	// Count occurrences of each existing entry ID
+	counts := make(map[int]int, len(entries))
+	for _, e := range entries {
+		counts[e.ID]++
+	}
+
+	// Every input ID must consume exactly one existing entry
+	for _, id := range entryIDs {
+		if counts[id] == 0 {
+			return false
+		}
+		counts[id]--
+	}
+
+	return true
+}
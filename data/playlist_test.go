@@ -0,0 +1,73 @@
+package data
+
+import (
+	"testing"
+)
+
+// TestPlaylistDatabase verifies that a Playlist can be saved, loaded, and modified in the database
+func TestPlaylistDatabase(t *testing.T) {
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
+
+	// Attempt to create and save the playlist
+	playlist, err := NewPlaylist(1, "TestPlaylist", false)
+	if err != nil {
+		t.Fatalf("Could not create and save playlist: %s", err.Error())
+	}
+
+	// Attempt to load the playlist
+	if err := playlist.Load(); err != nil {
+		t.Fatalf("Could not load playlist: %s", err.Error())
+	}
+
+	// Attempt to append songs to the playlist, including a duplicate
+	if err := playlist.Append([]int{1, 2, 1}); err != nil {
+		t.Fatalf("Could not append to playlist: %s", err.Error())
+	}
+
+	// Verify entries were added in order
+	entries, err := playlist.Entries()
+	if err != nil {
+		t.Fatalf("Could not load playlist entries: %s", err.Error())
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Mismatched playlist entry count: %d != %d", len(entries), 3)
+	}
+
+	// Attempt to reverse the playlist order
+	if err := playlist.Reorder([]int{entries[2].ID, entries[1].ID, entries[0].ID}); err != nil {
+		t.Fatalf("Could not reorder playlist: %s", err.Error())
+	}
+
+	// Verify an incomplete ordering is rejected
+	if err := playlist.Reorder([]int{entries[0].ID}); err != ErrPlaylistEntries {
+		t.Fatalf("Incomplete reorder did not fail: %v", err)
+	}
+
+	// Attempt to remove the middle entry
+	if err := playlist.Remove([]int{entries[1].ID}); err != nil {
+		t.Fatalf("Could not remove from playlist: %s", err.Error())
+	}
+
+	// Verify positions remain contiguous, and in the reordered sequence
+	entries2, err := playlist.Entries()
+	if err != nil {
+		t.Fatalf("Could not load playlist entries: %s", err.Error())
+	}
+	if len(entries2) != 2 || entries2[0].ID != entries[2].ID || entries2[1].Position != 1 {
+		t.Fatalf("Unexpected playlist entries after remove: %v", entries2)
+	}
+
+	// Attempt to update the playlist
+	playlist.Public = true
+	if err := playlist.Update(); err != nil {
+		t.Fatalf("Could not update playlist: %s", err.Error())
+	}
+
+	// Attempt to delete the playlist
+	if err := playlist.Delete(); err != nil {
+		t.Fatalf("Could not delete playlist: %s", err.Error())
+	}
+}
@@ -0,0 +1,353 @@
+package data
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QueryError is returned when a search query cannot be parsed
+type QueryError struct {
+	Reason string
+	Token  string
+}
+
+// Error returns the reason a search query could not be parsed, and the offending token
+func (e *QueryError) Error() string {
+	return e.Reason + ": " + e.Token
+}
+
+// queryField describes a field which may be used to filter songs in a search query.  Text
+// fields match any song which contains the value, ignoring case, while numeric fields may
+// also be compared using an operator.  Date fields are numeric fields containing a UNIX
+// timestamp, and rating fields are matched against the querying user's song ratings.
+type queryField struct {
+	column string
+	text   func(Song) string
+	number func(Song) int64
+	codec  bool
+	date   bool
+	rating bool
+}
+
+// queryFields maps the names of search query fields to the song fields they filter
+var queryFields = map[string]queryField{
+	"album":   {column: "albums.title", text: func(s Song) string { return s.Album }},
+	"artist":  {column: "artists.title", text: func(s Song) string { return s.Artist }},
+	"comment": {column: "songs.comment", text: func(s Song) string { return s.Comment }},
+	"genre":   {column: "songs.genre", text: func(s Song) string { return s.Genre }},
+	"title":   {column: "songs.title", text: func(s Song) string { return s.Title }},
+
+	"bitrate": {column: "songs.bitrate", number: func(s Song) int64 { return int64(s.Bitrate) }},
+	"codec":   {column: "songs.file_type_id", number: func(s Song) int64 { return int64(s.FileTypeID) }, codec: true},
+	"length":  {column: "songs.length", number: func(s Song) int64 { return int64(s.Length) }},
+	"plays":   {column: "(SELECT COUNT(*) FROM plays WHERE plays.song_id = songs.id)", number: func(s Song) int64 { return int64(s.PlayCount) }},
+	"track":   {column: "songs.track", number: func(s Song) int64 { return int64(s.Track) }},
+	"year":    {column: "songs.year", number: func(s Song) int64 { return int64(s.Year) }},
+
+	"added": {column: "songs.added", number: func(s Song) int64 { return s.Added }, date: true},
+	"lastplayed": {
+		column: "(SELECT COALESCE(MAX(plays.timestamp), 0) FROM plays WHERE plays.song_id = songs.id)",
+		number: func(s Song) int64 { return s.LastPlayed },
+		date:   true,
+	},
+
+	"rating": {
+		column: "COALESCE((SELECT ratings.rating FROM ratings WHERE ratings.user_id = {user} " +
+			"AND ratings.item_type = '" + ItemSong + "' AND ratings.item_id = songs.id), 0)",
+		rating: true,
+	},
+}
+
+// queryOperators contains the comparison operators for numeric fields, with longer
+// operators first, so that they are matched before their prefixes
+var queryOperators = []string{">=", "<=", ">", "<", "="}
+
+// queryAgeOperators maps comparison operators on the age of a date field to the equivalent
+// operators on its timestamp, so that a younger age is a later timestamp
+var queryAgeOperators = map[string]string{
+	">=": "<=",
+	"<=": ">=",
+	">":  "<",
+	"<":  ">",
+	"=":  ">",
+}
+
+// queryUnits maps the units of an age in a date field to their duration
+var queryUnits = map[byte]time.Duration{
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'y': 365 * 24 * time.Hour,
+}
+
+// queryFilter is a single field filter parsed from a search query
+type queryFilter struct {
+	field  queryField
+	op     string
+	text   string
+	number int64
+}
+
+// match determines if a song, with the querying user's rating, satisfies this filter
+func (f queryFilter) match(s Song, rating int) bool {
+	if f.field.text != nil {
+		return strings.Contains(strings.ToLower(f.field.text(s)), strings.ToLower(f.text))
+	}
+
+	n := int64(rating)
+	if !f.field.rating {
+		n = f.field.number(s)
+	}
+
+	switch f.op {
+	case ">=":
+		return n >= f.number
+	case "<=":
+		return n <= f.number
+	case ">":
+		return n > f.number
+	case "<":
+		return n < f.number
+	}
+
+	return n == f.number
+}
+
+// Query is a parsed search query, containing free text which is matched against the search
+// index, and filters on song fields.  Artists, albums, and folders match a query's filters if
+// any of their songs do.  Rating filters match the ratings of the user with UserID.
+type Query struct {
+	Text    string
+	UserID  int
+	filters []queryFilter
+}
+
+// ParseQuery parses a search query containing free text and field filters, such as
+// `artist:"Boards of Canada" year:>=1998 genre:ambient codec:flac bitrate:<256 length:>600`.
+// Values containing spaces may be quoted, and unknown field names are free text.  Numeric
+// fields may be prefixed with a comparison operator, or specify an inclusive range such as
+// `year:1990..1999`.  Date fields accept a date such as `added:>=2014-06-01`, or an age such as
+// `lastplayed:>30d`, in hours, days, weeks, or years.  An age with no operator matches items
+// younger than the age.
+func ParseQuery(input string) (Query, error) {
+	q := Query{}
+	text := make([]string, 0)
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		// Skip whitespace between tokens
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		// Check for a known field name followed by a colon.  Other words followed by a colon,
+		// such as in "Live: at Wembley", are free text.
+		j := i
+		for j < len(runes) && unicode.IsLetter(runes[j]) {
+			j++
+		}
+		name := strings.ToLower(string(runes[i:j]))
+		if _, ok := queryFields[name]; ok && j < len(runes) && runes[j] == ':' {
+			value, next, err := queryValue(runes, j+1)
+			if err != nil {
+				return Query{}, err
+			}
+
+			filters, err := newQueryFilters(name, value)
+			if err != nil {
+				return Query{}, err
+			}
+
+			q.filters = append(q.filters, filters...)
+			i = next
+			continue
+		}
+
+		// All other tokens are free text
+		value, next, err := queryValue(runes, i)
+		if err != nil {
+			return Query{}, err
+		}
+
+		text = append(text, value)
+		i = next
+	}
+
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// queryValue reads a value starting at the specified position, which ends at the next
+// whitespace outside of quotes.  The value and the position following it are returned.
+func queryValue(runes []rune, i int) (string, int, error) {
+	start := i
+	value := make([]rune, 0)
+	quoted := false
+	for ; i < len(runes); i++ {
+		switch {
+		case runes[i] == '"':
+			quoted = !quoted
+		case unicode.IsSpace(runes[i]) && !quoted:
+			return string(value), i, nil
+		default:
+			value = append(value, runes[i])
+		}
+	}
+
+	if quoted {
+		return "", i, &QueryError{"unterminated quote", string(runes[start:])}
+	}
+
+	return string(value), i, nil
+}
+
+// newQueryFilters creates the filters for the named field, parsing its operator and value.
+// Ranges produce a pair of filters.
+func newQueryFilters(name string, value string) ([]queryFilter, error) {
+	field, ok := queryFields[name]
+	if !ok {
+		return nil, &QueryError{"unknown field", name}
+	}
+	if value == "" {
+		return nil, &QueryError{"missing value for field", name}
+	}
+
+	// Text fields match any value
+	if field.text != nil {
+		return []queryFilter{{field: field, text: value}}, nil
+	}
+
+	// Codecs are matched by name
+	if field.codec {
+		for id, codec := range CodecMap {
+			if strings.EqualFold(codec, value) {
+				return []queryFilter{{field: field, op: "=", number: int64(id)}}, nil
+			}
+		}
+
+		return nil, &QueryError{"unknown codec", value}
+	}
+
+	// Numeric fields may begin with a comparison operator
+	op := ""
+	for _, o := range queryOperators {
+		if strings.HasPrefix(value, o) {
+			op = o
+			value = value[len(o):]
+			break
+		}
+	}
+	if value == "" {
+		return nil, &QueryError{"missing value for field", name}
+	}
+
+	if field.date {
+		return newDateFilters(field, name, op, value)
+	}
+
+	// Numeric fields may specify an inclusive range instead
+	if bounds := strings.SplitN(value, "..", 2); len(bounds) == 2 && op == "" {
+		min, err := strconv.ParseInt(bounds[0], 10, 64)
+		if err != nil {
+			return nil, &QueryError{"invalid integer for field " + name, bounds[0]}
+		}
+		max, err := strconv.ParseInt(bounds[1], 10, 64)
+		if err != nil {
+			return nil, &QueryError{"invalid integer for field " + name, bounds[1]}
+		}
+
+		return []queryFilter{{field: field, op: ">=", number: min}, {field: field, op: "<=", number: max}}, nil
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, &QueryError{"invalid integer for field " + name, value}
+	}
+
+	if op == "" {
+		op = "="
+	}
+
+	return []queryFilter{{field: field, op: op, number: number}}, nil
+}
+
+// newDateFilters creates the filters for a date field, whose value is either a date or an
+// age relative to the current time
+func newDateFilters(field queryField, name string, op string, value string) ([]queryFilter, error) {
+	// Ages compare the time elapsed since the timestamp, so their operators are reversed
+	if unit, ok := queryUnits[value[len(value)-1]]; ok {
+		n, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+		if err != nil {
+			return nil, &QueryError{"invalid age for field " + name, value}
+		}
+
+		if op == "" {
+			op = "<"
+		}
+
+		since := time.Now().Add(-time.Duration(n) * unit).Unix()
+		return []queryFilter{{field: field, op: queryAgeOperators[op], number: since}}, nil
+	}
+
+	// Dates are compared using the start of the day in local time
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, &QueryError{"invalid date for field " + name, value}
+	}
+
+	// With no operator, or equality, match the entire day
+	start := date.Unix()
+	if op == "" || op == "=" {
+		end := date.AddDate(0, 0, 1).Unix()
+		return []queryFilter{{field: field, op: ">=", number: start}, {field: field, op: "<", number: end}}, nil
+	}
+
+	return []queryFilter{{field: field, op: op, number: start}}, nil
+}
+
+// Empty determines if a query contains no search terms and no filters, and so matches nothing
+func (q Query) Empty() bool {
+	return len(searchTerms(q.Text)) == 0 && len(q.filters) == 0
+}
+
+// matchSong determines if a song, with the querying user's rating, satisfies all of the
+// query's filters
+func (q Query) matchSong(s Song, rating int) bool {
+	for _, f := range q.filters {
+		if !f.match(s, rating) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sqlConditions compiles the query's filters into SQL conditions over the songs table, joined
+// with the artists and albums tables.  The contains format is used to match text fields, and
+// param is called to generate each argument placeholder, in order.
+func (q Query) sqlConditions(contains string, param func() string) ([]string, []interface{}) {
+	conditions := make([]string, 0)
+	args := make([]interface{}, 0)
+	for _, f := range q.filters {
+		if f.field.text != nil {
+			conditions = append(conditions, strings.NewReplacer("{column}", f.field.column, "{param}", param()).Replace(contains))
+			args = append(args, f.text)
+			continue
+		}
+
+		// Ratings are selected for the querying user
+		column := f.field.column
+		if f.field.rating {
+			column = strings.Replace(column, "{user}", param(), 1)
+			args = append(args, q.UserID)
+		}
+
+		conditions = append(conditions, column+" "+f.op+" "+param())
+		args = append(args, f.number)
+	}
+
+	return conditions, args
+}
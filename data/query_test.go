@@ -0,0 +1,117 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseQuery verifies that search queries are parsed into free text and field filters,
+// and that invalid queries are rejected
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		input   string
+		text    string
+		filters int
+		reason  string
+	}{
+		// Free text only
+		{"boards of canada", "boards of canada", 0, ""},
+		{`"boards of" canada`, "boards of canada", 0, ""},
+		// Field filters, with quoted values and comparison operators
+		{`artist:"Boards of Canada" year:>=1998 genre:ambient codec:flac bitrate:<256 length:>600`, "", 6, ""},
+		{"ARTIST:boards roygbiv", "roygbiv", 1, ""},
+		{"track:3 year:=1998 year:<=2000 length:<60", "", 4, ""},
+		// Colons outside of known field names are free text
+		{`"re:member" 12:00`, "re:member 12:00", 0, ""},
+		{"foo:bar", "foo:bar", 0, ""},
+		{"Live: at Wembley", "Live: at Wembley", 0, ""},
+		{"Op. 27: Nocturne year:1835", "Op. 27: Nocturne", 1, ""},
+		{`Live: artist:"Queen"`, "Live:", 1, ""},
+		// Invalid queries
+		{"artist:", "", 0, "missing value for field"},
+		{"added:>=", "", 0, "missing value for field"},
+		{"lastplayed:<", "", 0, "missing value for field"},
+		{`year:">"`, "", 0, "missing value for field"},
+		{"rating:=", "", 0, "missing value for field"},
+		{`artist:"boards`, "", 0, "unterminated quote"},
+		{"codec:foo", "", 0, "unknown codec"},
+		{"year:>=foo", "", 0, "invalid integer for field year"},
+		{"year:1990..foo", "", 0, "invalid integer for field year"},
+		{"added:foo", "", 0, "invalid date for field added"},
+		{"lastplayed:>xd", "", 0, "invalid age for field lastplayed"},
+		// Ranges and dates may produce a pair of filters
+		{"year:1990..1999 rating:>=4", "", 3, ""},
+		{"added:2014-06-01 lastplayed:>30d plays:0", "", 4, ""},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.input)
+		if test.reason != "" {
+			if qErr, ok := err.(*QueryError); !ok || qErr.Reason != test.reason {
+				t.Fatalf("Unexpected error for %q: %v", test.input, err)
+			}
+
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Could not parse query %q: %s", test.input, err.Error())
+		}
+
+		if q.Text != test.text || len(q.filters) != test.filters {
+			t.Fatalf("Unexpected query for %q: %q, %d filters", test.input, q.Text, len(q.filters))
+		}
+	}
+}
+
+// TestQueryMatchSong verifies that query filters match songs using their comparison operators
+func TestQueryMatchSong(t *testing.T) {
+	now := time.Now()
+	s := Song{
+		Added:      now.Add(-2 * 24 * time.Hour).Unix(),
+		Artist:     "Boards of Canada",
+		Bitrate:    192,
+		FileTypeID: MP3,
+		Genre:      "Ambient",
+		LastPlayed: now.Add(-60 * 24 * time.Hour).Unix(),
+		Length:     640,
+		PlayCount:  3,
+		Year:       1998,
+	}
+
+	tests := []struct {
+		input string
+		match bool
+	}{
+		{`artist:"boards of canada" year:>=1998 genre:ambient codec:mp3 bitrate:<256 length:>600`, true},
+		{"artist:canada", true},
+		{"year:1998", true},
+		{"year:>1998", false},
+		{"year:<=1998", true},
+		{"year:1990..1999", true},
+		{"year:2000..2009", false},
+		{"codec:flac", false},
+		{"genre:ambient bitrate:>=256", false},
+		{"plays:>=3", true},
+		{"rating:4..5", true},
+		{"rating:5", false},
+		{"added:7d", true},
+		{"added:>1d", true},
+		{"added:>1w", false},
+		{"lastplayed:>30d", true},
+		{"lastplayed:<1y", true},
+		{"lastplayed:<30d", false},
+		{"added:" + now.Add(-2*24*time.Hour).Format("2006-01-02"), true},
+		{"added:<2000-01-01", false},
+	}
+
+	for _, test := range tests {
+		q, err := ParseQuery(test.input)
+		if err != nil {
+			t.Fatalf("Could not parse query %q: %s", test.input, err.Error())
+		}
+
+		if q.matchSong(s, 4) != test.match {
+			t.Fatalf("Unexpected match for %q: %v", test.input, !test.match)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+)
+
+var (
+	// ErrInvalidRating is returned when a rating is not an integer between 1 and 5
+	ErrInvalidRating = errors.New("rating: rating must be between 1 and 5")
+)
+
+// Rating represents a user's rating, from 1 to 5, of an item such as a song, album, artist,
+// or folder
+type Rating struct {
+	ID       int    `json:"id"`
+	UserID   int    `db:"user_id" json:"userId"`
+	ItemType string `db:"item_type" json:"itemType"`
+	ItemID   int    `db:"item_id" json:"itemId"`
+	Rating   int    `json:"rating"`
+}
+
+// NewRating generates and saves a new rating of the specified item by the specified user.
+// If the item is already rated, the existing rating is updated instead.
+func NewRating(userID int, itemType string, itemID int, rating int) (*Rating, error) {
+	// Verify rating is in range
+	if rating < 1 || rating > 5 {
+		return nil, ErrInvalidRating
+	}
+
+	// Verify item exists
+	if _, err := LoadItem(itemType, itemID); err != nil {
+		return nil, err
+	}
+
+	// Check for an existing rating by this user
+	r := &Rating{
+		UserID:   userID,
+		ItemType: itemType,
+		ItemID:   itemID,
+	}
+	if err := r.Load(); err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// Save new rating, or update the existing one
+	r.Rating = rating
+	if r.ID == 0 {
+		if err := r.Save(); err != nil {
+			return nil, err
+		}
+
+		return r, nil
+	}
+
+	if err := r.Update(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Delete removes an existing Rating from the database
+func (r *Rating) Delete() error {
+	return DB.DeleteRating(r)
+}
+
+// Load pulls an existing Rating from the database
+func (r *Rating) Load() error {
+	return DB.LoadRating(r)
+}
+
+// Save creates a new Rating in the database
+func (r *Rating) Save() error {
+	return DB.SaveRating(r)
+}
+
+// Update updates an existing Rating in the database
+func (r *Rating) Update() error {
+	return DB.UpdateRating(r)
+}
@@ -0,0 +1,148 @@
+package data
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// searchTerms splits a search query into lowercase terms, without diacritics.  As with the
+// sqlite unicode61 tokenizer, any run of letters and digits is a single term, and all other
+// characters separate terms.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(foldDiacritics(query)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// foldDiacritics removes diacritics from a string, by decomposing each character and dropping
+// the combining marks, so that "Café" is equivalent to "Cafe"
+func foldDiacritics(s string) string {
+	folded := make([]rune, 0, len(s))
+	for _, r := range norm.NFD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			folded = append(folded, r)
+		}
+	}
+
+	return string(folded)
+}
+
+// ftsQuery converts a search query into an sqlite FTS5 query, in which every term must
+// match the prefix of a word in the indexed columns
+func ftsQuery(query string) string {
+	terms := searchTerms(query)
+	for i, t := range terms {
+		terms[i] = `"` + t + `"*`
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// tsQuery converts a search query into a postgres tsquery, in which every term must
+// match the prefix of a word in the search vector
+func tsQuery(query string) string {
+	terms := searchTerms(query)
+	for i, t := range terms {
+		terms[i] = t + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// searchScore determines if every search term matches the prefix of a word in the input
+// fields, for backends without a full-text index.  If all terms match, a relevance score
+// is returned, which is the sum of the weights of each field matched by each term.
+func searchScore(terms []string, fields []string, weights []float64) (float64, bool) {
+	// Split each field into words, using the same rules as search terms
+	words := make([][]string, len(fields))
+	for i, f := range fields {
+		words[i] = searchTerms(f)
+	}
+
+	var score float64
+	for _, t := range terms {
+		matched := false
+		for i := range fields {
+			for _, w := range words[i] {
+				if strings.HasPrefix(w, t) {
+					score += weights[i]
+					matched = true
+					break
+				}
+			}
+		}
+
+		// Every term must match at least one field
+		if !matched {
+			return 0, false
+		}
+	}
+
+	return score, true
+}
+
+// searchResult is the position and relevance score of a matching item, used to rank
+// search results for backends without a full-text index
+type searchResult struct {
+	index int
+	score float64
+}
+
+// searchResults allows sorting of search results by descending score, then by position
+type searchResults []searchResult
+
+// Len returns the number of search results
+func (s searchResults) Len() int {
+	return len(s)
+}
+
+// Swap swaps two search results by index
+func (s searchResults) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less orders search results by descending score, then by ascending position
+func (s searchResults) Less(i, j int) bool {
+	if s[i].score != s[j].score {
+		return s[i].score > s[j].score
+	}
+
+	return s[i].index < s[j].index
+}
+
+// rankResults sorts search results by relevance, and returns the positions of the results
+// within the range specified by the input offset and count
+func rankResults(results searchResults, offset int, count int) []int {
+	sort.Sort(results)
+	start, end := limitRange(len(results), offset, count)
+
+	indexes := make([]int, 0, end-start)
+	for _, r := range results[start:end] {
+		indexes = append(indexes, r.index)
+	}
+
+	return indexes
+}
+
+// rankSearch scores the fields of each of n items against the search terms, and returns the
+// positions of matching items ranked by relevance, within the range specified by the input
+// offset and count, along with the total number of matches.  If there are no search terms,
+// all items match in their original order.
+func rankSearch(terms []string, n int, fields func(int) []string, weights []float64, offset int, count int) ([]int, int64) {
+	results := make(searchResults, 0)
+	for i := 0; i < n; i++ {
+		if len(terms) == 0 {
+			results = append(results, searchResult{i, 0})
+			continue
+		}
+
+		if score, ok := searchScore(terms, fields(i), weights); ok {
+			results = append(results, searchResult{i, score})
+		}
+	}
+
+	return rankResults(results, offset, count), int64(len(results))
+}
@@ -6,13 +6,10 @@ import (
 
 // TestSessionDatabase verifies that an Session can be saved and loaded from the database
 func TestSessionDatabase(t *testing.T) {
-	// Load database configuration
-	DB = new(SqliteBackend)
-	DB.DSN("~/.config/wavepipe/wavepipe.db")
-	if err := DB.Open(); err != nil {
-		t.Fatalf("Could not open database connection: %s", err.Error())
-	}
-	defer DB.Close()
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
 
 	// Attempt to create and save the session
 	session, err := NewSession(1, "TestPassword", "TestClient")
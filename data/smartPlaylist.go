@@ -0,0 +1,225 @@
+package data
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSmartPlaylistSort is returned when a smart playlist is created with an unknown sort order
+	ErrSmartPlaylistSort = errors.New("smartplaylist: invalid sort order")
+	// ErrSmartPlaylistLimit is returned when a smart playlist is created with a negative limit
+	ErrSmartPlaylistLimit = errors.New("smartplaylist: limit must not be negative")
+)
+
+// smartPlaylistSorts maps the names of smart playlist sort orders to functions which compare
+// two songs in ascending order
+var smartPlaylistSorts = map[string]func(a, b Song) bool{
+	"added":      func(a, b Song) bool { return a.Added < b.Added },
+	"album":      func(a, b Song) bool { return strings.ToLower(a.Album) < strings.ToLower(b.Album) },
+	"artist":     func(a, b Song) bool { return strings.ToLower(a.Artist) < strings.ToLower(b.Artist) },
+	"lastplayed": func(a, b Song) bool { return a.LastPlayed < b.LastPlayed },
+	"plays":      func(a, b Song) bool { return a.PlayCount < b.PlayCount },
+	"title":      func(a, b Song) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) },
+	"year":       func(a, b Song) bool { return a.Year < b.Year },
+}
+
+// SmartPlaylist represents a user-created, dynamic playlist, whose songs are chosen when it is
+// evaluated, using a saved search query, sort order, and limit.  Each smart playlist is owned
+// by a single user, and may be shared with other users by marking it public.
+type SmartPlaylist struct {
+	ID      int    `json:"id"`
+	UserID  int    `db:"user_id" json:"userId"`
+	Title   string `json:"title"`
+	Public  bool   `json:"public"`
+	Query   string `json:"query"`
+	Sort    string `json:"sort"`
+	Limit   int    `db:"song_limit" json:"limit"`
+	Created int64  `json:"created"`
+}
+
+// NewSmartPlaylist validates, generates, and saves a new smart playlist for the specified user
+func NewSmartPlaylist(userID int, title string, public bool, query string, sort string, limit int) (*SmartPlaylist, error) {
+	// Generate smart playlist
+	playlist := &SmartPlaylist{
+		UserID:  userID,
+		Title:   title,
+		Public:  public,
+		Query:   query,
+		Sort:    sort,
+		Limit:   limit,
+		Created: time.Now().Unix(),
+	}
+
+	// Verify its rules can be evaluated
+	if err := playlist.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Save smart playlist
+	if err := playlist.Save(); err != nil {
+		return nil, err
+	}
+
+	return playlist, nil
+}
+
+// Validate verifies that this smart playlist's query, sort order, and limit are valid.  Invalid
+// queries return a *QueryError.
+func (p SmartPlaylist) Validate() error {
+	if _, err := ParseQuery(p.Query); err != nil {
+		return err
+	}
+
+	// Sort orders may be reversed using a leading hyphen
+	if p.Sort != "" && p.Sort != "random" {
+		if _, ok := smartPlaylistSorts[strings.TrimPrefix(p.Sort, "-")]; !ok {
+			return ErrSmartPlaylistSort
+		}
+	}
+
+	if p.Limit < 0 {
+		return ErrSmartPlaylistLimit
+	}
+
+	return nil
+}
+
+// CanView determines if the input user is allowed to view this smart playlist
+func (p SmartPlaylist) CanView(user *User) bool {
+	return p.Public || p.CanEdit(user)
+}
+
+// CanEdit determines if the input user is allowed to modify this smart playlist.  Only the
+// owner of a smart playlist, or an administrator, may modify it.
+func (p SmartPlaylist) CanEdit(user *User) bool {
+	return p.UserID == user.ID || user.RoleID == RoleAdmin
+}
+
+// Songs evaluates this smart playlist, and retrieves the songs which currently match its query,
+// in its sort order, up to its limit.  Rating filters match the ratings of the playlist's owner.
+// With no sort order, songs matching free text are ordered by relevance, and all others by ID.
+func (p *SmartPlaylist) Songs() ([]Song, error) {
+	q, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+
+	return DB.SmartPlaylistSongs(q, p.Sort, p.Limit)
+}
+
+// Count evaluates this smart playlist without loading its songs, and retrieves the number of
+// songs which currently match its query, up to its limit, and their total length in seconds
+func (p *SmartPlaylist) Count() (int64, int64, error) {
+	q, err := p.query()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return DB.CountSmartPlaylistSongs(q, p.Sort, p.Limit)
+}
+
+// query parses this smart playlist's search query, which matches ratings using its owner's
+// ratings, and verifies its sort order
+func (p *SmartPlaylist) query() (Query, error) {
+	q, err := ParseQuery(p.Query)
+	if err != nil {
+		return Query{}, err
+	}
+	q.UserID = p.UserID
+
+	if p.Sort != "" && p.Sort != "random" {
+		if _, ok := smartPlaylistSorts[strings.TrimPrefix(p.Sort, "-")]; !ok {
+			return Query{}, ErrSmartPlaylistSort
+		}
+	}
+
+	return q, nil
+}
+
+// Delete removes an existing SmartPlaylist from the database
+func (p *SmartPlaylist) Delete() error {
+	return DB.DeleteSmartPlaylist(p)
+}
+
+// Load pulls an existing SmartPlaylist from the database
+func (p *SmartPlaylist) Load() error {
+	return DB.LoadSmartPlaylist(p)
+}
+
+// Save creates a new SmartPlaylist in the database
+func (p *SmartPlaylist) Save() error {
+	return DB.SaveSmartPlaylist(p)
+}
+
+// Update updates an existing SmartPlaylist in the database
+func (p *SmartPlaylist) Update() error {
+	return DB.UpdateSmartPlaylist(p)
+}
+
+// sortSmartPlaylist sorts songs in a smart playlist sort order, for backends which cannot sort
+// them in a query.  Songs which are equal in the sort order remain in their existing order.
+func sortSmartPlaylist(songs []Song, order string) {
+	switch order {
+	case "":
+	case "random":
+		for i := range songs {
+			j := rand.Intn(i + 1)
+			songs[i], songs[j] = songs[j], songs[i]
+		}
+	default:
+		less := smartPlaylistSorts[strings.TrimPrefix(order, "-")]
+		if strings.HasPrefix(order, "-") {
+			sort.Stable(songsBy{songs, func(a, b Song) bool { return less(b, a) }})
+		} else {
+			sort.Stable(songsBy{songs, less})
+		}
+	}
+}
+
+// smartPlaylistOrder returns the SQL ORDER BY expression for a smart playlist sort order, using
+// the column of the search query field with the same name.  The text format is used to compare
+// text columns ignoring case, and random order uses RANDOM().
+func smartPlaylistOrder(order string, text string) string {
+	switch order {
+	case "":
+		return ""
+	case "random":
+		return "RANDOM()"
+	}
+
+	field := queryFields[strings.TrimPrefix(order, "-")]
+	column := field.column
+	if field.text != nil {
+		column = strings.Replace(text, "{column}", column, 1)
+	}
+	if strings.HasPrefix(order, "-") {
+		column += " DESC"
+	}
+
+	return column
+}
+
+// songsBy allows sorting of songs using an arbitrary comparison function
+type songsBy struct {
+	songs []Song
+	less  func(a, b Song) bool
+}
+
+// Len returns the number of songs
+func (s songsBy) Len() int {
+	return len(s.songs)
+}
+
+// Swap swaps two songs by index
+func (s songsBy) Swap(i, j int) {
+	s.songs[i], s.songs[j] = s.songs[j], s.songs[i]
+}
+
+// Less compares two songs using the comparison function
+func (s songsBy) Less(i, j int) bool {
+	return s.less(s.songs[i], s.songs[j])
+}
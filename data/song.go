@@ -1,9 +1,13 @@
 package data
 
 import (
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"io"
 	"os"
+	"time"
 
 	"github.com/wtolson/go-taglib"
 )
@@ -18,6 +22,11 @@ const (
 	OGG
 	WMA
 	WV
+	WAV
+	AIFF
+	OPUS
+	ALAC
+	DSD
 )
 
 var (
@@ -25,16 +34,32 @@ var (
 	ErrSongTags = errors.New("song: required tags could not be extracted from TagLib file")
 	// ErrSongProperties is returned when required properties could not be extracted from a TagLib file
 	ErrSongProperties = errors.New("song: required properties could not be extracted from TagLib file")
+	// ErrSongCannotSplit is returned when a CUE track is streamed from a file which cannot be
+	// split at an arbitrary byte, and so must be transcoded instead
+	ErrSongCannotSplit = errors.New("song: CUE track cannot be split from its file")
 )
 
+// ReadSeekCloser is the interface which groups the Read, Seek, and Close methods, and is used
+// to stream a Song's file
+type ReadSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
 // FileTypeMap maps song extension to wavepipe file type IDs
 var FileTypeMap = map[string]int{
+	".aif":  AIFF,
+	".aiff": AIFF,
 	".ape":  APE,
+	".dff":  DSD,
+	".dsf":  DSD,
 	".flac": FLAC,
 	".m4a":  M4A,
 	".mp3":  MP3,
 	".mpc":  MPC,
 	".ogg":  OGG,
+	".opus": OPUS,
+	".wav":  WAV,
 	".wma":  WMA,
 	".wv":   WV,
 }
@@ -49,26 +74,41 @@ var CodecMap = map[int]string{
 	OGG:  "OGG",
 	WMA:  "WMA",
 	WV:   "WV",
+	WAV:  "WAV",
+	AIFF: "AIFF",
+	OPUS: "OPUS",
+	ALAC: "ALAC",
+	DSD:  "DSD",
 }
 
-// MIMEMap maps a wavepipe file type ID its MIME type
-// BUG(mdlayher): MIMEMap: verify correctness of MIME types
+// MIMEMap maps a wavepipe file type ID its MIME type.  AAC and ALAC files share the
+// MP4 container, and so share a MIME type.
 var MIMEMap = map[int]string{
-	APE:  "audio/ape",
+	APE:  "audio/x-ape",
 	FLAC: "audio/flac",
-	M4A:  "audio/aac",
+	M4A:  "audio/mp4",
 	MP3:  "audio/mpeg",
-	MPC:  "audio/mpc",
+	MPC:  "audio/x-musepack",
 	OGG:  "audio/ogg",
-	WMA:  "audio/wma",
-	WV:   "audio/wv",
+	WMA:  "audio/x-ms-wma",
+	WV:   "audio/x-wavpack",
+	WAV:  "audio/wav",
+	AIFF: "audio/aiff",
+	OPUS: "audio/ogg",
+	ALAC: "audio/mp4",
+	DSD:  "audio/x-dsd",
 }
 
 // Song represents a song known to wavepipe, and contains metadata regarding
-// the song, and where it resides in the filsystem
+// the song, and where it resides in the filsystem.  Songs read from CUE sheets are tracks
+// within a single file, which begin and end at offsets in milliseconds.  An end offset of
+// zero is the end of the file.  The fingerprint identifies the song's file by its contents,
+// so that the song may be found again if its file is moved.
 type Song struct {
 	ID           int    `json:"id"`
+	Added        int64  `json:"added"`
 	Album        string `json:"album"`
+	AlbumArtist  string `db:"album_artist" json:"albumArtist"`
 	AlbumID      int    `db:"album_id" json:"albumId"`
 	ArtID        int    `db:"art_id" json:"artId"`
 	Artist       string `json:"artist"`
@@ -76,16 +116,25 @@ type Song struct {
 	Bitrate      int    `json:"bitrate"`
 	Channels     int    `json:"channels"`
 	Comment      string `json:"comment"`
+	Disc         int    `json:"disc"`
+	DiscTotal    int    `db:"disc_total" json:"discTotal"`
+	EndOffset    int    `db:"end_offset" json:"endOffset"`
 	FileName     string `db:"file_name" json:"fileName"`
 	FileSize     int64  `db:"file_size" json:"fileSize"`
 	FileTypeID   int    `db:"file_type_id" json:"fileTypeId"`
+	Fingerprint  string `json:"-"`
 	FolderID     int    `db:"folder_id" json:"folderId"`
 	Genre        string `json:"genre"`
 	LastModified int64  `db:"last_modified" json:"lastModified"`
+	LastPlayed   int64  `db:"last_played" json:"lastPlayed"`
 	Length       int    `json:"length"`
+	LibraryID    int    `db:"library_id" json:"libraryId"`
+	PlayCount    int    `db:"play_count" json:"playCount"`
 	SampleRate   int    `db:"sample_rate" json:"sampleRate"`
+	StartOffset  int    `db:"start_offset" json:"startOffset"`
 	Title        string `json:"title"`
 	Track        int    `json:"track"`
+	TrackTotal   int    `db:"track_total" json:"trackTotal"`
 	Year         int    `json:"year"`
 }
 
@@ -138,8 +187,12 @@ func (s *Song) Load() error {
 	return DB.LoadSong(s)
 }
 
-// Save creates a new Song in the database
+// Save creates a new Song in the database, recording the time it was added
 func (s *Song) Save() error {
+	if s.Added == 0 {
+		s.Added = time.Now().Unix()
+	}
+
 	return DB.SaveSong(s)
 }
 
@@ -148,10 +201,81 @@ func (s *Song) Update() error {
 	return DB.UpdateSong(s)
 }
 
-// Stream generates a binary file stream from this Song's file location
-func (s Song) Stream() (io.ReadSeeker, error) {
+// Stream generates a binary file stream from this Song's file location, which must be closed
+// by the caller.  Tracks read from CUE sheets only stream their section of the file, so
+// ErrSongCannotSplit is returned for CUE tracks whose file cannot be split.
+func (s Song) Stream() (ReadSeekCloser, error) {
+	if s.IsCueTrack() && !s.Splittable() {
+		return nil, ErrSongCannotSplit
+	}
+
 	// Attempt to open the file associated with this song
-	return os.Open(s.FileName)
+	file, err := os.Open(s.FileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.IsCueTrack() {
+		return file, nil
+	}
+
+	start, end := s.byteRange()
+	return &sectionStream{io.NewSectionReader(file, start, end-start), file}, nil
+}
+
+// Splittable determines if this Song's file may be split at an arbitrary byte and remain
+// playable.  MP3 files are made of independent frames, which players find by scanning the
+// stream, but other formats begin with headers which describe the entire file.
+func (s Song) Splittable() bool {
+	return s.FileTypeID == MP3
+}
+
+// sectionStream is a stream of a section of a file, which closes the file when it is closed
+type sectionStream struct {
+	*io.SectionReader
+	file *os.File
+}
+
+// Close closes the file backing a section stream
+func (s *sectionStream) Close() error {
+	return s.file.Close()
+}
+
+// StreamSize returns the number of bytes in this Song's file stream
+func (s Song) StreamSize() int64 {
+	start, end := s.byteRange()
+	return end - start
+}
+
+// IsCueTrack determines if this Song is a track read from a CUE sheet, which only occupies a
+// section of its file
+func (s Song) IsCueTrack() bool {
+	return s.StartOffset > 0 || s.EndOffset > 0
+}
+
+// byteRange estimates the range of bytes in this Song's file which contain its audio, using the
+// file's average bitrate, so the range of a CUE track is approximate.  Other songs use their
+// entire file.
+func (s Song) byteRange() (int64, int64) {
+	if !s.IsCueTrack() || s.Bitrate == 0 {
+		return 0, s.FileSize
+	}
+
+	// A bitrate in kbps is the number of bits in each millisecond
+	offset := func(ms int) int64 {
+		if b := int64(ms) * int64(s.Bitrate) / 8; b < s.FileSize {
+			return b
+		}
+
+		return s.FileSize
+	}
+
+	end := s.FileSize
+	if s.EndOffset > 0 {
+		end = offset(s.EndOffset)
+	}
+
+	return offset(s.StartOffset), end
 }
 
 // SongSlice represents a slice of songs, and provides convenience methods to access their
@@ -168,3 +292,71 @@ func (s SongSlice) Length() int {
 
 	return length
 }
+
+// Save creates a batch of new Songs in the database in a single transaction.  Song IDs are
+// not loaded.
+func (s SongSlice) Save() error {
+	// Songs are added now, unless a time is already set
+	now := time.Now().Unix()
+	for i := range s {
+		if s[i].Added == 0 {
+			s[i].Added = now
+		}
+	}
+
+	return DB.SaveSongs(s)
+}
+
+// Update updates a batch of existing Songs in the database in a single transaction
+func (s SongSlice) Update() error {
+	return DB.UpdateSongs(s)
+}
+
+// SongFile contains the filesystem information of an indexed Song, which is used to detect
+// files which have not changed since they were last scanned
+type SongFile struct {
+	FileName     string `db:"file_name"`
+	FileSize     int64  `db:"file_size"`
+	Fingerprint  string `db:"fingerprint"`
+	LastModified int64  `db:"last_modified"`
+}
+
+// Changed determines if the input file size and modification time differ from this SongFile.
+// Files indexed without a fingerprint are also changed, so that one is recorded.
+func (f SongFile) Changed(size int64, modified int64) bool {
+	return size != f.FileSize || modified != f.LastModified || f.Fingerprint == ""
+}
+
+// fingerprintBlockSize is the number of bytes read from each end of a file to fingerprint it
+const fingerprintBlockSize = 64 * 1024
+
+// Fingerprint generates a fingerprint for a media file of the specified size, by hashing its size
+// along with the blocks at the beginning and end of the file.  Reading only these blocks is much
+// faster than hashing an entire file, and tag edits will usually change the beginning block.
+func Fingerprint(r io.ReadSeeker, size int64) (string, error) {
+	hash := sha1.New()
+	if err := binary.Write(hash, binary.LittleEndian, size); err != nil {
+		return "", err
+	}
+
+	// Hash the first block, and the last block if the file is large enough to have one
+	if _, err := io.CopyN(hash, r, fingerprintBlockSize); err != nil && err != io.EOF {
+		return "", err
+	}
+
+	if size > fingerprintBlockSize {
+		offset := size - fingerprintBlockSize
+		if offset < fingerprintBlockSize {
+			offset = fingerprintBlockSize
+		}
+
+		if _, err := r.Seek(offset, os.SEEK_SET); err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(hash, r); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
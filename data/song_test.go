@@ -1,6 +1,7 @@
 package data
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -18,13 +19,22 @@ var song = Song{
 
 // TestSongDatabase verifies that an Song can be saved and loaded from the database
 func TestSongDatabase(t *testing.T) {
-	// Load database configuration
-	DB = new(SqliteBackend)
-	DB.DSN("~/.config/wavepipe/wavepipe.db")
-	if err := DB.Open(); err != nil {
-		t.Fatalf("Could not open database connection: %s", err.Error())
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
+
+	// Save the song's artist and album, so the song can be loaded
+	a := &Artist{Title: song.Artist}
+	if err := a.Save(); err != nil {
+		t.Fatalf("Could not save artist: %s", err.Error())
+	}
+	al := &Album{ArtistID: a.ID, Title: song.Album}
+	if err := al.Save(); err != nil {
+		t.Fatalf("Could not save album: %s", err.Error())
 	}
-	defer DB.Close()
+	song.ArtistID = a.ID
+	song.AlbumID = al.ID
 
 	// Attempt to save the song
 	if err := song.Save(); err != nil {
@@ -41,3 +51,58 @@ func TestSongDatabase(t *testing.T) {
 		t.Fatalf("Could not delete song: %s", err.Error())
 	}
 }
+
+// TestFingerprint verifies that fingerprints depend on the size and the contents at each end of a
+// file, and that changed files are detected
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(buf []byte) string {
+		fp, err := Fingerprint(bytes.NewReader(buf), int64(len(buf)))
+		if err != nil {
+			t.Fatalf("Could not fingerprint file: %s", err.Error())
+		}
+
+		return fp
+	}
+
+	// Files smaller than a block, and files with a short final block
+	small := fingerprint([]byte("song"))
+	if small == "" || small == fingerprint([]byte("song2")) {
+		t.Fatalf("Unexpected small fingerprint: %s", small)
+	}
+
+	file := bytes.Repeat([]byte{0x01}, 3*fingerprintBlockSize)
+	fp := fingerprint(file)
+	if fp != fingerprint(append([]byte(nil), file...)) {
+		t.Fatalf("Fingerprints of identical files differ")
+	}
+	if fp == fingerprint(file[:fingerprintBlockSize+10]) {
+		t.Fatalf("Fingerprints of files with different sizes match")
+	}
+
+	// Changes at each end change the fingerprint, while changes in the middle do not
+	tests := []struct {
+		offset int
+		same   bool
+	}{
+		{0, false},
+		{fingerprintBlockSize + 100, true},
+		{len(file) - 1, false},
+	}
+	for _, test := range tests {
+		changed := append([]byte(nil), file...)
+		changed[test.offset] = 0x02
+		if same := fingerprint(changed) == fp; same != test.same {
+			t.Fatalf("Unexpected fingerprint for change at %d: %v != %v", test.offset, same, test.same)
+		}
+	}
+
+	// Files indexed without a fingerprint are always changed
+	f := SongFile{FileSize: 10, LastModified: 20}
+	if !f.Changed(10, 20) {
+		t.Fatalf("File without fingerprint was not changed")
+	}
+	f.Fingerprint = fp
+	if f.Changed(10, 20) || !f.Changed(11, 20) {
+		t.Fatalf("Unexpected changes for file with fingerprint")
+	}
+}
@@ -0,0 +1,53 @@
+package data
+
+import (
+	"time"
+)
+
+// Star represents a user's favorite item, such as a song, album, artist, or folder
+type Star struct {
+	ID       int    `json:"id"`
+	UserID   int    `db:"user_id" json:"userId"`
+	ItemType string `db:"item_type" json:"itemType"`
+	ItemID   int    `db:"item_id" json:"itemId"`
+	Created  int64  `json:"created"`
+}
+
+// NewStar generates and saves a new star of the specified item by the specified user.
+// If the item is already starred, the existing star is loaded instead.
+func NewStar(userID int, itemType string, itemID int) (*Star, error) {
+	// Verify item exists
+	if _, err := LoadItem(itemType, itemID); err != nil {
+		return nil, err
+	}
+
+	// Generate star
+	star := &Star{
+		UserID:   userID,
+		ItemType: itemType,
+		ItemID:   itemID,
+		Created:  time.Now().Unix(),
+	}
+
+	// Save star
+	if err := star.Save(); err != nil {
+		return nil, err
+	}
+
+	return star, nil
+}
+
+// Delete removes an existing Star from the database
+func (s *Star) Delete() error {
+	return DB.DeleteStar(s)
+}
+
+// Load pulls an existing Star from the database
+func (s *Star) Load() error {
+	return DB.LoadStar(s)
+}
+
+// Save creates a new Star in the database
+func (s *Star) Save() error {
+	return DB.SaveStar(s)
+}
@@ -0,0 +1,1082 @@
+package data
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxTagSize is the maximum size of a block of tags which will be read into memory, so that
+// a corrupt file cannot cause a huge allocation.  Blocks of tags may contain embedded art, so
+// this limit is generous.
+const maxTagSize = 32 * 1024 * 1024
+
+// Tag names used by wavepipe, after normalizing the tags of each format
+const (
+	tagAlbumArtist = "ALBUMARTIST"
+	tagDisc        = "DISCNUMBER"
+	tagDiscTotal   = "DISCTOTAL"
+	tagTrack       = "TRACKNUMBER"
+	tagTrackTotal  = "TRACKTOTAL"
+
+	// Basic tags, which are normally read by TagLib, but are read by wavepipe for formats
+	// which TagLib does not support
+	tagAlbum  = "ALBUM"
+	tagArtist = "ARTIST"
+	tagGenre  = "GENRE"
+	tagTitle  = "TITLE"
+	tagYear   = "DATE"
+
+	// tagCodec holds the format of an MP4 file's audio stream, such as "mp4a" or "alac", which is
+	// read from its sample description rather than its metadata
+	tagCodec = "CODEC"
+
+	// tagPicture holds the preferred picture embedded in a file, as a FLAC picture block.  Vorbis
+	// comments use the same name for base64-encoded pictures, which are decoded when read.
+	tagPicture = "METADATA_BLOCK_PICTURE"
+)
+
+// pictureFrontCover is the picture type of a front cover, shared by ID3v2 and FLAC pictures
+const pictureFrontCover = 3
+
+var (
+	// ErrTagsTooLarge is returned when a block of tags is larger than wavepipe is willing to read
+	ErrTagsTooLarge = errors.New("tags: block of tags is too large")
+)
+
+// tagAliases maps the normalized names of tags in Vorbis comments, ID3v2 frames, MP4 atoms, and
+// APEv2 items to the tag names used by wavepipe
+var tagAliases = map[string]string{
+	// Album
+	"ALBUM": tagAlbum,
+	"TAL":   tagAlbum,
+	"TALB":  tagAlbum,
+
+	// Artist
+	"ARTIST": tagArtist,
+	"TP1":    tagArtist,
+	"TPE1":   tagArtist,
+
+	// Album artist
+	"ALBUMARTIST": tagAlbumArtist,
+	"AART":        tagAlbumArtist,
+	"TP2":         tagAlbumArtist,
+	"TPE2":        tagAlbumArtist,
+
+	// Disc number, optionally with total, such as "1/2"
+	"DISC":       tagDisc,
+	"DISCNUMBER": tagDisc,
+	"DISK":       tagDisc,
+	"TPA":        tagDisc,
+	"TPOS":       tagDisc,
+
+	// Disc total
+	"DISCTOTAL":  tagDiscTotal,
+	"TOTALDISCS": tagDiscTotal,
+
+	// Track number, optionally with total, such as "3/12"
+	"TRACK":       tagTrack,
+	"TRACKNUMBER": tagTrack,
+	"TRK":         tagTrack,
+	"TRCK":        tagTrack,
+	"TRKN":        tagTrack,
+
+	// Track total
+	"TOTALTRACKS": tagTrackTotal,
+	"TRACKTOTAL":  tagTrackTotal,
+
+	// Genre
+	"GENRE": tagGenre,
+	"TCO":   tagGenre,
+	"TCON":  tagGenre,
+
+	// Title
+	"TIT2":  tagTitle,
+	"TITLE": tagTitle,
+	"TT2":   tagTitle,
+
+	// Year, or a full date beginning with the year
+	"DATE": tagYear,
+	"TDRC": tagYear,
+	"TYE":  tagYear,
+	"TYER": tagYear,
+	"YEAR": tagYear,
+}
+
+// Picture represents an image embedded in the tags of a media file, such as a front cover
+type Picture struct {
+	Type int
+	MIME string
+	Data []byte
+}
+
+// ReadExtendedTags reads tags which TagLib does not expose, such as album artist, disc number,
+// and disc and track totals, from the media file at the specified path, and copies them into
+// this song.  Fields are left unchanged if the file does not contain the matching tags.
+// The front cover embedded in the file is returned, or its first picture if it has no front
+// cover.  If the file contains no pictures, the picture is nil.
+func (s *Song) ReadExtendedTags(path string) (*Picture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tags, err := readTags(file)
+	if err != nil {
+		return nil, err
+	}
+
+	s.applyTags(tags)
+	return parsePicture([]byte(tags[tagPicture])), nil
+}
+
+// applyTags copies album artist, disc, and track information from a map of tags into this song
+func (s *Song) applyTags(tags map[string]string) {
+	values := aliasTags(tags)
+
+	if artist := values[tagAlbumArtist]; artist != "" {
+		s.AlbumArtist = artist
+	}
+
+	// Numbers may contain their total, which is overridden by an explicit total tag
+	if disc, total := parseTagNumber(values[tagDisc]); disc > 0 {
+		s.Disc = disc
+		if total > 0 {
+			s.DiscTotal = total
+		}
+	}
+	if total, _ := parseTagNumber(values[tagDiscTotal]); total > 0 {
+		s.DiscTotal = total
+	}
+
+	if track, total := parseTagNumber(values[tagTrack]); track > 0 {
+		if s.Track == 0 {
+			s.Track = track
+		}
+		if total > 0 {
+			s.TrackTotal = total
+		}
+	}
+	if total, _ := parseTagNumber(values[tagTrackTotal]); total > 0 {
+		s.TrackTotal = total
+	}
+
+	// MP4 files may contain Apple Lossless audio, rather than AAC
+	if s.FileTypeID == M4A && tags[tagCodec] == "alac" {
+		s.FileTypeID = ALAC
+	}
+}
+
+// aliasTags maps each tag in a map of tags to wavepipe's tag names, in a consistent order,
+// keeping the first non-empty value for each name
+func aliasTags(tags map[string]string) map[string]string {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]string)
+	for _, name := range names {
+		alias, ok := tagAliases[normalizeTag(name)]
+		if !ok {
+			continue
+		}
+
+		if value := strings.TrimSpace(tags[name]); value != "" && values[alias] == "" {
+			values[alias] = value
+		}
+	}
+
+	return values
+}
+
+// normalizeTag normalizes a tag name for comparison, ignoring case, spaces, and separators
+func normalizeTag(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(name))
+}
+
+// parseTagNumber parses a number, and an optional total, from a tag such as "3" or "3/12"
+func parseTagNumber(value string) (int, int) {
+	pair := strings.SplitN(value, "/", 2)
+	number, err := strconv.Atoi(strings.TrimSpace(pair[0]))
+	if err != nil {
+		return 0, 0
+	}
+
+	if len(pair) == 1 {
+		return number, 0
+	}
+
+	total, err := strconv.Atoi(strings.TrimSpace(pair[1]))
+	if err != nil {
+		return number, 0
+	}
+
+	return number, total
+}
+
+// pictureBlock encodes a picture as a FLAC picture block, with no description or dimensions
+func pictureBlock(pictureType int, mime string, data []byte) string {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.BigEndian, uint32(pictureType))
+	binary.Write(buf, binary.BigEndian, uint32(len(mime)))
+	buf.WriteString(mime)
+	buf.Write(make([]byte, 4*5))
+	binary.Write(buf, binary.BigEndian, uint32(len(data)))
+	buf.Write(data)
+
+	return buf.String()
+}
+
+// parsePicture decodes a FLAC picture block.  Invalid blocks, and pictures which are not
+// images, such as ID3v2 links, produce no picture.
+func parsePicture(block []byte) *Picture {
+	// Read a length-prefixed field from the block
+	next := func() ([]byte, bool) {
+		if len(block) < 4 {
+			return nil, false
+		}
+
+		length := binary.BigEndian.Uint32(block[0:4])
+		if uint64(length) > uint64(len(block)-4) {
+			return nil, false
+		}
+
+		value := block[4 : 4+length]
+		block = block[4+length:]
+		return value, true
+	}
+
+	if len(block) < 4 {
+		return nil
+	}
+	p := &Picture{Type: int(binary.BigEndian.Uint32(block[0:4]))}
+	block = block[4:]
+
+	mime, ok := next()
+	if !ok {
+		return nil
+	}
+	p.MIME = strings.ToLower(string(mime))
+
+	// Skip the description, dimensions, color depth, and number of colors
+	if _, ok := next(); !ok || len(block) < 4*4 {
+		return nil
+	}
+	block = block[4*4:]
+
+	if p.Data, ok = next(); !ok || len(p.Data) == 0 {
+		return nil
+	}
+
+	// Detect the type of images with a missing or generic MIME type
+	if !strings.HasPrefix(p.MIME, "image/") {
+		p.MIME = pictureMIME(p.Data)
+	}
+	if p.MIME == "" {
+		return nil
+	}
+
+	return p
+}
+
+// pictureMIME detects the MIME type of common image formats from their contents
+func pictureMIME(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return "image/jpeg"
+	case bytes.HasPrefix(data, []byte("\x89PNG")):
+		return "image/png"
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		return "image/gif"
+	case bytes.HasPrefix(data, []byte("BM")):
+		return "image/bmp"
+	}
+
+	return ""
+}
+
+// setPicture stores a picture block in a map of tags, unless a picture is already stored.
+// Front covers replace any other type of picture.
+func setPicture(tags map[string]string, block string) {
+	current, ok := tags[tagPicture]
+	if !ok {
+		tags[tagPicture] = block
+		return
+	}
+
+	if pictureType(block) == pictureFrontCover && pictureType(current) != pictureFrontCover {
+		tags[tagPicture] = block
+	}
+}
+
+// pictureType returns the type of a FLAC picture block
+func pictureType(block string) int {
+	if len(block) < 4 {
+		return -1
+	}
+
+	return int(binary.BigEndian.Uint32([]byte(block[0:4])))
+}
+
+// readTags detects the format of a media file, and reads a map of its tags.  Formats which
+// are not recognized, or which contain no tags, produce an empty map.
+func readTags(r io.ReadSeeker) (map[string]string, error) {
+	magic := make([]byte, 12)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		// Files too short to contain tags have no tags
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return map[string]string{}, nil
+		}
+
+		return nil, err
+	}
+	if _, err := r.Seek(0, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+
+	var tags map[string]string
+	var err error
+	switch {
+	case bytes.HasPrefix(magic, []byte("ID3")):
+		tags, err = readID3Tags(r)
+	case bytes.HasPrefix(magic, []byte("fLaC")):
+		tags, err = readFLACTags(r)
+	case bytes.HasPrefix(magic, []byte("OggS")):
+		tags, err = readOggTags(r)
+	case bytes.Equal(magic[4:8], []byte("ftyp")):
+		tags, err = readMP4Tags(r)
+	case bytes.HasPrefix(magic, []byte("RIFF")) && bytes.Equal(magic[8:12], []byte("WAVE")):
+		tags, err = readChunkTags(r, 12, binary.LittleEndian, 4)
+	case bytes.HasPrefix(magic, []byte("FORM")) && (bytes.Equal(magic[8:12], []byte("AIFF")) ||
+		bytes.Equal(magic[8:12], []byte("AIFC"))):
+		tags, err = readChunkTags(r, 12, binary.BigEndian, 4)
+	case bytes.HasPrefix(magic, []byte("FRM8")):
+		tags, err = readChunkTags(r, 16, binary.BigEndian, 8)
+	case bytes.HasPrefix(magic, []byte("DSD ")):
+		tags, err = readDSFTags(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// APEv2 tags may be appended to files of many formats, such as APE, MPC, WavPack, and MP3
+	if len(tags) == 0 {
+		return readAPETags(r)
+	}
+
+	return tags, nil
+}
+
+// readBlock reads a block of tags with the specified size into memory
+func readBlock(r io.Reader, size int64) ([]byte, error) {
+	if size < 0 || size > maxTagSize {
+		return nil, ErrTagsTooLarge
+	}
+
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+// readID3Tags reads the text frames of an ID3v2.2, ID3v2.3, or ID3v2.4 tag.  User-defined text
+// frames are keyed by their description.
+func readID3Tags(r io.Reader) (map[string]string, error) {
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	major := header[3]
+	flags := header[5]
+	buf, err := readBlock(r, int64(syncsafe(header[6:10])))
+	if err != nil {
+		return nil, err
+	}
+
+	// ID3v2.4 unsynchronises each frame, but earlier versions unsynchronise the whole tag
+	if flags&0x80 != 0 && major < 4 {
+		buf = removeUnsync(buf)
+	}
+
+	// Skip the extended header, if present
+	if flags&0x40 != 0 && major >= 3 && len(buf) >= 4 {
+		size := int(binary.BigEndian.Uint32(buf[0:4])) + 4
+		if major == 4 {
+			size = syncsafe(buf[0:4])
+		}
+		if size > len(buf) {
+			return map[string]string{}, nil
+		}
+		buf = buf[size:]
+	}
+
+	// ID3v2.2 frames have shorter IDs and sizes, and no flags
+	idLen, headerLen := 4, 10
+	if major == 2 {
+		idLen, headerLen = 3, 6
+	}
+
+	tags := make(map[string]string)
+	for len(buf) >= headerLen {
+		// Padding follows the last frame
+		if buf[0] == 0 {
+			break
+		}
+
+		id := string(buf[0:idLen])
+		var size int
+		switch major {
+		case 2:
+			size = int(buf[3])<<16 | int(buf[4])<<8 | int(buf[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(buf[4:8]))
+		default:
+			size = syncsafe(buf[4:8])
+		}
+		if size < 0 || size > len(buf)-headerLen {
+			break
+		}
+
+		// Only ID3v2.3 and ID3v2.4 frames have flags
+		var frameFlags byte
+		if major >= 3 {
+			frameFlags = buf[9]
+		}
+
+		frame := buf[headerLen : headerLen+size]
+		buf = buf[headerLen+size:]
+
+		// Only text and picture frames are needed
+		isPicture := id == "APIC" || id == "PIC"
+		if id[0] != 'T' && !isPicture {
+			continue
+		}
+		if frame = id3FrameData(major, frameFlags, frame); len(frame) == 0 {
+			continue
+		}
+
+		if isPicture {
+			if block := id3Picture(id, frame); block != "" {
+				setPicture(tags, block)
+			}
+
+			continue
+		}
+
+		text := decodeID3Text(frame)
+		if id == "TXXX" || id == "TXX" {
+			pair := strings.SplitN(text, "\x00", 2)
+			if len(pair) == 2 {
+				tags[pair[0]] = strings.TrimRight(pair[1], "\x00")
+			}
+
+			continue
+		}
+
+		// ID3v2.4 separates multiple values with NUL; keep only the first
+		tags[id] = strings.SplitN(text, "\x00", 2)[0]
+	}
+
+	return tags, nil
+}
+
+// id3Picture converts the content of an ID3v2 picture frame to a FLAC picture block.  ID3v2.2
+// frames contain an image format, such as "JPG", rather than a MIME type.
+func id3Picture(id string, frame []byte) string {
+	if len(frame) < 1 {
+		return ""
+	}
+	encoding := frame[0]
+	frame = frame[1:]
+
+	var mime string
+	if id == "PIC" {
+		if len(frame) < 3 {
+			return ""
+		}
+		mime = "image/" + strings.ToLower(string(frame[0:3]))
+		if mime == "image/jpg" {
+			mime = "image/jpeg"
+		}
+		frame = frame[3:]
+	} else {
+		end := bytes.IndexByte(frame, 0)
+		if end < 0 {
+			return ""
+		}
+		mime = string(frame[:end])
+		frame = frame[end+1:]
+	}
+
+	if len(frame) < 1 {
+		return ""
+	}
+	pictureType := int(frame[0])
+	frame = frame[1:]
+
+	// Skip the description, which is terminated by a NUL character in its encoding
+	end := -1
+	switch encoding {
+	case 1, 2:
+		for i := 0; i+1 < len(frame); i += 2 {
+			if frame[i] == 0 && frame[i+1] == 0 {
+				end = i + 2
+				break
+			}
+		}
+	default:
+		if i := bytes.IndexByte(frame, 0); i >= 0 {
+			end = i + 1
+		}
+	}
+	if end < 0 {
+		return ""
+	}
+
+	return pictureBlock(pictureType, mime, frame[end:])
+}
+
+// id3FrameData removes any extra information described by the flags of an ID3v2 frame, returning
+// only the frame's content.  Compressed and encrypted frames are not supported, and return nil.
+func id3FrameData(major byte, flags byte, frame []byte) []byte {
+	switch major {
+	case 3:
+		if flags&0xc0 != 0 {
+			return nil
+		}
+
+		// Skip group identifier
+		if flags&0x20 != 0 && len(frame) > 0 {
+			frame = frame[1:]
+		}
+	case 4:
+		if flags&0x0c != 0 {
+			return nil
+		}
+
+		// Skip group identifier and data length indicator
+		if flags&0x40 != 0 && len(frame) > 0 {
+			frame = frame[1:]
+		}
+		if flags&0x01 != 0 {
+			if len(frame) < 4 {
+				return nil
+			}
+			frame = frame[4:]
+		}
+
+		if flags&0x02 != 0 {
+			frame = removeUnsync(frame)
+		}
+	}
+
+	return frame
+}
+
+// decodeID3Text decodes the content of an ID3v2 text frame, using the encoding specified by its
+// first byte: ISO-8859-1, UTF-16 with byte order mark, UTF-16BE, or UTF-8
+func decodeID3Text(frame []byte) string {
+	if len(frame) == 0 {
+		return ""
+	}
+
+	text := frame[1:]
+	switch frame[0] {
+	case 0:
+		runes := make([]rune, len(text))
+		for i, b := range text {
+			runes[i] = rune(b)
+		}
+
+		return strings.TrimRight(string(runes), "\x00")
+	case 1, 2:
+		// UTF-16 is big endian unless a byte order mark specifies otherwise
+		var order binary.ByteOrder = binary.BigEndian
+		if frame[0] == 1 && len(text) >= 2 && text[0] == 0xff && text[1] == 0xfe {
+			order = binary.LittleEndian
+		}
+
+		units := make([]uint16, 0, len(text)/2)
+		for i := 0; i+1 < len(text); i += 2 {
+			units = append(units, order.Uint16(text[i:i+2]))
+		}
+
+		// Remove byte order marks, including those which begin each value
+		decoded := strings.Replace(string(utf16.Decode(units)), "\ufeff", "", -1)
+		return strings.TrimRight(decoded, "\x00")
+	default:
+		return strings.TrimRight(string(text), "\x00")
+	}
+}
+
+// syncsafe decodes a 28-bit synchsafe integer, as used in ID3v2 sizes
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7f)<<21 | int(b[1]&0x7f)<<14 | int(b[2]&0x7f)<<7 | int(b[3]&0x7f)
+}
+
+// removeUnsync reverses ID3v2 unsynchronisation, by removing each zero byte which follows 0xff
+func removeUnsync(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	for i := 0; i < len(b); i++ {
+		out = append(out, b[i])
+		if b[i] == 0xff && i+1 < len(b) && b[i+1] == 0x00 {
+			i++
+		}
+	}
+
+	return out
+}
+
+// readFLACTags reads the Vorbis comments and pictures from the metadata blocks of a FLAC file
+func readFLACTags(r io.ReadSeeker) (map[string]string, error) {
+	// Skip the "fLaC" marker
+	if _, err := r.Seek(4, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+
+		last := header[0]&0x80 != 0
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+
+		// Parse the Vorbis comment and picture blocks, skipping all others
+		switch header[0] & 0x7f {
+		case 4, 6:
+			buf, err := readBlock(r, size)
+			if err != nil {
+				return nil, err
+			}
+
+			if header[0]&0x7f == 6 {
+				setPicture(tags, string(buf))
+				break
+			}
+
+			for name, value := range parseVorbisComments(buf) {
+				if name == tagPicture {
+					setPicture(tags, value)
+					continue
+				}
+
+				tags[name] = value
+			}
+		default:
+			if _, err := r.Seek(size, os.SEEK_CUR); err != nil {
+				return nil, err
+			}
+		}
+
+		if last {
+			return tags, nil
+		}
+	}
+}
+
+// readOggTags reads the Vorbis comments from the comment header of an Ogg Vorbis or Ogg Opus
+// file, which is the second packet of the stream
+func readOggTags(r io.Reader) (map[string]string, error) {
+	header := make([]byte, 27)
+	packet := make([]byte, 0)
+	packets := 0
+	for {
+		// Read the page header and its table of segment lengths
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(header[0:4], []byte("OggS")) {
+			return map[string]string{}, nil
+		}
+
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(r, segments); err != nil {
+			return nil, err
+		}
+
+		// Packets span segments, and segments shorter than the maximum end a packet
+		for _, length := range segments {
+			if len(packet)+int(length) > maxTagSize {
+				return nil, ErrTagsTooLarge
+			}
+
+			segment := make([]byte, length)
+			if _, err := io.ReadFull(r, segment); err != nil {
+				return nil, err
+			}
+			packet = append(packet, segment...)
+
+			if length == 255 {
+				continue
+			}
+
+			packets++
+			if packets == 2 {
+				switch {
+				case bytes.HasPrefix(packet, []byte("\x03vorbis")):
+					return parseVorbisComments(packet[7:]), nil
+				case bytes.HasPrefix(packet, []byte("OpusTags")):
+					return parseVorbisComments(packet[8:]), nil
+				}
+
+				return map[string]string{}, nil
+			}
+
+			packet = packet[:0]
+		}
+	}
+}
+
+// parseVorbisComments parses a block of Vorbis comments, as used by FLAC, Ogg Vorbis, and Ogg
+// Opus, into a map of tags
+func parseVorbisComments(buf []byte) map[string]string {
+	tags := make(map[string]string)
+
+	// Skip the vendor string
+	next := func() ([]byte, bool) {
+		if len(buf) < 4 {
+			return nil, false
+		}
+
+		length := binary.LittleEndian.Uint32(buf[0:4])
+		if uint64(length) > uint64(len(buf)-4) {
+			return nil, false
+		}
+
+		value := buf[4 : 4+length]
+		buf = buf[4+length:]
+		return value, true
+	}
+	if _, ok := next(); !ok || len(buf) < 4 {
+		return tags
+	}
+
+	count := binary.LittleEndian.Uint32(buf[0:4])
+	buf = buf[4:]
+	for i := uint32(0); i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			break
+		}
+
+		// Comments are in the form NAME=value, and names may repeat; keep only the first
+		pair := strings.SplitN(string(comment), "=", 2)
+		if len(pair) != 2 {
+			continue
+		}
+
+		name := strings.ToUpper(pair[0])
+
+		// Pictures are base64-encoded FLAC picture blocks
+		if name == tagPicture {
+			if block, err := base64.StdEncoding.DecodeString(pair[1]); err == nil {
+				setPicture(tags, string(block))
+			}
+
+			continue
+		}
+
+		if _, ok := tags[name]; !ok {
+			tags[name] = pair[1]
+		}
+	}
+
+	return tags
+}
+
+// readMP4Tags reads the iTunes-style metadata items of an MP4 file, such as an M4A file.  Disc
+// and track numbers are formatted as "number/total".
+func readMP4Tags(r io.ReadSeeker) (map[string]string, error) {
+	// Find the top-level movie atom, which contains the metadata
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			// Files without a movie atom have no tags
+			if err == io.EOF {
+				return map[string]string{}, nil
+			}
+
+			return nil, err
+		}
+
+		name := string(header[4:8])
+		size := int64(binary.BigEndian.Uint32(header[0:4])) - 8
+		switch size {
+		case -8:
+			// Atom extends to the end of the file, so the movie atom is either this one, or missing
+			if name != "moov" {
+				return map[string]string{}, nil
+			}
+
+			size = maxTagSize + 1
+		case -7:
+			// Atom has a 64-bit size
+			if _, err := io.ReadFull(r, header); err != nil {
+				return nil, err
+			}
+			size = int64(binary.BigEndian.Uint64(header)) - 16
+		}
+
+		if name == "moov" {
+			moov, err := readBlock(r, size)
+			if err != nil {
+				return nil, err
+			}
+
+			tags := parseMP4Metadata(moov)
+			if codec := mp4Codec(moov); codec != "" {
+				tags[tagCodec] = codec
+			}
+
+			return tags, nil
+		}
+
+		if size < 0 {
+			return map[string]string{}, nil
+		}
+		if _, err := r.Seek(size, os.SEEK_CUR); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseMP4Metadata parses the metadata items contained in the body of an MP4 movie atom
+func parseMP4Metadata(moov []byte) map[string]string {
+	tags := make(map[string]string)
+
+	// Metadata is usually nested in the user data atom, but may also appear directly in the movie
+	meta := mp4Atoms(moov)["meta"]
+	if udta, ok := mp4Atoms(moov)["udta"]; ok {
+		if m, ok := mp4Atoms(udta)["meta"]; ok {
+			meta = m
+		}
+	}
+
+	// The metadata atom begins with a version and flags
+	if len(meta) < 4 {
+		return tags
+	}
+
+	for name, item := range mp4Atoms(mp4Atoms(meta[4:])["ilst"]) {
+		// Each item contains a data atom, which begins with a type and locale
+		data := mp4Atoms(item)["data"]
+		if len(data) < 8 {
+			continue
+		}
+		value := data[8:]
+
+		switch name {
+		case "covr":
+			// Cover art is a JPEG, PNG, or BMP image, specified by the data type
+			mime := map[byte]string{13: "image/jpeg", 14: "image/png", 27: "image/bmp"}[data[3]]
+			setPicture(tags, pictureBlock(pictureFrontCover, mime, value))
+		case "disk", "trkn":
+			// Disc and track numbers are binary pairs of number and total
+			if len(value) >= 6 {
+				tags[name] = strconv.Itoa(int(binary.BigEndian.Uint16(value[2:4]))) + "/" +
+					strconv.Itoa(int(binary.BigEndian.Uint16(value[4:6])))
+			}
+		default:
+			tags[name] = string(value)
+		}
+	}
+
+	return tags
+}
+
+// mp4Codec returns the format of the first sample description of the first track in the body
+// of an MP4 movie atom, such as "mp4a" for AAC, or "alac" for Apple Lossless
+func mp4Codec(moov []byte) string {
+	stbl := mp4Atoms(mp4Atoms(mp4Atoms(mp4Atoms(moov)["trak"])["mdia"])["minf"])["stbl"]
+
+	// The sample description atom begins with a version, flags, and entry count, followed by
+	// the size and format of each entry
+	stsd := mp4Atoms(stbl)["stsd"]
+	if len(stsd) < 16 {
+		return ""
+	}
+
+	return string(stsd[12:16])
+}
+
+// mp4Atoms splits the body of an MP4 atom into a map of its child atoms by name, keeping the
+// first atom with each name
+func mp4Atoms(buf []byte) map[string][]byte {
+	atoms := make(map[string][]byte)
+	for len(buf) >= 8 {
+		size := int(binary.BigEndian.Uint32(buf[0:4]))
+		if size < 8 || size > len(buf) {
+			break
+		}
+
+		name := string(buf[4:8])
+		if _, ok := atoms[name]; !ok {
+			atoms[name] = buf[8:size]
+		}
+		buf = buf[size:]
+	}
+
+	return atoms
+}
+
+// readChunkTags reads the ID3v2 tag stored in an "ID3 " chunk of a WAV, AIFF, or DSDIFF file,
+// whose chunks begin at the specified offset, and have sizes of the specified length and byte
+// order.  Files without an ID3v2 chunk have no tags.
+func readChunkTags(r io.ReadSeeker, offset int64, order binary.ByteOrder, sizeLen int) (map[string]string, error) {
+	if _, err := r.Seek(offset, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 4+sizeLen)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return map[string]string{}, nil
+			}
+
+			return nil, err
+		}
+
+		// WAV files may name the chunk in lower case
+		if strings.ToUpper(string(header[0:4])) == "ID3 " {
+			return readID3Tags(r)
+		}
+
+		var size int64
+		if sizeLen == 8 {
+			size = int64(order.Uint64(header[4:]))
+		} else {
+			size = int64(order.Uint32(header[4:]))
+		}
+		if size < 0 {
+			return map[string]string{}, nil
+		}
+
+		// Chunks are padded to an even size
+		if _, err := r.Seek(size+size%2, os.SEEK_CUR); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readDSFTags reads the ID3v2 tag at the end of a DSF file, whose offset is stored in the
+// file's header.  Files with no offset have no tags.
+func readDSFTags(r io.ReadSeeker) (map[string]string, error) {
+	header := make([]byte, 28)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	offset := int64(binary.LittleEndian.Uint64(header[20:28]))
+	if offset <= 0 {
+		return map[string]string{}, nil
+	}
+	if _, err := r.Seek(offset, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+
+	return readID3Tags(r)
+}
+
+// readAPETags reads the text items of an APEv2 tag at the end of a file, which may be followed
+// by an ID3v1 tag
+func readAPETags(r io.ReadSeeker) (map[string]string, error) {
+	end, err := r.Seek(0, os.SEEK_END)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for the footer at the end of the file, and before an ID3v1 tag
+	footer := make([]byte, 32)
+	found := false
+	for _, offset := range []int64{32, 128 + 32} {
+		if end < offset {
+			break
+		}
+
+		if _, err := r.Seek(end-offset, os.SEEK_SET); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(r, footer); err != nil {
+			return nil, err
+		}
+
+		if bytes.HasPrefix(footer, []byte("APETAGEX")) {
+			found = true
+			end -= offset
+			break
+		}
+	}
+	if !found {
+		return map[string]string{}, nil
+	}
+
+	// The tag size includes the footer, but not the header
+	size := int64(binary.LittleEndian.Uint32(footer[12:16])) - 32
+	count := binary.LittleEndian.Uint32(footer[16:20])
+	if size < 0 || size > end {
+		return map[string]string{}, nil
+	}
+	if _, err := r.Seek(end-size, os.SEEK_SET); err != nil {
+		return nil, err
+	}
+	buf, err := readBlock(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	for i := uint32(0); i < count && len(buf) >= 8; i++ {
+		length := binary.LittleEndian.Uint32(buf[0:4])
+		flags := binary.LittleEndian.Uint32(buf[4:8])
+
+		// Keys are terminated by NUL, and followed by the value
+		key := bytes.IndexByte(buf[8:], 0)
+		if key < 0 || uint64(length) > uint64(len(buf)-8-key-1) {
+			break
+		}
+		name := string(buf[8 : 8+key])
+		value := buf[8+key+1 : 8+key+1+int(length)]
+		buf = buf[8+key+1+int(length):]
+
+		// Keep UTF-8 text items, and the front cover, which is a binary item containing a file
+		// name and the image
+		switch flags & 0x06 {
+		case 0:
+			tags[name] = string(value)
+		case 2:
+			if normalizeTag(name) != "COVERART(FRONT)" {
+				break
+			}
+
+			if i := bytes.IndexByte(value, 0); i >= 0 {
+				setPicture(tags, pictureBlock(pictureFrontCover, "", value[i+1:]))
+			}
+		}
+	}
+
+	return tags, nil
+}
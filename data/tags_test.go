@@ -0,0 +1,482 @@
+package data
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+)
+
+// TestReadTags verifies that album artist, disc, and track information are read from the tags
+// of each supported format
+func TestReadTags(t *testing.T) {
+	tests := []struct {
+		format string
+		data   []byte
+	}{
+		{"ID3v2.2", id3Tag(2, 0,
+			id3Frame(2, "TP2", append([]byte{0}, "Various Artists"...)),
+			id3Frame(2, "TPA", append([]byte{0}, "2/3"...)),
+			id3Frame(2, "TRK", append([]byte{0}, "4/12"...)),
+		)},
+		{"ID3v2.3", id3Tag(3, 0,
+			id3Frame(3, "APIC", bytes.Repeat([]byte{0xff}, 64)),
+			id3Frame(3, "TPE2", append([]byte{1}, utf16LE("Various Artists")...)),
+			id3Frame(3, "TPOS", append([]byte{3}, "2/3"...)),
+			id3Frame(3, "TRCK", append([]byte{0}, "4/12"...)),
+		)},
+		{"ID3v2.4", id3Tag(4, 0,
+			id3Frame(4, "TXXX", append([]byte{3}, "ALBUM ARTIST\x00Various Artists"...)),
+			id3Frame(4, "TPOS", append([]byte{3}, "2\x001"...)),
+			id3Frame(4, "TXXX", append([]byte{3}, "TOTALDISCS\x003"...)),
+			id3Frame(4, "TRCK", append([]byte{3}, "4/12"...)),
+		)},
+		{"FLAC", flacFile()},
+		{"Ogg Vorbis", oggFile()},
+		{"MP4", mp4File()},
+		{"APEv2", apeFile(false)},
+		{"APEv2 and ID3v1", apeFile(true)},
+		{"WAV", wavFile()},
+		{"AIFF", aiffFile()},
+		{"DSF", dsfFile()},
+		{"DSDIFF", dffFile()},
+	}
+
+	for _, test := range tests {
+		tags, err := readTags(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("Could not read %s tags: %s", test.format, err.Error())
+		}
+
+		s := new(Song)
+		s.applyTags(tags)
+		if s.AlbumArtist != "Various Artists" || s.Disc != 2 || s.DiscTotal != 3 || s.Track != 4 || s.TrackTotal != 12 {
+			t.Fatalf("Unexpected %s tags: %q, disc %d/%d, track %d/%d", test.format,
+				s.AlbumArtist, s.Disc, s.DiscTotal, s.Track, s.TrackTotal)
+		}
+	}
+
+	// Verify files without tags leave a song unchanged
+	s := &Song{Track: 1}
+	tags, err := readTags(bytes.NewReader([]byte("not a media file, and no tags")))
+	if err != nil {
+		t.Fatalf("Could not read tags: %s", err.Error())
+	}
+	s.applyTags(tags)
+	if s.AlbumArtist != "" || s.Disc != 0 || s.Track != 1 {
+		t.Fatalf("Unexpected song after reading no tags: %v", s)
+	}
+}
+
+// TestReadPicture verifies that the front cover, or the first picture, is read from the tags
+// of each supported format
+func TestReadPicture(t *testing.T) {
+	jpeg := append([]byte("\xff\xd8\xff\xe0"), bytes.Repeat([]byte{1}, 32)...)
+	png := append([]byte("\x89PNG\r\n"), bytes.Repeat([]byte{2}, 32)...)
+
+	// apic generates the content of an ID3v2.3 picture frame with a UTF-16 description
+	apic := func(pictureType byte, mime string, data []byte) []byte {
+		frame := append([]byte{1}, mime+"\x00"...)
+		frame = append(frame, pictureType)
+		frame = append(frame, utf16LE("Cover")...)
+		return append(append(frame, 0, 0), data...)
+	}
+
+	// vorbisPicture generates a Vorbis comment block containing a base64-encoded picture
+	vorbisPicture := func() []byte {
+		comment := tagPicture + "=" + base64.StdEncoding.EncodeToString([]byte(pictureBlock(pictureFrontCover, "image/png", png)))
+
+		buf := new(bytes.Buffer)
+		binary.Write(buf, binary.LittleEndian, uint32(0))
+		binary.Write(buf, binary.LittleEndian, uint32(1))
+		binary.Write(buf, binary.LittleEndian, uint32(len(comment)))
+		buf.WriteString(comment)
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		format string
+		data   []byte
+		mime   string
+		image  []byte
+	}{
+		{"ID3v2.2", id3Tag(2, 0,
+			id3Frame(2, "PIC", append([]byte("\x00JPG\x03Cover\x00"), jpeg...)),
+		), "image/jpeg", jpeg},
+		{"ID3v2.3", id3Tag(3, 0,
+			id3Frame(3, "APIC", apic(4, "image/jpeg", jpeg)),
+			id3Frame(3, "APIC", apic(3, "image/png", png)),
+			id3Frame(3, "TRCK", append([]byte{0}, "4/12"...)),
+		), "image/png", png},
+		{"FLAC", flacBlocks(
+			[]byte{4}, vorbisComments(),
+			[]byte{6}, []byte(pictureBlock(pictureFrontCover, "image/jpeg", jpeg)),
+		), "image/jpeg", jpeg},
+		{"Vorbis comments", flacBlocks([]byte{4}, vorbisPicture()), "image/png", png},
+		{"MP4", append(mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00")),
+			mp4Atom("moov", mp4Atom("udta", mp4Atom("meta", make([]byte, 4), mp4Atom("ilst",
+				mp4Atom("covr", mp4Atom("data", []byte{0, 0, 0, 13, 0, 0, 0, 0}, jpeg)),
+			))))...,
+		), "image/jpeg", jpeg},
+	}
+
+	for _, test := range tests {
+		tags, err := readTags(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("Could not read %s tags: %s", test.format, err.Error())
+		}
+
+		p := parsePicture([]byte(tags[tagPicture]))
+		if p == nil {
+			t.Fatalf("No %s picture found", test.format)
+		}
+		if p.MIME != test.mime || !bytes.Equal(p.Data, test.image) {
+			t.Fatalf("Unexpected %s picture: %s, %d bytes", test.format, p.MIME, len(p.Data))
+		}
+	}
+
+	// Verify files without pictures, or with invalid pictures, produce no picture
+	for _, data := range [][]byte{flacFile(), apeFile(false), id3Tag(3, 0, id3Frame(3, "APIC", bytes.Repeat([]byte{0xff}, 64)))} {
+		tags, err := readTags(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Could not read tags: %s", err.Error())
+		}
+
+		if p := parsePicture([]byte(tags[tagPicture])); p != nil {
+			t.Fatalf("Unexpected picture: %v", p)
+		}
+	}
+}
+
+// TestReadCodec verifies that MP4 files containing Apple Lossless audio are identified by their
+// sample description
+func TestReadCodec(t *testing.T) {
+	tests := []struct {
+		codec    string
+		fileType int
+	}{
+		{"mp4a", M4A},
+		{"alac", ALAC},
+	}
+
+	for _, test := range tests {
+		stsd := mp4Atom("stsd", []byte{0, 0, 0, 0, 0, 0, 0, 1}, mp4Atom(test.codec, make([]byte, 28)))
+		trak := mp4Atom("trak", mp4Atom("mdia", mp4Atom("minf", mp4Atom("stbl", stsd))))
+		data := append(mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00")), mp4Atom("moov", trak)...)
+
+		tags, err := readTags(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Could not read %s tags: %s", test.codec, err.Error())
+		}
+
+		s := &Song{FileTypeID: M4A}
+		s.applyTags(tags)
+		if s.FileTypeID != test.fileType {
+			t.Fatalf("Unexpected file type for %s: %d != %d", test.codec, s.FileTypeID, test.fileType)
+		}
+	}
+}
+
+// TestSongFromDSD verifies that tags and properties are read from DSF and DSDIFF files
+func TestSongFromDSD(t *testing.T) {
+	tests := []struct {
+		format string
+		data   []byte
+	}{
+		{"DSF", dsfFile()},
+		{"DSDIFF", dffFile()},
+	}
+
+	for _, test := range tests {
+		s, err := SongFromDSD(bytes.NewReader(test.data))
+		if err != nil {
+			t.Fatalf("Could not read %s file: %s", test.format, err.Error())
+		}
+
+		if s.Title != "Title" || s.Artist != "Artist" || s.Album != "Album" || s.Year != 2014 {
+			t.Fatalf("Unexpected %s tags: %q, %q, %q, %d", test.format, s.Title, s.Artist, s.Album, s.Year)
+		}
+		if s.Channels != 2 || s.SampleRate != 2822400 || s.Length != 2 || s.Bitrate != 5644 {
+			t.Fatalf("Unexpected %s properties: %d channels, %d Hz, %d seconds, %d kbps", test.format,
+				s.Channels, s.SampleRate, s.Length, s.Bitrate)
+		}
+	}
+
+	// Verify files without required tags are rejected
+	data := dsfFile()
+	binary.LittleEndian.PutUint64(data[20:28], 0)
+	if _, err := SongFromDSD(bytes.NewReader(data)); err != ErrSongTags {
+		t.Fatalf("Unexpected error for DSF file without tags: %v", err)
+	}
+}
+
+// id3Tag generates an ID3v2 tag containing the input frames
+func id3Tag(major byte, flags byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	body = append(body, make([]byte, 16)...)
+
+	return append(append([]byte{'I', 'D', '3', major, 0, flags}, syncsafeBytes(len(body))...), body...)
+}
+
+// id3Frame generates an ID3v2 frame with the specified ID and content
+func id3Frame(major byte, id string, data []byte) []byte {
+	if major == 2 {
+		return append([]byte{id[0], id[1], id[2], byte(len(data) >> 16), byte(len(data) >> 8), byte(len(data))}, data...)
+	}
+
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(data)))
+	if major == 4 {
+		size = syncsafeBytes(len(data))
+	}
+
+	frame := append([]byte(id), size...)
+	return append(append(frame, 0, 0), data...)
+}
+
+// syncsafeBytes encodes a 28-bit synchsafe integer
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n>>21) & 0x7f, byte(n>>14) & 0x7f, byte(n>>7) & 0x7f, byte(n) & 0x7f}
+}
+
+// utf16LE encodes a string as UTF-16, with a little endian byte order mark
+func utf16LE(s string) []byte {
+	out := []byte{0xff, 0xfe}
+	for _, r := range s {
+		out = append(out, byte(r), byte(r>>8))
+	}
+
+	return out
+}
+
+// vorbisComments generates a block of Vorbis comments containing the test tags
+func vorbisComments() []byte {
+	comments := []string{
+		"ALBUMARTIST=Various Artists",
+		"DISCNUMBER=2",
+		"DISCTOTAL=3",
+		"tracknumber=4",
+		"TRACKTOTAL=12",
+	}
+
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, uint32(4))
+	buf.WriteString("test")
+	binary.Write(buf, binary.LittleEndian, uint32(len(comments)))
+	for _, c := range comments {
+		binary.Write(buf, binary.LittleEndian, uint32(len(c)))
+		buf.WriteString(c)
+	}
+
+	return buf.Bytes()
+}
+
+// flacFile generates a FLAC file containing a stream information block and Vorbis comments
+func flacFile() []byte {
+	comments := vorbisComments()
+
+	buf := bytes.NewBufferString("fLaC")
+	buf.Write([]byte{0, 0, 0, 34})
+	buf.Write(make([]byte, 34))
+	buf.Write([]byte{0x84, byte(len(comments) >> 16), byte(len(comments) >> 8), byte(len(comments))})
+	buf.Write(comments)
+
+	return buf.Bytes()
+}
+
+// flacBlocks generates a FLAC file containing metadata blocks, specified as pairs of a block
+// type and its contents
+func flacBlocks(blocks ...[]byte) []byte {
+	buf := bytes.NewBufferString("fLaC")
+	for i := 0; i+1 < len(blocks); i += 2 {
+		header := blocks[i][0]
+		if i+2 == len(blocks) {
+			header |= 0x80
+		}
+
+		size := len(blocks[i+1])
+		buf.Write([]byte{header, byte(size >> 16), byte(size >> 8), byte(size)})
+		buf.Write(blocks[i+1])
+	}
+
+	return buf.Bytes()
+}
+
+// oggFile generates an Ogg Vorbis file, with the comment header spanning two pages
+func oggFile() []byte {
+	// Pad the comment header to 600 bytes, so that it spans three segments
+	comment := append([]byte("\x03vorbis"), vorbisComments()...)
+	comment = append(comment, make([]byte, 600-len(comment))...)
+
+	page := func(packet []byte, segments []byte) []byte {
+		header := append([]byte("OggS"), make([]byte, 22)...)
+		header = append(header, byte(len(segments)))
+		return append(append(header, segments...), packet...)
+	}
+
+	buf := new(bytes.Buffer)
+	buf.Write(page(append([]byte("\x01vorbis"), make([]byte, 23)...), []byte{30}))
+	buf.Write(page(comment[:255], []byte{255}))
+	buf.Write(page(comment[255:], []byte{255, 90}))
+
+	return buf.Bytes()
+}
+
+// mp4Atom generates an MP4 atom with the specified name and body
+func mp4Atom(name string, body ...[]byte) []byte {
+	data := bytes.Join(body, nil)
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(data)+8))
+
+	return append(append(size, name...), data...)
+}
+
+// mp4File generates an MP4 file containing iTunes-style metadata items
+func mp4File() []byte {
+	data := func(value []byte) []byte {
+		return mp4Atom("data", make([]byte, 8), value)
+	}
+
+	ilst := mp4Atom("ilst",
+		mp4Atom("aART", data([]byte("Various Artists"))),
+		mp4Atom("disk", data([]byte{0, 0, 0, 2, 0, 3})),
+		mp4Atom("trkn", data([]byte{0, 0, 0, 4, 0, 12, 0, 0})),
+	)
+
+	return append(mp4Atom("ftyp", []byte("M4A \x00\x00\x00\x00")),
+		mp4Atom("moov", mp4Atom("udta", mp4Atom("meta", make([]byte, 4), ilst)))...)
+}
+
+// chunkTag generates an ID3v2.3 tag containing the test tags, as stored in a chunk of a WAV,
+// AIFF, or DSD file
+func chunkTag() []byte {
+	return id3Tag(3, 0,
+		id3Frame(3, "TIT2", append([]byte{0}, "Title"...)),
+		id3Frame(3, "TPE1", append([]byte{0}, "Artist"...)),
+		id3Frame(3, "TALB", append([]byte{0}, "Album"...)),
+		id3Frame(3, "TYER", append([]byte{0}, "2014"...)),
+		id3Frame(3, "TPE2", append([]byte{0}, "Various Artists"...)),
+		id3Frame(3, "TPOS", append([]byte{0}, "2/3"...)),
+		id3Frame(3, "TRCK", append([]byte{0}, "4/12"...)),
+	)
+}
+
+// chunk generates a chunk of a WAV, AIFF, or DSDIFF file, with a size of the specified length
+// and byte order, padded to an even size
+func chunk(order binary.ByteOrder, sizeLen int, id string, body ...[]byte) []byte {
+	data := bytes.Join(body, nil)
+	size := make([]byte, 8)
+	order.PutUint64(size, uint64(len(data)))
+	if sizeLen == 4 {
+		order.PutUint32(size, uint32(len(data)))
+	}
+
+	out := append(append([]byte(id), size[:sizeLen]...), data...)
+	if len(data)%2 != 0 {
+		out = append(out, 0)
+	}
+
+	return out
+}
+
+// wavFile generates a WAV file with an odd-sized data chunk, followed by an ID3v2 chunk
+func wavFile() []byte {
+	le := binary.LittleEndian
+	return chunk(le, 4, "RIFF", []byte("WAVE"),
+		chunk(le, 4, "fmt ", make([]byte, 16)),
+		chunk(le, 4, "data", make([]byte, 3)),
+		chunk(le, 4, "id3 ", chunkTag()),
+	)
+}
+
+// aiffFile generates an AIFF file with an odd-sized sound data chunk, followed by an ID3v2 chunk
+func aiffFile() []byte {
+	be := binary.BigEndian
+	return chunk(be, 4, "FORM", []byte("AIFF"),
+		chunk(be, 4, "COMM", make([]byte, 18)),
+		chunk(be, 4, "SSND", make([]byte, 5)),
+		chunk(be, 4, "ID3 ", chunkTag()),
+	)
+}
+
+// dsfFile generates a two second, stereo DSF file, with an ID3v2 tag following its audio
+func dsfFile() []byte {
+	le := binary.LittleEndian
+	samples := 2 * 2822400
+
+	format := new(bytes.Buffer)
+	for _, v := range []uint32{1, 0, 2, 2, 2822400, 1} {
+		binary.Write(format, le, v)
+	}
+	binary.Write(format, le, uint64(samples))
+	binary.Write(format, le, uint32(4096))
+	binary.Write(format, le, uint32(0))
+
+	body := append(chunk(le, 8, "fmt ", format.Bytes()), chunk(le, 8, "data", make([]byte, 16))...)
+
+	// DSF chunk sizes include their headers
+	binary.LittleEndian.PutUint64(body[4:12], uint64(12+format.Len()))
+	binary.LittleEndian.PutUint64(body[12+format.Len()+4:], 12+16)
+
+	tag := chunkTag()
+	header := []byte("DSD ")
+	for _, v := range []uint64{28, uint64(28 + len(body) + len(tag)), uint64(28 + len(body))} {
+		b := make([]byte, 8)
+		le.PutUint64(b, v)
+		header = append(header, b...)
+	}
+
+	return append(append(header, body...), tag...)
+}
+
+// dffFile generates a two second, stereo DSDIFF file, with an ID3v2 chunk following its audio
+func dffFile() []byte {
+	be := binary.BigEndian
+	rate := make([]byte, 4)
+	be.PutUint32(rate, 2822400)
+
+	return chunk(be, 8, "FRM8", []byte("DSD "),
+		chunk(be, 8, "FVER", []byte{1, 5, 0, 0}),
+		chunk(be, 8, "PROP", []byte("SND "),
+			chunk(be, 8, "FS  ", rate),
+			chunk(be, 8, "CHNL", []byte{0, 2}, []byte("SLFTSRGT")),
+		),
+		chunk(be, 8, "DSD ", make([]byte, 2*2*2822400/8)),
+		chunk(be, 8, "ID3 ", chunkTag()),
+	)
+}
+
+// apeFile generates a Monkey's Audio file containing an APEv2 tag, optionally followed by an
+// ID3v1 tag
+func apeFile(id3v1 bool) []byte {
+	items := [][2]string{
+		{"Album Artist", "Various Artists"},
+		{"Disc", "2/3"},
+		{"Track", "4/12"},
+	}
+
+	body := new(bytes.Buffer)
+	for _, item := range items {
+		binary.Write(body, binary.LittleEndian, uint32(len(item[1])))
+		binary.Write(body, binary.LittleEndian, uint32(0))
+		body.WriteString(item[0])
+		body.WriteByte(0)
+		body.WriteString(item[1])
+	}
+
+	buf := bytes.NewBufferString("MAC ")
+	buf.Write(make([]byte, 64))
+	buf.Write(body.Bytes())
+	buf.WriteString("APETAGEX")
+	binary.Write(buf, binary.LittleEndian, uint32(2000))
+	binary.Write(buf, binary.LittleEndian, uint32(body.Len()+32))
+	binary.Write(buf, binary.LittleEndian, uint32(len(items)))
+	buf.Write(make([]byte, 12))
+
+	if id3v1 {
+		buf.WriteString("TAG")
+		buf.Write(make([]byte, 125))
+	}
+
+	return buf.Bytes()
+}
@@ -6,13 +6,10 @@ import (
 
 // TestUserDatabase verifies that an User can be saved and loaded from the database
 func TestUserDatabase(t *testing.T) {
-	// Load database configuration
-	DB = new(SqliteBackend)
-	DB.DSN("~/.config/wavepipe/wavepipe.db")
-	if err := DB.Open(); err != nil {
-		t.Fatalf("Could not open database connection: %s", err.Error())
-	}
-	defer DB.Close()
+	// Load a temporary database
+	db, cleanup := testSqliteBackend(t)
+	defer cleanup()
+	DB = db
 
 	// Attempt to create and save the user
 	user, err := NewUser("TestUser", "TestPassword", RoleGuest)
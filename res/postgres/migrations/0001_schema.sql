@@ -0,0 +1,88 @@
+/* wavepipe postgres migration 0001: initial schema, including user playlists */
+/* albums */
+CREATE TABLE IF NOT EXISTS "albums" (
+	"id"        SERIAL PRIMARY KEY,
+	"artist_id" INTEGER NOT NULL,
+	"title"     TEXT,
+	"year"      INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "albums_unique_artistId_title" ON "albums" ("artist_id", "title");
+/* art */
+CREATE TABLE IF NOT EXISTS "art" (
+	"id"            SERIAL PRIMARY KEY,
+	"file_size"     BIGINT NOT NULL,
+	"file_name"     TEXT,
+	"last_modified" BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "art_unique_fileName" ON "art" ("file_name");
+/* artists */
+CREATE TABLE IF NOT EXISTS "artists" (
+	"id"    SERIAL PRIMARY KEY,
+	"title" TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "artists_unique_title" ON "artists" ("title");
+/* folders */
+CREATE TABLE IF NOT EXISTS "folders" (
+	"id"        SERIAL PRIMARY KEY,
+	"parent_id" INTEGER,
+	"title"     TEXT,
+	"path"      TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "folders_unique_path" ON "folders" ("path");
+/* playlist_entries */
+CREATE TABLE IF NOT EXISTS "playlist_entries" (
+	"id"          SERIAL PRIMARY KEY,
+	"playlist_id" INTEGER NOT NULL,
+	"song_id"     INTEGER NOT NULL,
+	"position"    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "playlist_entries_playlistId" ON "playlist_entries" ("playlist_id");
+/* playlists */
+CREATE TABLE IF NOT EXISTS "playlists" (
+	"id"      SERIAL PRIMARY KEY,
+	"user_id" INTEGER NOT NULL,
+	"title"   TEXT,
+	"public"  BOOLEAN NOT NULL,
+	"created" BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "playlists_unique_userId_title" ON "playlists" ("user_id", "title");
+/* sessions */
+CREATE TABLE IF NOT EXISTS "sessions" (
+	"id"      SERIAL PRIMARY KEY,
+	"user_id" INTEGER NOT NULL,
+	"client"  TEXT,
+	"expire"  BIGINT NOT NULL,
+	"key"     TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "sessions_unique_key" ON "sessions" ("key");
+/* songs */
+CREATE TABLE IF NOT EXISTS "songs" (
+	"id"            SERIAL PRIMARY KEY,
+	"album_id"      INTEGER NOT NULL,
+	"art_id"        INTEGER NOT NULL,
+	"artist_id"     INTEGER NOT NULL,
+	"bitrate"       INTEGER NOT NULL,
+	"channels"      INTEGER NOT NULL,
+	"comment"       TEXT,
+	"file_name"     TEXT,
+	"file_size"     BIGINT NOT NULL,
+	"file_type_id"  INTEGER NOT NULL,
+	"folder_id"     INTEGER NOT NULL,
+	"genre"         TEXT,
+	"last_modified" BIGINT NOT NULL,
+	"length"        INTEGER NOT NULL,
+	"sample_rate"   INTEGER NOT NULL,
+	"title"         TEXT,
+	"track"         INTEGER,
+	"year"          INTEGER
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "songs_unique_fileName" ON "songs" ("file_name");
+/* users */
+CREATE TABLE IF NOT EXISTS "users" (
+	"id"           SERIAL PRIMARY KEY,
+	"username"     TEXT,
+	"password"     TEXT,
+	"role_id"      INTEGER,
+	"lastfm_token" TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "users_unique_username" ON "users" ("username");
@@ -0,0 +1,9 @@
+/* wavepipe postgres migration 0002: song play history */
+CREATE TABLE IF NOT EXISTS "plays" (
+	"id"        SERIAL PRIMARY KEY,
+	"user_id"   INTEGER NOT NULL,
+	"song_id"   INTEGER NOT NULL,
+	"timestamp" BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "plays_unique_userId_songId_timestamp" ON "plays" ("user_id", "song_id", "timestamp");
+CREATE INDEX IF NOT EXISTS "plays_songId" ON "plays" ("song_id");
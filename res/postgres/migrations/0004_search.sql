@@ -0,0 +1,3 @@
+/* wavepipe postgres migration 0004: full-text search indexes */
+CREATE INDEX IF NOT EXISTS "artists_search" ON "artists" USING GIN (to_tsvector('simple', COALESCE("title", '')));
+CREATE INDEX IF NOT EXISTS "folders_search" ON "folders" USING GIN (to_tsvector('simple', COALESCE("title", '')));
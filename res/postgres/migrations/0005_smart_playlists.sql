@@ -0,0 +1,14 @@
+/* wavepipe postgres migration 0005: smart playlists, and song added time */
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "added" BIGINT NOT NULL DEFAULT 0;
+UPDATE "songs" SET "added" = "last_modified";
+CREATE TABLE IF NOT EXISTS "smart_playlists" (
+	"id"         SERIAL PRIMARY KEY,
+	"user_id"    INTEGER NOT NULL,
+	"title"      TEXT,
+	"public"     BOOLEAN NOT NULL,
+	"query"      TEXT,
+	"sort"       TEXT,
+	"song_limit" INTEGER NOT NULL,
+	"created"    BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "smart_playlists_unique_userId_title" ON "smart_playlists" ("user_id", "title");
@@ -0,0 +1,4 @@
+/* wavepipe postgres migration 0006: song disc numbers, and disc and track totals */
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "disc" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "disc_total" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "track_total" INTEGER NOT NULL DEFAULT 0;
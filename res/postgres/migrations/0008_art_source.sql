@@ -0,0 +1,2 @@
+/* wavepipe postgres migration 0008: embedded art source */
+ALTER TABLE "art" ADD COLUMN IF NOT EXISTS "source" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+/* wavepipe postgres migration 0009: album art and galleries */
+ALTER TABLE "albums" ADD COLUMN IF NOT EXISTS "art_id" INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS "album_gallery" (
+	"id"       SERIAL PRIMARY KEY,
+	"album_id" INTEGER NOT NULL,
+	"art_id"   INTEGER NOT NULL,
+	"position" INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "album_gallery_albumId" ON "album_gallery" ("album_id");
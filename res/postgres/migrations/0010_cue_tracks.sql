@@ -0,0 +1,5 @@
+/* wavepipe postgres migration 0010: CUE sheet tracks within a single file */
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "start_offset" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "end_offset" INTEGER NOT NULL DEFAULT 0;
+DROP INDEX IF EXISTS "songs_unique_fileName";
+CREATE UNIQUE INDEX IF NOT EXISTS "songs_unique_fileName_startOffset" ON "songs" ("file_name", "start_offset");
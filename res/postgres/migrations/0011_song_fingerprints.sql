@@ -0,0 +1,3 @@
+/* wavepipe postgres migration 0011: song fingerprints */
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "fingerprint" TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "songs_fingerprint" ON "songs" ("fingerprint");
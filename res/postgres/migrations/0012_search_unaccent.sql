@@ -0,0 +1,8 @@
+/* wavepipe postgres migration 0012: full-text search which ignores diacritics */
+CREATE EXTENSION IF NOT EXISTS "unaccent";
+CREATE TEXT SEARCH CONFIGURATION "wavepipe_search" (COPY = simple);
+ALTER TEXT SEARCH CONFIGURATION "wavepipe_search" ALTER MAPPING FOR "asciihword", "asciiword", "hword", "hword_asciipart", "hword_part", "word" WITH "unaccent", "simple";
+DROP INDEX IF EXISTS "artists_search";
+DROP INDEX IF EXISTS "folders_search";
+CREATE INDEX IF NOT EXISTS "artists_search" ON "artists" USING GIN (to_tsvector('wavepipe_search', COALESCE("title", '')));
+CREATE INDEX IF NOT EXISTS "folders_search" ON "folders" USING GIN (to_tsvector('wavepipe_search', COALESCE("title", '')));
@@ -0,0 +1,16 @@
+/* wavepipe sqlite migration 0001: user playlists */
+CREATE TABLE IF NOT EXISTS "playlist_entries" (
+	"id"          INTEGER PRIMARY KEY AUTOINCREMENT,
+	"playlist_id" INTEGER NOT NULL,
+	"song_id"     INTEGER NOT NULL,
+	"position"    INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "playlist_entries_playlistId" ON "playlist_entries" ("playlist_id");
+CREATE TABLE IF NOT EXISTS "playlists" (
+	"id"      INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id" INTEGER NOT NULL,
+	"title"   TEXT,
+	"public"  INTEGER NOT NULL,
+	"created" INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "playlists_unique_userId_title" ON "playlists" ("user_id", "title");
@@ -0,0 +1,17 @@
+/* wavepipe sqlite migration 0003: per-user stars and ratings */
+CREATE TABLE IF NOT EXISTS "ratings" (
+	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"   INTEGER NOT NULL,
+	"item_type" TEXT NOT NULL,
+	"item_id"   INTEGER NOT NULL,
+	"rating"    INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "ratings_unique_userId_itemType_itemId" ON "ratings" ("user_id", "item_type", "item_id");
+CREATE TABLE IF NOT EXISTS "stars" (
+	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"   INTEGER NOT NULL,
+	"item_type" TEXT NOT NULL,
+	"item_id"   INTEGER NOT NULL,
+	"created"   INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "stars_unique_userId_itemType_itemId" ON "stars" ("user_id", "item_type", "item_id");
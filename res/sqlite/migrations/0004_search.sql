@@ -0,0 +1,48 @@
+/* wavepipe sqlite migration 0004: full-text search index */
+CREATE VIRTUAL TABLE IF NOT EXISTS "albums_search" USING fts5("title", "artist", tokenize = 'unicode61 remove_diacritics 2');
+CREATE VIRTUAL TABLE IF NOT EXISTS "artists_search" USING fts5("title", tokenize = 'unicode61 remove_diacritics 2');
+CREATE VIRTUAL TABLE IF NOT EXISTS "folders_search" USING fts5("title", tokenize = 'unicode61 remove_diacritics 2');
+CREATE VIRTUAL TABLE IF NOT EXISTS "songs_search" USING fts5("title", "artist", "album", "genre", "comment", tokenize = 'unicode61 remove_diacritics 2');
+CREATE TRIGGER IF NOT EXISTS "albums_search_insert" AFTER INSERT ON "albums" BEGIN
+	INSERT INTO "albums_search" ("rowid", "title", "artist")
+		VALUES (new."id", new."title", (SELECT "title" FROM "artists" WHERE "id" = new."artist_id"));
+END;
+CREATE TRIGGER IF NOT EXISTS "albums_search_delete" AFTER DELETE ON "albums" BEGIN
+	DELETE FROM "albums_search" WHERE "rowid" = old."id";
+END;
+CREATE TRIGGER IF NOT EXISTS "artists_search_insert" AFTER INSERT ON "artists" BEGIN
+	INSERT INTO "artists_search" ("rowid", "title") VALUES (new."id", new."title");
+END;
+CREATE TRIGGER IF NOT EXISTS "artists_search_delete" AFTER DELETE ON "artists" BEGIN
+	DELETE FROM "artists_search" WHERE "rowid" = old."id";
+END;
+CREATE TRIGGER IF NOT EXISTS "folders_search_insert" AFTER INSERT ON "folders" BEGIN
+	INSERT INTO "folders_search" ("rowid", "title") VALUES (new."id", new."title");
+END;
+CREATE TRIGGER IF NOT EXISTS "folders_search_delete" AFTER DELETE ON "folders" BEGIN
+	DELETE FROM "folders_search" WHERE "rowid" = old."id";
+END;
+CREATE TRIGGER IF NOT EXISTS "songs_search_insert" AFTER INSERT ON "songs" BEGIN
+	INSERT INTO "songs_search" ("rowid", "title", "artist", "album", "genre", "comment")
+		VALUES (new."id", new."title", (SELECT "title" FROM "artists" WHERE "id" = new."artist_id"),
+			(SELECT "title" FROM "albums" WHERE "id" = new."album_id"), new."genre", new."comment");
+END;
+CREATE TRIGGER IF NOT EXISTS "songs_search_update" AFTER UPDATE ON "songs" BEGIN
+	DELETE FROM "songs_search" WHERE "rowid" = old."id";
+	INSERT INTO "songs_search" ("rowid", "title", "artist", "album", "genre", "comment")
+		VALUES (new."id", new."title", (SELECT "title" FROM "artists" WHERE "id" = new."artist_id"),
+			(SELECT "title" FROM "albums" WHERE "id" = new."album_id"), new."genre", new."comment");
+END;
+CREATE TRIGGER IF NOT EXISTS "songs_search_delete" AFTER DELETE ON "songs" BEGIN
+	DELETE FROM "songs_search" WHERE "rowid" = old."id";
+END;
+/* Index all existing items */
+INSERT INTO "albums_search" ("rowid", "title", "artist")
+	SELECT "albums"."id", "albums"."title", "artists"."title" FROM "albums"
+	LEFT JOIN "artists" ON "albums"."artist_id" = "artists"."id";
+INSERT INTO "artists_search" ("rowid", "title") SELECT "id", "title" FROM "artists";
+INSERT INTO "folders_search" ("rowid", "title") SELECT "id", "title" FROM "folders";
+INSERT INTO "songs_search" ("rowid", "title", "artist", "album", "genre", "comment")
+	SELECT "songs"."id", "songs"."title", "artists"."title", "albums"."title", "songs"."genre", "songs"."comment" FROM "songs"
+	LEFT JOIN "artists" ON "songs"."artist_id" = "artists"."id"
+	LEFT JOIN "albums" ON "songs"."album_id" = "albums"."id";
@@ -0,0 +1,14 @@
+/* wavepipe sqlite migration 0005: smart playlists, and song added time */
+ALTER TABLE "songs" ADD COLUMN "added" INTEGER NOT NULL DEFAULT 0;
+UPDATE "songs" SET "added" = "last_modified";
+CREATE TABLE IF NOT EXISTS "smart_playlists" (
+	"id"         INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"    INTEGER NOT NULL,
+	"title"      TEXT,
+	"public"     INTEGER NOT NULL,
+	"query"      TEXT,
+	"sort"       TEXT,
+	"song_limit" INTEGER NOT NULL,
+	"created"    INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "smart_playlists_unique_userId_title" ON "smart_playlists" ("user_id", "title");
@@ -0,0 +1,4 @@
+/* wavepipe sqlite migration 0006: song disc numbers, and disc and track totals */
+ALTER TABLE "songs" ADD COLUMN "disc" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "songs" ADD COLUMN "disc_total" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "songs" ADD COLUMN "track_total" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+/* wavepipe sqlite migration 0007: media libraries */
+CREATE TABLE IF NOT EXISTS "libraries" (
+	"id"   INTEGER PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT,
+	"path" TEXT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "libraries_unique_name" ON "libraries" ("name");
+ALTER TABLE "art" ADD COLUMN "library_id" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "folders" ADD COLUMN "library_id" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "songs" ADD COLUMN "library_id" INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "songs_libraryId" ON "songs" ("library_id");
@@ -0,0 +1,2 @@
+/* wavepipe sqlite migration 0008: embedded art source */
+ALTER TABLE "art" ADD COLUMN "source" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+/* wavepipe sqlite migration 0009: album art and galleries */
+ALTER TABLE "albums" ADD COLUMN "art_id" INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS "album_gallery" (
+	"id"       INTEGER PRIMARY KEY AUTOINCREMENT,
+	"album_id" INTEGER NOT NULL,
+	"art_id"   INTEGER NOT NULL,
+	"position" INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "album_gallery_albumId" ON "album_gallery" ("album_id");
@@ -0,0 +1,7 @@
+/* wavepipe sqlite migration 0011: song fingerprints, and folder search updates for moved folders */
+ALTER TABLE "songs" ADD COLUMN "fingerprint" TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "songs_fingerprint" ON "songs" ("fingerprint");
+CREATE TRIGGER IF NOT EXISTS "folders_search_update" AFTER UPDATE ON "folders" BEGIN
+	DELETE FROM "folders_search" WHERE "rowid" = old."id";
+	INSERT INTO "folders_search" ("rowid", "title") VALUES (new."id", new."title");
+END;
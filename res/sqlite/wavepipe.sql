@@ -1,12 +1,21 @@
 /* wavepipe sqlite schema */
 PRAGMA foreign_keys = OFF;
 BEGIN TRANSACTION;
+/* album_gallery */
+CREATE TABLE "album_gallery" (
+	"id"       INTEGER PRIMARY KEY AUTOINCREMENT,
+	"album_id" INTEGER NOT NULL,
+	"art_id"   INTEGER NOT NULL,
+	"position" INTEGER NOT NULL
+);
+CREATE INDEX "album_gallery_albumId" ON "album_gallery" ("album_id");
 /* albums */
 CREATE TABLE "albums" (
 	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
 	"artist_id" INTEGER NOT NULL,
 	"title"     TEXT,
-	"year"      INTEGER
+	"year"      INTEGER,
+	"art_id"    INTEGER NOT NULL DEFAULT 0
 );
 CREATE UNIQUE INDEX "albums_unique_artistId_title" ON "albums" ("artist_id", "title");
 /* art */
@@ -14,7 +23,9 @@ CREATE TABLE "art" (
 	"id"            INTEGER PRIMARY KEY AUTOINCREMENT,
 	"file_size"     INTEGER NOT NULL,
 	"file_name"     TEXT,
-	"last_modified" INTEGER NOT NULL
+	"last_modified" INTEGER NOT NULL,
+	"library_id"    INTEGER NOT NULL DEFAULT 0,
+	"source"        INTEGER NOT NULL DEFAULT 0
 );
 CREATE UNIQUE INDEX "art_unique_fileName" ON "art" ("file_name");
 /* artists */
@@ -25,12 +36,55 @@ CREATE TABLE "artists" (
 CREATE UNIQUE INDEX "artists_unique_title" ON "artists" ("title");
 /* folders */
 CREATE TABLE "folders" (
-	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
-	"parent_id" INTEGER,
-	"title"     TEXT,
-	"path"      TEXT
+	"id"         INTEGER PRIMARY KEY AUTOINCREMENT,
+	"parent_id"  INTEGER,
+	"title"      TEXT,
+	"path"       TEXT,
+	"library_id" INTEGER NOT NULL DEFAULT 0
 );
 CREATE UNIQUE INDEX "folders_unique_path" ON "folders" ("path");
+/* libraries */
+CREATE TABLE "libraries" (
+	"id"   INTEGER PRIMARY KEY AUTOINCREMENT,
+	"name" TEXT,
+	"path" TEXT
+);
+CREATE UNIQUE INDEX "libraries_unique_name" ON "libraries" ("name");
+/* plays */
+CREATE TABLE "plays" (
+	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"   INTEGER NOT NULL,
+	"song_id"   INTEGER NOT NULL,
+	"timestamp" INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX "plays_unique_userId_songId_timestamp" ON "plays" ("user_id", "song_id", "timestamp");
+CREATE INDEX "plays_songId" ON "plays" ("song_id");
+/* playlist_entries */
+CREATE TABLE "playlist_entries" (
+	"id"          INTEGER PRIMARY KEY AUTOINCREMENT,
+	"playlist_id" INTEGER NOT NULL,
+	"song_id"     INTEGER NOT NULL,
+	"position"    INTEGER NOT NULL
+);
+CREATE INDEX "playlist_entries_playlistId" ON "playlist_entries" ("playlist_id");
+/* playlists */
+CREATE TABLE "playlists" (
+	"id"      INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id" INTEGER NOT NULL,
+	"title"   TEXT,
+	"public"  INTEGER NOT NULL,
+	"created" INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX "playlists_unique_userId_title" ON "playlists" ("user_id", "title");
+/* ratings */
+CREATE TABLE "ratings" (
+	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"   INTEGER NOT NULL,
+	"item_type" TEXT NOT NULL,
+	"item_id"   INTEGER NOT NULL,
+	"rating"    INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX "ratings_unique_userId_itemType_itemId" ON "ratings" ("user_id", "item_type", "item_id");
 /* sessions */
 CREATE TABLE "sessions" (
 	"id"      INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -40,28 +94,59 @@ CREATE TABLE "sessions" (
 	"key"     TEXT
 );
 CREATE UNIQUE INDEX "sessions_unique_key" ON "sessions" ("key");
+/* smart_playlists */
+CREATE TABLE "smart_playlists" (
+	"id"         INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"    INTEGER NOT NULL,
+	"title"      TEXT,
+	"public"     INTEGER NOT NULL,
+	"query"      TEXT,
+	"sort"       TEXT,
+	"song_limit" INTEGER NOT NULL,
+	"created"    INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX "smart_playlists_unique_userId_title" ON "smart_playlists" ("user_id", "title");
 /* songs */
 CREATE TABLE "songs" (
 	"id"            INTEGER PRIMARY KEY AUTOINCREMENT,
+	"added"         INTEGER NOT NULL DEFAULT 0,
 	"album_id"      INTEGER NOT NULL,
 	"art_id"        INTEGER NOT NULL,
 	"artist_id"     INTEGER NOT NULL,
 	"bitrate"       INTEGER NOT NULL,
 	"channels"      INTEGER NOT NULL,
 	"comment"       TEXT,
+	"disc"          INTEGER NOT NULL DEFAULT 0,
+	"disc_total"    INTEGER NOT NULL DEFAULT 0,
+	"end_offset"    INTEGER NOT NULL DEFAULT 0,
 	"file_name"     TEXT,
 	"file_size"     INTEGER NOT NULL,
 	"file_type_id"  INTEGER NOT NULL,
+	"fingerprint"   TEXT NOT NULL DEFAULT '',
 	"folder_id"     INTEGER NOT NULL,
 	"genre"         TEXT,
 	"last_modified" INTEGER NOT NULL,
 	"length"        INTEGER NOT NULL,
+	"library_id"    INTEGER NOT NULL DEFAULT 0,
 	"sample_rate"   INTEGER NOT NULL,
+	"start_offset"  INTEGER NOT NULL DEFAULT 0,
 	"title"         TEXT,
 	"track"         INTEGER,
+	"track_total"   INTEGER NOT NULL DEFAULT 0,
 	"year"          INTEGER
 );
-CREATE UNIQUE INDEX "songs_unique_fileName" ON "songs" ("file_name");
+CREATE UNIQUE INDEX "songs_unique_fileName_startOffset" ON "songs" ("file_name", "start_offset");
+CREATE INDEX "songs_libraryId" ON "songs" ("library_id");
+CREATE INDEX "songs_fingerprint" ON "songs" ("fingerprint");
+/* stars */
+CREATE TABLE "stars" (
+	"id"        INTEGER PRIMARY KEY AUTOINCREMENT,
+	"user_id"   INTEGER NOT NULL,
+	"item_type" TEXT NOT NULL,
+	"item_id"   INTEGER NOT NULL,
+	"created"   INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX "stars_unique_userId_itemType_itemId" ON "stars" ("user_id", "item_type", "item_id");
 /* users */
 CREATE TABLE "users" (
 	"id"           INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -71,4 +156,48 @@ CREATE TABLE "users" (
 	"lastfm_token" TEXT
 );
 CREATE UNIQUE INDEX "users_unique_username" ON "users" ("username");
+/* full-text search index, maintained by triggers */
+CREATE VIRTUAL TABLE "albums_search" USING fts5("title", "artist", tokenize = 'unicode61 remove_diacritics 2');
+CREATE VIRTUAL TABLE "artists_search" USING fts5("title", tokenize = 'unicode61 remove_diacritics 2');
+CREATE VIRTUAL TABLE "folders_search" USING fts5("title", tokenize = 'unicode61 remove_diacritics 2');
+CREATE VIRTUAL TABLE "songs_search" USING fts5("title", "artist", "album", "genre", "comment", tokenize = 'unicode61 remove_diacritics 2');
+CREATE TRIGGER "albums_search_insert" AFTER INSERT ON "albums" BEGIN
+	INSERT INTO "albums_search" ("rowid", "title", "artist")
+		VALUES (new."id", new."title", (SELECT "title" FROM "artists" WHERE "id" = new."artist_id"));
+END;
+CREATE TRIGGER "albums_search_delete" AFTER DELETE ON "albums" BEGIN
+	DELETE FROM "albums_search" WHERE "rowid" = old."id";
+END;
+CREATE TRIGGER "artists_search_insert" AFTER INSERT ON "artists" BEGIN
+	INSERT INTO "artists_search" ("rowid", "title") VALUES (new."id", new."title");
+END;
+CREATE TRIGGER "artists_search_delete" AFTER DELETE ON "artists" BEGIN
+	DELETE FROM "artists_search" WHERE "rowid" = old."id";
+END;
+CREATE TRIGGER "folders_search_insert" AFTER INSERT ON "folders" BEGIN
+	INSERT INTO "folders_search" ("rowid", "title") VALUES (new."id", new."title");
+END;
+CREATE TRIGGER "folders_search_update" AFTER UPDATE ON "folders" BEGIN
+	DELETE FROM "folders_search" WHERE "rowid" = old."id";
+	INSERT INTO "folders_search" ("rowid", "title") VALUES (new."id", new."title");
+END;
+CREATE TRIGGER "folders_search_delete" AFTER DELETE ON "folders" BEGIN
+	DELETE FROM "folders_search" WHERE "rowid" = old."id";
+END;
+CREATE TRIGGER "songs_search_insert" AFTER INSERT ON "songs" BEGIN
+	INSERT INTO "songs_search" ("rowid", "title", "artist", "album", "genre", "comment")
+		VALUES (new."id", new."title", (SELECT "title" FROM "artists" WHERE "id" = new."artist_id"),
+			(SELECT "title" FROM "albums" WHERE "id" = new."album_id"), new."genre", new."comment");
+END;
+CREATE TRIGGER "songs_search_update" AFTER UPDATE ON "songs" BEGIN
+	DELETE FROM "songs_search" WHERE "rowid" = old."id";
+	INSERT INTO "songs_search" ("rowid", "title", "artist", "album", "genre", "comment")
+		VALUES (new."id", new."title", (SELECT "title" FROM "artists" WHERE "id" = new."artist_id"),
+			(SELECT "title" FROM "albums" WHERE "id" = new."album_id"), new."genre", new."comment");
+END;
+CREATE TRIGGER "songs_search_delete" AFTER DELETE ON "songs" BEGIN
+	DELETE FROM "songs_search" WHERE "rowid" = old."id";
+END;
 COMMIT;
+/* schema version, matching the latest migration in res/sqlite/migrations */
+PRAGMA user_version = 11;
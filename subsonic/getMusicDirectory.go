@@ -100,12 +100,12 @@ func GetMusicDirectory(res http.ResponseWriter, req *http.Request) {
 		// Add albums to children
 		for _, a := range albums {
 			children = append(children, Child{
-				ID:     "album_" + strconv.Itoa(a.ID),
-				Title:  a.Title,
-				Album:  a.Title,
-				Artist: a.Artist,
-				IsDir:  true,
-				//CoverArt: a.ArtID,
+				ID:       "album_" + strconv.Itoa(a.ID),
+				Title:    a.Title,
+				Album:    a.Title,
+				Artist:   a.Artist,
+				IsDir:    true,
+				CoverArt: a.ArtID,
 				//Created: time.Unix(a.LastModified, 0).Format("2006-01-02T15:04:05"),
 			})
 		}
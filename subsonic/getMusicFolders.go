@@ -4,10 +4,9 @@ import (
 	"encoding/xml"
 	"log"
 	"net/http"
-	"path"
 
 	"github.com/mdlayher/wavepipe/api"
-	"github.com/mdlayher/wavepipe/config"
+	"github.com/mdlayher/wavepipe/data"
 
 	"github.com/gorilla/context"
 	"github.com/unrolled/render"
@@ -23,13 +22,13 @@ type MusicFoldersContainer struct {
 }
 
 // GetMusicFolders is used in Subsonic to return a list of music folders.
-// Since wavepipe only has one, we only return one.
+// Each wavepipe media library is returned as a music folder.
 func GetMusicFolders(res http.ResponseWriter, req *http.Request) {
 	// Retrieve render
 	r := context.Get(req, api.CtxRender).(*render.Render)
 
-	// Load name of media folder from config
-	conf, err := config.C.Load()
+	// Load all media libraries
+	libraries, err := data.DB.AllLibraries()
 	if err != nil {
 		log.Println(err)
 		r.XML(res, 200, ErrGeneric)
@@ -39,7 +38,11 @@ func GetMusicFolders(res http.ResponseWriter, req *http.Request) {
 	// Create a new response container
 	c := newContainer()
 	c.MusicFolders = &MusicFoldersContainer{
-		MusicFolders: []MusicFolder{MusicFolder{0, path.Base(conf.Media())}},
+		MusicFolders: make([]MusicFolder, 0),
+	}
+
+	for _, l := range libraries {
+		c.MusicFolders.MusicFolders = append(c.MusicFolders.MusicFolders, MusicFolder{l.ID, l.Name})
 	}
 
 	// Write response
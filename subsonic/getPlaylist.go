@@ -0,0 +1,78 @@
+package subsonic
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/wavepipe/api"
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/unrolled/render"
+)
+
+// GetPlaylist is used in Subsonic to return a single playlist and its songs.  Only wavepipe's
+// smart playlists are available, and their songs are evaluated at the time of the request.
+func GetPlaylist(res http.ResponseWriter, req *http.Request) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Fetch ID parameter
+	pID := req.URL.Query().Get("id")
+	if pID == "" {
+		r.XML(res, 200, ErrMissingParameter)
+		return
+	}
+
+	// Only smart playlists are available
+	if !strings.HasPrefix(pID, "smart_") {
+		r.XML(res, 200, ErrNotFound)
+		return
+	}
+
+	// Parse ID
+	id, err := strconv.Atoi(strings.TrimPrefix(pID, "smart_"))
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Load smart playlist by ID
+	playlist := &data.SmartPlaylist{ID: id}
+	if err := playlist.Load(); err != nil {
+		if err == sql.ErrNoRows {
+			r.XML(res, 200, ErrNotFound)
+			return
+		}
+
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Private smart playlists may only be viewed by their owner
+	user, ok := context.Get(req, api.CtxUser).(*data.User)
+	if !ok || user == nil || !playlist.CanView(user) {
+		r.XML(res, 200, ErrNotFound)
+		return
+	}
+
+	// Evaluate the smart playlist, and copy it into output
+	out, err := subSmartPlaylist(*playlist, true)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Create a new response container
+	c := newContainer()
+	c.Playlist = out
+
+	// Write response
+	r.XML(res, 200, c)
+}
@@ -2,9 +2,12 @@ package subsonic
 
 import (
 	"encoding/xml"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/mdlayher/wavepipe/api"
+	"github.com/mdlayher/wavepipe/data"
 
 	"github.com/gorilla/context"
 	"github.com/unrolled/render"
@@ -13,19 +16,114 @@ import (
 // Playlists represents the Subsonic playlists container
 type Playlists struct {
 	XMLName xml.Name `xml:"playlists,omitempty"`
+
+	Playlists []Playlist `xml:"playlist"`
+}
+
+// Playlist represents an emulated Subsonic playlist
+type Playlist struct {
+	XMLName xml.Name `xml:"playlist,omitempty"`
+
+	// Subsonic fields
+	ID        string `xml:"id,attr"`
+	Name      string `xml:"name,attr"`
+	Comment   string `xml:"comment,attr"`
+	Owner     string `xml:"owner,attr"`
+	Public    bool   `xml:"public,attr"`
+	SongCount int    `xml:"songCount,attr"`
+	Duration  int    `xml:"duration,attr"`
+	Created   string `xml:"created,attr"`
+
+	// Songs, only present in getPlaylist.view
+	Entries []Song `xml:"entry"`
 }
 
-// GetPlaylists is used in Subsonic to return playlists from the server
+// GetPlaylists is used in Subsonic to return playlists from the server.  wavepipe's smart
+// playlists are returned as read-only playlists, evaluated at the time of the request.
 func GetPlaylists(res http.ResponseWriter, req *http.Request) {
 	// Retrieve render
 	r := context.Get(req, api.CtxRender).(*render.Render)
 
 	// Create a new response container
 	c := newContainer()
+	c.Playlists = &Playlists{
+		Playlists: make([]Playlist, 0),
+	}
+
+	// With no user, no playlists are visible
+	user, ok := context.Get(req, api.CtxUser).(*data.User)
+	if !ok || user == nil {
+		r.XML(res, 200, c)
+		return
+	}
+
+	// Load all smart playlists visible to this user
+	playlists, err := data.DB.SmartPlaylistsForUser(user.ID)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Count the songs in each smart playlist, which are only listed by getPlaylist.view
+	for _, p := range playlists {
+		playlist, err := subSmartPlaylist(p, false)
+		if err != nil {
+			log.Println(err)
+			r.XML(res, 200, ErrGeneric)
+			return
+		}
 
-	// wavepipe currently has no playlists.  Return a blank container.
-	c.Playlists = &Playlists{}
+		c.Playlists.Playlists = append(c.Playlists.Playlists, *playlist)
+	}
 
 	// Write response
 	r.XML(res, 200, c)
 }
+
+// subSmartPlaylist evaluates a wavepipe smart playlist, and turns it into a Subsonic format
+// playlist.  Its songs are only loaded and listed if entries is set, and are otherwise counted.
+// Smart playlist IDs are prefixed, so they are not mistaken for other items.
+func subSmartPlaylist(p data.SmartPlaylist, entries bool) (*Playlist, error) {
+	// Load the owner's username
+	owner := &data.User{ID: p.UserID}
+	if err := owner.Load(); err != nil {
+		return nil, err
+	}
+
+	playlist := &Playlist{
+		ID:      "smart_" + strconv.Itoa(p.ID),
+		Name:    p.Title,
+		Comment: p.Query,
+		Owner:   owner.Username,
+		Public:  p.Public,
+		Created: subTime(p.Created),
+	}
+
+	// Count the songs matching the smart playlist's rules, without loading them
+	if !entries {
+		count, length, err := p.Count()
+		if err != nil {
+			return nil, err
+		}
+
+		playlist.SongCount = int(count)
+		playlist.Duration = int(length)
+		return playlist, nil
+	}
+
+	// Evaluate the smart playlist's rules
+	songs, err := p.Songs()
+	if err != nil {
+		return nil, err
+	}
+
+	playlist.SongCount = len(songs)
+	playlist.Entries = make([]Song, 0)
+	for _, s := range songs {
+		playlist.Duration += s.Length
+		playlist.Entries = append(playlist.Entries, subSong(s))
+	}
+
+	return playlist, nil
+}
@@ -0,0 +1,82 @@
+package subsonic
+
+import (
+	"encoding/xml"
+	"log"
+	"net/http"
+
+	"github.com/mdlayher/wavepipe/api"
+	"github.com/mdlayher/wavepipe/common"
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/unrolled/render"
+)
+
+// ScanStatus represents the status of a Subsonic media scan
+type ScanStatus struct {
+	XMLName xml.Name `xml:"scanStatus,omitempty"`
+
+	Scanning bool  `xml:"scanning,attr"`
+	Count    int64 `xml:"count,attr"`
+}
+
+// subScanStatus generates a Subsonic scan status from the current wavepipe scan
+func subScanStatus() *ScanStatus {
+	scan := common.CurrentScan()
+	return &ScanStatus{
+		Scanning: scan.Running,
+		Count:    scan.Seen,
+	}
+}
+
+// GetScanStatus is used in Subsonic to return the status of the current media scan
+func GetScanStatus(res http.ResponseWriter, req *http.Request) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Create a new response container with the scan status
+	c := newContainer()
+	c.ScanStatus = subScanStatus()
+
+	// Write response
+	r.XML(res, 200, c)
+}
+
+// StartScan is used in Subsonic to start a media scan of all libraries.  Only administrators
+// may start a scan.
+func StartScan(res http.ResponseWriter, req *http.Request) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Only allow administrators to start scans
+	user, ok := context.Get(req, api.CtxUser).(*data.User)
+	if !ok || user == nil || user.RoleID < data.RoleAdmin {
+		r.XML(res, 200, ErrNotAuthorized)
+		return
+	}
+
+	// Queue a media scan for each library
+	libraries, err := data.DB.AllLibraries()
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	requests := make([]common.ScanRequest, 0)
+	for _, l := range libraries {
+		requests = append(requests, common.ScanRequest{Type: common.ScanMedia, LibraryID: l.ID})
+	}
+	if !common.QueueScan(requests...) {
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Create a new response container with the scan status
+	c := newContainer()
+	c.ScanStatus = subScanStatus()
+
+	// Write response
+	r.XML(res, 200, c)
+}
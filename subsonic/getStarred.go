@@ -1,18 +1,46 @@
 package subsonic
 
 import (
+	"database/sql"
 	"encoding/xml"
+	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/mdlayher/wavepipe/api"
+	"github.com/mdlayher/wavepipe/data"
 
 	"github.com/gorilla/context"
 	"github.com/unrolled/render"
 )
 
-// Starred represents a Subsonic license
+// Starred represents a Subsonic list of favorite items, organized by directory
 type Starred struct {
 	XMLName xml.Name `xml:"starred,omitempty"`
+
+	Artists []Artist    `xml:"artist"`
+	Albums  []Directory `xml:"album"`
+	Songs   []Song      `xml:"song"`
+}
+
+// Starred2 represents a Subsonic list of favorite items, organized by tags
+type Starred2 struct {
+	XMLName xml.Name `xml:"starred2,omitempty"`
+
+	Artists []Artist `xml:"artist"`
+	Albums  []Album  `xml:"album"`
+	Songs   []Song   `xml:"song"`
+}
+
+// Directory represents an emulated Subsonic directory, such as a starred album
+type Directory struct {
+	ID       string `xml:"id,attr"`
+	Parent   string `xml:"parent,attr"`
+	Title    string `xml:"title,attr"`
+	Artist   string `xml:"artist,attr"`
+	IsDir    bool   `xml:"isDir,attr"`
+	CoverArt string `xml:"coverArt,attr"`
+	Starred  string `xml:"starred,attr,omitempty"`
 }
 
 // GetStarred is used in Subsonic to return favorite items from the server
@@ -20,12 +48,128 @@ func GetStarred(res http.ResponseWriter, req *http.Request) {
 	// Retrieve render
 	r := context.Get(req, api.CtxRender).(*render.Render)
 
+	// Load all starred items for this user
+	starred, err := loadStarred(req)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Albums and artists use prefixed IDs, so they may be browsed using getMusicDirectory
+	out := &Starred{
+		Artists: make([]Artist, 0),
+		Albums:  make([]Directory, 0),
+		Songs:   starred.Songs,
+	}
+	for _, a := range starred.Artists {
+		a.ID = "artist_" + a.ID
+		out.Artists = append(out.Artists, a)
+	}
+	for _, a := range starred.Albums {
+		out.Albums = append(out.Albums, Directory{
+			ID:       "album_" + strconv.Itoa(a.ID),
+			Parent:   "artist_" + strconv.Itoa(a.ArtistID),
+			Title:    a.Name,
+			Artist:   a.Artist,
+			IsDir:    true,
+			CoverArt: a.CoverArt,
+			Starred:  a.Starred,
+		})
+	}
+
 	// Create a new response container
 	c := newContainer()
+	c.Starred = out
+
+	// Write response
+	r.XML(res, 200, c)
+}
+
+// GetStarred2 is used in Subsonic to return favorite items from the server, organized by tags
+func GetStarred2(res http.ResponseWriter, req *http.Request) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Load all starred items for this user
+	starred, err := loadStarred(req)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
 
-	// wavepipe currently has nothing like favorites/stars.  Return a blank container.
-	c.Starred = &Starred{}
+	// Create a new response container
+	c := newContainer()
+	c.Starred2 = starred
 
 	// Write response
 	r.XML(res, 200, c)
 }
+
+// loadStarred loads all artists, albums, and songs starred by the user stored in the request
+// context.  Items which no longer exist are skipped.
+func loadStarred(req *http.Request) (*Starred2, error) {
+	starred := &Starred2{
+		Artists: make([]Artist, 0),
+		Albums:  make([]Album, 0),
+		Songs:   make([]Song, 0),
+	}
+
+	// With no user, nothing is starred
+	user, ok := context.Get(req, api.CtxUser).(*data.User)
+	if !ok || user == nil {
+		return starred, nil
+	}
+
+	// Load all stars for this user
+	stars, err := data.DB.StarsForUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range stars {
+		// Folders are not exposed in the emulated Subsonic API
+		if s.ItemType == data.ItemFolder {
+			continue
+		}
+
+		// Load the starred item, skipping it if it no longer exists
+		item, err := data.LoadItem(s.ItemType, s.ItemID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+
+			return nil, err
+		}
+
+		switch v := item.(type) {
+		case *data.Artist:
+			starred.Artists = append(starred.Artists, Artist{
+				Name:    v.Title,
+				ID:      strconv.Itoa(v.ID),
+				Starred: subTime(s.Created),
+			})
+		case *data.Album:
+			// Load songs for album, skipping albums with no songs
+			songs, err := data.DB.SongsForAlbum(v.ID)
+			if err != nil {
+				return nil, err
+			}
+			if len(songs) == 0 {
+				continue
+			}
+
+			album := subAlbum(*v, songs)
+			album.Starred = subTime(s.Created)
+			starred.Albums = append(starred.Albums, album)
+		case *data.Song:
+			song := subSong(*v)
+			song.Starred = subTime(s.Created)
+			starred.Songs = append(starred.Songs, song)
+		}
+	}
+
+	return starred, nil
+}
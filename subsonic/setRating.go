@@ -0,0 +1,81 @@
+package subsonic
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/api"
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/unrolled/render"
+)
+
+// SetRating is used in Subsonic to set the current user's rating of an item, from 1 to 5.
+// A rating of 0 removes the rating.
+func SetRating(res http.ResponseWriter, req *http.Request) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Retrieve user from context
+	user, ok := context.Get(req, api.CtxUser).(*data.User)
+	if !ok || user == nil {
+		log.Println("subsonic: no user stored in request context!")
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Fetch ID and rating parameters
+	pID := req.URL.Query().Get("id")
+	pRating := req.URL.Query().Get("rating")
+	if pID == "" || pRating == "" {
+		r.XML(res, 200, ErrMissingParameter)
+		return
+	}
+
+	// Parse item type and ID
+	itemType, itemID, err := subItem(pID)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Parse rating as integer
+	rating, err := strconv.Atoi(pRating)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// A rating of 0 removes any existing rating
+	if rating == 0 {
+		if err := (&data.Rating{UserID: user.ID, ItemType: itemType, ItemID: itemID}).Delete(); err != nil {
+			log.Println(err)
+			r.XML(res, 200, ErrGeneric)
+			return
+		}
+
+		r.XML(res, 200, newContainer())
+		return
+	}
+
+	// Rate the item, or update the existing rating
+	if _, err := data.NewRating(user.ID, itemType, itemID, rating); err != nil {
+		// Check for missing item
+		if err == sql.ErrNoRows {
+			r.XML(res, 200, ErrNotFound)
+			return
+		}
+
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Write response
+	r.XML(res, 200, newContainer())
+}
@@ -0,0 +1,124 @@
+package subsonic
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/api"
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/gorilla/context"
+	"github.com/unrolled/render"
+)
+
+// starItem is an item which a Subsonic client has requested to star or unstar
+type starItem struct {
+	itemType string
+	itemID   int
+}
+
+// starItems parses the items requested by a Subsonic star or unstar call.  Items may be
+// specified by ID, album ID, or artist ID, and each parameter may be repeated.
+func starItems(req *http.Request) ([]starItem, error) {
+	query := req.URL.Query()
+	items := make([]starItem, 0)
+
+	// Parse songs and prefixed directory IDs
+	for _, id := range query["id"] {
+		itemType, itemID, err := subItem(id)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, starItem{itemType, itemID})
+	}
+
+	// Parse album and artist IDs, which are used when browsing by tags
+	params := []struct {
+		itemType string
+		name     string
+	}{
+		{data.ItemAlbum, "albumId"},
+		{data.ItemArtist, "artistId"},
+	}
+	for _, p := range params {
+		for _, id := range query[p.name] {
+			itemID, err := strconv.Atoi(id)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, starItem{p.itemType, itemID})
+		}
+	}
+
+	return items, nil
+}
+
+// Star is used in Subsonic to add an item to the current user's favorites
+func Star(res http.ResponseWriter, req *http.Request) {
+	starHandler(res, req, true)
+}
+
+// Unstar is used in Subsonic to remove an item from the current user's favorites
+func Unstar(res http.ResponseWriter, req *http.Request) {
+	starHandler(res, req, false)
+}
+
+// starHandler stars or unstars all items requested by a Subsonic client
+func starHandler(res http.ResponseWriter, req *http.Request, star bool) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Retrieve user from context
+	user, ok := context.Get(req, api.CtxUser).(*data.User)
+	if !ok || user == nil {
+		log.Println("subsonic: no user stored in request context!")
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// Parse requested items
+	items, err := starItems(req)
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+
+	// At least one item is required
+	if len(items) == 0 {
+		r.XML(res, 200, ErrMissingParameter)
+		return
+	}
+
+	// Star or unstar each item
+	for _, i := range items {
+		if !star {
+			if err := (&data.Star{UserID: user.ID, ItemType: i.itemType, ItemID: i.itemID}).Delete(); err != nil {
+				log.Println(err)
+				r.XML(res, 200, ErrGeneric)
+				return
+			}
+
+			continue
+		}
+
+		if _, err := data.NewStar(user.ID, i.itemType, i.itemID); err != nil {
+			// Check for missing item
+			if err == sql.ErrNoRows {
+				r.XML(res, 200, ErrNotFound)
+				return
+			}
+
+			log.Println(err)
+			r.XML(res, 200, ErrGeneric)
+			return
+		}
+	}
+
+	// Write response
+	r.XML(res, 200, newContainer())
+}
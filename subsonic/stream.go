@@ -2,6 +2,7 @@ package subsonic
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/mdlayher/wavepipe/api"
 	"github.com/mdlayher/wavepipe/data"
+	"github.com/mdlayher/wavepipe/transcode"
 
 	"github.com/gorilla/context"
 	"github.com/unrolled/render"
@@ -42,6 +44,25 @@ func Stream(res http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// Clients seek by requesting the stream again at a time offset, in seconds, which requires
+	// a transcode
+	if pOffset := req.URL.Query().Get("timeOffset"); pOffset != "" && pOffset != "0" {
+		offset, err := strconv.Atoi(pOffset)
+		if err != nil || offset < 0 || offset >= song.Length {
+			r.XML(res, 200, ErrGeneric)
+			return
+		}
+
+		streamTranscode(res, req, song, offset)
+		return
+	}
+
+	// CUE tracks in files which cannot be split, such as FLAC, must also be transcoded
+	if song.IsCueTrack() && !song.Splittable() {
+		streamTranscode(res, req, song, 0)
+		return
+	}
+
 	// Open file stream
 	stream, err := song.Stream()
 	if err != nil {
@@ -49,6 +70,7 @@ func Stream(res http.ResponseWriter, req *http.Request) {
 		r.XML(res, 200, ErrGeneric)
 		return
 	}
+	defer stream.Close()
 
 	// Generate a string used for logging this operation
 	opStr := fmt.Sprintf("[#%05d] %s - %s [%s %dkbps]", song.ID, song.Artist, song.Title,
@@ -57,8 +79,9 @@ func Stream(res http.ResponseWriter, req *http.Request) {
 	// Attempt to send file stream over HTTP
 	log.Println("stream: starting:", opStr)
 
-	// Pass stream using song's file size, auto-detect MIME type
-	if err := api.HTTPStream(song, "", song.FileSize, stream, req, res); err != nil {
+	// Pass stream using song's stream size, which is smaller than its file for CUE tracks,
+	// auto-detect MIME type
+	if err := api.HTTPStream(song, "", song.StreamSize(), stream, req, res); err != nil {
 		// Check for client reset
 		if strings.Contains(err.Error(), "connection reset by peer") || strings.Contains(err.Error(), "broken pipe") {
 			return
@@ -71,3 +94,84 @@ func Stream(res http.ResponseWriter, req *http.Request) {
 	log.Println("stream: completed:", opStr)
 	return
 }
+
+// streamTranscode returns a transcoded media stream for a single file, beginning at an offset
+// in seconds, which may be zero.  The codec and bitrate may be chosen using the format and maxBitRate parameters,
+// and otherwise default to 192kbps MP3, or the codec's default quality.
+func streamTranscode(res http.ResponseWriter, req *http.Request, song *data.Song, offset int) {
+	// Retrieve render
+	r := context.Get(req, api.CtxRender).(*render.Render)
+
+	// Use the requested format, unless the client requests the raw file
+	codec := strings.ToUpper(req.URL.Query().Get("format"))
+	if codec == "" || codec == "RAW" {
+		codec = "MP3"
+	}
+
+	// Use the requested bitrate, if the codec supports it
+	quality := ""
+	if maxBitRate := req.URL.Query().Get("maxBitRate"); maxBitRate != "" && maxBitRate != "0" {
+		if _, err := transcode.Factory(codec, maxBitRate, ""); err == nil {
+			quality = maxBitRate
+		}
+	}
+
+	transcoder, err := transcode.Factory(codec, quality, "")
+	if err != nil {
+		log.Println(err)
+		r.XML(res, 200, ErrGeneric)
+		return
+	}
+	transcoder.SetOffset(offset)
+
+	// Transcodes at an offset are not cached, so their exact length is unknown
+	stream, contentLength, err := api.OpenTranscode(res, req, song, transcoder)
+	if err != nil {
+		switch err {
+		// Too many transcodes queued, so the client should try again later
+		case transcode.ErrQueueFull:
+			res.Header().Set("Retry-After", strconv.Itoa(transcode.RetryAfter))
+			r.XML(res, 503, ErrGeneric)
+		// Client disconnected while queued
+		case transcode.ErrCanceled:
+		default:
+			log.Println(err)
+			r.XML(res, 200, ErrGeneric)
+		}
+
+		return
+	}
+	defer stream.Close()
+
+	// Generate a string used for logging this operation
+	opStr := fmt.Sprintf("[#%05d] %s - %s [%s %dkbps -> %s %s] [%ds]", song.ID, song.Artist, song.Title,
+		data.CodecMap[song.FileTypeID], song.Bitrate, transcoder.Codec(), transcoder.Quality(), offset)
+
+	// Attempt to send transcoded file stream over HTTP
+	log.Println("transcode: starting:", opStr)
+
+	// Transcodes of unknown length are sent with their estimated size in advisory headers, or
+	// using it as their Content-Length if the client requests an estimate.  The stream is
+	// truncated if it exceeds the estimate, but is not padded if it falls short.
+	var body io.Reader = stream
+	if contentLength < 0 {
+		estimate := api.SetTranscodeEstimate(res, song, transcoder)
+		if estimate > 0 && req.URL.Query().Get("estimateContentLength") == "true" {
+			contentLength = estimate
+			body = io.LimitReader(stream, estimate)
+		}
+	}
+
+	if err := api.HTTPStream(song, transcoder.MIMEType(), contentLength, body, req, res); err != nil {
+		// Check for client reset
+		if strings.Contains(err.Error(), "connection reset by peer") || strings.Contains(err.Error(), "broken pipe") {
+			return
+		}
+
+		log.Println("transcode: error:", err)
+		return
+	}
+
+	log.Println("transcode: completed:", opStr)
+	return
+}
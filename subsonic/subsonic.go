@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mdlayher/wavepipe/api"
@@ -55,6 +56,26 @@ var (
 		c.SubError = &Error{Code: 10, Message: "Required parameter is missing."}
 		return c
 	}()
+	// ErrNotAuthorized returns a user not authorized for operation response
+	ErrNotAuthorized = func() *Container {
+		// Generate new container with failed status
+		c := newContainer()
+		c.Status = "failed"
+
+		// Return error
+		c.SubError = &Error{Code: 50, Message: "User is not authorized for the given operation."}
+		return c
+	}()
+	// ErrNotFound returns a requested data not found response
+	ErrNotFound = func() *Container {
+		// Generate new container with failed status
+		c := newContainer()
+		c.Status = "failed"
+
+		// Return error
+		c.SubError = &Error{Code: 70, Message: "Requested data was not found."}
+		return c
+	}()
 )
 
 // newContainer creates a new, empty Container with the proper attributes
@@ -99,14 +120,23 @@ type Container struct {
 	// getMusicFolders.view
 	MusicFolders *MusicFoldersContainer
 
+	// getPlaylist.view
+	Playlist *Playlist `xml:"playlist"`
+
 	// getPlaylists.view
 	Playlists *Playlists `xml:"playlists"`
 
 	// getRandomSongs.view
 	RandomSongs *RandomSongsContainer
 
+	// getScanStatus.view, startScan.view
+	ScanStatus *ScanStatus `xml:"scanStatus"`
+
 	// getStarred.view
 	Starred *Starred `xml:"starred"`
+
+	// getStarred2.view
+	Starred2 *Starred2 `xml:"starred2"`
 }
 
 // Error returns the error code and message from Subsonic, and enables Subsonic
@@ -128,8 +158,9 @@ type Artist struct {
 	XMLName xml.Name `xml:"artist,omitempty"`
 
 	// Subsonic fields
-	Name string `xml:"name,attr"`
-	ID   string `xml:"id,attr"`
+	Name    string `xml:"name,attr"`
+	ID      string `xml:"id,attr"`
+	Starred string `xml:"starred,attr,omitempty"`
 }
 
 // Album represents an emulated Subsonic album
@@ -143,6 +174,7 @@ type Album struct {
 	SongCount int    `xml:"songCount,attr"`
 	Duration  int    `xml:"duration,attr"`
 	Created   string `xml:"created,attr"`
+	Starred   string `xml:"starred,attr,omitempty"`
 
 	// Nested data
 
@@ -150,14 +182,20 @@ type Album struct {
 	Songs []Song `xml:"song"`
 }
 
-// subAlbum turns a wavepipe album and songs into a Subsonic format album
+// subAlbum turns a wavepipe album and songs into a Subsonic format album.  Albums without art
+// use the art of their first song.
 func subAlbum(album data.Album, songs data.SongSlice) Album {
+	artID := album.ArtID
+	if artID == 0 {
+		artID = songs[0].ArtID
+	}
+
 	return Album{
 		ID:        album.ID,
 		Name:      album.Title,
 		Artist:    album.Artist,
 		ArtistID:  album.ArtistID,
-		CoverArt:  strconv.Itoa(songs[0].ArtID),
+		CoverArt:  strconv.Itoa(artID),
 		SongCount: len(songs),
 		Duration:  songs.Length(),
 		Created:   subTime(songs[0].LastModified),
@@ -188,28 +226,34 @@ type Song struct {
 	AlbumID     int    `xml:"albumId,attr"`
 	ArtistID    int    `xml:"artistId,attr"`
 	Type        string `xml:"type,attr"`
+	Starred     string `xml:"starred,attr,omitempty"`
 }
 
 // subSong turns a wavepipe song into a Subsonic format song
 func subSong(song data.Song) Song {
+	// Songs with no disc number tag are assumed to be on the first disc
+	disc := song.Disc
+	if disc == 0 {
+		disc = 1
+	}
+
 	return Song{
 		ID: song.ID,
 		// BUG(mdlayher): subsonic: wavepipe has no concept of a parent item, so leave blank?
-		Parent:   0,
-		Title:    song.Title,
-		Album:    song.Album,
-		Artist:   song.Artist,
-		IsDir:    false,
-		CoverArt: strconv.Itoa(song.ArtID),
-		Created:  subTime(song.LastModified),
-		Duration: song.Length,
-		BitRate:  song.Bitrate,
-		Track:    song.Track,
-		// BUG(mdlayher): subsonic: wavepipe cannot scan disc number without taggolib
-		DiscNumber:  1,
+		Parent:      0,
+		Title:       song.Title,
+		Album:       song.Album,
+		Artist:      song.Artist,
+		IsDir:       false,
+		CoverArt:    strconv.Itoa(song.ArtID),
+		Created:     subTime(song.LastModified),
+		Duration:    song.Length,
+		BitRate:     song.Bitrate,
+		Track:       song.Track,
+		DiscNumber:  disc,
 		Year:        song.Year,
 		Genre:       song.Genre,
-		Size:        song.FileSize,
+		Size:        song.StreamSize(),
 		Suffix:      path.Ext(song.FileName)[1:],
 		ContentType: data.MIMEMap[song.FileTypeID],
 		IsVideo:     false,
@@ -235,6 +279,30 @@ type MusicFolder struct {
 	Name string `xml:"name,attr"`
 }
 
+// subItem parses a Subsonic item ID into a wavepipe item type and ID.  Songs use their plain
+// integer ID, while other items use an ID with a type prefix, such as "artist_1".
+func subItem(id string) (string, int, error) {
+	// Default to songs, unless a prefix is present
+	itemType := data.ItemSong
+	if pair := strings.SplitN(id, "_", 2); len(pair) == 2 {
+		itemType = pair[0]
+		id = pair[1]
+	}
+
+	// Verify known item type
+	if !data.ValidItemType(itemType) {
+		return "", 0, data.ErrInvalidItemType
+	}
+
+	// Parse ID as integer
+	itemID, err := strconv.Atoi(id)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return itemType, itemID, nil
+}
+
 // subTime converts an input UNIX timestamp to the Subsonic format
 func subTime(unix int64) string {
 	return time.Unix(unix, 0).Format("2006-01-02T15:04:05")
@@ -0,0 +1,123 @@
+package transcode
+
+import (
+	"strconv"
+)
+
+// aacCodec contains the codec describing AAC
+const aacCodec = "AAC"
+
+// aacExt contains the extension describing AAC
+const aacExt = "m4a"
+
+// aacMuxerFlags contains the ffmpeg flags used to write a MP4 container to a pipe, which must
+// be fragmented, since the output cannot be seeked to write its header
+var aacMuxerFlags = []string{"-f", "mp4", "-movflags", "frag_keyframe+empty_moov"}
+
+// aacMIMEType contains the MIME type describing AAC
+const aacMIMEType = "audio/mp4"
+
+// aacVBRBitrates contains the approximate bitrates of AAC VBR qualities, in kbps
+var aacVBRBitrates = map[string]int{"Q1": 128, "Q2": 192}
+
+// AACCBROptions represents the options for an AAC CBR transcoder
+type AACCBROptions struct {
+	quality string
+}
+
+// Codec returns the codec used
+func (m AACCBROptions) Codec() string {
+	return aacCodec
+}
+
+// Ext returns the file extension used
+func (m AACCBROptions) Ext() string {
+	return aacExt
+}
+
+// FFmpegFlags returns the flag used by ffmpeg to signify this encoding
+func (m AACCBROptions) FFmpegFlags() string {
+	return "-ab"
+}
+
+// FFmpegCodec returns the codec used by ffmpeg
+func (m AACCBROptions) FFmpegCodec() string {
+	return FFmpegAACCodec
+}
+
+// FFmpegMuxerFlags returns the flags used by ffmpeg to write the MP4 container
+func (m AACCBROptions) FFmpegMuxerFlags() []string {
+	return aacMuxerFlags
+}
+
+// MIMEType returns the MIME type of this item
+func (m AACCBROptions) MIMEType() string {
+	return aacMIMEType
+}
+
+// Quality returns the quality used
+func (m AACCBROptions) Quality() string {
+	return m.quality + "kbps"
+}
+
+// Bitrate returns the bitrate used, in kbps
+func (m AACCBROptions) Bitrate() int {
+	bitrate, _ := strconv.Atoi(m.quality)
+	return bitrate
+}
+
+// FFmpegQuality returns the quality flag used by ffmpeg
+func (m AACCBROptions) FFmpegQuality() string {
+	return m.quality + "k"
+}
+
+// AACVBROptions represents the options for an AAC VBR transcoder
+type AACVBROptions struct {
+	quality string
+}
+
+// Codec returns the codec used
+func (m AACVBROptions) Codec() string {
+	return aacCodec
+}
+
+// Ext returns the file extension used
+func (m AACVBROptions) Ext() string {
+	return aacExt
+}
+
+// FFmpegCodec returns the codec used by ffmpeg
+func (m AACVBROptions) FFmpegCodec() string {
+	return FFmpegAACCodec
+}
+
+// FFmpegFlags returns the flag used by ffmpeg to signify this encoding
+func (m AACVBROptions) FFmpegFlags() string {
+	return "-aq"
+}
+
+// FFmpegMuxerFlags returns the flags used by ffmpeg to write the MP4 container
+func (m AACVBROptions) FFmpegMuxerFlags() []string {
+	return aacMuxerFlags
+}
+
+// MIMEType returns the MIME type of this item
+func (m AACVBROptions) MIMEType() string {
+	return aacMIMEType
+}
+
+// Quality returns the quality used
+func (m AACVBROptions) Quality() string {
+	return m.quality
+}
+
+// Bitrate returns the approximate bitrate of the quality used, in kbps
+func (m AACVBROptions) Bitrate() int {
+	return aacVBRBitrates[m.quality]
+}
+
+// FFmpegQuality returns the quality flag used by ffmpeg
+func (m AACVBROptions) FFmpegQuality() string {
+	// Return the number after 'Q'
+	return string(m.quality[1:])
+}
@@ -0,0 +1,140 @@
+package transcode
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/mdlayher/goset"
+)
+
+// AACTranscoder represents an AAC transcoding operation, whose output is stored in a MP4 container
+type AACTranscoder struct {
+	Options Options
+	ffmpeg  *FFmpeg
+	offset  int
+	profile *Profile
+}
+
+// Bitrate returns the approximate bitrate of the transcoder's output, in kbps
+func (m AACTranscoder) Bitrate() int {
+	return m.Options.Bitrate()
+}
+
+// Codec returns the selected codec used by the transcoder
+func (m AACTranscoder) Codec() string {
+	return m.Options.Codec()
+}
+
+// Command returns the command invoked by ffmpeg, for debugging
+func (m AACTranscoder) Command() []string {
+	// If ffmpeg not started, return no arguments
+	if m.ffmpeg == nil {
+		return nil
+	}
+
+	return append([]string{FFmpegPath}, m.ffmpeg.Arguments()...)
+}
+
+// Kill stops the transcoding process immediately
+func (m *AACTranscoder) Kill() error {
+	// Make sure ffmpeg was started, to avoid panic
+	if m.ffmpeg == nil {
+		return ErrFFmpegNotStarted
+	}
+
+	return m.ffmpeg.Kill()
+}
+
+// MIMEType returns the MIME type contained within the options, or the profile's container
+func (m AACTranscoder) MIMEType() string {
+	return m.profile.mimeType(m.Options)
+}
+
+// Start begins the transcoding process, and returns a stream which contains its output
+func (m *AACTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
+	// Set up the ffmpeg instance
+	m.ffmpeg = NewFFmpeg(song, m.Options, m.offset, m.profile)
+
+	// Invoke ffmpeg to create a transcoded audio stream
+	if err := m.ffmpeg.Start(); err != nil {
+		return nil, err
+	}
+
+	// Retrieve the stream from ffmpeg
+	return m.ffmpeg.Stream()
+}
+
+// Offset returns the number of seconds into the song at which the transcode begins
+func (m AACTranscoder) Offset() int {
+	return m.offset
+}
+
+// Profile returns the transcoding profile used by the transcoder, if one was selected
+func (m AACTranscoder) Profile() *Profile {
+	return m.profile
+}
+
+// SetOffset sets the number of seconds into the song at which the transcode begins
+func (m *AACTranscoder) SetOffset(offset int) {
+	m.offset = offset
+}
+
+// Quality returns the selected quality used by the transcoder
+func (m AACTranscoder) Quality() string {
+	// Check for CBR or VBR
+	if _, ok := m.Options.(*AACCBROptions); ok {
+		return "CBR " + m.Options.Quality()
+	}
+
+	return "VBR " + m.Options.Quality()
+}
+
+// Wait waits for the transcoding process to complete, returning an error if it fails
+func (m *AACTranscoder) Wait() error {
+	// Make sure ffmpeg was started, to avoid panic
+	if m.ffmpeg == nil {
+		return ErrFFmpegNotStarted
+	}
+
+	// Wait for ffmpeg
+	if err := m.ffmpeg.Wait(); err != nil {
+		return err
+	}
+
+	// Nullify ffmpeg process
+	m.ffmpeg = nil
+	return nil
+}
+
+// defaultQuality returns the quality used when none is specified, a 192kbps CBR encode
+func (m AACTranscoder) defaultQuality() string {
+	return "192"
+}
+
+// cbrSet returns the set of valid CBR qualities for this transcoder
+func (m AACTranscoder) cbrSet() *set.Set {
+	return set.New(96, 128, 192, 256, 320)
+}
+
+// vbrSet returns the set of valid VBR qualities for this transcoder
+func (m AACTranscoder) vbrSet() *set.Set {
+	return set.New("q1", "Q1", "q2", "Q2")
+}
+
+// setProfile sets the transcoding profile used by this transcoder
+func (m *AACTranscoder) setProfile(profile *Profile) {
+	m.profile = profile
+}
+
+// setCBR sets appropriate CBR options for this transcoder
+func (m *AACTranscoder) setCBR(cbr int) {
+	m.Options = &AACCBROptions{strconv.Itoa(cbr)}
+}
+
+// setVBR sets appropriate VBR options for this transcoder
+func (m *AACTranscoder) setVBR(vbr string) {
+	m.Options = &AACVBROptions{strings.ToUpper(vbr)}
+}
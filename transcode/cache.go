@@ -0,0 +1,573 @@
+package transcode
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mdlayher/wavepipe/data"
+)
+
+// cachePartialExt is the extension of cached files which are still being transcoded
+const cachePartialExt = ".partial"
+
+// errTranscodeKilled is returned to streams following a transcode which was stopped, because
+// all of its clients disconnected
+var errTranscodeKilled = errors.New("transcode: transcode stopped, all clients disconnected")
+
+// DefaultCache is the transcode cache used by the API, which is replaced by the transcode
+// manager once it is configured.  By default, transcodes are not cached, and the number of
+// ffmpeg processes is unlimited.
+var DefaultCache = NewCache("", 0, NewLimiter(0, 0))
+
+// Cache stores transcoded files on disk, so that repeated requests for the same song, codec,
+// and quality do not invoke ffmpeg again.  Files are removed in least recently used order once
+// the cache grows beyond its size limit.
+type Cache struct {
+	dir     string
+	limit   int64
+	limiter *Limiter
+
+	mutex   sync.Mutex
+	count   int
+	entries map[string]*cacheEntry
+	lru     *list.List
+	size    int64
+}
+
+// cacheEntry is a single transcoded file in the cache, which may still be written by ffmpeg.
+// Transcodes in progress are stopped once all of their readers are closed.
+type cacheEntry struct {
+	key        string
+	element    *list.Element
+	partial    string
+	transcoder Transcoder
+	stream     io.ReadCloser
+	readers    int
+	killed     bool
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	written int64
+	done    bool
+	err     error
+}
+
+// NewCache creates a new Cache which stores transcoded files in the input folder, up to the
+// input number of bytes, and uses the input limiter to start ffmpeg.  If the limit is zero,
+// transcodes are streamed directly from ffmpeg.
+func NewCache(dir string, limit int64, limiter *Limiter) *Cache {
+	return &Cache{
+		dir:     dir,
+		limit:   limit,
+		limiter: limiter,
+		entries: make(map[string]*cacheEntry),
+		lru:     list.New(),
+	}
+}
+
+// Load creates the cache folder if needed, and adds any transcoded files stored in it to the
+// cache, using their modify times to determine which were used most recently.  Partial files
+// left by an interrupted transcode are removed.
+func (c *Cache) Load() error {
+	if c.limit <= 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0775); err != nil {
+		return err
+	}
+
+	files, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	sort.Sort(byModTime(files))
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+
+		if strings.HasSuffix(f.Name(), cachePartialExt) {
+			if err := os.Remove(path.Join(c.dir, f.Name())); err != nil {
+				log.Println("transcode: could not remove partial file:", err)
+			}
+			continue
+		}
+
+		e := newCacheEntry(f.Name())
+		e.written = f.Size()
+		e.done = true
+		e.element = c.lru.PushFront(e)
+
+		c.entries[e.key] = e
+		c.size += e.written
+	}
+
+	c.evict()
+	return nil
+}
+
+// Open returns a stream of the input song transcoded by the input transcoder, and its length.
+// Finished transcodes are read from the cache with their length, and may be seeked.  Otherwise,
+// the stream follows the file as ffmpeg writes it, and its length is unknown.  Concurrent
+// requests for a transcode in progress share a single ffmpeg process.  Transcodes which begin
+// at an offset are not cached, and their length is unknown.
+//
+// Before ffmpeg is started, the input user waits for a process from the cache's limiter.  If
+// the cancel channel fires, such as when the client disconnects, waiting stops, and ffmpeg is
+// killed once no other streams are reading its output.
+func (c *Cache) Open(song *data.Song, t Transcoder, user int, cancel <-chan bool) (io.ReadCloser, int64, error) {
+	// Without a cache, stream directly from ffmpeg
+	if c.limit <= 0 || t.Offset() > 0 {
+		return c.openDirect(song, t, user, cancel)
+	}
+
+	key := cacheKey(song, t)
+
+	c.mutex.Lock()
+	stream, size, ok, err := c.openEntry(key, cancel)
+	c.mutex.Unlock()
+	if ok {
+		return stream, size, err
+	}
+
+	// Wait for a process without holding the lock, so other requests may be served
+	if err := c.limiter.Acquire(user, cancel); err != nil {
+		return nil, -1, err
+	}
+
+	c.mutex.Lock()
+
+	// Another request may have begun the same transcode while this one was waiting
+	if stream, size, ok, err := c.openEntry(key, cancel); ok {
+		c.mutex.Unlock()
+		c.limiter.Release(user)
+		return stream, size, err
+	}
+
+	// Begin a new transcode, writing it to a partial file until it is complete.  Partial files
+	// are named uniquely, so a stopped transcode cannot remove the file of its replacement.
+	c.count++
+	partial, err := os.Create(fmt.Sprintf("%s.%d%s", c.path(key), c.count, cachePartialExt))
+	if err != nil {
+		c.mutex.Unlock()
+		c.limiter.Release(user)
+		return nil, -1, err
+	}
+
+	file, err := os.Open(partial.Name())
+	if err != nil {
+		c.mutex.Unlock()
+		partial.Close()
+		os.Remove(partial.Name())
+		c.limiter.Release(user)
+		return nil, -1, err
+	}
+
+	// Add the transcode before ffmpeg starts, so that concurrent requests follow it, and hold
+	// a reader for this request, so that it cannot be stopped before it starts
+	e := newCacheEntry(key)
+	e.partial = partial.Name()
+	e.transcoder = t
+	e.readers = 1
+	c.entries[key] = e
+	c.mutex.Unlock()
+
+	// Start ffmpeg without holding the lock, so other requests may be served
+	stream, err = t.Start(song)
+	if err != nil {
+		file.Close()
+		partial.Close()
+		c.limiter.Release(user)
+		c.fail(e, err)
+		return nil, -1, err
+	}
+	log.Println("transcode: command:", t.Command())
+
+	c.mutex.Lock()
+	e.stream = stream
+	c.mutex.Unlock()
+	go c.transcode(e, user, stream, partial)
+
+	return c.follow(e, file, cancel), -1, nil
+}
+
+// openDirect starts ffmpeg once the input user acquires a process, and returns its output
+// directly, with an unknown length.
+func (c *Cache) openDirect(song *data.Song, t Transcoder, user int, cancel <-chan bool) (io.ReadCloser, int64, error) {
+	if err := c.limiter.Acquire(user, cancel); err != nil {
+		return nil, -1, err
+	}
+
+	stream, err := t.Start(song)
+	if err != nil {
+		c.limiter.Release(user)
+		return nil, -1, err
+	}
+	log.Println("transcode: command:", t.Command())
+
+	s := &waitStream{
+		ReadCloser: stream,
+		transcoder: t,
+		limiter:    c.limiter,
+		user:       user,
+		closed:     make(chan struct{}),
+	}
+	go s.watch(cancel)
+
+	return s, -1, nil
+}
+
+// openEntry opens the transcode with the input key, if it is cached or in progress.  The cache
+// must be locked by the caller.
+func (c *Cache) openEntry(key string, cancel <-chan bool) (io.ReadCloser, int64, bool, error) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, -1, false, nil
+	}
+
+	// Serve finished transcodes from disk, marking them recently used
+	if e.element != nil {
+		file, err := os.Open(c.path(key))
+		if err != nil {
+			return nil, -1, true, err
+		}
+
+		c.lru.MoveToFront(e.element)
+		now := time.Now()
+		os.Chtimes(c.path(key), now, now)
+
+		log.Println("transcode: cached:", key)
+		return file, e.written, true, nil
+	}
+
+	// Follow transcodes which are in progress
+	file, err := os.Open(e.partial)
+	if err != nil {
+		return nil, -1, true, err
+	}
+
+	e.readers++
+	return c.follow(e, file, cancel), -1, true, nil
+}
+
+// follow creates a stream which reads a transcode in progress, and is closed if the cancel
+// channel fires
+func (c *Cache) follow(e *cacheEntry, file *os.File, cancel <-chan bool) *cacheStream {
+	s := &cacheStream{
+		cache:  c,
+		file:   file,
+		entry:  e,
+		closed: make(chan struct{}),
+	}
+	go s.watch(cancel)
+
+	return s
+}
+
+// transcode copies the output of ffmpeg to a partial file in the cache, waking streams which
+// follow the file as it grows.  Once ffmpeg exits, its process is released, and the file is
+// added to the cache, unless ffmpeg was killed.
+func (c *Cache) transcode(e *cacheEntry, user int, stream io.ReadCloser, partial *os.File) {
+	buf := make([]byte, 32*1024)
+	var err error
+	for {
+		n, rErr := stream.Read(buf)
+		if n > 0 {
+			if _, wErr := partial.Write(buf[:n]); wErr != nil {
+				err = wErr
+				break
+			}
+
+			e.mutex.Lock()
+			e.written += int64(n)
+			e.cond.Broadcast()
+			e.mutex.Unlock()
+		}
+
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			err = rErr
+			break
+		}
+	}
+
+	// Kill ffmpeg if its output was closed because all of its streams were closed, or drain
+	// any remaining output, so ffmpeg is able to exit
+	c.mutex.Lock()
+	killed := e.killed
+	c.mutex.Unlock()
+	if killed {
+		e.transcoder.Kill()
+	} else {
+		io.Copy(ioutil.Discard, stream)
+	}
+
+	if wErr := e.transcoder.Wait(); err == nil {
+		err = wErr
+	}
+	if cErr := partial.Close(); err == nil {
+		err = cErr
+	}
+	c.limiter.Release(user)
+
+	// Rename the file while locked, so new streams do not look for the partial file
+	c.mutex.Lock()
+	if e.killed {
+		err = errTranscodeKilled
+	}
+	if err == nil {
+		err = os.Rename(e.partial, c.path(e.key))
+	}
+	if err != nil {
+		c.mutex.Unlock()
+		if err != errTranscodeKilled {
+			log.Println("transcode: error:", err)
+		}
+
+		c.fail(e, err)
+		return
+	}
+
+	e.element = c.lru.PushFront(e)
+	c.size += e.written
+	c.evict()
+	c.mutex.Unlock()
+
+	e.finish(nil)
+}
+
+// fail removes a transcode which could not be completed, along with its partial file, and
+// returns the input error to the streams which follow it
+func (c *Cache) fail(e *cacheEntry, err error) {
+	c.mutex.Lock()
+	if c.entries[e.key] == e {
+		delete(c.entries, e.key)
+	}
+	os.Remove(e.partial)
+	c.mutex.Unlock()
+
+	e.finish(err)
+}
+
+// release is called as each stream following a transcode in progress is closed.  Once none
+// remain, ffmpeg's output is closed, so that its transcode goroutine kills it, and the
+// transcode is removed so that a later request starts again.
+func (c *Cache) release(e *cacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e.readers--
+	if e.readers > 0 || e.element != nil || e.killed || c.entries[e.key] != e {
+		return
+	}
+
+	e.killed = true
+	delete(c.entries, e.key)
+	e.stream.Close()
+}
+
+// evict removes the least recently used files from the cache until it is within its size
+// limit.  Files which are still being read remain readable until they are closed.
+func (c *Cache) evict() {
+	for c.size > c.limit {
+		element := c.lru.Back()
+		if element == nil {
+			return
+		}
+
+		e := element.Value.(*cacheEntry)
+		if err := os.Remove(c.path(e.key)); err != nil && !os.IsNotExist(err) {
+			log.Println("transcode: could not remove cached file:", err)
+		}
+
+		c.lru.Remove(element)
+		delete(c.entries, e.key)
+		c.size -= e.written
+	}
+}
+
+// path returns the location of a cached file with the input key
+func (c *Cache) path(key string) string {
+	return path.Join(c.dir, key)
+}
+
+// cacheKey generates the name of a cached file from the song's ID and modify time, and the
+// transcoder's codec, quality, and profile, so that transcodes of a modified file are not reused
+func cacheKey(song *data.Song, t Transcoder) string {
+	quality := strings.ToLower(strings.Replace(t.Quality(), " ", "", -1))
+	key := fmt.Sprintf("%d_%d_%s_%s", song.ID, song.LastModified, strings.ToLower(t.Codec()), quality)
+	if p := t.Profile(); p != nil {
+		key += "_" + p.key()
+	}
+
+	return key
+}
+
+// newCacheEntry creates a cache entry with the input key
+func newCacheEntry(key string) *cacheEntry {
+	e := &cacheEntry{key: key}
+	e.cond = sync.NewCond(&e.mutex)
+	return e
+}
+
+// finish marks a transcode as complete, waking the streams which follow it
+func (e *cacheEntry) finish(err error) {
+	e.mutex.Lock()
+	e.done = true
+	e.err = err
+	e.cond.Broadcast()
+	e.mutex.Unlock()
+}
+
+// cacheStream reads a transcoded file as it is written to the cache, waiting for more output
+// from ffmpeg until the transcode is complete
+type cacheStream struct {
+	cache  *Cache
+	file   *os.File
+	entry  *cacheEntry
+	offset int64
+
+	once   sync.Once
+	closed chan struct{}
+	err    error
+}
+
+// Read reads transcoded data which has been written to the cache, blocking until more is
+// available, or the transcode completes
+func (s *cacheStream) Read(p []byte) (int, error) {
+	e := s.entry
+	e.mutex.Lock()
+	for e.written <= s.offset && !e.done {
+		e.cond.Wait()
+	}
+	written, done, err := e.written, e.done, e.err
+	e.mutex.Unlock()
+
+	if err != nil {
+		return 0, err
+	}
+	if done && s.offset >= written {
+		return 0, io.EOF
+	}
+
+	if remaining := written - s.offset; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := s.file.ReadAt(p, s.offset)
+	s.offset += int64(n)
+	if err == io.EOF {
+		err = nil
+	}
+
+	return n, err
+}
+
+// Close closes the transcoded file.  The transcode continues while other streams follow it,
+// so that it may be cached.
+func (s *cacheStream) Close() error {
+	s.once.Do(func() {
+		s.err = s.file.Close()
+		s.cache.release(s.entry)
+		close(s.closed)
+	})
+
+	return s.err
+}
+
+// watch closes the stream if the cancel channel fires before it is closed
+func (s *cacheStream) watch(cancel <-chan bool) {
+	select {
+	case <-cancel:
+		s.Close()
+	case <-s.closed:
+	}
+}
+
+// waitStream is a stream read directly from ffmpeg, which stops ffmpeg, waits for it to exit,
+// and releases its process once it is closed
+type waitStream struct {
+	io.ReadCloser
+	transcoder Transcoder
+	limiter    *Limiter
+	user       int
+
+	// Accessed atomically, since the stream may be closed while it is read
+	eof    int32
+	killed int32
+
+	once   sync.Once
+	closed chan struct{}
+	err    error
+}
+
+// Read reads from the ffmpeg output stream, noting when it ends.  If ffmpeg was killed, its
+// output ends early, so an error is returned instead.
+func (s *waitStream) Read(p []byte) (int, error) {
+	n, err := s.ReadCloser.Read(p)
+	if err == io.EOF {
+		if atomic.LoadInt32(&s.killed) == 1 {
+			return n, errTranscodeKilled
+		}
+
+		atomic.StoreInt32(&s.eof, 1)
+	}
+
+	return n, err
+}
+
+// Close closes the ffmpeg output stream, killing ffmpeg if it did not finish, and waits for
+// ffmpeg to exit
+func (s *waitStream) Close() error {
+	s.once.Do(func() {
+		eof := atomic.LoadInt32(&s.eof) == 1
+		if !eof {
+			atomic.StoreInt32(&s.killed, 1)
+			s.transcoder.Kill()
+		}
+
+		s.err = s.ReadCloser.Close()
+		if err := s.transcoder.Wait(); s.err == nil && eof {
+			s.err = err
+		}
+
+		s.limiter.Release(s.user)
+		close(s.closed)
+	})
+
+	return s.err
+}
+
+// watch closes the stream if the cancel channel fires before it is closed
+func (s *waitStream) watch(cancel <-chan bool) {
+	select {
+	case <-cancel:
+		s.Close()
+	case <-s.closed:
+	}
+}
+
+// byModTime sorts files by their modify time, oldest first
+type byModTime []os.FileInfo
+
+func (b byModTime) Len() int           { return len(b) }
+func (b byModTime) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byModTime) Less(i, j int) bool { return b[i].ModTime().Before(b[j].ModTime()) }
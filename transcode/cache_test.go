@@ -0,0 +1,384 @@
+package transcode
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mdlayher/wavepipe/data"
+)
+
+// testTranscoder is a Transcoder whose output is written by a test, in place of ffmpeg
+type testTranscoder struct {
+	Transcoder
+	quality string
+	offset  int
+
+	// If set, Start signals starting, and waits for start to be closed
+	starting chan struct{}
+	start    chan struct{}
+
+	started bool
+	output  *io.PipeWriter
+	killed  int32
+	exited  chan struct{}
+}
+
+// newTestTranscoder creates a testTranscoder with the input quality
+func newTestTranscoder(quality string) *testTranscoder {
+	return &testTranscoder{
+		quality: quality,
+		exited:  make(chan struct{}),
+	}
+}
+
+func (t *testTranscoder) Codec() string     { return "TEST" }
+func (t *testTranscoder) Command() []string { return []string{"test"} }
+func (t *testTranscoder) Offset() int       { return t.offset }
+func (t *testTranscoder) Profile() *Profile { return nil }
+func (t *testTranscoder) Quality() string   { return t.quality }
+
+// Start returns a pipe, which is written using the transcoder's output
+func (t *testTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
+	if t.starting != nil {
+		close(t.starting)
+		<-t.start
+	}
+
+	r, w := io.Pipe()
+	t.started = true
+	t.output = w
+	return r, nil
+}
+
+// Kill records that the transcoder was killed, and ends its output
+func (t *testTranscoder) Kill() error {
+	atomic.StoreInt32(&t.killed, 1)
+	return t.output.Close()
+}
+
+// Wait signals that the transcoder exited
+func (t *testTranscoder) Wait() error {
+	close(t.exited)
+	return nil
+}
+
+// testCache creates a Cache in a temporary directory, returning the cache and a function
+// which removes it
+func testCache(t *testing.T, limit int64) (*Cache, func()) {
+	dir, err := ioutil.TempDir("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %s", err.Error())
+	}
+
+	c := NewCache(dir, limit, NewLimiter(0, 0))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Could not load cache: %s", err.Error())
+	}
+
+	return c, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// testTranscode opens a transcode of the input song, writes the input output if ffmpeg is
+// started, and returns the transcoder, the stream's length, and its contents
+func testTranscode(t *testing.T, c *Cache, song *data.Song, output string) (*testTranscoder, int64, string) {
+	tt := newTestTranscoder("test")
+	stream, size, err := c.Open(song, tt, 1, nil)
+	if err != nil {
+		t.Fatalf("Could not open transcode: %s", err.Error())
+	}
+	defer stream.Close()
+
+	if tt.started {
+		tt.output.Write([]byte(output))
+		tt.output.Close()
+	}
+
+	buf, err := ioutil.ReadAll(stream)
+	if err != nil {
+		t.Fatalf("Could not read transcode: %s", err.Error())
+	}
+
+	return tt, size, string(buf)
+}
+
+// TestCacheFollow verifies that a transcode may be read while ffmpeg writes it, that other
+// requests share its output without waiting for ffmpeg to start, and that it is then cached
+func TestCacheFollow(t *testing.T) {
+	c, cleanup := testCache(t, 1024)
+	defer cleanup()
+
+	song := &data.Song{ID: 1, LastModified: 1}
+
+	// Begin a transcode whose ffmpeg process is slow to start
+	tt := newTestTranscoder("test")
+	tt.starting = make(chan struct{})
+	tt.start = make(chan struct{})
+
+	type result struct {
+		stream io.ReadCloser
+		err    error
+	}
+	opened := make(chan result)
+	go func() {
+		stream, _, err := c.Open(song, tt, 1, nil)
+		opened <- result{stream, err}
+	}()
+	<-tt.starting
+
+	// A second request follows the transcode, while ffmpeg is still starting
+	other := newTestTranscoder("test")
+	follower, size, err := c.Open(song, other, 2, nil)
+	if err != nil {
+		t.Fatalf("Could not follow transcode: %s", err.Error())
+	}
+	defer follower.Close()
+	if size != -1 || other.started {
+		t.Fatalf("Second request did not follow transcode: %d, %v", size, other.started)
+	}
+
+	close(tt.start)
+	r := <-opened
+	if r.err != nil {
+		t.Fatalf("Could not open transcode: %s", r.err.Error())
+	}
+	stream := r.stream
+	defer stream.Close()
+
+	// Read the output as it is written
+	tt.output.Write([]byte("hello "))
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(stream, buf); err != nil || string(buf) != "hello " {
+		t.Fatalf("Unexpected partial transcode: %q (%v)", string(buf), err)
+	}
+
+	tt.output.Write([]byte("world"))
+	tt.output.Close()
+
+	for i, s := range []io.Reader{stream, follower} {
+		out, err := ioutil.ReadAll(s)
+		if err != nil {
+			t.Fatalf("Could not read transcode %d: %s", i, err.Error())
+		}
+		if expected := []string{"world", "hello world"}[i]; string(out) != expected {
+			t.Fatalf("Unexpected transcode %d: %q != %q", i, string(out), expected)
+		}
+	}
+
+	// The finished transcode is read from the cache, with its length
+	cached, size, out := testTranscode(t, c, song, "")
+	if cached.started || size != 11 || out != "hello world" {
+		t.Fatalf("Unexpected cached transcode: %v, %d, %q", cached.started, size, out)
+	}
+}
+
+// TestCacheKill verifies that ffmpeg is killed once the last stream following its transcode
+// is closed, and that the transcode is not cached
+func TestCacheKill(t *testing.T) {
+	c, cleanup := testCache(t, 1024)
+	defer cleanup()
+
+	song := &data.Song{ID: 1, LastModified: 1}
+
+	tt := newTestTranscoder("test")
+	stream, _, err := c.Open(song, tt, 1, nil)
+	if err != nil {
+		t.Fatalf("Could not open transcode: %s", err.Error())
+	}
+	follower, _, err := c.Open(song, newTestTranscoder("test"), 2, nil)
+	if err != nil {
+		t.Fatalf("Could not follow transcode: %s", err.Error())
+	}
+	tt.output.Write([]byte("partial"))
+
+	// Closing one stream leaves ffmpeg running for the other
+	stream.Close()
+	if atomic.LoadInt32(&tt.killed) == 1 {
+		t.Fatalf("Transcode killed while another stream follows it")
+	}
+
+	follower.Close()
+	select {
+	case <-tt.exited:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Transcode was not stopped")
+	}
+	if atomic.LoadInt32(&tt.killed) != 1 {
+		t.Fatalf("Transcode exited without being killed")
+	}
+
+	// A new request starts the transcode again
+	restarted, _, out := testTranscode(t, c, song, "complete")
+	if !restarted.started || out != "complete" {
+		t.Fatalf("Unexpected restarted transcode: %v, %q", restarted.started, out)
+	}
+}
+
+// TestCacheOffset verifies that transcodes which begin at an offset are streamed directly from
+// ffmpeg, with an unknown length and without modifying its output, and that ffmpeg is killed
+// if the stream is closed early
+func TestCacheOffset(t *testing.T) {
+	c, cleanup := testCache(t, 1024)
+	defer cleanup()
+
+	song := &data.Song{ID: 1, Length: 60}
+
+	tests := []struct {
+		output string
+		close  bool
+	}{
+		{"", false},
+		{"short output", false},
+		{string(make([]byte, 64*1024)), false},
+		{"partial output", true},
+	}
+
+	for i, test := range tests {
+		tt := newTestTranscoder("test")
+		tt.offset = 30
+
+		stream, size, err := c.Open(song, tt, 1, nil)
+		if err != nil {
+			t.Fatalf("Could not open transcode %d: %s", i, err.Error())
+		}
+		if size != -1 {
+			t.Fatalf("Unexpected length for transcode %d: %d", i, size)
+		}
+
+		go func(w *io.PipeWriter, output string, close bool) {
+			w.Write([]byte(output))
+			if !close {
+				w.Close()
+			}
+		}(tt.output, test.output, test.close)
+
+		// Verify ffmpeg is killed if its output is not read to the end
+		if test.close {
+			buf := make([]byte, len(test.output))
+			if _, err := io.ReadFull(stream, buf); err != nil {
+				t.Fatalf("Could not read transcode %d: %s", i, err.Error())
+			}
+			stream.Close()
+
+			if atomic.LoadInt32(&tt.killed) != 1 {
+				t.Fatalf("Transcode %d was not killed", i)
+			}
+			continue
+		}
+
+		out, err := ioutil.ReadAll(stream)
+		if err != nil {
+			t.Fatalf("Could not read transcode %d: %s", i, err.Error())
+		}
+		if err := stream.Close(); err != nil {
+			t.Fatalf("Could not close transcode %d: %s", i, err.Error())
+		}
+		if string(out) != test.output || atomic.LoadInt32(&tt.killed) == 1 {
+			t.Fatalf("Unexpected transcode %d: %d bytes", i, len(out))
+		}
+	}
+
+	if len(c.entries) != 0 {
+		t.Fatalf("Transcodes at an offset were cached: %v", c.entries)
+	}
+}
+
+// TestCacheEvict verifies that the least recently used transcodes are removed once the cache
+// grows beyond its size limit
+func TestCacheEvict(t *testing.T) {
+	c, cleanup := testCache(t, 12)
+	defer cleanup()
+
+	songs := []*data.Song{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	testTranscode(t, c, songs[0], "0123")
+	testTranscode(t, c, songs[1], "4567")
+
+	// Reading the first song marks it more recently used than the second
+	if tt, _, _ := testTranscode(t, c, songs[0], ""); tt.started {
+		t.Fatalf("First song was not cached")
+	}
+
+	// Adding the third song evicts the second
+	testTranscode(t, c, songs[2], "89abcdef")
+	if c.size != 12 || len(c.entries) != 2 {
+		t.Fatalf("Unexpected cache size: %d bytes, %d entries", c.size, len(c.entries))
+	}
+
+	for i, cached := range []bool{true, false, true} {
+		_, err := os.Stat(c.path(cacheKey(songs[i], newTestTranscoder("test"))))
+		if cached && err != nil || !cached && !os.IsNotExist(err) {
+			t.Fatalf("Unexpected cached file for song %d: %v", i, err)
+		}
+	}
+
+	if tt, _, out := testTranscode(t, c, songs[1], "4567"); !tt.started || out != "4567" {
+		t.Fatalf("Evicted song was not transcoded again: %v, %q", tt.started, out)
+	}
+}
+
+// TestCacheLoad verifies that transcoded files are loaded from disk in order of use, that
+// the cache is evicted to its size limit, and that partial files are removed
+func TestCacheLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wavepipe")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	songs := []*data.Song{{ID: 1}, {ID: 2}}
+	tt := newTestTranscoder("test")
+
+	// The first song's file is used least recently
+	cache := path.Join(dir, "cache")
+	if err := os.MkdirAll(cache, 0775); err != nil {
+		t.Fatalf("Could not create cache directory: %s", err.Error())
+	}
+	files := map[string]string{
+		cacheKey(songs[0], tt):                          "0123",
+		cacheKey(songs[1], tt):                          "4567",
+		cacheKey(songs[0], tt) + ".1" + cachePartialExt: "01",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(path.Join(cache, name), []byte(contents), 0664); err != nil {
+			t.Fatalf("Could not write cached file: %s", err.Error())
+		}
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path.Join(cache, cacheKey(songs[0], tt)), old, old); err != nil {
+		t.Fatalf("Could not set modify time: %s", err.Error())
+	}
+
+	c := NewCache(cache, 6, NewLimiter(0, 0))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Could not load cache: %s", err.Error())
+	}
+
+	// Only the most recently used file fits within the limit
+	names, err := ioutil.ReadDir(cache)
+	if err != nil {
+		t.Fatalf("Could not read cache directory: %s", err.Error())
+	}
+	if len(names) != 1 || names[0].Name() != cacheKey(songs[1], tt) || c.size != 4 {
+		t.Fatalf("Unexpected cached files: %v, %d bytes", names, c.size)
+	}
+
+	if cached, size, out := testTranscode(t, c, songs[1], ""); cached.started || size != 4 || out != "4567" {
+		t.Fatalf("Unexpected loaded transcode: %v, %d, %q", cached.started, size, out)
+	}
+
+	// A missing cache directory is created
+	c = NewCache(path.Join(dir, "missing"), 6, NewLimiter(0, 0))
+	if err := c.Load(); err != nil {
+		t.Fatalf("Could not load missing cache: %s", err.Error())
+	}
+	if _, err := os.Stat(path.Join(dir, "missing")); err != nil {
+		t.Fatalf("Cache directory was not created: %s", err.Error())
+	}
+}
@@ -2,6 +2,7 @@ package transcode
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"os/exec"
 
@@ -14,36 +15,76 @@ var (
 	ErrFFmpegNotStarted = errors.New("ffmpeg: transcoding process has not started")
 )
 
+// muxerOptions is implemented by Options whose container requires additional ffmpeg flags,
+// such as MP4, which cannot otherwise be written to a pipe
+type muxerOptions interface {
+	FFmpegMuxerFlags() []string
+}
+
 // FFmpeg represents the ffmpeg media encoder, and is used to provide a more flexible
 // interface than chaining together command-line arguments
 type FFmpeg struct {
 	ffmpeg  *exec.Cmd
+	offset  int
 	options Options
+	profile *Profile
 	song    *data.Song
 	started bool
 	stream  io.ReadCloser
 }
 
-// NewFFmpeg creates a new FFmpeg instance using the input song and options
-func NewFFmpeg(song *data.Song, options Options) *FFmpeg {
+// NewFFmpeg creates a new FFmpeg instance using the input song, options, and optional profile,
+// which begins transcoding at the input offset in seconds
+func NewFFmpeg(song *data.Song, options Options, offset int, profile *Profile) *FFmpeg {
 	return &FFmpeg{
+		offset:  offset,
 		options: options,
+		profile: profile,
 		song:    song,
 		started: false,
 	}
 }
 
-// Arguments outputs a slice of the ffmpeg arguments needed to output audio on stdout
+// Arguments outputs a slice of the ffmpeg arguments needed to output audio on stdout.  Embedded
+// cover art, which ffmpeg reads as a video stream, is dropped.  Tracks read from CUE sheets are
+// cut from their file by seeking to the track's offsets, and transcodes which begin at an offset
+// seek past it before decoding.  A profile's settings follow the codec's quality.
 func (f FFmpeg) Arguments() []string {
-	return []string{
+	args := make([]string, 0)
+	start := f.song.StartOffset + f.offset*1000
+	if start > 0 {
+		args = append(args, "-ss", ffmpegTime(start))
+	}
+	if f.song.EndOffset > 0 {
+		args = append(args, "-t", ffmpegTime(f.song.EndOffset-start))
+	}
+
+	args = append(args,
 		"-i",
 		f.song.FileName,
+		"-vn",
 		"-acodec",
 		f.options.FFmpegCodec(),
 		f.options.FFmpegFlags(),
 		f.options.FFmpegQuality(),
-		"pipe:1." + f.options.Ext(),
+	)
+
+	// Profiles may change the sample rate, channels, filters, and container
+	if f.profile != nil {
+		args = append(args, f.profile.FFmpegFlags()...)
+	}
+	if f.profile != nil && f.profile.Container != "" {
+		args = append(args, f.profile.FFmpegMuxerFlags()...)
+	} else if m, ok := f.options.(muxerOptions); ok {
+		args = append(args, m.FFmpegMuxerFlags()...)
 	}
+
+	return append(args, "pipe:1."+f.options.Ext())
+}
+
+// ffmpegTime formats an offset in milliseconds as seconds, for use as an ffmpeg time duration
+func ffmpegTime(ms int) string {
+	return fmt.Sprintf("%d.%03d", ms/1000, ms%1000)
 }
 
 // Start invokes the ffmpeg media encoder using the path discovered by the transcode manager
@@ -79,6 +120,16 @@ func (f FFmpeg) Stream() (io.ReadCloser, error) {
 	return f.stream, nil
 }
 
+// Kill stops the ffmpeg instance immediately, such as when its client disconnects
+func (f *FFmpeg) Kill() error {
+	// Verify ffmpeg is running
+	if !f.started {
+		return ErrFFmpegNotStarted
+	}
+
+	return f.ffmpeg.Process.Kill()
+}
+
 // Wait waits for the ffmpeg instance to exit
 func (f *FFmpeg) Wait() error {
 	// Verify ffmpeg is running
@@ -0,0 +1,56 @@
+package transcode
+
+// flacCodec contains the codec describing FLAC
+const flacCodec = "FLAC"
+
+// flacExt contains the extension describing FLAC
+const flacExt = "flac"
+
+// flacMIMEType contains the MIME type describing FLAC
+const flacMIMEType = "audio/flac"
+
+// FLACOptions represents the options for a FLAC transcoder.  FLAC is lossless, so its quality
+// is a compression level, which trades encoding speed for a smaller file.
+type FLACOptions struct {
+	level string
+}
+
+// Codec returns the codec used
+func (m FLACOptions) Codec() string {
+	return flacCodec
+}
+
+// Ext returns the file extension used
+func (m FLACOptions) Ext() string {
+	return flacExt
+}
+
+// FFmpegFlags returns the flag used by ffmpeg to signify this encoding
+func (m FLACOptions) FFmpegFlags() string {
+	return "-compression_level"
+}
+
+// FFmpegCodec returns the codec used by ffmpeg
+func (m FLACOptions) FFmpegCodec() string {
+	return FFmpegFLACCodec
+}
+
+// MIMEType returns the MIME type of this item
+func (m FLACOptions) MIMEType() string {
+	return flacMIMEType
+}
+
+// Quality returns the quality used
+func (m FLACOptions) Quality() string {
+	return "level " + m.level
+}
+
+// Bitrate returns zero, because the bitrate of lossless audio depends on its contents
+func (m FLACOptions) Bitrate() int {
+	return 0
+}
+
+// FFmpegQuality returns the quality flag used by ffmpeg
+func (m FLACOptions) FFmpegQuality() string {
+	return m.level
+}
@@ -0,0 +1,136 @@
+package transcode
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/mdlayher/goset"
+)
+
+// FLACTranscoder represents a FLAC transcoding operation
+type FLACTranscoder struct {
+	Options Options
+	ffmpeg  *FFmpeg
+	offset  int
+	profile *Profile
+}
+
+// Bitrate returns zero, because the bitrate of lossless audio depends on its contents
+func (m FLACTranscoder) Bitrate() int {
+	return m.Options.Bitrate()
+}
+
+// Codec returns the selected codec used by the transcoder
+func (m FLACTranscoder) Codec() string {
+	return m.Options.Codec()
+}
+
+// Command returns the command invoked by ffmpeg, for debugging
+func (m FLACTranscoder) Command() []string {
+	// If ffmpeg not started, return no arguments
+	if m.ffmpeg == nil {
+		return nil
+	}
+
+	return append([]string{FFmpegPath}, m.ffmpeg.Arguments()...)
+}
+
+// Kill stops the transcoding process immediately
+func (m *FLACTranscoder) Kill() error {
+	// Make sure ffmpeg was started, to avoid panic
+	if m.ffmpeg == nil {
+		return ErrFFmpegNotStarted
+	}
+
+	return m.ffmpeg.Kill()
+}
+
+// MIMEType returns the MIME type contained within the options, or the profile's container
+func (m FLACTranscoder) MIMEType() string {
+	return m.profile.mimeType(m.Options)
+}
+
+// Start begins the transcoding process, and returns a stream which contains its output
+func (m *FLACTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
+	// Set up the ffmpeg instance
+	m.ffmpeg = NewFFmpeg(song, m.Options, m.offset, m.profile)
+
+	// Invoke ffmpeg to create a transcoded audio stream
+	if err := m.ffmpeg.Start(); err != nil {
+		return nil, err
+	}
+
+	// Retrieve the stream from ffmpeg
+	return m.ffmpeg.Stream()
+}
+
+// Offset returns the number of seconds into the song at which the transcode begins
+func (m FLACTranscoder) Offset() int {
+	return m.offset
+}
+
+// Profile returns the transcoding profile used by the transcoder, if one was selected
+func (m FLACTranscoder) Profile() *Profile {
+	return m.profile
+}
+
+// SetOffset sets the number of seconds into the song at which the transcode begins
+func (m *FLACTranscoder) SetOffset(offset int) {
+	m.offset = offset
+}
+
+// Quality returns the selected quality used by the transcoder
+func (m FLACTranscoder) Quality() string {
+	return m.Options.Quality()
+}
+
+// Wait waits for the transcoding process to complete, returning an error if it fails
+func (m *FLACTranscoder) Wait() error {
+	// Make sure ffmpeg was started, to avoid panic
+	if m.ffmpeg == nil {
+		return ErrFFmpegNotStarted
+	}
+
+	// Wait for ffmpeg
+	if err := m.ffmpeg.Wait(); err != nil {
+		return err
+	}
+
+	// Nullify ffmpeg process
+	m.ffmpeg = nil
+	return nil
+}
+
+// defaultQuality returns the compression level used when none is specified, which is
+// also the default of the FLAC encoder
+func (m FLACTranscoder) defaultQuality() string {
+	return "5"
+}
+
+// cbrSet returns the set of valid compression levels for this transcoder, which are used in
+// place of CBR qualities
+func (m FLACTranscoder) cbrSet() *set.Set {
+	return set.New(0, 1, 2, 3, 4, 5, 6, 7, 8)
+}
+
+// vbrSet returns the set of valid VBR qualities for this transcoder, of which there are none
+func (m FLACTranscoder) vbrSet() *set.Set {
+	return set.New()
+}
+
+// setProfile sets the transcoding profile used by this transcoder
+func (m *FLACTranscoder) setProfile(profile *Profile) {
+	m.profile = profile
+}
+
+// setCBR sets the compression level for this transcoder
+func (m *FLACTranscoder) setCBR(level int) {
+	m.Options = &FLACOptions{strconv.Itoa(level)}
+}
+
+// setVBR sets the compression level for this transcoder, though FLAC has no VBR qualities
+func (m *FLACTranscoder) setVBR(level string) {
+	m.Options = &FLACOptions{level}
+}
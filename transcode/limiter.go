@@ -0,0 +1,124 @@
+package transcode
+
+import (
+	"errors"
+	"sync"
+)
+
+// RetryAfter is the number of seconds clients are asked to wait before retrying a transcode,
+// when too many transcodes are queued
+const RetryAfter = 10
+
+var (
+	// ErrQueueFull is returned when the maximum number of ffmpeg processes are running, and
+	// too many transcodes are already waiting for one to finish
+	ErrQueueFull = errors.New("transcode: too many transcodes are queued")
+	// ErrCanceled is returned when a client disconnects while its transcode is queued
+	ErrCanceled = errors.New("transcode: transcode canceled while queued")
+)
+
+// Limiter limits the number of ffmpeg processes which may run at once.  Transcodes beyond the
+// limit wait in a queue, and as each process exits, the next is chosen from the user with the
+// fewest running processes, so that no single user can starve others.
+type Limiter struct {
+	max   int
+	queue int
+
+	mutex   sync.Mutex
+	running int
+	users   map[int]int
+	waiting []*limiterWaiter
+}
+
+// limiterWaiter is a queued transcode, which is woken once it may start
+type limiterWaiter struct {
+	user  int
+	ready chan struct{}
+}
+
+// NewLimiter creates a new Limiter which allows up to max ffmpeg processes, and queues up to
+// queue transcodes beyond that.  If max is zero, the number of processes is unlimited.
+func NewLimiter(max int, queue int) *Limiter {
+	return &Limiter{
+		max:   max,
+		queue: queue,
+		users: make(map[int]int),
+	}
+}
+
+// Acquire waits until the input user may start an ffmpeg process, or until the cancel channel
+// is closed or receives a value.  If the queue is full, ErrQueueFull is returned immediately.
+// Each successful call must be paired with a call to Release.
+func (l *Limiter) Acquire(user int, cancel <-chan bool) error {
+	l.mutex.Lock()
+	if l.max <= 0 || (l.running < l.max && len(l.waiting) == 0) {
+		l.start(user)
+		l.mutex.Unlock()
+		return nil
+	}
+
+	if len(l.waiting) >= l.queue {
+		l.mutex.Unlock()
+		return ErrQueueFull
+	}
+
+	w := &limiterWaiter{user: user, ready: make(chan struct{})}
+	l.waiting = append(l.waiting, w)
+	l.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-cancel:
+	}
+
+	// Leave the queue, or give up the process if one was granted while canceling
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for i, w2 := range l.waiting {
+		if w2 == w {
+			l.waiting = append(l.waiting[:i], l.waiting[i+1:]...)
+			return ErrCanceled
+		}
+	}
+
+	l.stop(user)
+	return ErrCanceled
+}
+
+// Release frees the ffmpeg process held by the input user, waking the next queued transcode
+func (l *Limiter) Release(user int) {
+	l.mutex.Lock()
+	l.stop(user)
+	l.mutex.Unlock()
+}
+
+// start records a running process for the input user
+func (l *Limiter) start(user int) {
+	l.running++
+	l.users[user]++
+}
+
+// stop records that a process stopped for the input user, and starts queued transcodes while
+// processes are available, favoring the users with the fewest running processes
+func (l *Limiter) stop(user int) {
+	l.running--
+	if l.users[user]--; l.users[user] <= 0 {
+		delete(l.users, user)
+	}
+
+	for len(l.waiting) > 0 && l.running < l.max {
+		next := 0
+		for i, w := range l.waiting {
+			if l.users[w.user] < l.users[l.waiting[next].user] {
+				next = i
+			}
+		}
+
+		w := l.waiting[next]
+		l.waiting = append(l.waiting[:next], l.waiting[next+1:]...)
+		l.start(w.user)
+		close(w.ready)
+	}
+}
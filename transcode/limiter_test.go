@@ -0,0 +1,155 @@
+package transcode
+
+import (
+	"testing"
+	"time"
+)
+
+// testQueued waits until the input number of transcodes are queued by the limiter
+func testQueued(t *testing.T, l *Limiter, n int) {
+	for i := 0; i < 500; i++ {
+		l.mutex.Lock()
+		queued := len(l.waiting)
+		l.mutex.Unlock()
+
+		if queued == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("Limiter did not queue %d transcodes", n)
+}
+
+// TestLimiterUnlimited verifies that a limiter with no maximum never queues transcodes
+func TestLimiterUnlimited(t *testing.T) {
+	l := NewLimiter(0, 0)
+	for i := 0; i < 10; i++ {
+		if err := l.Acquire(1, nil); err != nil {
+			t.Fatalf("Could not acquire process %d: %s", i, err.Error())
+		}
+	}
+}
+
+// TestLimiterFairness verifies that queued transcodes start as processes are released, favoring
+// users with the fewest running processes, and otherwise in the order they were queued
+func TestLimiterFairness(t *testing.T) {
+	l := NewLimiter(2, 4)
+
+	// The first user holds both processes
+	for i := 0; i < 2; i++ {
+		if err := l.Acquire(1, nil); err != nil {
+			t.Fatalf("Could not acquire process %d: %s", i, err.Error())
+		}
+	}
+
+	// Two more transcodes by the first user are queued ahead of one by the second user
+	started := make(chan string, 3)
+	waiters := []struct {
+		name string
+		user int
+	}{
+		{"first", 1},
+		{"second", 1},
+		{"other", 2},
+	}
+	for i, w := range waiters {
+		go func(name string, user int) {
+			if err := l.Acquire(user, nil); err != nil {
+				t.Errorf("Could not acquire process for %s: %s", name, err.Error())
+			}
+			started <- name
+		}(w.name, w.user)
+
+		testQueued(t, l, i+1)
+	}
+
+	// The second user has no running processes, so its transcode starts first, followed by
+	// the first user's transcodes in order
+	for _, expected := range []string{"other", "first", "second"} {
+		l.Release(1)
+
+		select {
+		case name := <-started:
+			if name != expected {
+				t.Fatalf("Unexpected transcode started: %s != %s", name, expected)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Transcode %s was not started", expected)
+		}
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.running != 2 || l.users[1] != 1 || l.users[2] != 1 {
+		t.Fatalf("Unexpected running processes: %d, %v", l.running, l.users)
+	}
+}
+
+// TestLimiterQueueFull verifies that transcodes are rejected once the queue is full
+func TestLimiterQueueFull(t *testing.T) {
+	l := NewLimiter(1, 1)
+	if err := l.Acquire(1, nil); err != nil {
+		t.Fatalf("Could not acquire process: %s", err.Error())
+	}
+
+	started := make(chan error)
+	go func() {
+		started <- l.Acquire(2, nil)
+	}()
+	testQueued(t, l, 1)
+
+	if err := l.Acquire(3, nil); err != ErrQueueFull {
+		t.Fatalf("Unexpected error for full queue: %v", err)
+	}
+
+	// Once a process is released, the queued transcode starts, and there is room to queue again
+	l.Release(1)
+	if err := <-started; err != nil {
+		t.Fatalf("Could not acquire queued process: %s", err.Error())
+	}
+
+	go func() {
+		started <- l.Acquire(3, nil)
+	}()
+	testQueued(t, l, 1)
+	l.Release(2)
+	if err := <-started; err != nil {
+		t.Fatalf("Could not acquire queued process: %s", err.Error())
+	}
+}
+
+// TestLimiterCancel verifies that a canceled transcode leaves the queue without taking a
+// process
+func TestLimiterCancel(t *testing.T) {
+	l := NewLimiter(1, 2)
+	if err := l.Acquire(1, nil); err != nil {
+		t.Fatalf("Could not acquire process: %s", err.Error())
+	}
+
+	cancel := make(chan bool)
+	canceled := make(chan error)
+	go func() {
+		canceled <- l.Acquire(2, cancel)
+	}()
+	testQueued(t, l, 1)
+
+	close(cancel)
+	if err := <-canceled; err != ErrCanceled {
+		t.Fatalf("Unexpected error for canceled transcode: %v", err)
+	}
+	testQueued(t, l, 0)
+
+	// Releasing the process leaves it free, rather than granting it to the canceled transcode
+	l.Release(1)
+	l.mutex.Lock()
+	running, users := l.running, len(l.users)
+	l.mutex.Unlock()
+	if running != 0 || users != 0 {
+		t.Fatalf("Unexpected running processes after cancel: %d, %d users", running, users)
+	}
+
+	if err := l.Acquire(3, nil); err != nil {
+		t.Fatalf("Could not acquire process after cancel: %s", err.Error())
+	}
+}
@@ -1,5 +1,9 @@
 package transcode
 
+import (
+	"strconv"
+)
+
 // mp3Codec contains the codec describing MP3
 const mp3Codec = "MP3"
 
@@ -9,6 +13,9 @@ const mp3Ext = "mp3"
 // mp3MIMEType contains the MIME type describing MP3
 const mp3MIMEType = "audio/mpeg"
 
+// mp3VBRBitrates contains the approximate bitrates of MP3 VBR qualities, in kbps
+var mp3VBRBitrates = map[string]int{"V0": 245, "V2": 190, "V4": 165}
+
 // MP3CBROptions represents the options for a MP3 CBR transcoder
 type MP3CBROptions struct {
 	quality string
@@ -44,6 +51,12 @@ func (m MP3CBROptions) Quality() string {
 	return m.quality + "kbps"
 }
 
+// Bitrate returns the bitrate used, in kbps
+func (m MP3CBROptions) Bitrate() int {
+	bitrate, _ := strconv.Atoi(m.quality)
+	return bitrate
+}
+
 // FFmpegQuality returns the quality flag used by ffmpeg
 func (m MP3CBROptions) FFmpegQuality() string {
 	return m.quality + "k"
@@ -84,6 +97,11 @@ func (m MP3VBROptions) Quality() string {
 	return m.quality
 }
 
+// Bitrate returns the approximate bitrate of the quality used, in kbps
+func (m MP3VBROptions) Bitrate() int {
+	return mp3VBRBitrates[m.quality]
+}
+
 // FFmpegQuality returns the quality flag used by ffmpeg
 func (m MP3VBROptions) FFmpegQuality() string {
 	// Return the number after 'V'
@@ -1,5 +1,9 @@
 package transcode
 
+import (
+	"strconv"
+)
+
 // oggCodec contains the codec describing OGG
 const oggCodec = "Ogg Vorbis"
 
@@ -9,6 +13,9 @@ const oggExt = "ogg"
 // oggMIMEType contains the MIME type describing OGG
 const oggMIMEType = "audio/ogg"
 
+// oggVBRBitrates contains the approximate bitrates of OGG VBR qualities, in kbps
+var oggVBRBitrates = map[string]int{"Q10": 500, "Q8": 256, "Q6": 192}
+
 // OGGCBROptions represents the options for a OGG CBR transcoder
 type OGGCBROptions struct {
 	quality string
@@ -44,6 +51,12 @@ func (m OGGCBROptions) Quality() string {
 	return m.quality + "kbps"
 }
 
+// Bitrate returns the bitrate used, in kbps
+func (m OGGCBROptions) Bitrate() int {
+	bitrate, _ := strconv.Atoi(m.quality)
+	return bitrate
+}
+
 // FFmpegQuality returns the quality flag used by ffmpeg
 func (m OGGCBROptions) FFmpegQuality() string {
 	return m.quality + "k"
@@ -84,6 +97,11 @@ func (m OGGVBROptions) Quality() string {
 	return m.quality
 }
 
+// Bitrate returns the approximate bitrate of the quality used, in kbps
+func (m OGGVBROptions) Bitrate() int {
+	return oggVBRBitrates[m.quality]
+}
+
 // FFmpegQuality returns the quality flag used by ffmpeg
 func (m OGGVBROptions) FFmpegQuality() string {
 	// Return the number after 'Q'
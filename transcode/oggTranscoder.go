@@ -14,6 +14,13 @@ import (
 type OGGTranscoder struct {
 	Options Options
 	ffmpeg  *FFmpeg
+	offset  int
+	profile *Profile
+}
+
+// Bitrate returns the approximate bitrate of the transcoder's output, in kbps
+func (m OGGTranscoder) Bitrate() int {
+	return m.Options.Bitrate()
 }
 
 // Codec returns the selected codec used by the transcoder
@@ -31,15 +38,25 @@ func (m OGGTranscoder) Command() []string {
 	return append([]string{FFmpegPath}, m.ffmpeg.Arguments()...)
 }
 
-// MIMEType returns the MIME type contained within the options
+// Kill stops the transcoding process immediately
+func (m *OGGTranscoder) Kill() error {
+	// Make sure ffmpeg was started, to avoid panic
+	if m.ffmpeg == nil {
+		return ErrFFmpegNotStarted
+	}
+
+	return m.ffmpeg.Kill()
+}
+
+// MIMEType returns the MIME type contained within the options, or the profile's container
 func (m OGGTranscoder) MIMEType() string {
-	return m.Options.MIMEType()
+	return m.profile.mimeType(m.Options)
 }
 
 // Start begins the transcoding process, and returns a stream which contains its output
 func (m *OGGTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
 	// Set up the ffmpeg instance
-	m.ffmpeg = NewFFmpeg(song, m.Options)
+	m.ffmpeg = NewFFmpeg(song, m.Options, m.offset, m.profile)
 
 	// Invoke ffmpeg to create a transcoded audio stream
 	if err := m.ffmpeg.Start(); err != nil {
@@ -50,6 +67,21 @@ func (m *OGGTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
 	return m.ffmpeg.Stream()
 }
 
+// Offset returns the number of seconds into the song at which the transcode begins
+func (m OGGTranscoder) Offset() int {
+	return m.offset
+}
+
+// Profile returns the transcoding profile used by the transcoder, if one was selected
+func (m OGGTranscoder) Profile() *Profile {
+	return m.profile
+}
+
+// SetOffset sets the number of seconds into the song at which the transcode begins
+func (m *OGGTranscoder) SetOffset(offset int) {
+	m.offset = offset
+}
+
 // Quality returns the selected quality used by the transcoder
 func (m OGGTranscoder) Quality() string {
 	// Check for CBR or VBR
@@ -77,6 +109,11 @@ func (m *OGGTranscoder) Wait() error {
 	return nil
 }
 
+// defaultQuality returns the quality used when none is specified, a 192kbps CBR encode
+func (m OGGTranscoder) defaultQuality() string {
+	return "192"
+}
+
 // cbrSet returns the set of valid CBR qualities for this transcoder
 func (m OGGTranscoder) cbrSet() *set.Set {
 	return set.New(128, 192, 256, 320, 500)
@@ -87,6 +124,11 @@ func (m OGGTranscoder) vbrSet() *set.Set {
 	return set.New("q6", "Q6", "q8", "Q8", "q10", "Q10")
 }
 
+// setProfile sets the transcoding profile used by this transcoder
+func (m *OGGTranscoder) setProfile(profile *Profile) {
+	m.profile = profile
+}
+
 // setCBR sets appropriate CBR options for this transcoder
 func (m *OGGTranscoder) setCBR(cbr int) {
 	m.Options = &OGGCBROptions{strconv.Itoa(cbr)}
@@ -1,5 +1,9 @@
 package transcode
 
+import (
+	"strconv"
+)
+
 // opusCodec contains the codec describing OPUS
 const opusCodec = "Ogg Opus"
 
@@ -9,6 +13,9 @@ const opusExt = "opus"
 // opusMIMEType contains the MIME type describing OPUS
 const opusMIMEType = "audio/ogg; codecs=opus"
 
+// opusVBRBitrates contains the approximate bitrates of OPUS VBR qualities, in kbps
+var opusVBRBitrates = map[string]int{"V0": 245, "V2": 190, "V4": 165}
+
 // OPUSCBROptions represents the options for a OPUS CBR transcoder
 type OPUSCBROptions struct {
 	quality string
@@ -44,6 +51,12 @@ func (m OPUSCBROptions) Quality() string {
 	return m.quality + "kbps"
 }
 
+// Bitrate returns the bitrate used, in kbps
+func (m OPUSCBROptions) Bitrate() int {
+	bitrate, _ := strconv.Atoi(m.quality)
+	return bitrate
+}
+
 // FFmpegQuality returns the quality flag used by ffmpeg
 func (m OPUSCBROptions) FFmpegQuality() string {
 	return m.quality + "k"
@@ -84,6 +97,11 @@ func (m OPUSVBROptions) Quality() string {
 	return m.quality
 }
 
+// Bitrate returns the approximate bitrate of the quality used, in kbps
+func (m OPUSVBROptions) Bitrate() int {
+	return opusVBRBitrates[m.quality]
+}
+
 // FFmpegQuality returns the quality flag used by ffmpeg
 func (m OPUSVBROptions) FFmpegQuality() string {
 	// Return the number after 'Q'
@@ -14,6 +14,13 @@ import (
 type OPUSTranscoder struct {
 	Options Options
 	ffmpeg  *FFmpeg
+	offset  int
+	profile *Profile
+}
+
+// Bitrate returns the approximate bitrate of the transcoder's output, in kbps
+func (m OPUSTranscoder) Bitrate() int {
+	return m.Options.Bitrate()
 }
 
 // Codec returns the selected codec used by the transcoder
@@ -31,15 +38,25 @@ func (m OPUSTranscoder) Command() []string {
 	return append([]string{FFmpegPath}, m.ffmpeg.Arguments()...)
 }
 
-// MIMEType returns the MIME type contained within the options
+// Kill stops the transcoding process immediately
+func (m *OPUSTranscoder) Kill() error {
+	// Make sure ffmpeg was started, to avoid panic
+	if m.ffmpeg == nil {
+		return ErrFFmpegNotStarted
+	}
+
+	return m.ffmpeg.Kill()
+}
+
+// MIMEType returns the MIME type contained within the options, or the profile's container
 func (m OPUSTranscoder) MIMEType() string {
-	return m.Options.MIMEType()
+	return m.profile.mimeType(m.Options)
 }
 
 // Start begins the transcoding process, and returns a stream which contains its output
 func (m *OPUSTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
 	// Set up the ffmpeg instance
-	m.ffmpeg = NewFFmpeg(song, m.Options)
+	m.ffmpeg = NewFFmpeg(song, m.Options, m.offset, m.profile)
 
 	// Invoke ffmpeg to create a transcoded audio stream
 	if err := m.ffmpeg.Start(); err != nil {
@@ -50,6 +67,21 @@ func (m *OPUSTranscoder) Start(song *data.Song) (io.ReadCloser, error) {
 	return m.ffmpeg.Stream()
 }
 
+// Offset returns the number of seconds into the song at which the transcode begins
+func (m OPUSTranscoder) Offset() int {
+	return m.offset
+}
+
+// Profile returns the transcoding profile used by the transcoder, if one was selected
+func (m OPUSTranscoder) Profile() *Profile {
+	return m.profile
+}
+
+// SetOffset sets the number of seconds into the song at which the transcode begins
+func (m *OPUSTranscoder) SetOffset(offset int) {
+	m.offset = offset
+}
+
 // Quality returns the selected quality used by the transcoder
 func (m OPUSTranscoder) Quality() string {
 	// Check for CBR or VBR
@@ -77,6 +109,11 @@ func (m *OPUSTranscoder) Wait() error {
 	return nil
 }
 
+// defaultQuality returns the quality used when none is specified, a 192kbps CBR encode
+func (m OPUSTranscoder) defaultQuality() string {
+	return "192"
+}
+
 // cbrSet returns the set of valid CBR qualities for this transcoder
 func (m OPUSTranscoder) cbrSet() *set.Set {
 	return set.New(128, 192, 256, 320, 500)
@@ -87,6 +124,11 @@ func (m OPUSTranscoder) vbrSet() *set.Set {
 	return set.New("v0", "V0", "v2", "V2", "v4", "V4")
 }
 
+// setProfile sets the transcoding profile used by this transcoder
+func (m *OPUSTranscoder) setProfile(profile *Profile) {
+	m.profile = profile
+}
+
 // setCBR sets appropriate CBR options for this transcoder
 func (m *OPUSTranscoder) setCBR(cbr int) {
 	m.Options = &OPUSCBROptions{strconv.Itoa(cbr)}
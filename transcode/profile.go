@@ -0,0 +1,113 @@
+package transcode
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidProfile is returned when a transcoding profile does not exist
+	ErrInvalidProfile = errors.New("transcode: no such transcoding profile")
+	// ErrInvalidContainer is returned when a transcoding profile uses an unknown container
+	ErrInvalidContainer = errors.New("transcode: invalid container for transcoding profile")
+	// ErrInvalidProfileSettings is returned when a transcoding profile uses a negative sample
+	// rate or channel count
+	ErrInvalidProfileSettings = errors.New("transcode: invalid sample rate or channels for transcoding profile")
+)
+
+// containerMIMETypes maps the ffmpeg containers which transcoding profiles may use to their
+// MIME types
+var containerMIMETypes = map[string]string{
+	"adts":     "audio/aac",
+	"flac":     "audio/flac",
+	"matroska": "audio/x-matroska",
+	"mp3":      "audio/mpeg",
+	"mp4":      "audio/mp4",
+	"ogg":      "audio/ogg",
+	"wav":      "audio/wav",
+	"webm":     "audio/webm",
+}
+
+// Profiles are the named transcoding profiles set in config, which are set up by the
+// transcode manager
+var Profiles = make(map[string]*Profile)
+
+// Profile represents a named set of transcoding settings, which may change the sample rate,
+// channels, and container of a transcode, and apply ffmpeg audio filters.  Settings which
+// are not set are left to ffmpeg.
+type Profile struct {
+	Name       string
+	Codec      string
+	Quality    string
+	SampleRate int
+	Channels   int
+	Filters    string
+	Container  string
+}
+
+// Validate verifies that a profile uses a valid codec, quality, and container, whether or not
+// ffmpeg is able to use the codec
+func (p Profile) Validate() error {
+	if _, err := factory(strings.ToUpper(p.Codec), p.Quality, false); err != nil {
+		return err
+	}
+
+	if _, ok := containerMIMETypes[p.Container]; p.Container != "" && !ok {
+		return ErrInvalidContainer
+	}
+
+	if p.SampleRate < 0 || p.Channels < 0 {
+		return ErrInvalidProfileSettings
+	}
+
+	return nil
+}
+
+// FFmpegFlags returns the flags used by ffmpeg to apply this profile's settings
+func (p Profile) FFmpegFlags() []string {
+	flags := make([]string, 0)
+	if p.SampleRate > 0 {
+		flags = append(flags, "-ar", strconv.Itoa(p.SampleRate))
+	}
+	if p.Channels > 0 {
+		flags = append(flags, "-ac", strconv.Itoa(p.Channels))
+	}
+	if p.Filters != "" {
+		flags = append(flags, "-af", p.Filters)
+	}
+
+	return flags
+}
+
+// FFmpegMuxerFlags returns the flags used by ffmpeg to write this profile's container.  MP4
+// containers must be fragmented, in order to be written to a pipe.
+func (p Profile) FFmpegMuxerFlags() []string {
+	if p.Container == "" {
+		return nil
+	}
+
+	if p.Container == "mp4" {
+		return aacMuxerFlags
+	}
+
+	return []string{"-f", p.Container}
+}
+
+// key returns a string which identifies this profile and its settings, so that transcodes
+// are not reused from the cache if a profile's settings change
+func (p Profile) key() string {
+	return fmt.Sprintf("%s-%08x", p.Name, crc32.ChecksumIEEE([]byte(fmt.Sprintf("%+v", p))))
+}
+
+// mimeType returns the MIME type of this profile's container, or that of the input options if
+// there is no profile, or the profile does not set a container
+func (p *Profile) mimeType(options Options) string {
+	if p == nil || p.Container == "" {
+		return options.MIMEType()
+	}
+
+	return containerMIMETypes[p.Container]
+}
@@ -0,0 +1,142 @@
+package transcode
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mdlayher/wavepipe/data"
+)
+
+// TestProfileValidate verifies that profiles must use a valid codec, quality, and container,
+// and may not use a negative sample rate or channel count
+func TestProfileValidate(t *testing.T) {
+	// Profiles are validated whether or not ffmpeg supports their codec
+	defer testEnable()()
+
+	tests := []struct {
+		profile Profile
+		err     error
+	}{
+		{Profile{Codec: "mp3"}, nil},
+		{Profile{Codec: "OPUS", Quality: "128", SampleRate: 48000, Channels: 2, Container: "webm"}, nil},
+		{Profile{Codec: "AAC", Quality: "Q1", Container: "adts"}, nil},
+		{Profile{Codec: "FLAC", Quality: "8", Filters: "volume=0.5"}, nil},
+		{Profile{Codec: "WAV"}, ErrInvalidCodec},
+		{Profile{Codec: "MP3", Quality: "Q10"}, ErrInvalidQuality},
+		{Profile{Codec: "OGG", Container: "avi"}, ErrInvalidContainer},
+		{Profile{Codec: "MP3", SampleRate: -1}, ErrInvalidProfileSettings},
+		{Profile{Codec: "MP3", Channels: -2}, ErrInvalidProfileSettings},
+	}
+
+	for _, test := range tests {
+		if err := test.profile.Validate(); err != test.err {
+			t.Fatalf("Unexpected error for profile %v: %v != %v", test.profile, err, test.err)
+		}
+	}
+}
+
+// TestProfileFFmpegFlags verifies that only the settings a profile sets are passed to ffmpeg,
+// and that its container determines the output format and MIME type
+func TestProfileFFmpegFlags(t *testing.T) {
+	tests := []struct {
+		profile Profile
+		flags   []string
+		muxer   []string
+		mime    string
+	}{
+		{Profile{}, []string{}, nil, "audio/mpeg"},
+		{Profile{SampleRate: 22050}, []string{"-ar", "22050"}, nil, "audio/mpeg"},
+		{Profile{Channels: 1, Filters: "volume=0.5,highpass=f=200"}, []string{"-ac", "1", "-af", "volume=0.5,highpass=f=200"}, nil, "audio/mpeg"},
+		{Profile{SampleRate: 48000, Channels: 2, Container: "ogg"}, []string{"-ar", "48000", "-ac", "2"}, []string{"-f", "ogg"}, "audio/ogg"},
+		{Profile{Container: "mp4"}, []string{}, aacMuxerFlags, "audio/mp4"},
+	}
+
+	options := MP3CBROptions{"192"}
+	for _, test := range tests {
+		if flags := test.profile.FFmpegFlags(); !reflect.DeepEqual(flags, test.flags) {
+			t.Fatalf("Unexpected flags for profile %v: %v != %v", test.profile, flags, test.flags)
+		}
+		if muxer := test.profile.FFmpegMuxerFlags(); !reflect.DeepEqual(muxer, test.muxer) {
+			t.Fatalf("Unexpected muxer flags for profile %v: %v != %v", test.profile, muxer, test.muxer)
+		}
+		if mime := test.profile.mimeType(options); mime != test.mime {
+			t.Fatalf("Unexpected MIME type for profile %v: %s != %s", test.profile, mime, test.mime)
+		}
+	}
+
+	// Without a profile, the codec's MIME type is used
+	var p *Profile
+	if mime := p.mimeType(options); mime != "audio/mpeg" {
+		t.Fatalf("Unexpected MIME type without profile: %s", mime)
+	}
+}
+
+// TestProfileFactory verifies that a named profile selects its codec and quality, and that
+// its settings are passed to ffmpeg after the codec's quality
+func TestProfileFactory(t *testing.T) {
+	defer testEnable(FFmpegAACCodec, FFmpegMP3Codec)()
+
+	profiles := Profiles
+	defer func() {
+		Profiles = profiles
+	}()
+	Profiles = map[string]*Profile{
+		"mobile": {Name: "mobile", Codec: "aac", Quality: "96", Channels: 1, Container: "adts"},
+	}
+
+	if _, err := Factory("MP3", "", "missing"); err != ErrInvalidProfile {
+		t.Fatalf("Unexpected error for missing profile: %v", err)
+	}
+
+	transcoder, err := Factory("MP3", "320", "mobile")
+	if err != nil {
+		t.Fatalf("Could not create transcoder for profile: %s", err.Error())
+	}
+	if transcoder.Codec() != "AAC" || transcoder.Quality() != "CBR 96kbps" || transcoder.Profile() != Profiles["mobile"] {
+		t.Fatalf("Unexpected transcoder for profile: %s %s", transcoder.Codec(), transcoder.Quality())
+	}
+	if transcoder.MIMEType() != "audio/aac" {
+		t.Fatalf("Unexpected MIME type for profile: %s", transcoder.MIMEType())
+	}
+
+	// The profile's container replaces the MP4 container used by AAC
+	song := &data.Song{FileName: "/music/song.flac"}
+	args := NewFFmpeg(song, testOptions(transcoder), 0, transcoder.Profile()).Arguments()
+	expected := []string{"-i", song.FileName, "-vn", "-acodec", "aac", "-ab", "96k", "-ac", "1", "-f", "adts", "pipe:1.m4a"}
+	if !reflect.DeepEqual(args, expected) {
+		t.Fatalf("Unexpected arguments for profile: %v != %v", args, expected)
+	}
+}
+
+// TestProfileCacheKey verifies that transcodes using a profile are cached separately from
+// those without one, and again whenever the profile's settings change
+func TestProfileCacheKey(t *testing.T) {
+	defer testEnable(FFmpegMP3Codec)()
+
+	song := &data.Song{ID: 1, LastModified: 1234}
+	transcoder, err := Factory("MP3", "192", "")
+	if err != nil {
+		t.Fatalf("Could not create transcoder: %s", err.Error())
+	}
+
+	plain := cacheKey(song, transcoder)
+	if strings.Contains(plain, "mobile") {
+		t.Fatalf("Unexpected profile in cache key: %s", plain)
+	}
+
+	p := &Profile{Name: "mobile", Codec: "MP3", Quality: "192", SampleRate: 22050}
+	transcoder.setProfile(p)
+	first := cacheKey(song, transcoder)
+	if !strings.HasPrefix(first, plain+"_mobile-") {
+		t.Fatalf("Unexpected cache key for profile: %s", first)
+	}
+	if again := cacheKey(song, transcoder); again != first {
+		t.Fatalf("Unstable cache key for profile: %s != %s", again, first)
+	}
+
+	p.SampleRate = 44100
+	if changed := cacheKey(song, transcoder); changed == first {
+		t.Fatalf("Cache key unchanged after profile settings changed: %s", changed)
+	}
+}
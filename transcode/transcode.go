@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/mdlayher/wavepipe/data"
 
@@ -17,6 +18,10 @@ const (
 	FFmpegOGGCodec = "libvorbis"
 	// FFmpegOPUSCodec contains the ffmpeg codec used to transcode to Opus
 	FFmpegOPUSCodec = "libopus"
+	// FFmpegAACCodec contains the ffmpeg codec used to transcode to AAC
+	FFmpegAACCodec = "aac"
+	// FFmpegFLACCodec contains the ffmpeg codec used to transcode to FLAC
+	FFmpegFLACCodec = "flac"
 )
 
 var (
@@ -36,6 +41,12 @@ var (
 	// ErrOPUSDisabled is returned when OPUS transcoding is disabled, due to ffmpeg not
 	// containing the necessary codec
 	ErrOPUSDisabled = errors.New("transcode: " + FFmpegOPUSCodec + " codec not found, OPUS transcoding is disabled")
+	// ErrAACDisabled is returned when AAC transcoding is disabled, due to ffmpeg not
+	// containing the necessary codec
+	ErrAACDisabled = errors.New("transcode: " + FFmpegAACCodec + " codec not found, AAC transcoding is disabled")
+	// ErrFLACDisabled is returned when FLAC transcoding is disabled, due to ffmpeg not
+	// containing the necessary codec
+	ErrFLACDisabled = errors.New("transcode: " + FFmpegFLACCodec + " codec not found, FLAC transcoding is disabled")
 )
 
 // Enabled determines whether transcoding is available and enabled for wavepipe
@@ -50,13 +61,20 @@ var CodecSet = set.New()
 // Transcoder represents a transcoding operation, and the methods which must be defined
 // for a transcoder
 type Transcoder interface {
+	Bitrate() int
 	Codec() string
 	Command() []string
+	Kill() error
 	MIMEType() string
+	Offset() int
+	Profile() *Profile
+	SetOffset(int)
 	Start(*data.Song) (io.ReadCloser, error)
 	Wait() error
 	Quality() string
 
+	defaultQuality() string
+	setProfile(*Profile)
 	cbrSet() *set.Set
 	vbrSet() *set.Set
 	setCBR(int)
@@ -66,6 +84,7 @@ type Transcoder interface {
 // Options represents an audio codec and its quality settings, and includes methods to
 // retrieve these settings
 type Options interface {
+	Bitrate() int
 	Codec() string
 	Ext() string
 	FFmpegCodec() string
@@ -75,13 +94,36 @@ type Options interface {
 	Quality() string
 }
 
-// Factory generates a new Transcoder depending on the input parameters
-func Factory(codec string, quality string) (Transcoder, error) {
+// Factory generates a new Transcoder depending on the input parameters.  If a profile is
+// named, its codec, quality, and settings are used instead.
+func Factory(codec string, quality string, profile string) (Transcoder, error) {
 	// Check if transcoding is disabled
 	if !Enabled {
 		return nil, ErrTranscodingDisabled
 	}
 
+	// Check for a named profile
+	if profile == "" {
+		return factory(codec, quality, true)
+	}
+
+	p, ok := Profiles[profile]
+	if !ok {
+		return nil, ErrInvalidProfile
+	}
+
+	transcoder, err := factory(strings.ToUpper(p.Codec), p.Quality, true)
+	if err != nil {
+		return nil, err
+	}
+
+	transcoder.setProfile(p)
+	return transcoder, nil
+}
+
+// factory generates a new Transcoder using the input codec and quality, optionally verifying
+// that ffmpeg is able to use the codec
+func factory(codec string, quality string, checkCodec bool) (Transcoder, error) {
 	// Output transcoder
 	var transcoder Transcoder
 
@@ -90,7 +132,7 @@ func Factory(codec string, quality string) (Transcoder, error) {
 	// MP3
 	case "MP3":
 		// Verify MP3 transcoding is enabled
-		if !CodecSet.Has(FFmpegMP3Codec) {
+		if checkCodec && !CodecSet.Has(FFmpegMP3Codec) {
 			return nil, ErrMP3Disabled
 		}
 
@@ -98,7 +140,7 @@ func Factory(codec string, quality string) (Transcoder, error) {
 	// Ogg Vorbis
 	case "OGG":
 		// Verify OGG transcoding is enabled
-		if !CodecSet.Has(FFmpegOGGCodec) {
+		if checkCodec && !CodecSet.Has(FFmpegOGGCodec) {
 			return nil, ErrOGGDisabled
 		}
 
@@ -106,18 +148,39 @@ func Factory(codec string, quality string) (Transcoder, error) {
 	// Ogg Opus
 	case "OPUS":
 		// Verify OPUS transcoding is enabled
-		if !CodecSet.Has(FFmpegOPUSCodec) {
+		if checkCodec && !CodecSet.Has(FFmpegOPUSCodec) {
 			return nil, ErrOPUSDisabled
 		}
 
 		transcoder = new(OPUSTranscoder)
+	// AAC, in a MP4 container
+	case "AAC":
+		// Verify AAC transcoding is enabled
+		if checkCodec && !CodecSet.Has(FFmpegAACCodec) {
+			return nil, ErrAACDisabled
+		}
+
+		transcoder = new(AACTranscoder)
+	// FLAC
+	case "FLAC":
+		// Verify FLAC transcoding is enabled
+		if checkCodec && !CodecSet.Has(FFmpegFLACCodec) {
+			return nil, ErrFLACDisabled
+		}
+
+		transcoder = new(FLACTranscoder)
 	// Invalid choice
 	default:
 		return nil, ErrInvalidCodec
 	}
 
+	// Use the codec's default quality if none is specified
+	if quality == "" {
+		quality = transcoder.defaultQuality()
+	}
+
 	// Check for valid quality option
-	// If quality is a valid integer, CBR encode
+	// If quality is a valid integer, CBR encode, or use it as a FLAC compression level
 	if cbr, err := strconv.Atoi(quality); err == nil {
 		// Check for valid CBR quality
 		if !transcoder.cbrSet().Has(cbr) {
@@ -139,3 +202,15 @@ func Factory(codec string, quality string) (Transcoder, error) {
 	// Return the final transcoder
 	return transcoder, nil
 }
+
+// EstimateSize estimates the size in bytes of the input song transcoded by the input transcoder,
+// using the transcoder's bitrate and the length of the song which remains after its offset.  -1
+// is returned if the size cannot be estimated, such as for lossless codecs.
+func EstimateSize(song *data.Song, t Transcoder) int64 {
+	remaining := song.Length - t.Offset()
+	if remaining <= 0 || t.Bitrate() <= 0 {
+		return -1
+	}
+
+	return int64(t.Bitrate()) * 1000 / 8 * int64(remaining)
+}
@@ -0,0 +1,179 @@
+package transcode
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mdlayher/wavepipe/data"
+
+	"github.com/mdlayher/goset"
+)
+
+// testEnable enables transcoding with the input ffmpeg codecs, returning a function which
+// restores the previous settings
+func testEnable(codecs ...interface{}) func() {
+	enabled, codecSet := Enabled, CodecSet
+
+	Enabled = true
+	CodecSet = set.New(codecs...)
+
+	return func() {
+		Enabled, CodecSet = enabled, codecSet
+	}
+}
+
+// testOptions returns the options selected by a transcoder
+func testOptions(t Transcoder) Options {
+	switch t := t.(type) {
+	case *AACTranscoder:
+		return t.Options
+	case *FLACTranscoder:
+		return t.Options
+	case *MP3Transcoder:
+		return t.Options
+	case *OGGTranscoder:
+		return t.Options
+	case *OPUSTranscoder:
+		return t.Options
+	}
+
+	return nil
+}
+
+// TestFactory verifies that Factory accepts only the qualities each codec supports, and that
+// the resulting transcoders report their quality, bitrate, and MIME type
+func TestFactory(t *testing.T) {
+	defer testEnable(FFmpegAACCodec, FFmpegFLACCodec)()
+
+	tests := []struct {
+		codec   string
+		quality string
+		err     error
+		output  string
+		bitrate int
+		mime    string
+	}{
+		// AAC, with CBR bitrates and VBR qualities
+		{"AAC", "", nil, "CBR 192kbps", 192, "audio/mp4"},
+		{"AAC", "96", nil, "CBR 96kbps", 96, "audio/mp4"},
+		{"AAC", "320", nil, "CBR 320kbps", 320, "audio/mp4"},
+		{"AAC", "q1", nil, "VBR Q1", 128, "audio/mp4"},
+		{"AAC", "Q2", nil, "VBR Q2", 192, "audio/mp4"},
+		{"AAC", "64", ErrInvalidQuality, "", 0, ""},
+		{"AAC", "Q3", ErrInvalidQuality, "", 0, ""},
+		{"AAC", "V0", ErrInvalidQuality, "", 0, ""},
+		// FLAC, with compression levels and no VBR qualities
+		{"FLAC", "", nil, "level 5", 0, "audio/flac"},
+		{"FLAC", "0", nil, "level 0", 0, "audio/flac"},
+		{"FLAC", "8", nil, "level 8", 0, "audio/flac"},
+		{"FLAC", "9", ErrInvalidQuality, "", 0, ""},
+		{"FLAC", "-1", ErrInvalidQuality, "", 0, ""},
+		{"FLAC", "V0", ErrInvalidQuality, "", 0, ""},
+		// Codecs which ffmpeg does not support, or which do not exist
+		{"MP3", "", ErrMP3Disabled, "", 0, ""},
+		{"OGG", "", ErrOGGDisabled, "", 0, ""},
+		{"OPUS", "", ErrOPUSDisabled, "", 0, ""},
+		{"WAV", "", ErrInvalidCodec, "", 0, ""},
+	}
+
+	for _, test := range tests {
+		transcoder, err := Factory(test.codec, test.quality, "")
+		if err != test.err {
+			t.Fatalf("Unexpected error for %s %q: %v != %v", test.codec, test.quality, err, test.err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if transcoder.Codec() != test.codec || transcoder.Quality() != test.output {
+			t.Fatalf("Unexpected transcoder for %s %q: %s %s", test.codec, test.quality, transcoder.Codec(), transcoder.Quality())
+		}
+		if transcoder.Bitrate() != test.bitrate || transcoder.MIMEType() != test.mime {
+			t.Fatalf("Unexpected output for %s %q: %d kbps, %s", test.codec, test.quality, transcoder.Bitrate(), transcoder.MIMEType())
+		}
+	}
+}
+
+// TestFactoryDisabled verifies that Factory fails when ffmpeg or one of its codecs is
+// unavailable
+func TestFactoryDisabled(t *testing.T) {
+	defer testEnable(FFmpegFLACCodec)()
+
+	if _, err := Factory("AAC", "", ""); err != ErrAACDisabled {
+		t.Fatalf("Unexpected error for disabled AAC: %v", err)
+	}
+
+	CodecSet = set.New(FFmpegAACCodec)
+	if _, err := Factory("FLAC", "", ""); err != ErrFLACDisabled {
+		t.Fatalf("Unexpected error for disabled FLAC: %v", err)
+	}
+
+	Enabled = false
+	if _, err := Factory("AAC", "", ""); err != ErrTranscodingDisabled {
+		t.Fatalf("Unexpected error with transcoding disabled: %v", err)
+	}
+}
+
+// TestFFmpegArguments verifies that ffmpeg is invoked with each codec's quality flags, and
+// that AAC is written to a MP4 container which can be streamed through a pipe
+func TestFFmpegArguments(t *testing.T) {
+	defer testEnable(FFmpegAACCodec, FFmpegFLACCodec)()
+
+	song := &data.Song{FileName: "/music/song.flac"}
+	input := []string{"-i", song.FileName, "-vn", "-acodec"}
+
+	tests := []struct {
+		codec   string
+		quality string
+		args    []string
+	}{
+		{"AAC", "256", []string{"aac", "-ab", "256k", "-f", "mp4", "-movflags", "frag_keyframe+empty_moov", "pipe:1.m4a"}},
+		{"AAC", "Q2", []string{"aac", "-aq", "2", "-f", "mp4", "-movflags", "frag_keyframe+empty_moov", "pipe:1.m4a"}},
+		{"FLAC", "", []string{"flac", "-compression_level", "5", "pipe:1.flac"}},
+		{"FLAC", "8", []string{"flac", "-compression_level", "8", "pipe:1.flac"}},
+	}
+
+	for _, test := range tests {
+		transcoder, err := Factory(test.codec, test.quality, "")
+		if err != nil {
+			t.Fatalf("Could not create transcoder for %s %q: %s", test.codec, test.quality, err.Error())
+		}
+
+		args := NewFFmpeg(song, testOptions(transcoder), 0, nil).Arguments()
+		if expected := append(input, test.args...); !reflect.DeepEqual(args, expected) {
+			t.Fatalf("Unexpected arguments for %s %q: %v != %v", test.codec, test.quality, args, expected)
+		}
+	}
+}
+
+// TestEstimateSize verifies that transcode sizes are estimated from the transcoder's bitrate and
+// the length of the song which remains after its offset, and are unknown for lossless codecs
+func TestEstimateSize(t *testing.T) {
+	defer testEnable(FFmpegAACCodec, FFmpegFLACCodec)()
+
+	song := &data.Song{Length: 300}
+	tests := []struct {
+		codec   string
+		quality string
+		offset  int
+		size    int64
+	}{
+		{"AAC", "192", 0, 192 * 1000 / 8 * 300},
+		{"AAC", "128", 60, 128 * 1000 / 8 * 240},
+		{"AAC", "Q2", 299, 192 * 1000 / 8},
+		{"AAC", "192", 300, -1},
+		{"FLAC", "", 60, -1},
+	}
+
+	for _, test := range tests {
+		transcoder, err := Factory(test.codec, test.quality, "")
+		if err != nil {
+			t.Fatalf("Could not create transcoder for %s %q: %s", test.codec, test.quality, err.Error())
+		}
+		transcoder.SetOffset(test.offset)
+
+		if size := EstimateSize(song, transcoder); size != test.size {
+			t.Fatalf("Unexpected size for %s %q at %ds: %d != %d", test.codec, test.quality, test.offset, size, test.size)
+		}
+	}
+}